@@ -70,8 +70,17 @@ type RecommendedContainerStatus struct {
 	ContainerName string `json:"containerName,omitempty"`
 	// Recommended resources of container
 	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// Recommended resource limits of container
+	Limits corev1.ResourceList `json:"limits,omitempty"`
 }
 
+const (
+	// RecommendationConditionTargetResolved indicates whether the target has been resolved into a series of pods
+	RecommendationConditionTargetResolved = "TargetResolved"
+	// RecommendationConditionRecommendationProvided indicates whether the recommended resources have been computed
+	RecommendationConditionRecommendationProvided = "RecommendationProvided"
+)
+
 // RecommendationStatus defines the observed state of Recommendation
 type RecommendationStatus struct {
 	// PodStatus records the most recently computed amount of resources recommended
@@ -242,6 +242,8 @@ type ColocationStrategy struct {
 	MetricReportIntervalSeconds    *int64                               `json:"metricReportIntervalSeconds,omitempty" validate:"omitempty,min=1"`
 	MetricAggregatePolicy          *slov1alpha1.AggregatePolicy         `json:"metricAggregatePolicy,omitempty"`
 	MetricMemoryCollectPolicy      *slov1alpha1.NodeMemoryCollectPolicy `json:"metricMemoryCollectPolicy,omitempty"`
+	// MetricContainerAggregatePolicy enables reporting the per-container aggregated usages in the NodeMetric
+	MetricContainerAggregatePolicy *slov1alpha1.ContainerAggregatePolicy `json:"metricContainerAggregatePolicy,omitempty"`
 
 	CPUReclaimThresholdPercent *int64 `json:"cpuReclaimThresholdPercent,omitempty" validate:"omitempty,min=0"`
 	// CPUCalculatePolicy determines the calculation policy of the CPU resources for the Batch pods.
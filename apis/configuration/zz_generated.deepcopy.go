@@ -159,6 +159,11 @@ func (in *ColocationStrategy) DeepCopyInto(out *ColocationStrategy) {
 		*out = new(v1alpha1.NodeMemoryCollectPolicy)
 		**out = **in
 	}
+	if in.MetricContainerAggregatePolicy != nil {
+		in, out := &in.MetricContainerAggregatePolicy, &out.MetricContainerAggregatePolicy
+		*out = new(v1alpha1.ContainerAggregatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CPUReclaimThresholdPercent != nil {
 		in, out := &in.CPUReclaimThresholdPercent, &out.CPUReclaimThresholdPercent
 		*out = new(int64)
@@ -89,10 +89,12 @@ const (
 type AggregationType string
 
 const (
-	// max is not welcomed since it may import outliers
 	AVG AggregationType = "avg"
 	P99 AggregationType = "p99"
 	P95 AggregationType = "p95"
 	P90 AggregationType = "p90"
 	P50 AggregationType = "p50"
+	// MAX is only reported for the container aggregated usages, it is not welcomed for the node usages
+	// since it may import outliers
+	MAX AggregationType = "max"
 )
@@ -19,6 +19,8 @@ package extension
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apiserver/pkg/quota/v1"
@@ -42,6 +44,7 @@ const (
 	LabelQuotaIgnoreDefaultTree          = QuotaKoordinatorPrefix + "/ignore-default-tree"
 	LabelPreemptible                     = QuotaKoordinatorPrefix + "/preemptible"
 	LabelAllowForceUpdate                = QuotaKoordinatorPrefix + "/allow-force-update"
+	LabelQuotaMinWeight                  = QuotaKoordinatorPrefix + "/min-weight"
 	AnnotationSharedWeight               = QuotaKoordinatorPrefix + "/shared-weight"
 	AnnotationRuntime                    = QuotaKoordinatorPrefix + "/runtime"
 	AnnotationRequest                    = QuotaKoordinatorPrefix + "/request"
@@ -56,6 +59,10 @@ const (
 	AnnotationNonPreemptibleUsed         = QuotaKoordinatorPrefix + "/non-preemptible-used"
 	AnnotationAdmission                  = QuotaKoordinatorPrefix + "/admission"
 	AnnotationMaxStrictCheckResourceKeys = QuotaKoordinatorPrefix + "/max-strict-check-resource-keys"
+	AnnotationFairShareWeight            = QuotaKoordinatorPrefix + "/fair-share-weight"
+	AnnotationLendingSchedules           = QuotaKoordinatorPrefix + "/lending-schedules"
+	AnnotationMaxBorrowDuration          = QuotaKoordinatorPrefix + "/max-borrow-duration"
+	AnnotationRevokeGracePeriod          = QuotaKoordinatorPrefix + "/revoke-grace-period"
 )
 
 func GetParentQuotaName(quota *v1alpha1.ElasticQuota) string {
@@ -230,3 +237,23 @@ func GetMaxStrictCheckResourceKeys(quota *v1alpha1.ElasticQuota) ([]corev1.Resou
 	}
 	return resources, nil
 }
+
+// DefaultFairShareWeight is the fair-share weight of the quota without AnnotationFairShareWeight.
+const DefaultFairShareWeight = 1.0
+
+// GetFairShareWeight returns the weight of the quota when ordering pending pods by the fair share of their quotas.
+// The quota with a larger weight is entitled to a larger share.
+func GetFairShareWeight(quota *v1alpha1.ElasticQuota) (float64, error) {
+	value := quota.Annotations[AnnotationFairShareWeight]
+	if value == "" {
+		return DefaultFairShareWeight, nil
+	}
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return DefaultFairShareWeight, err
+	}
+	if !(weight > 0) || math.IsInf(weight, 1) {
+		return DefaultFairShareWeight, fmt.Errorf("fair-share weight should be positive, got %v", value)
+	}
+	return weight, nil
+}
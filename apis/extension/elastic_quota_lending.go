@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+// LendingSchedule is a time window when the quota lends a ratio of its min to the other quotas, no matter whether
+// LabelAllowLentResource is set. E.g. a quota not lending resources can lend 80% of its min on nights and weekends:
+//
+//	[{"start": "20:00", "end": "08:00", "lendRatio": 0.8},
+//	 {"days": ["Sat", "Sun"], "start": "00:00", "end": "00:00", "lendRatio": 0.8}]
+type LendingSchedule struct {
+	// Days are the days of the week when the window starts, e.g. "Mon", "Sat". Empty means every day.
+	Days []string `json:"days,omitempty"`
+	// Start and End are the time of the day in the format "15:04". The window spans midnight if End is before
+	// Start, and lasts a whole day if End equals Start.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// TimeZone is the IANA time zone of Start and End, defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// LendRatio is the ratio of min lent to the other quotas in the window, in (0, 1].
+	LendRatio float64 `json:"lendRatio"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Validate checks whether the schedule is well-formed.
+func (s *LendingSchedule) Validate() error {
+	for _, day := range s.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid day %q", day)
+		}
+	}
+	if _, err := parseMinuteOfDay(s.Start); err != nil {
+		return fmt.Errorf("invalid start %q, err: %w", s.Start, err)
+	}
+	if _, err := parseMinuteOfDay(s.End); err != nil {
+		return fmt.Errorf("invalid end %q, err: %w", s.End, err)
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return fmt.Errorf("invalid timeZone %q, err: %w", s.TimeZone, err)
+	}
+	if !(s.LendRatio > 0 && s.LendRatio <= 1) {
+		return fmt.Errorf("lendRatio should be in (0, 1], got %v", s.LendRatio)
+	}
+	return nil
+}
+
+// IsActive returns whether t is in the window of the schedule. The invalid schedule is never active.
+func (s *LendingSchedule) IsActive(t time.Time) bool {
+	start, err := parseMinuteOfDay(s.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseMinuteOfDay(s.End)
+	if err != nil {
+		return false
+	}
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return false
+	}
+	t = t.In(location)
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return s.matchDay(t.Weekday()) && minute >= start && minute < end
+	}
+	// the window spans midnight, it may start today or yesterday
+	return (s.matchDay(t.Weekday()) && minute >= start) ||
+		(s.matchDay((t.Weekday()+6)%7) && minute < end)
+}
+
+func (s *LendingSchedule) matchDay(day time.Weekday) bool {
+	if len(s.Days) == 0 {
+		return true
+	}
+	for _, d := range s.Days {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseMinuteOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// GetLendingSchedules returns the lending schedules of the quota.
+func GetLendingSchedules(quota *v1alpha1.ElasticQuota) ([]LendingSchedule, error) {
+	value := quota.Annotations[AnnotationLendingSchedules]
+	if value == "" {
+		return nil, nil
+	}
+	var schedules []LendingSchedule
+	if err := json.Unmarshal([]byte(value), &schedules); err != nil {
+		return nil, err
+	}
+	for i := range schedules {
+		if err := schedules[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid lending schedule %d, err: %w", i, err)
+		}
+	}
+	return schedules, nil
+}
+
+// GetActiveLendRatio returns the max lend ratio of the schedules active at t, or zero if none is active.
+func GetActiveLendRatio(schedules []LendingSchedule, t time.Time) float64 {
+	var ratio float64
+	for i := range schedules {
+		if schedules[i].LendRatio > ratio && schedules[i].IsActive(t) {
+			ratio = schedules[i].LendRatio
+		}
+	}
+	return ratio
+}
+
+// GetMaxBorrowDuration returns how long the pods of the quota can use the resources borrowed from the other quotas,
+// after which they can be preempted by the pods of the other quotas. Zero means unlimited.
+// The borrowing time is counted by the scheduler in memory, so it restarts when the scheduler restarts.
+func GetMaxBorrowDuration(quota *v1alpha1.ElasticQuota) (time.Duration, error) {
+	return parseNonNegativeDuration(quota.Annotations[AnnotationMaxBorrowDuration])
+}
+
+// GetRevokeGracePeriod returns how long the quota can use more than its runtime before its pods are revoked.
+// It returns false if the quota doesn't specify one.
+func GetRevokeGracePeriod(quota *v1alpha1.ElasticQuota) (time.Duration, bool, error) {
+	value := quota.Annotations[AnnotationRevokeGracePeriod]
+	if value == "" {
+		return 0, false, nil
+	}
+	d, err := parseNonNegativeDuration(value)
+	if err != nil {
+		return 0, false, err
+	}
+	return d, true, nil
+}
+
+func parseNonNegativeDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration should not be negative, got %v", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestLendingScheduleIsActive(t *testing.T) {
+	// 2024-01-05 is a Friday
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 5, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		schedule LendingSchedule
+		t        time.Time
+		want     bool
+	}{
+		{
+			name:     "in the daytime window",
+			schedule: LendingSchedule{Start: "09:00", End: "18:00", LendRatio: 1},
+			t:        friday(12, 0),
+			want:     true,
+		},
+		{
+			name:     "end is exclusive",
+			schedule: LendingSchedule{Start: "09:00", End: "18:00", LendRatio: 1},
+			t:        friday(18, 0),
+			want:     false,
+		},
+		{
+			name:     "overnight window before midnight",
+			schedule: LendingSchedule{Start: "20:00", End: "08:00", LendRatio: 1},
+			t:        friday(23, 0),
+			want:     true,
+		},
+		{
+			name:     "overnight window after midnight",
+			schedule: LendingSchedule{Start: "20:00", End: "08:00", LendRatio: 1},
+			t:        friday(7, 59),
+			want:     true,
+		},
+		{
+			name:     "out of the overnight window",
+			schedule: LendingSchedule{Start: "20:00", End: "08:00", LendRatio: 1},
+			t:        friday(12, 0),
+			want:     false,
+		},
+		{
+			name:     "whole day on weekends",
+			schedule: LendingSchedule{Days: []string{"Sat", "Sun"}, Start: "00:00", End: "00:00", LendRatio: 1},
+			t:        friday(12, 0).Add(24 * time.Hour),
+			want:     true,
+		},
+		{
+			name:     "not on the days",
+			schedule: LendingSchedule{Days: []string{"Sat", "Sun"}, Start: "00:00", End: "00:00", LendRatio: 1},
+			t:        friday(12, 0),
+			want:     false,
+		},
+		{
+			name:     "overnight window started yesterday",
+			schedule: LendingSchedule{Days: []string{"Fri"}, Start: "20:00", End: "08:00", LendRatio: 1},
+			t:        friday(7, 0).Add(24 * time.Hour),
+			want:     true,
+		},
+		{
+			name:     "overnight window not started yesterday",
+			schedule: LendingSchedule{Days: []string{"Fri"}, Start: "20:00", End: "08:00", LendRatio: 1},
+			t:        friday(7, 0),
+			want:     false,
+		},
+		{
+			name:     "in the time zone",
+			schedule: LendingSchedule{Start: "20:00", End: "08:00", TimeZone: "Asia/Shanghai", LendRatio: 1},
+			t:        friday(12, 0),
+			want:     true,
+		},
+		{
+			name:     "invalid schedule",
+			schedule: LendingSchedule{Start: "9am", End: "18:00", LendRatio: 1},
+			t:        friday(12, 0),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schedule.IsActive(tt.t))
+		})
+	}
+}
+
+func TestGetLendingSchedules(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []LendingSchedule
+		wantErr bool
+	}{
+		{
+			name: "no schedules",
+		},
+		{
+			name:  "valid schedules",
+			value: `[{"start":"20:00","end":"08:00","lendRatio":0.8},{"days":["Sat","Sun"],"start":"00:00","end":"00:00","lendRatio":0.8}]`,
+			want: []LendingSchedule{
+				{Start: "20:00", End: "08:00", LendRatio: 0.8},
+				{Days: []string{"Sat", "Sun"}, Start: "00:00", End: "00:00", LendRatio: 0.8},
+			},
+		},
+		{
+			name:    "invalid json",
+			value:   `{`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid day",
+			value:   `[{"days":["Someday"],"start":"00:00","end":"00:00","lendRatio":0.8}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid time zone",
+			value:   `[{"start":"00:00","end":"00:00","timeZone":"Mars/Olympus","lendRatio":0.8}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid lend ratio",
+			value:   `[{"start":"00:00","end":"00:00","lendRatio":1.5}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quota := &v1alpha1.ElasticQuota{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if tt.value != "" {
+				quota.Annotations[AnnotationLendingSchedules] = tt.value
+			}
+			got, err := GetLendingSchedules(quota)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetActiveLendRatio(t *testing.T) {
+	schedules := []LendingSchedule{
+		{Start: "20:00", End: "08:00", LendRatio: 0.5},
+		{Start: "22:00", End: "06:00", LendRatio: 0.8},
+	}
+	assert.Equal(t, 0.0, GetActiveLendRatio(schedules, time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0.5, GetActiveLendRatio(schedules, time.Date(2024, 1, 5, 21, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0.8, GetActiveLendRatio(schedules, time.Date(2024, 1, 5, 23, 0, 0, 0, time.UTC)))
+}
+
+func TestGetBorrowingDurations(t *testing.T) {
+	quota := &v1alpha1.ElasticQuota{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+	d, err := GetMaxBorrowDuration(quota)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+	_, ok, err := GetRevokeGracePeriod(quota)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	quota.Annotations[AnnotationMaxBorrowDuration] = "2h"
+	quota.Annotations[AnnotationRevokeGracePeriod] = "0s"
+	d, err = GetMaxBorrowDuration(quota)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, d)
+	d, ok, err = GetRevokeGracePeriod(quota)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	quota.Annotations[AnnotationMaxBorrowDuration] = "-1h"
+	quota.Annotations[AnnotationRevokeGracePeriod] = "soon"
+	_, err = GetMaxBorrowDuration(quota)
+	assert.Error(t, err)
+	_, _, err = GetRevokeGracePeriod(quota)
+	assert.Error(t, err)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,9 +36,54 @@ type ElasticQuotaProfileSpec struct {
 	// NodeSelector defines a node selector to select nodes.
 	// +required
 	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
+	// ChildMinPolicy defines how to derive the min of the child quotas of the quota from the selected nodes.
+	// The min of the child quotas is left untouched if it is not set.
+	// +optional
+	ChildMinPolicy *ChildMinPolicy `json:"childMinPolicy,omitempty"`
 }
 
+type ChildMinPolicyType string
+
+const (
+	// ChildMinPolicyProportional divides the min of the quota among the weighted child quotas in proportion to
+	// their weights, after reserving the min of the child quotas without weights.
+	ChildMinPolicyProportional ChildMinPolicyType = "Proportional"
+)
+
+type ChildMinPolicy struct {
+	// Type is the type of the policy.
+	// +kubebuilder:validation:Enum=Proportional
+	Type ChildMinPolicyType `json:"type"`
+	// WeightLabelKey is the label key on the child quotas whose value is the weight of the child quota.
+	// Defaults to quota.scheduling.koordinator.sh/min-weight.
+	// +optional
+	WeightLabelKey string `json:"weightLabelKey,omitempty"`
+}
+
+const (
+	// ElasticQuotaProfileQuotaSynced means the quota is synced with the selected nodes.
+	ElasticQuotaProfileQuotaSynced = "QuotaSynced"
+	// ElasticQuotaProfileChildMinSynced means the min of the child quotas is derived by the ChildMinPolicy.
+	ElasticQuotaProfileChildMinSynced = "ChildMinSynced"
+)
+
 type ElasticQuotaProfileStatus struct {
+	// TotalResource is the total allocatable resource of the selected nodes, decorated by the ResourceRatio.
+	TotalResource corev1.ResourceList `json:"totalResource,omitempty"`
+	// UnschedulableResource is the allocatable resource of the unschedulable or not ready nodes,
+	// decorated by the ResourceRatio.
+	UnschedulableResource corev1.ResourceList `json:"unschedulableResource,omitempty"`
+	// NodeCount is the number of the selected nodes.
+	NodeCount int32 `json:"nodeCount,omitempty"`
+	// UnschedulableNodeCount is the number of the unschedulable or not ready nodes.
+	UnschedulableNodeCount int32 `json:"unschedulableNodeCount,omitempty"`
+	// ChildMin is the min derived for the child quotas by the ChildMinPolicy, keyed by the quota name.
+	ChildMin map[string]corev1.ResourceList `json:"childMin,omitempty"`
+	// LastSyncTime is the last time the status is synced.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Conditions is the latest observations of the profile.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //  ElasticQuotaProfile is the Schema for the ElasticQuotaProfile API
@@ -46,6 +92,7 @@ type ElasticQuotaProfileStatus struct {
 // +genclient
 // +kubebuilder:resource:shortName=eqp
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 type ElasticQuotaProfile struct {
 	metav1.TypeMeta   `json:",inline"`
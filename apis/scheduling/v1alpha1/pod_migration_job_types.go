@@ -165,6 +165,9 @@ const (
 	PodMigrationJobConditionReservationPodBoundReservation PodMigrationJobConditionType = "PodBoundReservation"
 	PodMigrationJobConditionBoundPodReady                  PodMigrationJobConditionType = "BoundPodReady"
 	PodMigrationJobConditionReservationBound               PodMigrationJobConditionType = "ReservationBound"
+	PodMigrationJobConditionMigrationWindow                PodMigrationJobConditionType = "MigrationWindow"
+	PodMigrationJobConditionPreEvictionHook                PodMigrationJobConditionType = "PreEvictionHook"
+	PodMigrationJobConditionGangReservationScheduled       PodMigrationJobConditionType = "GangReservationScheduled"
 )
 
 // These are valid reasons of PodMigrationJob.
@@ -183,6 +186,15 @@ const (
 	PodMigrationJobReasonEvictComplete             = "EvictComplete"
 	PodMigrationJobReasonWaitForPodBindReservation = "WaitForPodBindReservation"
 	PodMigrationJobReasonWaitForBoundPodReady      = "WaitForBoundPodReady"
+	PodMigrationJobReasonWaitForMigrationWindow    = "WaitForMigrationWindow"
+	PodMigrationJobReasonMigrationWindowOpened     = "MigrationWindowOpened"
+	PodMigrationJobReasonWaitForPreEvictionHook    = "WaitForPreEvictionHook"
+	PodMigrationJobReasonPreEvictionHookReady      = "PreEvictionHookReady"
+	PodMigrationJobReasonPreEvictionHookDenied     = "PreEvictionHookDenied"
+	PodMigrationJobReasonPreEvictionHookFailed     = "PreEvictionHookFailed"
+	PodMigrationJobReasonWaitForGangReservations   = "WaitForGangReservations"
+	PodMigrationJobReasonGangReservationsScheduled = "GangReservationsScheduled"
+	PodMigrationJobReasonGangMigrationAborted      = "GangMigrationAborted"
 )
 
 type PodMigrationJobConditionStatus string
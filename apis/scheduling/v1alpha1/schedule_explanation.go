@@ -28,7 +28,7 @@ type ScheduleExplanationSpec struct {
 	*/
 	QuestionObjectKey string `json:"questionObjectKey,omitempty"`
 
-	// QuestionObjectTemplate defines the questioned pod template. Now it is unsupported
+	// QuestionObjectTemplate defines the questioned pod template, which is explained with a dry-run scheduling cycle.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:Schemaless
 	QuestionObjectTemplate *corev1.PodTemplateSpec `json:"questionObjectTemplate,omitempty"`
@@ -51,8 +51,8 @@ type ScheduleExplanationStatus struct {
 	// FailedMessage indicates the reason for the QuestionObject can't be scheduled.
 	FailedMessage string `json:"failedMessage,omitempty"`
 
-	// LastUpdateTime records when the status was last written. It is updated whenever
-	// the status content changes, or at least once per minute as a heartbeat.
+	// LastUpdateTime records when the status was last written. It is updated only when
+	// the status content changes.
 	// +optional
 	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
 
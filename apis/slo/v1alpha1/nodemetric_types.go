@@ -79,6 +79,18 @@ type PodMetricInfo struct {
 	QoS apiext.QoSClass `json:"qos,omitempty"`
 	// Third party extensions for PodMetric
 	Extensions *ExtensionsMap `json:"extensions,omitempty"`
+	// ContainersMetric contains the metrics for containers of the pod, which is reported only if the
+	// ContainerAggregatePolicy is enabled
+	ContainersMetric []*ContainerMetricInfo `json:"containersMetric,omitempty"`
+}
+
+type ContainerMetricInfo struct {
+	// Name of the container
+	Name string `json:"name,omitempty"`
+	// ContainerUsage is the resource usage of the container
+	ContainerUsage ResourceMap `json:"containerUsage,omitempty"`
+	// AggregatedContainerUsages will report only if there are enough samples
+	AggregatedContainerUsages []AggregatedUsage `json:"aggregatedContainerUsages,omitempty"`
 }
 
 type HostApplicationMetricInfo struct {
@@ -108,12 +120,28 @@ type NodeMetricCollectPolicy struct {
 	NodeAggregatePolicy *AggregatePolicy `json:"nodeAggregatePolicy,omitempty"`
 	// NodeMemoryPolicy represents apply which method collect memory info
 	NodeMemoryCollectPolicy *NodeMemoryCollectPolicy `json:"nodeMemoryCollectPolicy,omitempty"`
+	// ContainerAggregatePolicy represents the target grain of container aggregated usages, disabled if not specified
+	ContainerAggregatePolicy *ContainerAggregatePolicy `json:"containerAggregatePolicy,omitempty"`
 }
 
 type AggregatePolicy struct {
 	Durations []metav1.Duration `json:"durations,omitempty"`
 }
 
+// ContainerAggregatePolicy defines the policy to report the per-container aggregated usages
+type ContainerAggregatePolicy struct {
+	// Enable indicates whether to report the container aggregated usages
+	Enable *bool `json:"enable,omitempty"`
+	// Durations represents the aggregation windows of the container usages, the p50/p90/p99/max of the cpu and memory
+	// are reported for each window
+	Durations []metav1.Duration `json:"durations,omitempty"`
+	// MaxContainers limits the number of containers reported on a node to bound the size of the NodeMetric,
+	// the containers of pods with a higher priority are reported first, and a pod is reported with either all of
+	// its containers or none of them
+	// +kubebuilder:validation:Minimum=0
+	MaxContainers *int64 `json:"maxContainers,omitempty"`
+}
+
 // ReclaimableMetric defines the reclaimable metric of resource priority
 type ReclaimableMetric struct {
 	// Resource is the resource usage of the prediction
@@ -413,6 +413,122 @@ type ResourceThresholdStrategy struct {
 	EvictEnabledPriorityThreshold *int32 `json:"evictEnabledPriorityThreshold,omitempty"`
 	// AllocatableEvictPriorityThreshold defines the highest priority for the xxxAllocatableEvict feature. must less than koord-prod
 	AllocatableEvictPriorityThreshold *int32 `json:"allocatableEvictPriorityThreshold,omitempty" validate:"omitempty,min=0,max=7999"`
+
+	// Note: used for feature: BEPSISuppress
+	// PSISuppress defines the pressure thresholds of the non-BE pods to throttle and evict the BE pods.
+	PSISuppress *PSISuppressStrategy `json:"psiSuppress,omitempty"`
+
+	// Note: used for feature: BEInterferenceEvict
+	// Interference defines how to detect the interference of the BE pods on the non-BE pods by CPI and resctrl metrics.
+	Interference *InterferenceStrategy `json:"interference,omitempty"`
+
+	// Note: used for feature: BEMemoryHighTune
+	// MemoryHighTune defines how to tune the memory.high of the BE pods by the memory reclaim feedback.
+	MemoryHighTune *MemoryHighTuneStrategy `json:"memoryHighTune,omitempty"`
+}
+
+// PSISuppressStrategy defines the thresholds on the pressure stall information (PSI) of the non-BE pods.
+// A threshold is compared with the max `some avg10` percentage of the non-BE pods on the resource, and the resource
+// whose threshold is not set is not watched.
+// When the pressure exceeds a throttle threshold, the cfs quota of the BE pods is decreased step by step, and it is
+// increased step by step after the pressure drops. When the pressure exceeds an evict threshold, the BE pods are
+// evicted one by one.
+type PSISuppressStrategy struct {
+	// cpu pressure threshold percentage (0,100) to throttle the BE pods
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	CPUThrottleThresholdPercent *int64 `json:"cpuThrottleThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// memory pressure threshold percentage (0,100) to throttle the BE pods
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemoryThrottleThresholdPercent *int64 `json:"memoryThrottleThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// io pressure threshold percentage (0,100) to throttle the BE pods
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	IOThrottleThresholdPercent *int64 `json:"ioThrottleThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+
+	// cpu pressure threshold percentage (0,100) to evict the BE pods
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	CPUEvictThresholdPercent *int64 `json:"cpuEvictThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// memory pressure threshold percentage (0,100) to evict the BE pods
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemoryEvictThresholdPercent *int64 `json:"memoryEvictThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// io pressure threshold percentage (0,100) to evict the BE pods
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	IOEvictThresholdPercent *int64 `json:"ioEvictThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+
+	// the percentage (0,100] of the node cpu capacity to decrease or increase the BE cfs quota per round, default = 10
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	ThrottleStepPercent *int64 `json:"throttleStepPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// the min percentage (0,100) of the node cpu capacity kept for the BE cfs quota, default = 10
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	ThrottleMinPercent *int64 `json:"throttleMinPercent,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+// InterferenceStrategy defines the detection of the interference on the non-BE pods and the actions on the BE pods.
+// A baseline of the CPI (cycles per instruction) of each non-BE pod, and a baseline of the MBPI (memory bandwidth per
+// instruction) of the non-BE resctrl groups are learned while no interference is detected. A degradation beyond the
+// baseline is attributed to the BE pods when the BE group takes enough memory bandwidth. Then the MBA of the BE
+// group is lowered step by step, and the BE pods are evicted one by one if the degradation persists after the MBA
+// reaches the min. Nothing is done if the BE memory bandwidth share is unknown without the resctrl metrics, and a
+// baseline is relearned if the degradation lasts too long, e.g. after the workload shifts.
+type InterferenceStrategy struct {
+	// the CPI degradation percentage over the baseline to regard a non-BE pod as interfered, default = 30
+	// +kubebuilder:validation:Minimum=1
+	CPIDegradationPercent *int64 `json:"cpiDegradationPercent,omitempty" validate:"omitempty,min=1"`
+	// the MBPI degradation percentage over the baseline to regard the non-BE pods as interfered, default = 30
+	// +kubebuilder:validation:Minimum=1
+	MBPIDegradationPercent *int64 `json:"mbpiDegradationPercent,omitempty" validate:"omitempty,min=1"`
+	// the min share percentage (0,100) of the BE group in the node memory bandwidth to attribute the interference to
+	// the BE pods, default = 30
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	BEMemoryBandwidthSharePercent *int64 `json:"beMemoryBandwidthSharePercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the MBA percentage lowered for the BE group per round, default = 10
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	BEMBAPercentStep *int64 `json:"beMBAPercentStep,omitempty" validate:"omitempty,min=1,max=100"`
+	// the min MBA percentage (0,100] of the BE group, default = 10
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	BEMinMBAPercent *int64 `json:"beMinMBAPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// the consecutive interfered rounds after the BE MBA reaches the min to start evicting the BE pods, default = 3
+	// +kubebuilder:validation:Minimum=1
+	EvictAfterRounds *int64 `json:"evictAfterRounds,omitempty" validate:"omitempty,min=1"`
+}
+
+// MemoryHighTuneStrategy defines the feedback control on the memory.high of the BE pods.
+// When the node memory usage exceeds the target, the memory.high of the BE pods is lowered by a step each round so
+// that the kernel reclaims the BE memory before the LS pods are affected. When the node has free memory, the
+// memory.high of a BE pod is raised by a step if the pod suffers from the memory pressure or the refaults, and it is
+// reset to max after it reaches the memory limit. The memory.high is never lower than the memory request of the pod
+// or the MinMemoryHighPercent of its memory limit, and it is never raised when the node memory usage reaches the
+// MemoryEvictThresholdPercent. The BE pods whose memory.high is throttled by the MemoryQOS are not tuned.
+type MemoryHighTuneStrategy struct {
+	// the node memory usage percentage (0,100) to start lowering the memory.high of the BE pods, default = 70
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	NodeMemoryTargetPercent *int64 `json:"nodeMemoryTargetPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the memory pressure percentage (0,100) of a BE pod to raise its memory.high, default = 20
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	PSIThresholdPercent *int64 `json:"psiThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the refaulted pages per second of a BE pod to raise its memory.high, default = 1000
+	// +kubebuilder:validation:Minimum=0
+	RefaultThreshold *int64 `json:"refaultThreshold,omitempty" validate:"omitempty,min=0"`
+	// the percentage (0,100] of the memory limit of a BE pod to lower or raise its memory.high per round, default = 10
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	StepPercent *int64 `json:"stepPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// the min percentage (0,100] of the memory limit of a BE pod kept for its memory.high, default = 20
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	MinMemoryHighPercent *int64 `json:"minMemoryHighPercent,omitempty" validate:"omitempty,min=1,max=100"`
 }
 
 // ResctrlQOSCfg stores node-level config of resctrl qos
@@ -498,6 +614,14 @@ type SystemStrategy struct {
 
 	// TotalNetworkBandwidth indicates the overall network bandwidth, cluster manager can set this field, and default value taken from /sys/class/net/${NIC_NAME}/speed, unit: Mbps
 	TotalNetworkBandwidth resource.Quantity `json:"totalNetworkBandwidth,omitempty"`
+	// NetworkIngressShaping indicates whether to shape the ingress traffic by the tc network qos policy.
+	// The ingress traffic is redirected to an IFB device, where the system/LS/BE classes are built with the ingress
+	// requests and limits of the NetworkQOS. Disabled by default.
+	NetworkIngressShaping *bool `json:"networkIngressShaping,omitempty"`
+	// NetworkAdaptiveBECeil indicates whether to adjust the ceil of the BE class by the tc network qos policy.
+	// The BE ceil is raised or lowered according to the measured LS throughput against the TotalNetworkBandwidth,
+	// and it is kept between the request and limit of the BE NetworkQOS. Disabled by default.
+	NetworkAdaptiveBECeil *bool `json:"networkAdaptiveBECeil,omitempty"`
 }
 
 // NodeSLOSpec defines the desired state of NodeSLO
@@ -519,8 +643,61 @@ type NodeSLOSpec struct {
 
 // NodeSLOStatus defines the observed state of NodeSLO
 type NodeSLOStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ObservedGeneration is the generation of the NodeSLO spec which koordlet has observed.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Strategies reports the apply results of the strategies on the node.
+	// +optional
+	Strategies []StrategyStatus `json:"strategies,omitempty"`
+	// KernelCapabilities reports whether the kernel features which the strategies rely on are supported.
+	// +optional
+	KernelCapabilities []KernelCapability `json:"kernelCapabilities,omitempty"`
+}
+
+type StrategyPhase string
+
+const (
+	// StrategyPhaseEnabled indicates the strategy is enabled on the node. The reason tells whether it is reported
+	// applied by the plugin or only configured in the spec.
+	StrategyPhaseEnabled StrategyPhase = "Enabled"
+	// StrategyPhaseDisabled indicates the strategy is disabled in the spec.
+	StrategyPhaseDisabled StrategyPhase = "Disabled"
+	// StrategyPhaseSkipped indicates the strategy is enabled but skipped since the kernel lacks the support.
+	StrategyPhaseSkipped StrategyPhase = "Skipped"
+	// StrategyPhaseFailed indicates the strategy is enabled but failed to apply.
+	StrategyPhaseFailed StrategyPhase = "Failed"
+)
+
+// The names of the strategies reported in the NodeSLO status.
+const (
+	StrategyNameCPUQOS     = "ResourceQOSStrategy.CPUQOS"
+	StrategyNameMemoryQOS  = "ResourceQOSStrategy.MemoryQOS"
+	StrategyNameResctrlQOS = "ResourceQOSStrategy.ResctrlQOS"
+	StrategyNameBlkIOQOS   = "ResourceQOSStrategy.BlkIOQOS"
+	StrategyNameCPUBurst   = "CPUBurstStrategy"
+	StrategyNameSystem     = "SystemStrategy"
+)
+
+type StrategyStatus struct {
+	// Name is the name of the strategy, e.g. ResourceQOSStrategy.CPUQOS, CPUBurstStrategy, SystemStrategy.
+	Name string `json:"name"`
+	// Phase is the apply result of the strategy.
+	Phase StrategyPhase `json:"phase,omitempty"`
+	// Reason is a brief CamelCase reason for the phase.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable explanation for the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type KernelCapability struct {
+	// Name is the name of the kernel capability, e.g. CPUBurst, GroupIdentity, Resctrl.
+	Name string `json:"name"`
+	// Supported indicates whether the kernel capability is supported on the node.
+	Supported bool `json:"supported"`
+	// Message is a human-readable explanation of the probe result.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
@@ -252,6 +252,59 @@ func (in *CgroupPath) DeepCopy() *CgroupPath {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerAggregatePolicy) DeepCopyInto(out *ContainerAggregatePolicy) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Durations != nil {
+		in, out := &in.Durations, &out.Durations
+		*out = make([]metav1.Duration, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxContainers != nil {
+		in, out := &in.MaxContainers, &out.MaxContainers
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerAggregatePolicy.
+func (in *ContainerAggregatePolicy) DeepCopy() *ContainerAggregatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerAggregatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerMetricInfo) DeepCopyInto(out *ContainerMetricInfo) {
+	*out = *in
+	in.ContainerUsage.DeepCopyInto(&out.ContainerUsage)
+	if in.AggregatedContainerUsages != nil {
+		in, out := &in.AggregatedContainerUsages, &out.AggregatedContainerUsages
+		*out = make([]AggregatedUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerMetricInfo.
+func (in *ContainerMetricInfo) DeepCopy() *ContainerMetricInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerMetricInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostApplicationMetricInfo) DeepCopyInto(out *HostApplicationMetricInfo) {
 	*out = *in
@@ -403,6 +456,106 @@ func (in *IOCfg) DeepCopy() *IOCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterferenceStrategy) DeepCopyInto(out *InterferenceStrategy) {
+	*out = *in
+	if in.CPIDegradationPercent != nil {
+		in, out := &in.CPIDegradationPercent, &out.CPIDegradationPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MBPIDegradationPercent != nil {
+		in, out := &in.MBPIDegradationPercent, &out.MBPIDegradationPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BEMemoryBandwidthSharePercent != nil {
+		in, out := &in.BEMemoryBandwidthSharePercent, &out.BEMemoryBandwidthSharePercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BEMBAPercentStep != nil {
+		in, out := &in.BEMBAPercentStep, &out.BEMBAPercentStep
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BEMinMBAPercent != nil {
+		in, out := &in.BEMinMBAPercent, &out.BEMinMBAPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EvictAfterRounds != nil {
+		in, out := &in.EvictAfterRounds, &out.EvictAfterRounds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterferenceStrategy.
+func (in *InterferenceStrategy) DeepCopy() *InterferenceStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(InterferenceStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelCapability) DeepCopyInto(out *KernelCapability) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelCapability.
+func (in *KernelCapability) DeepCopy() *KernelCapability {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelCapability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryHighTuneStrategy) DeepCopyInto(out *MemoryHighTuneStrategy) {
+	*out = *in
+	if in.NodeMemoryTargetPercent != nil {
+		in, out := &in.NodeMemoryTargetPercent, &out.NodeMemoryTargetPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PSIThresholdPercent != nil {
+		in, out := &in.PSIThresholdPercent, &out.PSIThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RefaultThreshold != nil {
+		in, out := &in.RefaultThreshold, &out.RefaultThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StepPercent != nil {
+		in, out := &in.StepPercent, &out.StepPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinMemoryHighPercent != nil {
+		in, out := &in.MinMemoryHighPercent, &out.MinMemoryHighPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryHighTuneStrategy.
+func (in *MemoryHighTuneStrategy) DeepCopy() *MemoryHighTuneStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryHighTuneStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemoryQOS) DeepCopyInto(out *MemoryQOS) {
 	*out = *in
@@ -627,6 +780,11 @@ func (in *NodeMetricCollectPolicy) DeepCopyInto(out *NodeMetricCollectPolicy) {
 		*out = new(NodeMemoryCollectPolicy)
 		**out = **in
 	}
+	if in.ContainerAggregatePolicy != nil {
+		in, out := &in.ContainerAggregatePolicy, &out.ContainerAggregatePolicy
+		*out = new(ContainerAggregatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricCollectPolicy.
@@ -791,7 +949,7 @@ func (in *NodeSLO) DeepCopyInto(out *NodeSLO) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSLO.
@@ -893,6 +1051,16 @@ func (in *NodeSLOSpec) DeepCopy() *NodeSLOSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSLOStatus) DeepCopyInto(out *NodeSLOStatus) {
 	*out = *in
+	if in.Strategies != nil {
+		in, out := &in.Strategies, &out.Strategies
+		*out = make([]StrategyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.KernelCapabilities != nil {
+		in, out := &in.KernelCapabilities, &out.KernelCapabilities
+		*out = make([]KernelCapability, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSLOStatus.
@@ -927,6 +1095,61 @@ func (in *OriginAllocatable) DeepCopy() *OriginAllocatable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PSISuppressStrategy) DeepCopyInto(out *PSISuppressStrategy) {
+	*out = *in
+	if in.CPUThrottleThresholdPercent != nil {
+		in, out := &in.CPUThrottleThresholdPercent, &out.CPUThrottleThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryThrottleThresholdPercent != nil {
+		in, out := &in.MemoryThrottleThresholdPercent, &out.MemoryThrottleThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IOThrottleThresholdPercent != nil {
+		in, out := &in.IOThrottleThresholdPercent, &out.IOThrottleThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CPUEvictThresholdPercent != nil {
+		in, out := &in.CPUEvictThresholdPercent, &out.CPUEvictThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryEvictThresholdPercent != nil {
+		in, out := &in.MemoryEvictThresholdPercent, &out.MemoryEvictThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IOEvictThresholdPercent != nil {
+		in, out := &in.IOEvictThresholdPercent, &out.IOEvictThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ThrottleStepPercent != nil {
+		in, out := &in.ThrottleStepPercent, &out.ThrottleStepPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ThrottleMinPercent != nil {
+		in, out := &in.ThrottleMinPercent, &out.ThrottleMinPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PSISuppressStrategy.
+func (in *PSISuppressStrategy) DeepCopy() *PSISuppressStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(PSISuppressStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PeakMetric) DeepCopyInto(out *PeakMetric) {
 	*out = *in
@@ -967,6 +1190,17 @@ func (in *PodMetricInfo) DeepCopyInto(out *PodMetricInfo) {
 		in, out := &in.Extensions, &out.Extensions
 		*out = (*in).DeepCopy()
 	}
+	if in.ContainersMetric != nil {
+		in, out := &in.ContainersMetric, &out.ContainersMetric
+		*out = make([]*ContainerMetricInfo, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ContainerMetricInfo)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMetricInfo.
@@ -1273,6 +1507,21 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int32)
 		**out = **in
 	}
+	if in.PSISuppress != nil {
+		in, out := &in.PSISuppress, &out.PSISuppress
+		*out = new(PSISuppressStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Interference != nil {
+		in, out := &in.Interference, &out.Interference
+		*out = new(InterferenceStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemoryHighTune != nil {
+		in, out := &in.MemoryHighTune, &out.MemoryHighTune
+		*out = new(MemoryHighTuneStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceThresholdStrategy.
@@ -1285,6 +1534,21 @@ func (in *ResourceThresholdStrategy) DeepCopy() *ResourceThresholdStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrategyStatus) DeepCopyInto(out *StrategyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrategyStatus.
+func (in *StrategyStatus) DeepCopy() *StrategyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StrategyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemStrategy) DeepCopyInto(out *SystemStrategy) {
 	*out = *in
@@ -1326,6 +1590,16 @@ func (in *SystemStrategy) DeepCopyInto(out *SystemStrategy) {
 		**out = **in
 	}
 	out.TotalNetworkBandwidth = in.TotalNetworkBandwidth.DeepCopy()
+	if in.NetworkIngressShaping != nil {
+		in, out := &in.NetworkIngressShaping, &out.NetworkIngressShaping
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NetworkAdaptiveBECeil != nil {
+		in, out := &in.NetworkAdaptiveBECeil, &out.NetworkAdaptiveBECeil
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemStrategy.
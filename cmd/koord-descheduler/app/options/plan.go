@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/logs"
+	logsapi "k8s.io/component-base/logs/api/v1"
+
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/plan"
+)
+
+const (
+	PlanOutputYAML = "yaml"
+	PlanOutputJSON = "json"
+)
+
+// PlanOptions has all the params needed to plan a descheduling cycle.
+type PlanOptions struct {
+	Logs *logs.Options
+
+	// ConfigFile is the location of the descheduler configuration file, the default configuration is used if empty.
+	ConfigFile string
+	// SnapshotDir is the directory of the YAML or JSON files holding the cluster snapshot.
+	SnapshotDir string
+	// Kubeconfig is the path to the kubeconfig of the cluster read in read-only mode.
+	Kubeconfig string
+	// Output is the format of the plan, yaml or json.
+	Output string
+
+	// Flags hold the parsed CLI flags.
+	Flags *cliflag.NamedFlagSets
+}
+
+// NewPlanOptions returns default plan options.
+func NewPlanOptions() *PlanOptions {
+	o := &PlanOptions{
+		Logs:   logs.NewOptions(),
+		Output: PlanOutputYAML,
+	}
+	o.initFlags()
+	return o
+}
+
+func (o *PlanOptions) initFlags() {
+	if o.Flags != nil {
+		return
+	}
+
+	nfs := cliflag.NamedFlagSets{}
+	fs := nfs.FlagSet("misc")
+	fs.StringVar(&o.ConfigFile, "config", o.ConfigFile, "The path to the configuration file.")
+	fs.StringVar(&o.SnapshotDir, "snapshot", o.SnapshotDir, "The directory of the YAML or JSON files holding the cluster snapshot, e.g. the output of 'kubectl get -o yaml'.")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "The path to the kubeconfig of the cluster to plan against, the cluster is only read.")
+	fs.StringVarP(&o.Output, "output", "o", o.Output, "The output format of the plan, one of yaml and json.")
+	logsapi.AddFlags(o.Logs, nfs.FlagSet("logs"))
+
+	o.Flags = &nfs
+}
+
+// Validate validates all the required options.
+func (o *PlanOptions) Validate() []error {
+	var errs []error
+	if (o.SnapshotDir == "") == (o.Kubeconfig == "") {
+		errs = append(errs, fmt.Errorf("exactly one of --snapshot and --kubeconfig must be set"))
+	}
+	if o.Output != PlanOutputYAML && o.Output != PlanOutputJSON {
+		errs = append(errs, fmt.Errorf("unsupported output format %q, must be one of %s and %s", o.Output, PlanOutputYAML, PlanOutputJSON))
+	}
+	return errs
+}
+
+// ComponentConfig loads and validates the descheduler configuration.
+func (o *PlanOptions) ComponentConfig() (*deschedulerconfig.DeschedulerConfiguration, error) {
+	if o.ConfigFile == "" {
+		return newDefaultComponentConfig()
+	}
+	cfg, err := loadConfigFromFile(o.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateDeschedulerConfiguration(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ClientSets returns the clientSets serving the snapshot, or reading the cluster of the kubeconfig. The kube config
+// is nil if the snapshot is used.
+func (o *PlanOptions) ClientSets() (clientset.Interface, koordclientset.Interface, *restclient.Config, error) {
+	if o.SnapshotDir != "" {
+		objs, err := plan.LoadSnapshot(o.SnapshotDir)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		client, koordClient, err := plan.NewSnapshotClientSets(objs)
+		return client, koordClient, nil, err
+	}
+
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", o.Kubeconfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	kubeConfig = plan.ReadOnlyConfig(restclient.AddUserAgent(kubeConfig, "koord-descheduler-plan"))
+	client, err := clientset.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	koordClient, err := koordclientset.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return client, koordClient, kubeConfig, nil
+}
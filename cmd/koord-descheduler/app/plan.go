@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	cliflag "k8s.io/component-base/cli/flag"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	"k8s.io/component-base/term"
+	"sigs.k8s.io/yaml"
+
+	"github.com/koordinator-sh/koordinator/cmd/koord-descheduler/app/options"
+	frameworkruntime "github.com/koordinator-sh/koordinator/pkg/descheduler/framework/runtime"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/plan"
+)
+
+// newPlanCommand creates the command which runs one descheduling cycle of all the profiles without side effects,
+// and prints the proposed evictions and PodMigrationJobs.
+func newPlanCommand(registryOptions ...Option) *cobra.Command {
+	opts := options.NewPlanOptions()
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Print the evictions and PodMigrationJobs proposed by one descheduling cycle without side effects",
+		Long: `Run one descheduling cycle of all the profiles against a cluster snapshot or a cluster read in read-only mode,
+and print the proposed evictions and PodMigrationJobs instead of executing them.
+
+The plugins which require consecutive abnormal observations, e.g. the anomaly condition of LowNodeLoad, should be
+configured to act on the first observation to be planned.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlanCommand(cmd.Context(), cmd.OutOrStdout(), opts, registryOptions...)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	fs := cmd.Flags()
+	for _, f := range opts.Flags.FlagSets {
+		fs.AddFlagSet(f)
+	}
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cliflag.SetUsageAndHelpFunc(cmd, *opts.Flags, cols)
+
+	return cmd
+}
+
+func runPlanCommand(ctx context.Context, out io.Writer, opts *options.PlanOptions, registryOptions ...Option) error {
+	if err := logsapi.ValidateAndApply(opts.Logs, utilfeature.DefaultFeatureGate); err != nil {
+		return err
+	}
+	if errs := opts.Validate(); len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	cfg, err := opts.ComponentConfig()
+	if err != nil {
+		return err
+	}
+	client, koordClient, kubeConfig, err := opts.ClientSets()
+	if err != nil {
+		return err
+	}
+	outOfTreeRegistry := make(frameworkruntime.Registry)
+	for _, option := range registryOptions {
+		if err := option(outOfTreeRegistry); err != nil {
+			return err
+		}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p, err := plan.Run(ctx, plan.Options{
+		Config:            cfg,
+		ClientSet:         client,
+		KoordClientSet:    koordClient,
+		KubeConfig:        kubeConfig,
+		OutOfTreeRegistry: outOfTreeRegistry,
+	})
+	if err != nil {
+		return err
+	}
+	return printPlan(out, p, opts.Output)
+}
+
+func printPlan(out io.Writer, p *plan.Plan, format string) error {
+	var data []byte
+	var err error
+	switch format {
+	case options.PlanOutputJSON:
+		data, err = json.MarshalIndent(p, "", "  ")
+		data = append(data, '\n')
+	case options.PlanOutputYAML:
+		data, err = yaml.Marshal(p)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
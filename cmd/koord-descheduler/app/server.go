@@ -96,6 +96,8 @@ func NewDeschedulerCommand(registryOptions ...Option) *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(newPlanCommand(registryOptions...))
+
 	nfs := opts.Flags
 	verflag.AddFlags(nfs.FlagSet("global"))
 	globalflag.AddGlobalFlags(nfs.FlagSet("global"), cmd.Name(), logs.SkipLoggingConfigurationFlags())
@@ -49,11 +49,16 @@ func runDeschedulerCmd() error {
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
-	err := command.ParseFlags(os.Args[1:])
+	// parse the flags of the subcommand if any, e.g. plan.
+	cmd, args, err := command.Find(os.Args[1:])
 	if err != nil {
-		// when fail to parse flags, return error with the usage message.
 		return fmt.Errorf("%v\n%s", err, command.UsageString())
 	}
+	err = cmd.ParseFlags(args)
+	if err != nil {
+		// when fail to parse flags, return error with the usage message.
+		return fmt.Errorf("%v\n%s", err, cmd.UsageString())
+	}
 
 	return command.Execute()
 }
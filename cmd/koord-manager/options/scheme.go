@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
+	analysisv1alpha1 "github.com/koordinator-sh/koordinator/apis/analysis/v1alpha1"
 	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
 	quotav1alpha1 "github.com/koordinator-sh/koordinator/apis/quota/v1alpha1"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
@@ -36,11 +37,13 @@ func init() {
 	_ = quotav1alpha1.AddToScheme(clientgoscheme.Scheme)
 	_ = slov1alpha1.AddToScheme(clientgoscheme.Scheme)
 	_ = schedulingv1alpha1.AddToScheme(clientgoscheme.Scheme)
+	_ = analysisv1alpha1.AddToScheme(clientgoscheme.Scheme)
 
 	_ = configv1alpha1.AddToScheme(Scheme)
 	_ = quotav1alpha1.AddToScheme(Scheme)
 	_ = slov1alpha1.AddToScheme(Scheme)
 	_ = schedulingv1alpha1.AddToScheme(Scheme)
+	_ = analysisv1alpha1.AddToScheme(Scheme)
 	_ = v1alpha1.AddToScheme(Scheme)
 
 	Scheme.AddUnversionedTypes(metav1.SchemeGroupVersion, &metav1.UpdateOptions{}, &metav1.DeleteOptions{}, &metav1.CreateOptions{})
@@ -72,6 +72,7 @@ import (
 	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/informer"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/networktopology"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/scheduleexplanation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/workloadauditor"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/metrics"
@@ -122,6 +123,7 @@ for cost reduction and efficiency enhancement.
 	AddSyncBarrierFlags(nfs.FlagSet("global"))
 	globalflag.AddGlobalFlags(nfs.FlagSet("global"), cmd.Name(), logs.SkipLoggingConfigurationFlags())
 	workloadauditor.AddFlags(nfs.FlagSet("extend"))
+	scheduleexplanation.AddFlags(nfs.FlagSet("extend"))
 	frameworkext.AddFlags(nfs.FlagSet("extend"))
 	fs := cmd.Flags()
 	for _, f := range nfs.FlagSets {
@@ -551,6 +553,12 @@ func Setup(ctx context.Context, opts *options.Options, outOfTreeRegistryOptions
 
 	eventhandlers.AddScheduleEventHandler(sched, schedAdapter, cc.InformerFactory, cc.KoordinatorSharedInformerFactory, crossSchedulerNominator)
 	workloadauditor.AddEventHandler(sched, workloadAuditor, cc.InformerFactory, cc.KoordinatorSharedInformerFactory)
+	if scheduleexplanation.ScheduleExplanationEnabled {
+		explanationController := scheduleexplanation.New(cc.InformerFactory, cc.KoordinatorSharedInformerFactory,
+			cc.KoordinatorClient, frameworkExtenderFactory, scheduleexplanation.ScheduleExplanationWorkers)
+		frameworkext.RegisterDiagnosisRecorder(explanationController)
+		frameworkExtenderFactory.RegisterController(scheduleexplanation.Name, explanationController)
+	}
 	reservationErrorHandler := eventhandlers.MakeReservationErrorHandler(
 		sched,
 		schedAdapter,
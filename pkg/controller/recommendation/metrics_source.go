@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+// ContainerUsageSample is a usage sample of a container at a point in time.
+type ContainerUsageSample struct {
+	// PodKey is the namespaced name of the pod
+	PodKey string
+	// ContainerName is the name of the container
+	ContainerName string
+	// Usage is the resource usage of the container, currently cpu and memory are used
+	Usage corev1.ResourceList
+	// Timestamp is the time when the usage is collected
+	Timestamp time.Time
+}
+
+// MetricsSource provides the usage samples of containers for the recommender.
+// The source can be NodeMetric, or an external metrics system such as Prometheus.
+type MetricsSource interface {
+	// GetContainerUsages returns the latest usage samples of the containers of the given pods.
+	// Pods which have no usage reported are ignored.
+	GetContainerUsages(ctx context.Context, pods []*corev1.Pod) ([]ContainerUsageSample, error)
+}
+
+var _ MetricsSource = &nodeMetricSource{}
+
+// nodeMetricSource gets the pod usages from the NodeMetric reported by koordlet.
+type nodeMetricSource struct {
+	client client.Client
+}
+
+func NewNodeMetricSource(c client.Client) MetricsSource {
+	return &nodeMetricSource{client: c}
+}
+
+func (s *nodeMetricSource) GetContainerUsages(ctx context.Context, pods []*corev1.Pod) ([]ContainerUsageSample, error) {
+	podsByNode := map[string][]*corev1.Pod{}
+	for _, pod := range pods {
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+
+	var samples []ContainerUsageSample
+	for nodeName, nodePods := range podsByNode {
+		nodeMetric := &slov1alpha1.NodeMetric{}
+		if err := s.client.Get(ctx, types.NamespacedName{Name: nodeName}, nodeMetric); err != nil {
+			if errors.IsNotFound(err) {
+				klog.V(5).InfoS("nodeMetric not found, skip collecting pod usages", "node", nodeName)
+				continue
+			}
+			return nil, err
+		}
+		if nodeMetric.Status.UpdateTime == nil {
+			continue
+		}
+		podMetrics := map[string]*slov1alpha1.PodMetricInfo{}
+		for _, podMetric := range nodeMetric.Status.PodsMetric {
+			if podMetric == nil {
+				continue
+			}
+			podMetrics[podMetric.Namespace+"/"+podMetric.Name] = podMetric
+		}
+		for _, pod := range nodePods {
+			podKey := pod.Namespace + "/" + pod.Name
+			podMetric, ok := podMetrics[podKey]
+			if !ok {
+				continue
+			}
+			for containerName, usage := range getContainerUsages(pod, podMetric) {
+				samples = append(samples, ContainerUsageSample{
+					PodKey:        podKey,
+					ContainerName: containerName,
+					Usage:         usage,
+					Timestamp:     nodeMetric.Status.UpdateTime.Time,
+				})
+			}
+		}
+	}
+	return samples, nil
+}
+
+// getContainerUsages returns the container usages reported in the pod metric if the container aggregate policy
+// is enabled, otherwise the container usages are estimated from the pod usage.
+func getContainerUsages(pod *corev1.Pod, podMetric *slov1alpha1.PodMetricInfo) map[string]corev1.ResourceList {
+	if len(podMetric.ContainersMetric) <= 0 {
+		return splitPodUsage(pod, podMetric.PodUsage.ResourceList)
+	}
+	usages := make(map[string]corev1.ResourceList, len(podMetric.ContainersMetric))
+	for _, containerMetric := range podMetric.ContainersMetric {
+		if containerMetric == nil || len(containerMetric.ContainerUsage.ResourceList) <= 0 {
+			continue
+		}
+		usages[containerMetric.Name] = containerMetric.ContainerUsage.ResourceList
+	}
+	return usages
+}
+
+// splitPodUsage apportions the pod usage to its containers by the container requests.
+// The usage is split evenly if no container has requested the resource.
+func splitPodUsage(pod *corev1.Pod, podUsage corev1.ResourceList) map[string]corev1.ResourceList {
+	containers := pod.Spec.Containers
+	if len(containers) <= 0 {
+		return nil
+	}
+	usages := make(map[string]corev1.ResourceList, len(containers))
+	for _, container := range containers {
+		usages[container.Name] = corev1.ResourceList{}
+	}
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		total, ok := podUsage[resourceName]
+		if !ok {
+			continue
+		}
+		var requestSum int64
+		for _, container := range containers {
+			q := container.Resources.Requests[resourceName]
+			requestSum += q.MilliValue()
+		}
+		for _, container := range containers {
+			var ratio float64
+			if requestSum > 0 {
+				q := container.Resources.Requests[resourceName]
+				ratio = float64(q.MilliValue()) / float64(requestSum)
+			} else {
+				ratio = 1 / float64(len(containers))
+			}
+			if resourceName == corev1.ResourceCPU {
+				usages[container.Name][resourceName] = *resource.NewMilliQuantity(int64(float64(total.MilliValue())*ratio), resource.DecimalSI)
+			} else {
+				usages[container.Name][resourceName] = *resource.NewQuantity(int64(float64(total.Value())*ratio), resource.BinarySI)
+			}
+		}
+	}
+	return usages
+}
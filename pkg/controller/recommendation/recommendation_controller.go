@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	analysisv1alpha1 "github.com/koordinator-sh/koordinator/apis/analysis/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const Name = "recommendation"
+
+const (
+	ReasonTargetResolved        = "TargetResolved"
+	ReasonTargetInvalid         = "TargetInvalid"
+	ReasonNoPodsMatched         = "NoPodsMatched"
+	ReasonMetricsUnavailable    = "MetricsUnavailable"
+	ReasonInsufficientSamples   = "InsufficientSamples"
+	ReasonRecommendationUpdated = "RecommendationUpdated"
+)
+
+var (
+	ReconcileInterval = time.Minute
+
+	CPURequestPercentile    = 0.9
+	CPULimitPercentile      = 0.99
+	MemoryRequestPercentile = 0.9
+	MemoryLimitPercentile   = 0.99
+	SafetyMarginFraction    = 0.15
+	CPUHistogramHalfLife    = 12 * time.Hour
+	MemoryHistogramHalfLife = 24 * time.Hour
+	MinSampleCount          = 10
+	MinCPUMilliCores        = int64(10)
+	MinMemoryBytes          = int64(16 << 20)
+)
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets;replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch
+// +kubebuilder:rbac:groups=analysis.koordinator.sh,resources=recommendations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=analysis.koordinator.sh,resources=recommendations/status,verbs=get;update;patch
+
+type Reconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	Scheme   *runtime.Scheme
+
+	metricsSource MetricsSource
+	recommender   *recommender
+	clock         clock.Clock
+}
+
+func newReconciler(mgr ctrl.Manager) *Reconciler {
+	return &Reconciler{
+		Client:        mgr.GetClient(),
+		Recorder:      mgr.GetEventRecorderFor(Name),
+		Scheme:        mgr.GetScheme(),
+		metricsSource: NewNodeMetricSource(mgr.GetClient()),
+		recommender:   newRecommender(),
+		clock:         clock.RealClock{},
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	recommendation := &analysisv1alpha1.Recommendation{}
+	if err := r.Client.Get(ctx, req.NamespacedName, recommendation); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.ErrorS(err, "failed to get recommendation", "recommendation", req.NamespacedName)
+			return ctrl.Result{Requeue: true}, err
+		}
+		// not found, clean up the histograms
+		r.recommender.Delete(req.NamespacedName.String())
+		return ctrl.Result{}, nil
+	}
+
+	if recommendation.DeletionTimestamp != nil { // skip for a terminating recommendation
+		r.recommender.Delete(req.NamespacedName.String())
+		return ctrl.Result{}, nil
+	}
+
+	newStatus := recommendation.Status.DeepCopy()
+	if err := r.updateRecommendation(ctx, recommendation, newStatus); err != nil {
+		klog.ErrorS(err, "failed to compute recommendation", "recommendation", req.NamespacedName)
+	}
+
+	if !equality.Semantic.DeepEqual(&recommendation.Status, newStatus) {
+		recommendation.Status = *newStatus
+		if err := r.Client.Status().Update(ctx, recommendation); err != nil {
+			klog.ErrorS(err, "failed to update recommendation status", "recommendation", req.NamespacedName)
+			return ctrl.Result{Requeue: true}, err
+		}
+		klog.V(4).InfoS("successfully update recommendation status", "recommendation", req.NamespacedName)
+	}
+
+	return ctrl.Result{RequeueAfter: ReconcileInterval}, nil
+}
+
+// updateRecommendation resolves the target, feeds the latest usages into the histograms and fills the status.
+// The returned error is only used for logging since the failure has been recorded into the conditions.
+func (r *Reconciler) updateRecommendation(ctx context.Context, recommendation *analysisv1alpha1.Recommendation,
+	status *analysisv1alpha1.RecommendationStatus) error {
+	key := client.ObjectKeyFromObject(recommendation).String()
+	generation := recommendation.Generation
+
+	pods, err := r.listPodsForTarget(ctx, recommendation)
+	if err != nil {
+		setCondition(status, analysisv1alpha1.RecommendationConditionTargetResolved, metav1.ConditionFalse,
+			ReasonTargetInvalid, err.Error(), generation)
+		return err
+	}
+	if len(pods) <= 0 {
+		setCondition(status, analysisv1alpha1.RecommendationConditionTargetResolved, metav1.ConditionFalse,
+			ReasonNoPodsMatched, "no running pods matched the target", generation)
+		return nil
+	}
+	setCondition(status, analysisv1alpha1.RecommendationConditionTargetResolved, metav1.ConditionTrue,
+		ReasonTargetResolved, "", generation)
+	klog.V(5).InfoS("list pods for recommendation", "recommendation", key, "pods", len(pods))
+
+	samples, err := r.metricsSource.GetContainerUsages(ctx, pods)
+	if err != nil {
+		setCondition(status, analysisv1alpha1.RecommendationConditionRecommendationProvided, metav1.ConditionFalse,
+			ReasonMetricsUnavailable, err.Error(), generation)
+		return err
+	}
+	added := r.recommender.AddSamples(key, samples)
+	klog.V(5).InfoS("add usage samples for recommendation", "recommendation", key,
+		"samples", len(samples), "added", added)
+
+	containerStatuses, insufficient := r.recommender.Recommend(key, getContainerNames(pods))
+	if len(containerStatuses) <= 0 {
+		setCondition(status, analysisv1alpha1.RecommendationConditionRecommendationProvided, metav1.ConditionFalse,
+			ReasonInsufficientSamples, "not enough usage samples for containers "+strings.Join(insufficient, ","), generation)
+		return nil
+	}
+
+	newPodStatus := &analysisv1alpha1.RecommendedPodStatus{ContainerStatuses: containerStatuses}
+	if !equality.Semantic.DeepEqual(status.PodStatus, newPodStatus) {
+		status.PodStatus = newPodStatus
+		status.UpdateTime = &metav1.Time{Time: r.clock.Now()}
+	}
+	message := ""
+	if len(insufficient) > 0 {
+		message = "not enough usage samples for containers " + strings.Join(insufficient, ",")
+	}
+	setCondition(status, analysisv1alpha1.RecommendationConditionRecommendationProvided, metav1.ConditionTrue,
+		ReasonRecommendationUpdated, message, generation)
+	return nil
+}
+
+func setCondition(status *analysisv1alpha1.RecommendationStatus, conditionType string, conditionStatus metav1.ConditionStatus,
+	reason, message string, generation int64) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&analysisv1alpha1.Recommendation{}).
+		Named(Name).
+		Complete(r)
+}
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.DurationVar(&ReconcileInterval, "recommendation-reconcile-interval", ReconcileInterval, "The interval to reconcile Recommendation and collect usage samples.")
+	pflag.Float64Var(&CPURequestPercentile, "recommendation-cpu-request-percentile", CPURequestPercentile, "The percentile of cpu usage to recommend as the cpu request.")
+	pflag.Float64Var(&CPULimitPercentile, "recommendation-cpu-limit-percentile", CPULimitPercentile, "The percentile of cpu usage to recommend as the cpu limit.")
+	pflag.Float64Var(&MemoryRequestPercentile, "recommendation-memory-request-percentile", MemoryRequestPercentile, "The percentile of memory usage to recommend as the memory request.")
+	pflag.Float64Var(&MemoryLimitPercentile, "recommendation-memory-limit-percentile", MemoryLimitPercentile, "The percentile of memory usage to recommend as the memory limit.")
+	pflag.Float64Var(&SafetyMarginFraction, "recommendation-safety-margin-fraction", SafetyMarginFraction, "The fraction of usage added as the safety margin to the recommended resources.")
+	pflag.DurationVar(&CPUHistogramHalfLife, "recommendation-cpu-histogram-decay-halflife", CPUHistogramHalfLife, "Half-life of the cpu usage histogram, the older the data, the lower the weight.")
+	pflag.DurationVar(&MemoryHistogramHalfLife, "recommendation-memory-histogram-decay-halflife", MemoryHistogramHalfLife, "Half-life of the memory usage histogram, the older the data, the lower the weight.")
+	pflag.IntVar(&MinSampleCount, "recommendation-min-sample-count", MinSampleCount, "The minimal number of usage samples of a container before its resources are recommended.")
+	pflag.Int64Var(&MinCPUMilliCores, "recommendation-min-cpu-millicores", MinCPUMilliCores, "The minimal cpu in milli-cores recommended for a container.")
+	pflag.Int64Var(&MinMemoryBytes, "recommendation-min-memory-bytes", MinMemoryBytes, "The minimal memory in bytes recommended for a container.")
+}
+
+func Add(mgr ctrl.Manager) error {
+	if !utilfeature.DefaultMutableFeatureGate.Enabled(features.RecommendationController) {
+		klog.InfoS("RecommendationController feature is disabled")
+		return nil
+	}
+
+	klog.InfoS("RecommendationController is enabled, add the controller")
+	reconciler := newReconciler(mgr)
+	return reconciler.SetupWithManager(mgr)
+}
+
+func getContainerNames(pods []*corev1.Pod) []string {
+	var names []string
+	seen := map[string]struct{}{}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if _, ok := seen[container.Name]; ok {
+				continue
+			}
+			seen[container.Name] = struct{}{}
+			names = append(names, container.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	analysisv1alpha1 "github.com/koordinator-sh/koordinator/apis/analysis/v1alpha1"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestReconciler_Reconcile(t *testing.T) {
+	testDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-deployment",
+			Namespace: "default",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": "test",
+				},
+			},
+		},
+	}
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app": "test",
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node",
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("3"),
+							corev1.ResourceMemory: resource.MustParse("3Gi"),
+						},
+					},
+				},
+				{
+					Name: "sidecar",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+	testNodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: slov1alpha1.NodeMetricStatus{
+			PodsMetric: []*slov1alpha1.PodMetricInfo{
+				{
+					Name:      "test-pod",
+					Namespace: "default",
+					PodUsage: slov1alpha1.ResourceMap{
+						ResourceList: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("2"),
+							corev1.ResourceMemory: resource.MustParse("2Gi"),
+						},
+					},
+				},
+			},
+		},
+	}
+	testRecommendation := &analysisv1alpha1.Recommendation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-recommendation",
+			Namespace: "default",
+		},
+		Spec: analysisv1alpha1.RecommendationSpec{
+			Target: analysisv1alpha1.RecommendationTarget{
+				Type: analysisv1alpha1.RecommendationTargetWorkload,
+				Workload: &analysisv1alpha1.CrossVersionObjectReference{
+					Kind: "Deployment",
+					Name: "test-deployment",
+				},
+			},
+		},
+	}
+
+	oldMinSampleCount := MinSampleCount
+	MinSampleCount = 3
+	defer func() {
+		MinSampleCount = oldMinSampleCount
+	}()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(getTestScheme()).
+		WithObjects(testDeployment, testPod, testNodeMetric, testRecommendation).
+		WithStatusSubresource(&analysisv1alpha1.Recommendation{}, &slov1alpha1.NodeMetric{}).Build()
+	now := time.Now()
+	fakeClock := clocktesting.NewFakeClock(now)
+	r := &Reconciler{
+		Client:        fakeClient,
+		Recorder:      record.NewFakeRecorder(1024),
+		Scheme:        getTestScheme(),
+		metricsSource: NewNodeMetricSource(fakeClient),
+		recommender:   newRecommender(),
+		clock:         fakeClock,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-recommendation"}}
+
+	for i := 0; i < MinSampleCount; i++ {
+		nodeMetric := &slov1alpha1.NodeMetric{}
+		assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "test-node"}, nodeMetric))
+		nodeMetric.Status.UpdateTime = &metav1.Time{Time: now.Add(time.Duration(i) * time.Minute)}
+		assert.NoError(t, fakeClient.Status().Update(context.TODO(), nodeMetric))
+
+		result, err := r.Reconcile(context.TODO(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: ReconcileInterval}, result)
+
+		got := &analysisv1alpha1.Recommendation{}
+		assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, got))
+		assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, analysisv1alpha1.RecommendationConditionTargetResolved))
+		if i < MinSampleCount-1 {
+			assert.False(t, meta.IsStatusConditionTrue(got.Status.Conditions, analysisv1alpha1.RecommendationConditionRecommendationProvided))
+			assert.Nil(t, got.Status.PodStatus)
+		}
+	}
+
+	got := &analysisv1alpha1.Recommendation{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, got))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, analysisv1alpha1.RecommendationConditionRecommendationProvided))
+	assert.NotNil(t, got.Status.UpdateTime)
+	assert.NotNil(t, got.Status.PodStatus)
+	assert.Len(t, got.Status.PodStatus.ContainerStatuses, 2)
+	mainStatus := got.Status.PodStatus.ContainerStatuses[0]
+	assert.Equal(t, "main", mainStatus.ContainerName)
+	// the main container uses 1.5 cores and 1.5Gi memory, recommended with the safety margin
+	mainCPU := mainStatus.Resources[corev1.ResourceCPU]
+	assert.InDelta(t, 1.5*(1+SafetyMarginFraction), float64(mainCPU.MilliValue())/1000, 0.1)
+	mainMemory := mainStatus.Resources[corev1.ResourceMemory]
+	assert.InDelta(t, 1.5*(1+SafetyMarginFraction), float64(mainMemory.Value())/(1<<30), 0.1)
+	mainCPULimit := mainStatus.Limits[corev1.ResourceCPU]
+	assert.GreaterOrEqual(t, mainCPULimit.MilliValue(), mainCPU.MilliValue())
+	assert.Equal(t, "sidecar", got.Status.PodStatus.ContainerStatuses[1].ContainerName)
+
+	// delete the recommendation
+	assert.NoError(t, fakeClient.Delete(context.TODO(), got))
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	_, ok := r.recommender.models[req.NamespacedName.String()]
+	assert.False(t, ok)
+}
+
+func TestReconciler_ReconcileInvalidTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     analysisv1alpha1.RecommendationTarget
+		objs       []client.Object
+		wantReason string
+	}{
+		{
+			name: "workload not found",
+			target: analysisv1alpha1.RecommendationTarget{
+				Type: analysisv1alpha1.RecommendationTargetWorkload,
+				Workload: &analysisv1alpha1.CrossVersionObjectReference{
+					Kind: "Deployment",
+					Name: "not-found",
+				},
+			},
+			wantReason: ReasonTargetInvalid,
+		},
+		{
+			name: "missing pod selector",
+			target: analysisv1alpha1.RecommendationTarget{
+				Type: analysisv1alpha1.RecommendationPodSelector,
+			},
+			wantReason: ReasonTargetInvalid,
+		},
+		{
+			name: "unknown kind without apiVersion",
+			target: analysisv1alpha1.RecommendationTarget{
+				Type: analysisv1alpha1.RecommendationTargetWorkload,
+				Workload: &analysisv1alpha1.CrossVersionObjectReference{
+					Kind: "Unknown",
+					Name: "test",
+				},
+			},
+			wantReason: ReasonTargetInvalid,
+		},
+		{
+			name: "no pods matched",
+			target: analysisv1alpha1.RecommendationTarget{
+				Type: analysisv1alpha1.RecommendationPodSelector,
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app": "test",
+					},
+				},
+			},
+			objs: []client.Object{
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pending-pod",
+						Namespace: "default",
+						Labels: map[string]string{
+							"app": "test",
+						},
+					},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodPending,
+					},
+				},
+			},
+			wantReason: ReasonNoPodsMatched,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recommendation := &analysisv1alpha1.Recommendation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-recommendation",
+					Namespace: "default",
+				},
+				Spec: analysisv1alpha1.RecommendationSpec{
+					Target: tt.target,
+				},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(getTestScheme()).
+				WithObjects(append(tt.objs, recommendation)...).
+				WithStatusSubresource(&analysisv1alpha1.Recommendation{}).Build()
+			r := &Reconciler{
+				Client:        fakeClient,
+				Scheme:        getTestScheme(),
+				metricsSource: NewNodeMetricSource(fakeClient),
+				recommender:   newRecommender(),
+				clock:         clocktesting.NewFakeClock(time.Now()),
+			}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-recommendation"}}
+			result, err := r.Reconcile(context.TODO(), req)
+			assert.NoError(t, err)
+			assert.Equal(t, ctrl.Result{RequeueAfter: ReconcileInterval}, result)
+
+			got := &analysisv1alpha1.Recommendation{}
+			assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, got))
+			condition := meta.FindStatusCondition(got.Status.Conditions, analysisv1alpha1.RecommendationConditionTargetResolved)
+			assert.NotNil(t, condition)
+			assert.Equal(t, metav1.ConditionFalse, condition.Status)
+			assert.Equal(t, tt.wantReason, condition.Reason)
+		})
+	}
+}
+
+func Test_splitPodUsage(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "a",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("3"),
+						},
+					},
+				},
+				{
+					Name: "b",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+	}
+	got := splitPodUsage(pod, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+	want := map[string]corev1.ResourceList{
+		"a": {
+			corev1.ResourceCPU:    *resource.NewMilliQuantity(1500, resource.DecimalSI),
+			corev1.ResourceMemory: *resource.NewQuantity(1<<30, resource.BinarySI),
+		},
+		"b": {
+			corev1.ResourceCPU:    *resource.NewMilliQuantity(500, resource.DecimalSI),
+			corev1.ResourceMemory: *resource.NewQuantity(1<<30, resource.BinarySI),
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func getTestScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = slov1alpha1.AddToScheme(s)
+	_ = analysisv1alpha1.AddToScheme(s)
+	return s
+}
+
+func Test_getContainerUsages(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "a"}, {Name: "b"}},
+		},
+	}
+	podMetric := &slov1alpha1.PodMetricInfo{
+		PodUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("2"),
+			},
+		},
+	}
+	got := getContainerUsages(pod, podMetric)
+	assert.Equal(t, map[string]corev1.ResourceList{
+		"a": {corev1.ResourceCPU: *resource.NewMilliQuantity(1000, resource.DecimalSI)},
+		"b": {corev1.ResourceCPU: *resource.NewMilliQuantity(1000, resource.DecimalSI)},
+	}, got)
+
+	podMetric.ContainersMetric = []*slov1alpha1.ContainerMetricInfo{
+		{
+			Name: "a",
+			ContainerUsage: slov1alpha1.ResourceMap{
+				ResourceList: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1500m"),
+				},
+			},
+		},
+		{
+			Name: "b",
+			ContainerUsage: slov1alpha1.ResourceMap{
+				ResourceList: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("500m"),
+				},
+			},
+		},
+	}
+	got = getContainerUsages(pod, podMetric)
+	assert.Equal(t, map[string]corev1.ResourceList{
+		"a": {corev1.ResourceCPU: resource.MustParse("1500m")},
+		"b": {corev1.ResourceCPU: resource.MustParse("500m")},
+	}, got)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	analysisv1alpha1 "github.com/koordinator-sh/koordinator/apis/analysis/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util/histogram"
+)
+
+var (
+	// minSampleWeight is the weight of every usage sample prior to including decaying factor
+	minSampleWeight = 1.0
+	// epsilon is the minimal weight kept in histograms
+	epsilon = 0.001 * minSampleWeight
+	// histogramBucketSizeGrowth is the growth ratio of the exponential histogram buckets
+	histogramBucketSizeGrowth = 0.05
+)
+
+// containerModel aggregates the usages of the containers with the same name across all pods of the target.
+type containerModel struct {
+	CPU         histogram.Histogram
+	Memory      histogram.Histogram
+	SampleCount int
+}
+
+type recommendationModel struct {
+	containers map[string]*containerModel
+	// lastSampleTimes records the timestamp of the latest sample of each pod container to avoid duplicates
+	lastSampleTimes map[string]time.Time
+}
+
+// recommender holds the usage histograms of all recommendations in memory.
+type recommender struct {
+	lock   sync.Mutex
+	models map[string]*recommendationModel
+}
+
+func newRecommender() *recommender {
+	return &recommender{
+		models: map[string]*recommendationModel{},
+	}
+}
+
+func newCPUHistogram() histogram.Histogram {
+	// cpu usages are in cores
+	options, err := histogram.NewExponentialHistogramOptions(1024, 0.001, 1.+histogramBucketSizeGrowth, epsilon)
+	if err != nil {
+		klog.Fatal("failed to create CPU HistogramOptions")
+	}
+	return histogram.NewDecayingHistogram(options, CPUHistogramHalfLife)
+}
+
+func newMemoryHistogram() histogram.Histogram {
+	// memory usages are in bytes
+	options, err := histogram.NewExponentialHistogramOptions(1<<41, 1<<20, 1.+histogramBucketSizeGrowth, epsilon)
+	if err != nil {
+		klog.Fatal("failed to create Memory HistogramOptions")
+	}
+	return histogram.NewDecayingHistogram(options, MemoryHistogramHalfLife)
+}
+
+// AddSamples adds the usage samples into the model of the recommendation, and returns the number of the samples
+// actually added. Samples not newer than the last sample of the same pod container are ignored.
+func (r *recommender) AddSamples(key string, samples []ContainerUsageSample) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	model, ok := r.models[key]
+	if !ok {
+		model = &recommendationModel{
+			containers:      map[string]*containerModel{},
+			lastSampleTimes: map[string]time.Time{},
+		}
+		r.models[key] = model
+	}
+
+	added := 0
+	currentKeys := make(map[string]struct{}, len(samples))
+	for _, sample := range samples {
+		sampleKey := sample.PodKey + "/" + sample.ContainerName
+		currentKeys[sampleKey] = struct{}{}
+		if lastTime, ok := model.lastSampleTimes[sampleKey]; ok && !sample.Timestamp.After(lastTime) {
+			continue
+		}
+		model.lastSampleTimes[sampleKey] = sample.Timestamp
+
+		container, ok := model.containers[sample.ContainerName]
+		if !ok {
+			container = &containerModel{
+				CPU:    newCPUHistogram(),
+				Memory: newMemoryHistogram(),
+			}
+			model.containers[sample.ContainerName] = container
+		}
+		if cpu, ok := sample.Usage[corev1.ResourceCPU]; ok {
+			container.CPU.AddSample(float64(cpu.MilliValue())/1000, minSampleWeight, sample.Timestamp)
+		}
+		if memory, ok := sample.Usage[corev1.ResourceMemory]; ok {
+			container.Memory.AddSample(float64(memory.Value()), minSampleWeight, sample.Timestamp)
+		}
+		container.SampleCount++
+		added++
+	}
+
+	// forget the pods which are gone
+	for sampleKey := range model.lastSampleTimes {
+		if _, ok := currentKeys[sampleKey]; !ok {
+			delete(model.lastSampleTimes, sampleKey)
+		}
+	}
+	return added
+}
+
+// Recommend computes the recommended resources of the given containers. It returns the statuses of the containers
+// having enough samples, and the names of the containers which are not recommended due to insufficient samples.
+func (r *recommender) Recommend(key string, containerNames []string) ([]analysisv1alpha1.RecommendedContainerStatus, []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var statuses []analysisv1alpha1.RecommendedContainerStatus
+	var insufficient []string
+	model := r.models[key]
+	for _, name := range containerNames {
+		var container *containerModel
+		if model != nil {
+			container = model.containers[name]
+		}
+		if container == nil || container.SampleCount < MinSampleCount {
+			insufficient = append(insufficient, name)
+			continue
+		}
+		statuses = append(statuses, analysisv1alpha1.RecommendedContainerStatus{
+			ContainerName: name,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:    cpuQuantity(container.CPU.Percentile(CPURequestPercentile)),
+				corev1.ResourceMemory: memoryQuantity(container.Memory.Percentile(MemoryRequestPercentile)),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    cpuQuantity(container.CPU.Percentile(CPULimitPercentile)),
+				corev1.ResourceMemory: memoryQuantity(container.Memory.Percentile(MemoryLimitPercentile)),
+			},
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ContainerName < statuses[j].ContainerName
+	})
+	sort.Strings(insufficient)
+	return statuses, insufficient
+}
+
+func (r *recommender) Delete(key string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.models, key)
+}
+
+func cpuQuantity(cores float64) resource.Quantity {
+	milliCores := int64(math.Ceil(cores * (1 + SafetyMarginFraction) * 1000))
+	if milliCores < MinCPUMilliCores {
+		milliCores = MinCPUMilliCores
+	}
+	return *resource.NewMilliQuantity(milliCores, resource.DecimalSI)
+}
+
+func memoryQuantity(bytes float64) resource.Quantity {
+	value := int64(math.Ceil(bytes * (1 + SafetyMarginFraction)))
+	if value < MinMemoryBytes {
+		value = MinMemoryBytes
+	}
+	return *resource.NewQuantity(value, resource.BinarySI)
+}
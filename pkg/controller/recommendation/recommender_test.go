@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestRecommender(t *testing.T) {
+	oldMinSampleCount := MinSampleCount
+	MinSampleCount = 5
+	defer func() {
+		MinSampleCount = oldMinSampleCount
+	}()
+
+	r := newRecommender()
+	now := time.Now()
+	var samples []ContainerUsageSample
+	for i := 0; i < 10; i++ {
+		samples = append(samples, ContainerUsageSample{
+			PodKey:        "default/test-pod",
+			ContainerName: "main",
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(int64(100*(i+1)), resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(int64(100<<20*(i+1)), resource.BinarySI),
+			},
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	added := r.AddSamples("default/test", samples[:2])
+	assert.Equal(t, 2, added)
+	// duplicated samples are ignored
+	added = r.AddSamples("default/test", samples[:2])
+	assert.Equal(t, 0, added)
+
+	statuses, insufficient := r.Recommend("default/test", []string{"main", "sidecar"})
+	assert.Empty(t, statuses)
+	assert.Equal(t, []string{"main", "sidecar"}, insufficient)
+
+	added = r.AddSamples("default/test", samples)
+	assert.Equal(t, 8, added)
+	statuses, insufficient = r.Recommend("default/test", []string{"main", "sidecar"})
+	assert.Equal(t, []string{"sidecar"}, insufficient)
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "main", statuses[0].ContainerName)
+	cpuRequest := statuses[0].Resources[corev1.ResourceCPU]
+	cpuLimit := statuses[0].Limits[corev1.ResourceCPU]
+	assert.InDelta(t, 1.0*(1+SafetyMarginFraction), float64(cpuRequest.MilliValue())/1000, 0.1)
+	assert.GreaterOrEqual(t, cpuLimit.MilliValue(), cpuRequest.MilliValue())
+	memoryRequest := statuses[0].Resources[corev1.ResourceMemory]
+	memoryLimit := statuses[0].Limits[corev1.ResourceMemory]
+	assert.InDelta(t, 1000*(1+SafetyMarginFraction), float64(memoryRequest.Value())/(1<<20), 100)
+	assert.GreaterOrEqual(t, memoryLimit.Value(), memoryRequest.Value())
+
+	r.Delete("default/test")
+	statuses, _ = r.Recommend("default/test", []string{"main"})
+	assert.Empty(t, statuses)
+}
+
+func TestRecommenderMinResources(t *testing.T) {
+	assert.Equal(t, MinCPUMilliCores, func() int64 { q := cpuQuantity(0); return q.MilliValue() }())
+	assert.Equal(t, MinMemoryBytes, func() int64 { q := memoryQuantity(0); return q.Value() }())
+}
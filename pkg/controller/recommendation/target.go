@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	analysisv1alpha1 "github.com/koordinator-sh/koordinator/apis/analysis/v1alpha1"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+// defaultWorkloadAPIVersions is used when the workload reference does not specify the APIVersion.
+var defaultWorkloadAPIVersions = map[string]string{
+	"Deployment":  "apps/v1",
+	"StatefulSet": "apps/v1",
+	"DaemonSet":   "apps/v1",
+	"ReplicaSet":  "apps/v1",
+	"Job":         "batch/v1",
+}
+
+// listPodsForTarget returns the running pods of the recommendation target.
+func (r *Reconciler) listPodsForTarget(ctx context.Context, recommendation *analysisv1alpha1.Recommendation) ([]*corev1.Pod, error) {
+	selector, err := r.getTargetSelector(ctx, recommendation)
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate selector %+v, err: %w", selector, err)
+	}
+	if labelSelector.Empty() {
+		return nil, fmt.Errorf("empty selector is not allowed")
+	}
+
+	podList := &corev1.PodList{}
+	if err = r.Client.List(ctx, podList, &client.ListOptions{
+		Namespace:     recommendation.Namespace,
+		LabelSelector: labelSelector,
+	}, utilclient.DisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("list pods failed for selector %+v, err: %w", selector, err)
+	}
+
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		// NOTE: Only the running pods have the usages.
+		if pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+func (r *Reconciler) getTargetSelector(ctx context.Context, recommendation *analysisv1alpha1.Recommendation) (*metav1.LabelSelector, error) {
+	target := recommendation.Spec.Target
+	switch target.Type {
+	case analysisv1alpha1.RecommendationPodSelector:
+		if target.PodSelector == nil {
+			return nil, fmt.Errorf("podSelector is required for target type %s", target.Type)
+		}
+		return target.PodSelector, nil
+	case analysisv1alpha1.RecommendationTargetWorkload:
+		if target.Workload == nil {
+			return nil, fmt.Errorf("workload is required for target type %s", target.Type)
+		}
+		return r.getWorkloadSelector(ctx, recommendation.Namespace, target.Workload)
+	default:
+		return nil, fmt.Errorf("unsupported target type %q", target.Type)
+	}
+}
+
+// getWorkloadSelector fetches the referred workload and returns its `spec.selector`, which is the common
+// convention of the workloads managing pods, e.g. Deployment, StatefulSet, and most of the custom workloads.
+func (r *Reconciler) getWorkloadSelector(ctx context.Context, namespace string,
+	ref *analysisv1alpha1.CrossVersionObjectReference) (*metav1.LabelSelector, error) {
+	apiVersion := ref.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultWorkloadAPIVersions[ref.Kind]
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || apiVersion == "" {
+		return nil, fmt.Errorf("invalid apiVersion %q for workload kind %s", apiVersion, ref.Kind)
+	}
+
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(gv.WithKind(ref.Kind))
+	if err = r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, workload); err != nil {
+		return nil, fmt.Errorf("failed to get workload %s %s/%s, err: %w", ref.Kind, namespace, ref.Name, err)
+	}
+
+	rawSelector, found, err := unstructured.NestedMap(workload.Object, "spec", "selector")
+	if err != nil || !found {
+		return nil, fmt.Errorf("workload %s %s/%s has no valid spec.selector", ref.Kind, namespace, ref.Name)
+	}
+	selector := &metav1.LabelSelector{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawSelector, selector); err != nil {
+		return nil, fmt.Errorf("failed to parse selector of workload %s %s/%s, err: %w", ref.Kind, namespace, ref.Name, err)
+	}
+	return selector, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeviceFragmentationArgs holds arguments used to configure the DeviceFragmentation plugin.
+type DeviceFragmentationArgs struct {
+	metav1.TypeMeta
+
+	Paused bool
+	DryRun bool
+
+	NodeSelector *metav1.LabelSelector
+
+	EvictableNamespaces *Namespaces
+
+	PodSelectors []DeviceFragmentationPodSelector
+
+	NodeFit bool
+
+	// DeviceTypes are the types of the devices to defragment, e.g. gpu and rdma.
+	DeviceTypes []string
+
+	// MaxPodsToEvictPerDevice is the max number of Pods migrated to free a device.
+	MaxPodsToEvictPerDevice int32
+}
+
+type DeviceFragmentationPodSelector struct {
+	Selector *metav1.LabelSelector
+}
@@ -32,6 +32,8 @@ const (
 	EvictionGateMaxMigratingPerNode       EvictionGate = "MaxMigratingPerNode"
 	EvictionGateMaxMigratingPerNamespace  EvictionGate = "MaxMigratingPerNamespace"
 	EvictionGateMaxMigratingGlobally      EvictionGate = "MaxMigratingGlobally"
+	EvictionGatePodDisruptionBudget       EvictionGate = "PodDisruptionBudget"
+	EvictionGateMigrationCooldown         EvictionGate = "MigrationCooldown"
 
 	// Policy gates (evictability / scope / safety)
 	EvictionGateExpectedReplicas  EvictionGate = "ExpectedReplicas"
@@ -97,6 +99,7 @@ type MigrationControllerArgs struct {
 	//   - MaxMigratingPerNode
 	//   - MaxMigratingPerNamespace
 	//   - MaxMigratingGlobally
+	//   - PodDisruptionBudget
 	//   - ExpectedReplicas
 	//   - PVC
 	//   - BarePods
@@ -145,6 +148,16 @@ type MigrationControllerArgs struct {
 	// Default is 5 minute
 	DefaultJobTTL metav1.Duration
 
+	// PodMigrationCooldown is the duration during which a migrated Pod is not migrated again.
+	// The Pod created by a migration and the Pod with the same name as a migrated Pod are both in the cooldown.
+	// Default is 0 and the cooldown is disabled.
+	PodMigrationCooldown metav1.Duration
+
+	// WorkloadMigrationCooldown is the duration during which the Pods of a workload are not migrated again after
+	// one of them was migrated.
+	// Default is 0 and the cooldown is disabled.
+	WorkloadMigrationCooldown metav1.Duration
+
 	// EvictQPS controls the number of evict per second
 	EvictQPS *Float64OrString
 	// EvictBurst is the maximum number of tokens
@@ -159,6 +172,77 @@ type MigrationControllerArgs struct {
 
 	// ArbitrationArgs defines the control parameters of the Arbitration Mechanism.
 	ArbitrationArgs *ArbitrationArgs
+
+	// MigrationWindows defines when the PodMigrationJobs are allowed to be executed.
+	// A window with PodSelector applies to the Pods of the matched workloads, a window with Namespaces applies to
+	// the Pods in these namespaces, and a window without both applies to the whole cluster.
+	// Only the most specific windows matching the Pod take effect. The PodMigrationJobs outside their windows
+	// are kept in the queue until a window opens.
+	// Default is empty and the PodMigrationJobs can be executed at any time.
+	MigrationWindows []MigrationWindow
+
+	// PreEvictionHooks defines the webhooks called before evicting the Pods, so that the workloads can prepare for
+	// the eviction, e.g. draining the connections or transferring the leadership.
+	// The first hook matched the Pod is called after the Reservation is scheduled, and the Pod is evicted only if
+	// the hook responds Ready.
+	PreEvictionHooks []PreEvictionHook
+
+	// GangMigrationPolicy defines how to migrate the members of the coscheduling gangs, Ignore, Skip or Atomic.
+	// Default is Ignore.
+	GangMigrationPolicy GangMigrationPolicy
+}
+
+type GangMigrationPolicy string
+
+const (
+	// GangMigrationPolicyIgnore migrates the gang members individually like the other Pods.
+	GangMigrationPolicyIgnore GangMigrationPolicy = "Ignore"
+	// GangMigrationPolicySkip never migrates the gang members, so that the gangs are preserved.
+	GangMigrationPolicySkip GangMigrationPolicy = "Skip"
+	// GangMigrationPolicyAtomic migrates all the members of the gang together when any of them is evicted.
+	// The members are migrated in ReservationFirst mode, and none of them is evicted until the Reservations
+	// of all the members are scheduled.
+	GangMigrationPolicyAtomic GangMigrationPolicy = "Atomic"
+)
+
+type PreEvictionHookFailurePolicy string
+
+const (
+	// PreEvictionHookFailurePolicyFail retries the hook until the PodMigrationJob times out.
+	PreEvictionHookFailurePolicyFail PreEvictionHookFailurePolicy = "Fail"
+	// PreEvictionHookFailurePolicyIgnore evicts the Pod as if the hook responded Ready.
+	PreEvictionHookFailurePolicyIgnore PreEvictionHookFailurePolicy = "Ignore"
+)
+
+// PreEvictionHook is a webhook called before evicting the Pods matched the PodSelector.
+type PreEvictionHook struct {
+	// Name is the unique name of the hook.
+	Name string
+	// PodSelector selects the Pods which the hook applies to.
+	PodSelector *metav1.LabelSelector
+	// URL is the HTTP(S) address which the PreEvictionRequest is posted to.
+	URL string
+	// Timeout is the timeout of each call to the hook.
+	// Default is 10 seconds.
+	Timeout metav1.Duration
+	// FailurePolicy defines how to handle the failure of calling the hook, Fail or Ignore.
+	// Default is Fail.
+	FailurePolicy PreEvictionHookFailurePolicy
+}
+
+// MigrationWindow is a recurring time window in which the migrations are allowed.
+type MigrationWindow struct {
+	// Schedule is the cron expression in the standard 5-field format which indicates when the window opens.
+	Schedule string
+	// Duration indicates how long the window keeps open.
+	Duration metav1.Duration
+	// TimeZone is the IANA time zone name used to interpret the Schedule, e.g. Asia/Shanghai.
+	// Default is the local time zone of koord-descheduler.
+	TimeZone string
+	// Namespaces limits the window to the Pods in these namespaces.
+	Namespaces []string
+	// PodSelector limits the window to the Pods of the matched workloads.
+	PodSelector *metav1.LabelSelector
 }
 
 type MigrationLimitObjectType string
@@ -191,4 +275,19 @@ type ArbitrationArgs struct {
 	// Interval defines the running interval (ms) of the Arbitration Mechanism.
 	// Default is 500 ms
 	Interval *metav1.Duration
+
+	// CostPlugins defines the plugins and their weights to score the migration cost of the Pods.
+	// The weighted cost is compared by the pod sorter after the priorities, QoS classes and eviction costs and before
+	// the creation time of the Pods, so that the Pods cheaper to migrate are migrated first.
+	// The built-in plugins are RestartCost, WorkloadReplicas, PodAge, LocalPV and RecentMigrations.
+	// Default is empty and the migration cost is not considered.
+	CostPlugins []MigrationCostPlugin
+}
+
+// MigrationCostPlugin is a plugin scoring the migration cost with its weight.
+type MigrationCostPlugin struct {
+	// Name is the name of the plugin.
+	Name string
+	// Weight is the weight of the plugin in the migration cost.
+	Weight int32
 }
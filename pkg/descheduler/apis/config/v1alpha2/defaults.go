@@ -46,7 +46,13 @@ const (
 	defaultMigrationEvictBurst         = 1
 	defaultSchedulerSupportReservation = "koord-scheduler"
 	defaultArbitrationInterval         = 500 * time.Millisecond
+	defaultPreEvictionHookTimeout      = 10 * time.Second
 	defaultDetectorCacheTimeout        = 5 * time.Minute
+
+	defaultNUMAAwareMaxPodCPUs            = 4
+	defaultNUMAAwareMaxPodsToEvictPerNode = 4
+
+	defaultDeviceFragmentationMaxPodsToEvictPerDevice = 4
 )
 
 var (
@@ -256,6 +262,18 @@ func SetDefaults_MigrationControllerArgs(obj *MigrationControllerArgs) {
 	if obj.ArbitrationArgs.Interval == nil {
 		obj.ArbitrationArgs.Interval = &metav1.Duration{Duration: defaultArbitrationInterval}
 	}
+	for i := range obj.PreEvictionHooks {
+		hook := &obj.PreEvictionHooks[i]
+		if hook.Timeout == nil {
+			hook.Timeout = &metav1.Duration{Duration: defaultPreEvictionHookTimeout}
+		}
+		if hook.FailurePolicy == "" {
+			hook.FailurePolicy = config.PreEvictionHookFailurePolicyFail
+		}
+	}
+	if obj.GangMigrationPolicy == "" {
+		obj.GangMigrationPolicy = config.GangMigrationPolicyIgnore
+	}
 }
 
 func SetDefaults_LowNodeLoadArgs(obj *LowNodeLoadArgs) {
@@ -382,3 +400,39 @@ func SetDefaults_ScaleDownBinPackArgs(obj *ScaleDownBinPackArgs) {
 		}
 	}
 }
+
+func SetDefaults_NUMAAwareArgs(obj *NUMAAwareArgs) {
+	if obj.Paused == nil {
+		obj.Paused = ptr.To[bool](false)
+	}
+	if obj.DryRun == nil {
+		obj.DryRun = ptr.To[bool](false)
+	}
+	if obj.NodeFit == nil {
+		obj.NodeFit = ptr.To[bool](true)
+	}
+	if obj.MaxPodCPUs == nil {
+		obj.MaxPodCPUs = ptr.To[int32](defaultNUMAAwareMaxPodCPUs)
+	}
+	if obj.MaxPodsToEvictPerNode == nil {
+		obj.MaxPodsToEvictPerNode = ptr.To[int32](defaultNUMAAwareMaxPodsToEvictPerNode)
+	}
+}
+
+func SetDefaults_DeviceFragmentationArgs(obj *DeviceFragmentationArgs) {
+	if obj.Paused == nil {
+		obj.Paused = ptr.To[bool](false)
+	}
+	if obj.DryRun == nil {
+		obj.DryRun = ptr.To[bool](false)
+	}
+	if obj.NodeFit == nil {
+		obj.NodeFit = ptr.To[bool](true)
+	}
+	if len(obj.DeviceTypes) == 0 {
+		obj.DeviceTypes = []string{string(sev1alpha1.GPU)}
+	}
+	if obj.MaxPodsToEvictPerDevice == nil {
+		obj.MaxPodsToEvictPerDevice = ptr.To[int32](defaultDeviceFragmentationMaxPodsToEvictPerDevice)
+	}
+}
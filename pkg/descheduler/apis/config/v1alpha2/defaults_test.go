@@ -24,6 +24,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 )
 
 func TestSetDefaults_LowNodeLoadArgs(t *testing.T) {
@@ -455,6 +457,124 @@ func TestSetDefaults_FragmentationAwareArgs(t *testing.T) {
 	}
 }
 
+func TestSetDefaults_NUMAAwareArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     *NUMAAwareArgs
+		expected *NUMAAwareArgs
+	}{
+		{
+			name: "default values",
+			args: &NUMAAwareArgs{},
+			expected: &NUMAAwareArgs{
+				Paused:                ptr.To[bool](false),
+				DryRun:                ptr.To[bool](false),
+				NodeFit:               ptr.To[bool](true),
+				MaxPodCPUs:            ptr.To[int32](4),
+				MaxPodsToEvictPerNode: ptr.To[int32](4),
+			},
+		},
+		{
+			name: "override defaults",
+			args: &NUMAAwareArgs{
+				Paused:                ptr.To[bool](true),
+				DryRun:                ptr.To[bool](true),
+				NodeFit:               ptr.To[bool](false),
+				MaxPodCPUs:            ptr.To[int32](8),
+				MaxPodsToEvictPerNode: ptr.To[int32](2),
+			},
+			expected: &NUMAAwareArgs{
+				Paused:                ptr.To[bool](true),
+				DryRun:                ptr.To[bool](true),
+				NodeFit:               ptr.To[bool](false),
+				MaxPodCPUs:            ptr.To[int32](8),
+				MaxPodsToEvictPerNode: ptr.To[int32](2),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDefaults_NUMAAwareArgs(tt.args)
+			assert.Equal(t, tt.expected, tt.args)
+		})
+	}
+}
+
+func TestSetDefaults_DeviceFragmentationArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     *DeviceFragmentationArgs
+		expected *DeviceFragmentationArgs
+	}{
+		{
+			name: "default values",
+			args: &DeviceFragmentationArgs{},
+			expected: &DeviceFragmentationArgs{
+				Paused:                  ptr.To[bool](false),
+				DryRun:                  ptr.To[bool](false),
+				NodeFit:                 ptr.To[bool](true),
+				DeviceTypes:             []string{"gpu"},
+				MaxPodsToEvictPerDevice: ptr.To[int32](4),
+			},
+		},
+		{
+			name: "override defaults",
+			args: &DeviceFragmentationArgs{
+				Paused:                  ptr.To[bool](true),
+				DryRun:                  ptr.To[bool](true),
+				NodeFit:                 ptr.To[bool](false),
+				DeviceTypes:             []string{"gpu", "rdma"},
+				MaxPodsToEvictPerDevice: ptr.To[int32](2),
+			},
+			expected: &DeviceFragmentationArgs{
+				Paused:                  ptr.To[bool](true),
+				DryRun:                  ptr.To[bool](true),
+				NodeFit:                 ptr.To[bool](false),
+				DeviceTypes:             []string{"gpu", "rdma"},
+				MaxPodsToEvictPerDevice: ptr.To[int32](2),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDefaults_DeviceFragmentationArgs(tt.args)
+			assert.Equal(t, tt.expected, tt.args)
+		})
+	}
+}
+
+func TestSetDefaults_MigrationControllerArgs_PreEvictionHooks(t *testing.T) {
+	args := &MigrationControllerArgs{
+		PreEvictionHooks: []PreEvictionHook{
+			{
+				Name: "default",
+				URL:  "http://127.0.0.1:8080/pre-evict",
+			},
+			{
+				Name:          "override",
+				URL:           "http://127.0.0.1:8080/pre-evict",
+				Timeout:       &metav1.Duration{Duration: time.Minute},
+				FailurePolicy: config.PreEvictionHookFailurePolicyIgnore,
+			},
+		},
+	}
+	SetDefaults_MigrationControllerArgs(args)
+	assert.Equal(t, &metav1.Duration{Duration: 10 * time.Second}, args.PreEvictionHooks[0].Timeout)
+	assert.Equal(t, config.PreEvictionHookFailurePolicyFail, args.PreEvictionHooks[0].FailurePolicy)
+	assert.Equal(t, &metav1.Duration{Duration: time.Minute}, args.PreEvictionHooks[1].Timeout)
+	assert.Equal(t, config.PreEvictionHookFailurePolicyIgnore, args.PreEvictionHooks[1].FailurePolicy)
+}
+
+func TestSetDefaults_MigrationControllerArgs_GangMigrationPolicy(t *testing.T) {
+	args := &MigrationControllerArgs{}
+	SetDefaults_MigrationControllerArgs(args)
+	assert.Equal(t, config.GangMigrationPolicyIgnore, args.GangMigrationPolicy)
+
+	args = &MigrationControllerArgs{GangMigrationPolicy: config.GangMigrationPolicyAtomic}
+	SetDefaults_MigrationControllerArgs(args)
+	assert.Equal(t, config.GangMigrationPolicyAtomic, args.GangMigrationPolicy)
+}
+
 func TestSetDefaults_ScaleDownBinPackArgs(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -59,6 +59,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CustomPriorityArgs{},
 		&FragmentationAwareArgs{},
 		&ScaleDownBinPackArgs{},
+		&NUMAAwareArgs{},
+		&DeviceFragmentationArgs{},
 	)
 
 	return nil
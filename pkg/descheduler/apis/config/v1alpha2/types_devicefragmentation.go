@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeviceFragmentationArgs holds arguments used to configure the DeviceFragmentation plugin.
+type DeviceFragmentationArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Paused indicates whether the DeviceFragmentation plugin is enabled.
+	// Default is false.
+	Paused *bool `json:"paused,omitempty"`
+
+	// DryRun executes the descheduling logic without evicting Pods.
+	// Default is false.
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// NodeSelector selects the nodes that match the labelSelector.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// EvictableNamespaces limits the namespaces of pods that can be evicted.
+	EvictableNamespaces *Namespaces `json:"evictableNamespaces,omitempty"`
+
+	// PodSelectors selects the pods that match the labelSelector.
+	PodSelectors []DeviceFragmentationPodSelector `json:"podSelectors,omitempty"`
+
+	// NodeFit enables checking whether a candidate Pod can fit on at least one
+	// other node before eviction.
+	// Default is true.
+	NodeFit *bool `json:"nodeFit,omitempty"`
+
+	// DeviceTypes are the types of the devices to defragment, gpu and rdma are supported.
+	// Only the Pods sharing a single device of the types are migrated.
+	// Default is [gpu].
+	DeviceTypes []string `json:"deviceTypes,omitempty"`
+
+	// MaxPodsToEvictPerDevice is the max number of Pods migrated to free a device.
+	// The device which needs more migrations is skipped.
+	// Default is 4.
+	MaxPodsToEvictPerDevice *int32 `json:"maxPodsToEvictPerDevice,omitempty"`
+}
+
+type DeviceFragmentationPodSelector struct {
+	// Selector is a label query over pods for migration.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
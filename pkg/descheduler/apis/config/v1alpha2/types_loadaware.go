@@ -91,6 +91,29 @@ type LowNodeLoadArgs struct {
 
 	// NodePools supports multiple different types of batch nodes to configure different strategies
 	NodePools []LowNodeLoadNodePool `json:"nodePools,omitempty"`
+
+	// Planning simulates the whole batch of migrations before evicting any Pod
+	Planning *LowNodeLoadPlanning `json:"planning,omitempty"`
+
+	// DestinationCooldown is the duration during which the nodes that received migrated Pods are not selected
+	// as the source nodes, so that the Pods are not moved back and forth.
+	// Default is 0 and the cooldown is disabled.
+	DestinationCooldown *metav1.Duration `json:"destinationCooldown,omitempty"`
+}
+
+// LowNodeLoadPlanning configures the what-if planning of LowNodeLoad.
+// When enabled, the victims and their destination nodes are picked on an in-memory copy of the node usage, where the
+// resources reserved by the available Reservations on the destination nodes are also counted as used. The plan is
+// reported by the logs and the events, and the Pods are evicted only if the plan brings all the source nodes under
+// the high thresholds. If DryRun is set, the plan is reported only.
+type LowNodeLoadPlanning struct {
+	// Enabled indicates whether to plan the migrations before evicting.
+	// Default is false
+	Enabled *bool `json:"enabled,omitempty"`
+	// MaxMoves limits the number of Pods moved in a plan of one node pool, and the plan is rejected if the source
+	// nodes are still overutilized after the max moves. Zero means no limit.
+	// Default is 0
+	MaxMoves *int32 `json:"maxMoves,omitempty"`
 }
 
 type LowNodeLoadNodePool struct {
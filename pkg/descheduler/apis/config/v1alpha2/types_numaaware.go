@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NUMAAwareArgs holds arguments used to configure the NUMAAware plugin.
+type NUMAAwareArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Paused indicates whether the NUMAAware plugin is enabled.
+	// Default is false.
+	Paused *bool `json:"paused,omitempty"`
+
+	// DryRun executes the descheduling logic without evicting Pods.
+	// Default is false.
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// NodeSelector selects the nodes that match the labelSelector.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// EvictableNamespaces limits the namespaces of pods that can be evicted.
+	EvictableNamespaces *Namespaces `json:"evictableNamespaces,omitempty"`
+
+	// PodSelectors selects the pods that match the labelSelector.
+	PodSelectors []NUMAAwarePodSelector `json:"podSelectors,omitempty"`
+
+	// NodeFit enables checking whether a candidate Pod can fit on at least one
+	// other node before eviction.
+	// Default is true.
+	NodeFit *bool `json:"nodeFit,omitempty"`
+
+	// MaxPodCPUs is the max number of CPUs allocated to a Pod that can be migrated.
+	// Only the small Pods bound to a single NUMA node are migrated to free the NUMA node.
+	// Default is 4.
+	MaxPodCPUs *int32 `json:"maxPodCPUs,omitempty"`
+
+	// MaxPodsToEvictPerNode is the max number of Pods migrated to free a NUMA node.
+	// The NUMA node which needs more migrations is skipped.
+	// Default is 4.
+	MaxPodsToEvictPerNode *int32 `json:"maxPodsToEvictPerNode,omitempty"`
+}
+
+type NUMAAwarePodSelector struct {
+	// Selector is a label query over pods for migration.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
@@ -108,6 +108,16 @@ type MigrationControllerArgs struct {
 	// Default is 5 minute
 	DefaultJobTTL *metav1.Duration `json:"defaultJobTTL,omitempty"`
 
+	// PodMigrationCooldown is the duration during which a migrated Pod is not migrated again.
+	// The Pod created by a migration and the Pod with the same name as a migrated Pod are both in the cooldown.
+	// Default is 0 and the cooldown is disabled.
+	PodMigrationCooldown *metav1.Duration `json:"podMigrationCooldown,omitempty"`
+
+	// WorkloadMigrationCooldown is the duration during which the Pods of a workload are not migrated again after
+	// one of them was migrated.
+	// Default is 0 and the cooldown is disabled.
+	WorkloadMigrationCooldown *metav1.Duration `json:"workloadMigrationCooldown,omitempty"`
+
 	// SchedulerNames defines options to assign schedulers that can handle reservation if pmj.mode is ReservationFirst, koord-scheduler by default.
 	SchedulerNames []string `json:"schedulerNames,omitempty"`
 
@@ -122,6 +132,60 @@ type MigrationControllerArgs struct {
 
 	// ArbitrationArgs defines the control parameters of the Arbitration Mechanism.
 	ArbitrationArgs *ArbitrationArgs `json:"arbitrationArgs,omitempty"`
+
+	// MigrationWindows defines when the PodMigrationJobs are allowed to be executed.
+	// A window with PodSelector applies to the Pods of the matched workloads, a window with Namespaces applies to
+	// the Pods in these namespaces, and a window without both applies to the whole cluster.
+	// Only the most specific windows matching the Pod take effect. The PodMigrationJobs outside their windows
+	// are kept in the queue until a window opens.
+	// Default is empty and the PodMigrationJobs can be executed at any time.
+	MigrationWindows []MigrationWindow `json:"migrationWindows,omitempty"`
+
+	// PreEvictionHooks defines the webhooks called before evicting the Pods, so that the workloads can prepare for
+	// the eviction, e.g. draining the connections or transferring the leadership.
+	// The first hook matched the Pod is called after the Reservation is scheduled, and the Pod is evicted only if
+	// the hook responds Ready.
+	PreEvictionHooks []PreEvictionHook `json:"preEvictionHooks,omitempty"`
+
+	// GangMigrationPolicy defines how to migrate the members of the coscheduling gangs.
+	// Ignore migrates the gang members individually like the other Pods.
+	// Skip never migrates the gang members, so that the gangs are preserved.
+	// Atomic migrates all the members of the gang together when any of them is evicted, and none of them is
+	// evicted until the Reservations of all the members are scheduled.
+	// Default is Ignore.
+	GangMigrationPolicy config.GangMigrationPolicy `json:"gangMigrationPolicy,omitempty"`
+}
+
+// PreEvictionHook is a webhook called before evicting the Pods matched the PodSelector.
+type PreEvictionHook struct {
+	// Name is the unique name of the hook.
+	Name string `json:"name"`
+	// PodSelector selects the Pods which the hook applies to.
+	PodSelector *metav1.LabelSelector `json:"podSelector"`
+	// URL is the HTTP(S) address which the PreEvictionRequest is posted to.
+	URL string `json:"url"`
+	// Timeout is the timeout of each call to the hook.
+	// Default is 10 seconds.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// FailurePolicy defines how to handle the failure of calling the hook, Fail or Ignore.
+	// Fail retries the hook until the PodMigrationJob times out, and Ignore evicts the Pod as if the hook responded Ready.
+	// Default is Fail.
+	FailurePolicy config.PreEvictionHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// MigrationWindow is a recurring time window in which the migrations are allowed.
+type MigrationWindow struct {
+	// Schedule is the cron expression in the standard 5-field format which indicates when the window opens.
+	Schedule string `json:"schedule"`
+	// Duration indicates how long the window keeps open.
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone is the IANA time zone name used to interpret the Schedule, e.g. Asia/Shanghai.
+	// Default is the local time zone of koord-descheduler.
+	TimeZone string `json:"timeZone,omitempty"`
+	// Namespaces limits the window to the Pods in these namespaces.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// PodSelector limits the window to the Pods of the matched workloads.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
 }
 
 type MigrationLimitObjectType string
@@ -153,4 +217,19 @@ type ArbitrationArgs struct {
 	// Interval defines the running interval (ms) of the Arbitration Mechanism.
 	// Default is 500 ms
 	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// CostPlugins defines the plugins and their weights to score the migration cost of the Pods.
+	// The weighted cost is compared by the pod sorter after the priorities, QoS classes and eviction costs and before
+	// the creation time of the Pods, so that the Pods cheaper to migrate are migrated first.
+	// The built-in plugins are RestartCost, WorkloadReplicas, PodAge, LocalPV and RecentMigrations.
+	// Default is empty and the migration cost is not considered.
+	CostPlugins []MigrationCostPlugin `json:"costPlugins,omitempty"`
+}
+
+// MigrationCostPlugin is a plugin scoring the migration cost with its weight.
+type MigrationCostPlugin struct {
+	// Name is the name of the plugin.
+	Name string `json:"name"`
+	// Weight is the weight of the plugin in the migration cost.
+	Weight int32 `json:"weight"`
 }
@@ -75,6 +75,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DeviceFragmentationArgs)(nil), (*config.DeviceFragmentationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DeviceFragmentationArgs_To_config_DeviceFragmentationArgs(a.(*DeviceFragmentationArgs), b.(*config.DeviceFragmentationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DeviceFragmentationArgs)(nil), (*DeviceFragmentationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DeviceFragmentationArgs_To_v1alpha2_DeviceFragmentationArgs(a.(*config.DeviceFragmentationArgs), b.(*DeviceFragmentationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeviceFragmentationPodSelector)(nil), (*config.DeviceFragmentationPodSelector)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DeviceFragmentationPodSelector_To_config_DeviceFragmentationPodSelector(a.(*DeviceFragmentationPodSelector), b.(*config.DeviceFragmentationPodSelector), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DeviceFragmentationPodSelector)(nil), (*DeviceFragmentationPodSelector)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DeviceFragmentationPodSelector_To_v1alpha2_DeviceFragmentationPodSelector(a.(*config.DeviceFragmentationPodSelector), b.(*DeviceFragmentationPodSelector), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*FragmentationAwareArgs)(nil), (*config.FragmentationAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_FragmentationAwareArgs_To_config_FragmentationAwareArgs(a.(*FragmentationAwareArgs), b.(*config.FragmentationAwareArgs), scope)
 	}); err != nil {
@@ -120,6 +140,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LowNodeLoadPlanning)(nil), (*config.LowNodeLoadPlanning)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LowNodeLoadPlanning_To_config_LowNodeLoadPlanning(a.(*LowNodeLoadPlanning), b.(*config.LowNodeLoadPlanning), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LowNodeLoadPlanning)(nil), (*LowNodeLoadPlanning)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LowNodeLoadPlanning_To_v1alpha2_LowNodeLoadPlanning(a.(*config.LowNodeLoadPlanning), b.(*LowNodeLoadPlanning), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LowNodeLoadPodSelector)(nil), (*config.LowNodeLoadPodSelector)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_LowNodeLoadPodSelector_To_config_LowNodeLoadPodSelector(a.(*LowNodeLoadPodSelector), b.(*config.LowNodeLoadPodSelector), scope)
 	}); err != nil {
@@ -140,6 +170,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MigrationCostPlugin)(nil), (*config.MigrationCostPlugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MigrationCostPlugin_To_config_MigrationCostPlugin(a.(*MigrationCostPlugin), b.(*config.MigrationCostPlugin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MigrationCostPlugin)(nil), (*MigrationCostPlugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MigrationCostPlugin_To_v1alpha2_MigrationCostPlugin(a.(*config.MigrationCostPlugin), b.(*MigrationCostPlugin), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MigrationObjectLimiter)(nil), (*config.MigrationObjectLimiter)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_MigrationObjectLimiter_To_config_MigrationObjectLimiter(a.(*MigrationObjectLimiter), b.(*config.MigrationObjectLimiter), scope)
 	}); err != nil {
@@ -150,6 +190,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MigrationWindow)(nil), (*config.MigrationWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MigrationWindow_To_config_MigrationWindow(a.(*MigrationWindow), b.(*config.MigrationWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MigrationWindow)(nil), (*MigrationWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MigrationWindow_To_v1alpha2_MigrationWindow(a.(*config.MigrationWindow), b.(*MigrationWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NUMAAwareArgs)(nil), (*config.NUMAAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NUMAAwareArgs_To_config_NUMAAwareArgs(a.(*NUMAAwareArgs), b.(*config.NUMAAwareArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NUMAAwareArgs)(nil), (*NUMAAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NUMAAwareArgs_To_v1alpha2_NUMAAwareArgs(a.(*config.NUMAAwareArgs), b.(*NUMAAwareArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NUMAAwarePodSelector)(nil), (*config.NUMAAwarePodSelector)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NUMAAwarePodSelector_To_config_NUMAAwarePodSelector(a.(*NUMAAwarePodSelector), b.(*config.NUMAAwarePodSelector), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NUMAAwarePodSelector)(nil), (*NUMAAwarePodSelector)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NUMAAwarePodSelector_To_v1alpha2_NUMAAwarePodSelector(a.(*config.NUMAAwarePodSelector), b.(*NUMAAwarePodSelector), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Namespaces)(nil), (*config.Namespaces)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_Namespaces_To_config_Namespaces(a.(*Namespaces), b.(*config.Namespaces), scope)
 	}); err != nil {
@@ -200,6 +270,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PreEvictionHook)(nil), (*config.PreEvictionHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PreEvictionHook_To_config_PreEvictionHook(a.(*PreEvictionHook), b.(*config.PreEvictionHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PreEvictionHook)(nil), (*PreEvictionHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PreEvictionHook_To_v1alpha2_PreEvictionHook(a.(*config.PreEvictionHook), b.(*PreEvictionHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PriorityThreshold)(nil), (*config.PriorityThreshold)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_PriorityThreshold_To_config_PriorityThreshold(a.(*PriorityThreshold), b.(*config.PriorityThreshold), scope)
 	}); err != nil {
@@ -266,6 +346,7 @@ func RegisterConversions(s *runtime.Scheme) error {
 func autoConvert_v1alpha2_ArbitrationArgs_To_config_ArbitrationArgs(in *ArbitrationArgs, out *config.ArbitrationArgs, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Interval = (*v1.Duration)(unsafe.Pointer(in.Interval))
+	out.CostPlugins = *(*[]config.MigrationCostPlugin)(unsafe.Pointer(&in.CostPlugins))
 	return nil
 }
 
@@ -277,6 +358,7 @@ func Convert_v1alpha2_ArbitrationArgs_To_config_ArbitrationArgs(in *ArbitrationA
 func autoConvert_config_ArbitrationArgs_To_v1alpha2_ArbitrationArgs(in *config.ArbitrationArgs, out *ArbitrationArgs, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Interval = (*v1.Duration)(unsafe.Pointer(in.Interval))
+	out.CostPlugins = *(*[]MigrationCostPlugin)(unsafe.Pointer(&in.CostPlugins))
 	return nil
 }
 
@@ -472,6 +554,76 @@ func Convert_config_DeschedulerProfile_To_v1alpha2_DeschedulerProfile(in *config
 	return autoConvert_config_DeschedulerProfile_To_v1alpha2_DeschedulerProfile(in, out, s)
 }
 
+func autoConvert_v1alpha2_DeviceFragmentationArgs_To_config_DeviceFragmentationArgs(in *DeviceFragmentationArgs, out *config.DeviceFragmentationArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.Paused, &out.Paused, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.DryRun, &out.DryRun, s); err != nil {
+		return err
+	}
+	out.NodeSelector = (*v1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.EvictableNamespaces = (*config.Namespaces)(unsafe.Pointer(in.EvictableNamespaces))
+	out.PodSelectors = *(*[]config.DeviceFragmentationPodSelector)(unsafe.Pointer(&in.PodSelectors))
+	if err := v1.Convert_Pointer_bool_To_bool(&in.NodeFit, &out.NodeFit, s); err != nil {
+		return err
+	}
+	out.DeviceTypes = *(*[]string)(unsafe.Pointer(&in.DeviceTypes))
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxPodsToEvictPerDevice, &out.MaxPodsToEvictPerDevice, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha2_DeviceFragmentationArgs_To_config_DeviceFragmentationArgs is an autogenerated conversion function.
+func Convert_v1alpha2_DeviceFragmentationArgs_To_config_DeviceFragmentationArgs(in *DeviceFragmentationArgs, out *config.DeviceFragmentationArgs, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DeviceFragmentationArgs_To_config_DeviceFragmentationArgs(in, out, s)
+}
+
+func autoConvert_config_DeviceFragmentationArgs_To_v1alpha2_DeviceFragmentationArgs(in *config.DeviceFragmentationArgs, out *DeviceFragmentationArgs, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.Paused, &out.Paused, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.DryRun, &out.DryRun, s); err != nil {
+		return err
+	}
+	out.NodeSelector = (*v1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.EvictableNamespaces = (*Namespaces)(unsafe.Pointer(in.EvictableNamespaces))
+	out.PodSelectors = *(*[]DeviceFragmentationPodSelector)(unsafe.Pointer(&in.PodSelectors))
+	if err := v1.Convert_bool_To_Pointer_bool(&in.NodeFit, &out.NodeFit, s); err != nil {
+		return err
+	}
+	out.DeviceTypes = *(*[]string)(unsafe.Pointer(&in.DeviceTypes))
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxPodsToEvictPerDevice, &out.MaxPodsToEvictPerDevice, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_DeviceFragmentationArgs_To_v1alpha2_DeviceFragmentationArgs is an autogenerated conversion function.
+func Convert_config_DeviceFragmentationArgs_To_v1alpha2_DeviceFragmentationArgs(in *config.DeviceFragmentationArgs, out *DeviceFragmentationArgs, s conversion.Scope) error {
+	return autoConvert_config_DeviceFragmentationArgs_To_v1alpha2_DeviceFragmentationArgs(in, out, s)
+}
+
+func autoConvert_v1alpha2_DeviceFragmentationPodSelector_To_config_DeviceFragmentationPodSelector(in *DeviceFragmentationPodSelector, out *config.DeviceFragmentationPodSelector, s conversion.Scope) error {
+	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
+	return nil
+}
+
+// Convert_v1alpha2_DeviceFragmentationPodSelector_To_config_DeviceFragmentationPodSelector is an autogenerated conversion function.
+func Convert_v1alpha2_DeviceFragmentationPodSelector_To_config_DeviceFragmentationPodSelector(in *DeviceFragmentationPodSelector, out *config.DeviceFragmentationPodSelector, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DeviceFragmentationPodSelector_To_config_DeviceFragmentationPodSelector(in, out, s)
+}
+
+func autoConvert_config_DeviceFragmentationPodSelector_To_v1alpha2_DeviceFragmentationPodSelector(in *config.DeviceFragmentationPodSelector, out *DeviceFragmentationPodSelector, s conversion.Scope) error {
+	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
+	return nil
+}
+
+// Convert_config_DeviceFragmentationPodSelector_To_v1alpha2_DeviceFragmentationPodSelector is an autogenerated conversion function.
+func Convert_config_DeviceFragmentationPodSelector_To_v1alpha2_DeviceFragmentationPodSelector(in *config.DeviceFragmentationPodSelector, out *DeviceFragmentationPodSelector, s conversion.Scope) error {
+	return autoConvert_config_DeviceFragmentationPodSelector_To_v1alpha2_DeviceFragmentationPodSelector(in, out, s)
+}
+
 func autoConvert_v1alpha2_FragmentationAwareArgs_To_config_FragmentationAwareArgs(in *FragmentationAwareArgs, out *config.FragmentationAwareArgs, s conversion.Scope) error {
 	if err := v1.Convert_Pointer_bool_To_bool(&in.Paused, &out.Paused, s); err != nil {
 		return err
@@ -622,6 +774,16 @@ func autoConvert_v1alpha2_LowNodeLoadArgs_To_config_LowNodeLoadArgs(in *LowNodeL
 	} else {
 		out.NodePools = nil
 	}
+	if in.Planning != nil {
+		in, out := &in.Planning, &out.Planning
+		*out = new(config.LowNodeLoadPlanning)
+		if err := Convert_v1alpha2_LowNodeLoadPlanning_To_config_LowNodeLoadPlanning(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Planning = nil
+	}
+	out.DestinationCooldown = (*v1.Duration)(unsafe.Pointer(in.DestinationCooldown))
 	return nil
 }
 
@@ -671,6 +833,16 @@ func autoConvert_config_LowNodeLoadArgs_To_v1alpha2_LowNodeLoadArgs(in *config.L
 	} else {
 		out.NodePools = nil
 	}
+	if in.Planning != nil {
+		in, out := &in.Planning, &out.Planning
+		*out = new(LowNodeLoadPlanning)
+		if err := Convert_config_LowNodeLoadPlanning_To_v1alpha2_LowNodeLoadPlanning(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Planning = nil
+	}
+	out.DestinationCooldown = (*v1.Duration)(unsafe.Pointer(in.DestinationCooldown))
 	return nil
 }
 
@@ -731,6 +903,36 @@ func Convert_config_LowNodeLoadNodePool_To_v1alpha2_LowNodeLoadNodePool(in *conf
 	return autoConvert_config_LowNodeLoadNodePool_To_v1alpha2_LowNodeLoadNodePool(in, out, s)
 }
 
+func autoConvert_v1alpha2_LowNodeLoadPlanning_To_config_LowNodeLoadPlanning(in *LowNodeLoadPlanning, out *config.LowNodeLoadPlanning, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxMoves, &out.MaxMoves, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha2_LowNodeLoadPlanning_To_config_LowNodeLoadPlanning is an autogenerated conversion function.
+func Convert_v1alpha2_LowNodeLoadPlanning_To_config_LowNodeLoadPlanning(in *LowNodeLoadPlanning, out *config.LowNodeLoadPlanning, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LowNodeLoadPlanning_To_config_LowNodeLoadPlanning(in, out, s)
+}
+
+func autoConvert_config_LowNodeLoadPlanning_To_v1alpha2_LowNodeLoadPlanning(in *config.LowNodeLoadPlanning, out *LowNodeLoadPlanning, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxMoves, &out.MaxMoves, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_LowNodeLoadPlanning_To_v1alpha2_LowNodeLoadPlanning is an autogenerated conversion function.
+func Convert_config_LowNodeLoadPlanning_To_v1alpha2_LowNodeLoadPlanning(in *config.LowNodeLoadPlanning, out *LowNodeLoadPlanning, s conversion.Scope) error {
+	return autoConvert_config_LowNodeLoadPlanning_To_v1alpha2_LowNodeLoadPlanning(in, out, s)
+}
+
 func autoConvert_v1alpha2_LowNodeLoadPodSelector_To_config_LowNodeLoadPodSelector(in *LowNodeLoadPodSelector, out *config.LowNodeLoadPodSelector, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
@@ -780,6 +982,12 @@ func autoConvert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerA
 	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.DefaultJobTTL, &out.DefaultJobTTL, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.PodMigrationCooldown, &out.PodMigrationCooldown, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.WorkloadMigrationCooldown, &out.WorkloadMigrationCooldown, s); err != nil {
+		return err
+	}
 	out.SchedulerNames = *(*[]string)(unsafe.Pointer(&in.SchedulerNames))
 	out.EvictQPS = (*config.Float64OrString)(unsafe.Pointer(in.EvictQPS))
 	if err := v1.Convert_Pointer_int32_To_int32(&in.EvictBurst, &out.EvictBurst, s); err != nil {
@@ -788,6 +996,19 @@ func autoConvert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerA
 	out.EvictionPolicy = in.EvictionPolicy
 	out.DefaultDeleteOptions = (*v1.DeleteOptions)(unsafe.Pointer(in.DefaultDeleteOptions))
 	out.ArbitrationArgs = (*config.ArbitrationArgs)(unsafe.Pointer(in.ArbitrationArgs))
+	out.MigrationWindows = *(*[]config.MigrationWindow)(unsafe.Pointer(&in.MigrationWindows))
+	if in.PreEvictionHooks != nil {
+		in, out := &in.PreEvictionHooks, &out.PreEvictionHooks
+		*out = make([]config.PreEvictionHook, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_PreEvictionHook_To_config_PreEvictionHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PreEvictionHooks = nil
+	}
+	out.GangMigrationPolicy = config.GangMigrationPolicy(in.GangMigrationPolicy)
 	return nil
 }
 
@@ -823,6 +1044,12 @@ func autoConvert_config_MigrationControllerArgs_To_v1alpha2_MigrationControllerA
 	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.DefaultJobTTL, &out.DefaultJobTTL, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.PodMigrationCooldown, &out.PodMigrationCooldown, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.WorkloadMigrationCooldown, &out.WorkloadMigrationCooldown, s); err != nil {
+		return err
+	}
 	out.EvictQPS = (*config.Float64OrString)(unsafe.Pointer(in.EvictQPS))
 	if err := v1.Convert_int32_To_Pointer_int32(&in.EvictBurst, &out.EvictBurst, s); err != nil {
 		return err
@@ -831,6 +1058,19 @@ func autoConvert_config_MigrationControllerArgs_To_v1alpha2_MigrationControllerA
 	out.DefaultDeleteOptions = (*v1.DeleteOptions)(unsafe.Pointer(in.DefaultDeleteOptions))
 	out.SchedulerNames = *(*[]string)(unsafe.Pointer(&in.SchedulerNames))
 	out.ArbitrationArgs = (*ArbitrationArgs)(unsafe.Pointer(in.ArbitrationArgs))
+	out.MigrationWindows = *(*[]MigrationWindow)(unsafe.Pointer(&in.MigrationWindows))
+	if in.PreEvictionHooks != nil {
+		in, out := &in.PreEvictionHooks, &out.PreEvictionHooks
+		*out = make([]PreEvictionHook, len(*in))
+		for i := range *in {
+			if err := Convert_config_PreEvictionHook_To_v1alpha2_PreEvictionHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PreEvictionHooks = nil
+	}
+	out.GangMigrationPolicy = config.GangMigrationPolicy(in.GangMigrationPolicy)
 	return nil
 }
 
@@ -839,6 +1079,28 @@ func Convert_config_MigrationControllerArgs_To_v1alpha2_MigrationControllerArgs(
 	return autoConvert_config_MigrationControllerArgs_To_v1alpha2_MigrationControllerArgs(in, out, s)
 }
 
+func autoConvert_v1alpha2_MigrationCostPlugin_To_config_MigrationCostPlugin(in *MigrationCostPlugin, out *config.MigrationCostPlugin, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_v1alpha2_MigrationCostPlugin_To_config_MigrationCostPlugin is an autogenerated conversion function.
+func Convert_v1alpha2_MigrationCostPlugin_To_config_MigrationCostPlugin(in *MigrationCostPlugin, out *config.MigrationCostPlugin, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MigrationCostPlugin_To_config_MigrationCostPlugin(in, out, s)
+}
+
+func autoConvert_config_MigrationCostPlugin_To_v1alpha2_MigrationCostPlugin(in *config.MigrationCostPlugin, out *MigrationCostPlugin, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_config_MigrationCostPlugin_To_v1alpha2_MigrationCostPlugin is an autogenerated conversion function.
+func Convert_config_MigrationCostPlugin_To_v1alpha2_MigrationCostPlugin(in *config.MigrationCostPlugin, out *MigrationCostPlugin, s conversion.Scope) error {
+	return autoConvert_config_MigrationCostPlugin_To_v1alpha2_MigrationCostPlugin(in, out, s)
+}
+
 func autoConvert_v1alpha2_MigrationObjectLimiter_To_config_MigrationObjectLimiter(in *MigrationObjectLimiter, out *config.MigrationObjectLimiter, s conversion.Scope) error {
 	out.Duration = in.Duration
 	out.MaxMigrating = (*intstr.IntOrString)(unsafe.Pointer(in.MaxMigrating))
@@ -863,6 +1125,108 @@ func Convert_config_MigrationObjectLimiter_To_v1alpha2_MigrationObjectLimiter(in
 	return autoConvert_config_MigrationObjectLimiter_To_v1alpha2_MigrationObjectLimiter(in, out, s)
 }
 
+func autoConvert_v1alpha2_MigrationWindow_To_config_MigrationWindow(in *MigrationWindow, out *config.MigrationWindow, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Duration = in.Duration
+	out.TimeZone = in.TimeZone
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.PodSelector = (*v1.LabelSelector)(unsafe.Pointer(in.PodSelector))
+	return nil
+}
+
+// Convert_v1alpha2_MigrationWindow_To_config_MigrationWindow is an autogenerated conversion function.
+func Convert_v1alpha2_MigrationWindow_To_config_MigrationWindow(in *MigrationWindow, out *config.MigrationWindow, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MigrationWindow_To_config_MigrationWindow(in, out, s)
+}
+
+func autoConvert_config_MigrationWindow_To_v1alpha2_MigrationWindow(in *config.MigrationWindow, out *MigrationWindow, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Duration = in.Duration
+	out.TimeZone = in.TimeZone
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.PodSelector = (*v1.LabelSelector)(unsafe.Pointer(in.PodSelector))
+	return nil
+}
+
+// Convert_config_MigrationWindow_To_v1alpha2_MigrationWindow is an autogenerated conversion function.
+func Convert_config_MigrationWindow_To_v1alpha2_MigrationWindow(in *config.MigrationWindow, out *MigrationWindow, s conversion.Scope) error {
+	return autoConvert_config_MigrationWindow_To_v1alpha2_MigrationWindow(in, out, s)
+}
+
+func autoConvert_v1alpha2_NUMAAwareArgs_To_config_NUMAAwareArgs(in *NUMAAwareArgs, out *config.NUMAAwareArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.Paused, &out.Paused, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.DryRun, &out.DryRun, s); err != nil {
+		return err
+	}
+	out.NodeSelector = (*v1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.EvictableNamespaces = (*config.Namespaces)(unsafe.Pointer(in.EvictableNamespaces))
+	out.PodSelectors = *(*[]config.NUMAAwarePodSelector)(unsafe.Pointer(&in.PodSelectors))
+	if err := v1.Convert_Pointer_bool_To_bool(&in.NodeFit, &out.NodeFit, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxPodCPUs, &out.MaxPodCPUs, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxPodsToEvictPerNode, &out.MaxPodsToEvictPerNode, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha2_NUMAAwareArgs_To_config_NUMAAwareArgs is an autogenerated conversion function.
+func Convert_v1alpha2_NUMAAwareArgs_To_config_NUMAAwareArgs(in *NUMAAwareArgs, out *config.NUMAAwareArgs, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NUMAAwareArgs_To_config_NUMAAwareArgs(in, out, s)
+}
+
+func autoConvert_config_NUMAAwareArgs_To_v1alpha2_NUMAAwareArgs(in *config.NUMAAwareArgs, out *NUMAAwareArgs, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.Paused, &out.Paused, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.DryRun, &out.DryRun, s); err != nil {
+		return err
+	}
+	out.NodeSelector = (*v1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.EvictableNamespaces = (*Namespaces)(unsafe.Pointer(in.EvictableNamespaces))
+	out.PodSelectors = *(*[]NUMAAwarePodSelector)(unsafe.Pointer(&in.PodSelectors))
+	if err := v1.Convert_bool_To_Pointer_bool(&in.NodeFit, &out.NodeFit, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxPodCPUs, &out.MaxPodCPUs, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxPodsToEvictPerNode, &out.MaxPodsToEvictPerNode, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_NUMAAwareArgs_To_v1alpha2_NUMAAwareArgs is an autogenerated conversion function.
+func Convert_config_NUMAAwareArgs_To_v1alpha2_NUMAAwareArgs(in *config.NUMAAwareArgs, out *NUMAAwareArgs, s conversion.Scope) error {
+	return autoConvert_config_NUMAAwareArgs_To_v1alpha2_NUMAAwareArgs(in, out, s)
+}
+
+func autoConvert_v1alpha2_NUMAAwarePodSelector_To_config_NUMAAwarePodSelector(in *NUMAAwarePodSelector, out *config.NUMAAwarePodSelector, s conversion.Scope) error {
+	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
+	return nil
+}
+
+// Convert_v1alpha2_NUMAAwarePodSelector_To_config_NUMAAwarePodSelector is an autogenerated conversion function.
+func Convert_v1alpha2_NUMAAwarePodSelector_To_config_NUMAAwarePodSelector(in *NUMAAwarePodSelector, out *config.NUMAAwarePodSelector, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NUMAAwarePodSelector_To_config_NUMAAwarePodSelector(in, out, s)
+}
+
+func autoConvert_config_NUMAAwarePodSelector_To_v1alpha2_NUMAAwarePodSelector(in *config.NUMAAwarePodSelector, out *NUMAAwarePodSelector, s conversion.Scope) error {
+	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
+	return nil
+}
+
+// Convert_config_NUMAAwarePodSelector_To_v1alpha2_NUMAAwarePodSelector is an autogenerated conversion function.
+func Convert_config_NUMAAwarePodSelector_To_v1alpha2_NUMAAwarePodSelector(in *config.NUMAAwarePodSelector, out *NUMAAwarePodSelector, s conversion.Scope) error {
+	return autoConvert_config_NUMAAwarePodSelector_To_v1alpha2_NUMAAwarePodSelector(in, out, s)
+}
+
 func autoConvert_v1alpha2_Namespaces_To_config_Namespaces(in *Namespaces, out *config.Namespaces, s conversion.Scope) error {
 	out.Include = *(*[]string)(unsafe.Pointer(&in.Include))
 	out.Exclude = *(*[]string)(unsafe.Pointer(&in.Exclude))
@@ -995,6 +1359,38 @@ func Convert_config_Plugins_To_v1alpha2_Plugins(in *config.Plugins, out *Plugins
 	return autoConvert_config_Plugins_To_v1alpha2_Plugins(in, out, s)
 }
 
+func autoConvert_v1alpha2_PreEvictionHook_To_config_PreEvictionHook(in *PreEvictionHook, out *config.PreEvictionHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PodSelector = (*v1.LabelSelector)(unsafe.Pointer(in.PodSelector))
+	out.URL = in.URL
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.Timeout, &out.Timeout, s); err != nil {
+		return err
+	}
+	out.FailurePolicy = config.PreEvictionHookFailurePolicy(in.FailurePolicy)
+	return nil
+}
+
+// Convert_v1alpha2_PreEvictionHook_To_config_PreEvictionHook is an autogenerated conversion function.
+func Convert_v1alpha2_PreEvictionHook_To_config_PreEvictionHook(in *PreEvictionHook, out *config.PreEvictionHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PreEvictionHook_To_config_PreEvictionHook(in, out, s)
+}
+
+func autoConvert_config_PreEvictionHook_To_v1alpha2_PreEvictionHook(in *config.PreEvictionHook, out *PreEvictionHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PodSelector = (*v1.LabelSelector)(unsafe.Pointer(in.PodSelector))
+	out.URL = in.URL
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.Timeout, &out.Timeout, s); err != nil {
+		return err
+	}
+	out.FailurePolicy = config.PreEvictionHookFailurePolicy(in.FailurePolicy)
+	return nil
+}
+
+// Convert_config_PreEvictionHook_To_v1alpha2_PreEvictionHook is an autogenerated conversion function.
+func Convert_config_PreEvictionHook_To_v1alpha2_PreEvictionHook(in *config.PreEvictionHook, out *PreEvictionHook, s conversion.Scope) error {
+	return autoConvert_config_PreEvictionHook_To_v1alpha2_PreEvictionHook(in, out, s)
+}
+
 func autoConvert_v1alpha2_PriorityThreshold_To_config_PriorityThreshold(in *PriorityThreshold, out *config.PriorityThreshold, s conversion.Scope) error {
 	out.Value = (*int32)(unsafe.Pointer(in.Value))
 	out.Name = in.Name
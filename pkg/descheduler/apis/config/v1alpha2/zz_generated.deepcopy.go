@@ -37,6 +37,11 @@ func (in *ArbitrationArgs) DeepCopyInto(out *ArbitrationArgs) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.CostPlugins != nil {
+		in, out := &in.CostPlugins, &out.CostPlugins
+		*out = make([]MigrationCostPlugin, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -239,6 +244,94 @@ func (in *DeschedulerProfile) DeepCopy() *DeschedulerProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceFragmentationArgs) DeepCopyInto(out *DeviceFragmentationArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EvictableNamespaces != nil {
+		in, out := &in.EvictableNamespaces, &out.EvictableNamespaces
+		*out = new(Namespaces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelectors != nil {
+		in, out := &in.PodSelectors, &out.PodSelectors
+		*out = make([]DeviceFragmentationPodSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeFit != nil {
+		in, out := &in.NodeFit, &out.NodeFit
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeviceTypes != nil {
+		in, out := &in.DeviceTypes, &out.DeviceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxPodsToEvictPerDevice != nil {
+		in, out := &in.MaxPodsToEvictPerDevice, &out.MaxPodsToEvictPerDevice
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceFragmentationArgs.
+func (in *DeviceFragmentationArgs) DeepCopy() *DeviceFragmentationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceFragmentationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceFragmentationArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceFragmentationPodSelector) DeepCopyInto(out *DeviceFragmentationPodSelector) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceFragmentationPodSelector.
+func (in *DeviceFragmentationPodSelector) DeepCopy() *DeviceFragmentationPodSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceFragmentationPodSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FragmentationAwareArgs) DeepCopyInto(out *FragmentationAwareArgs) {
 	*out = *in
@@ -456,6 +549,16 @@ func (in *LowNodeLoadArgs) DeepCopyInto(out *LowNodeLoadArgs) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Planning != nil {
+		in, out := &in.Planning, &out.Planning
+		*out = new(LowNodeLoadPlanning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DestinationCooldown != nil {
+		in, out := &in.DestinationCooldown, &out.DestinationCooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -538,6 +641,32 @@ func (in *LowNodeLoadNodePool) DeepCopy() *LowNodeLoadNodePool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LowNodeLoadPlanning) DeepCopyInto(out *LowNodeLoadPlanning) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxMoves != nil {
+		in, out := &in.MaxMoves, &out.MaxMoves
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LowNodeLoadPlanning.
+func (in *LowNodeLoadPlanning) DeepCopy() *LowNodeLoadPlanning {
+	if in == nil {
+		return nil
+	}
+	out := new(LowNodeLoadPlanning)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LowNodeLoadPodSelector) DeepCopyInto(out *LowNodeLoadPodSelector) {
 	*out = *in
@@ -630,6 +759,16 @@ func (in *MigrationControllerArgs) DeepCopyInto(out *MigrationControllerArgs) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.PodMigrationCooldown != nil {
+		in, out := &in.PodMigrationCooldown, &out.PodMigrationCooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.WorkloadMigrationCooldown != nil {
+		in, out := &in.WorkloadMigrationCooldown, &out.WorkloadMigrationCooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.SchedulerNames != nil {
 		in, out := &in.SchedulerNames, &out.SchedulerNames
 		*out = make([]string, len(*in))
@@ -655,6 +794,20 @@ func (in *MigrationControllerArgs) DeepCopyInto(out *MigrationControllerArgs) {
 		*out = new(ArbitrationArgs)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MigrationWindows != nil {
+		in, out := &in.MigrationWindows, &out.MigrationWindows
+		*out = make([]MigrationWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreEvictionHooks != nil {
+		in, out := &in.PreEvictionHooks, &out.PreEvictionHooks
+		*out = make([]PreEvictionHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -676,6 +829,22 @@ func (in *MigrationControllerArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationCostPlugin) DeepCopyInto(out *MigrationCostPlugin) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationCostPlugin.
+func (in *MigrationCostPlugin) DeepCopy() *MigrationCostPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationCostPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrationObjectLimiter) DeepCopyInto(out *MigrationObjectLimiter) {
 	*out = *in
@@ -698,6 +867,121 @@ func (in *MigrationObjectLimiter) DeepCopy() *MigrationObjectLimiter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationWindow) DeepCopyInto(out *MigrationWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationWindow.
+func (in *MigrationWindow) DeepCopy() *MigrationWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NUMAAwareArgs) DeepCopyInto(out *NUMAAwareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EvictableNamespaces != nil {
+		in, out := &in.EvictableNamespaces, &out.EvictableNamespaces
+		*out = new(Namespaces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelectors != nil {
+		in, out := &in.PodSelectors, &out.PodSelectors
+		*out = make([]NUMAAwarePodSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeFit != nil {
+		in, out := &in.NodeFit, &out.NodeFit
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxPodCPUs != nil {
+		in, out := &in.MaxPodCPUs, &out.MaxPodCPUs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPodsToEvictPerNode != nil {
+		in, out := &in.MaxPodsToEvictPerNode, &out.MaxPodsToEvictPerNode
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMAAwareArgs.
+func (in *NUMAAwareArgs) DeepCopy() *NUMAAwareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(NUMAAwareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NUMAAwareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NUMAAwarePodSelector) DeepCopyInto(out *NUMAAwarePodSelector) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMAAwarePodSelector.
+func (in *NUMAAwarePodSelector) DeepCopy() *NUMAAwarePodSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(NUMAAwarePodSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Namespaces) DeepCopyInto(out *Namespaces) {
 	*out = *in
@@ -825,6 +1109,32 @@ func (in *Plugins) DeepCopy() *Plugins {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreEvictionHook) DeepCopyInto(out *PreEvictionHook) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreEvictionHook.
+func (in *PreEvictionHook) DeepCopy() *PreEvictionHook {
+	if in == nil {
+		return nil
+	}
+	out := new(PreEvictionHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PriorityThreshold) DeepCopyInto(out *PriorityThreshold) {
 	*out = *in
@@ -31,9 +31,11 @@ import (
 func RegisterDefaults(scheme *runtime.Scheme) error {
 	scheme.AddTypeDefaultingFunc(&CustomPriorityArgs{}, func(obj interface{}) { SetObjectDefaults_CustomPriorityArgs(obj.(*CustomPriorityArgs)) })
 	scheme.AddTypeDefaultingFunc(&DeschedulerConfiguration{}, func(obj interface{}) { SetObjectDefaults_DeschedulerConfiguration(obj.(*DeschedulerConfiguration)) })
+	scheme.AddTypeDefaultingFunc(&DeviceFragmentationArgs{}, func(obj interface{}) { SetObjectDefaults_DeviceFragmentationArgs(obj.(*DeviceFragmentationArgs)) })
 	scheme.AddTypeDefaultingFunc(&FragmentationAwareArgs{}, func(obj interface{}) { SetObjectDefaults_FragmentationAwareArgs(obj.(*FragmentationAwareArgs)) })
 	scheme.AddTypeDefaultingFunc(&LowNodeLoadArgs{}, func(obj interface{}) { SetObjectDefaults_LowNodeLoadArgs(obj.(*LowNodeLoadArgs)) })
 	scheme.AddTypeDefaultingFunc(&MigrationControllerArgs{}, func(obj interface{}) { SetObjectDefaults_MigrationControllerArgs(obj.(*MigrationControllerArgs)) })
+	scheme.AddTypeDefaultingFunc(&NUMAAwareArgs{}, func(obj interface{}) { SetObjectDefaults_NUMAAwareArgs(obj.(*NUMAAwareArgs)) })
 	scheme.AddTypeDefaultingFunc(&ScaleDownBinPackArgs{}, func(obj interface{}) { SetObjectDefaults_ScaleDownBinPackArgs(obj.(*ScaleDownBinPackArgs)) })
 	return nil
 }
@@ -46,6 +48,10 @@ func SetObjectDefaults_DeschedulerConfiguration(in *DeschedulerConfiguration) {
 	SetDefaults_DeschedulerConfiguration(in)
 }
 
+func SetObjectDefaults_DeviceFragmentationArgs(in *DeviceFragmentationArgs) {
+	SetDefaults_DeviceFragmentationArgs(in)
+}
+
 func SetObjectDefaults_FragmentationAwareArgs(in *FragmentationAwareArgs) {
 	SetDefaults_FragmentationAwareArgs(in)
 }
@@ -58,6 +64,10 @@ func SetObjectDefaults_MigrationControllerArgs(in *MigrationControllerArgs) {
 	SetDefaults_MigrationControllerArgs(in)
 }
 
+func SetObjectDefaults_NUMAAwareArgs(in *NUMAAwareArgs) {
+	SetDefaults_NUMAAwareArgs(in)
+}
+
 func SetObjectDefaults_ScaleDownBinPackArgs(in *ScaleDownBinPackArgs) {
 	SetDefaults_ScaleDownBinPackArgs(in)
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+)
+
+func TestValidateDeviceFragmentationArgs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          *deschedulerconfig.DeviceFragmentationArgs
+		expectedError string
+	}{
+		{
+			name: "valid args",
+			args: &deschedulerconfig.DeviceFragmentationArgs{
+				DeviceTypes:             []string{"gpu", "rdma"},
+				MaxPodsToEvictPerDevice: 4,
+			},
+		},
+		{
+			name:          "nil args",
+			args:          nil,
+			expectedError: "DeviceFragmentationArgs must not be nil",
+		},
+		{
+			name: "missing deviceTypes",
+			args: &deschedulerconfig.DeviceFragmentationArgs{
+				MaxPodsToEvictPerDevice: 4,
+			},
+			expectedError: "deviceTypes",
+		},
+		{
+			name: "unsupported deviceTypes",
+			args: &deschedulerconfig.DeviceFragmentationArgs{
+				DeviceTypes:             []string{"fpga"},
+				MaxPodsToEvictPerDevice: 4,
+			},
+			expectedError: "Unsupported value",
+		},
+		{
+			name: "duplicated deviceTypes",
+			args: &deschedulerconfig.DeviceFragmentationArgs{
+				DeviceTypes:             []string{"gpu", "gpu"},
+				MaxPodsToEvictPerDevice: 4,
+			},
+			expectedError: "Duplicate value",
+		},
+		{
+			name: "invalid maxPodsToEvictPerDevice",
+			args: &deschedulerconfig.DeviceFragmentationArgs{
+				DeviceTypes:             []string{"gpu"},
+				MaxPodsToEvictPerDevice: 0,
+			},
+			expectedError: "maxPodsToEvictPerDevice",
+		},
+		{
+			name: "invalid pod selector",
+			args: &deschedulerconfig.DeviceFragmentationArgs{
+				DeviceTypes:             []string{"gpu"},
+				MaxPodsToEvictPerDevice: 4,
+				PodSelectors: []deschedulerconfig.DeviceFragmentationPodSelector{
+					{
+						Selector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{
+									Operator: "invalid-op",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedError: "invalid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDeviceFragmentationArgs(nil, tc.args)
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
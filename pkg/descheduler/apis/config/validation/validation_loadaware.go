@@ -38,6 +38,14 @@ func ValidateLowLoadUtilizationArgs(path *field.Path, args *deschedulerconfig.Lo
 		allErrs = append(allErrs, field.Invalid(path.Child("evictableNamespaces"), args.EvictableNamespaces, "only one of Include/Exclude namespaces can be set"))
 	}
 
+	if args.Planning != nil && args.Planning.MaxMoves < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("planning", "maxMoves"), args.Planning.MaxMoves, "must be greater than or equal to 0"))
+	}
+
+	if args.DestinationCooldown != nil && args.DestinationCooldown.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("destinationCooldown"), args.DestinationCooldown, "must be greater than or equal to 0"))
+	}
+
 	for i, v := range args.PodSelectors {
 		if v.Selector != nil {
 			if _, err := metav1.LabelSelectorAsSelector(v.Selector); err != nil {
@@ -18,6 +18,9 @@ package validation
 
 import (
 	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/stretchr/testify/assert"
@@ -214,3 +217,71 @@ func TestValidateLowLoadUtilizationArgs_NodePoolThresholds(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateLowLoadUtilizationArgs_Planning(t *testing.T) {
+	testCases := []struct {
+		maxMoves      int32
+		expectedError bool
+	}{
+		{
+			maxMoves:      0,
+			expectedError: false,
+		},
+		{
+			maxMoves:      10,
+			expectedError: false,
+		},
+		{
+			maxMoves:      -1,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		args := &deschedulerconfig.LowNodeLoadArgs{
+			Planning: &deschedulerconfig.LowNodeLoadPlanning{
+				Enabled:  true,
+				MaxMoves: tc.maxMoves,
+			},
+		}
+		err := ValidateLowLoadUtilizationArgs(nil, args)
+		if tc.expectedError {
+			assert.Error(t, err, "Expected an error for invalid MaxMoves")
+			assert.Contains(t, err.Error(), "planning.maxMoves", "Expected specific error field")
+		} else {
+			assert.Nil(t, err, "Expected no error for valid configuration")
+		}
+	}
+}
+
+func TestValidateLowLoadUtilizationArgs_DestinationCooldown(t *testing.T) {
+	testCases := []struct {
+		destinationCooldown *metav1.Duration
+		expectedError       bool
+	}{
+		{
+			destinationCooldown: nil,
+			expectedError:       false,
+		},
+		{
+			destinationCooldown: &metav1.Duration{Duration: 30 * time.Minute},
+			expectedError:       false,
+		},
+		{
+			destinationCooldown: &metav1.Duration{Duration: -time.Minute},
+			expectedError:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		args := &deschedulerconfig.LowNodeLoadArgs{
+			DestinationCooldown: tc.destinationCooldown,
+		}
+		err := ValidateLowLoadUtilizationArgs(nil, args)
+		if tc.expectedError {
+			assert.Error(t, err, "Expected an error for invalid DestinationCooldown")
+		} else {
+			assert.Nil(t, err, "Expected no error for valid configuration")
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+)
+
+func TestValidateNUMAAwareArgs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          *deschedulerconfig.NUMAAwareArgs
+		expectedError string
+	}{
+		{
+			name: "valid args",
+			args: &deschedulerconfig.NUMAAwareArgs{
+				MaxPodCPUs:            4,
+				MaxPodsToEvictPerNode: 4,
+			},
+		},
+		{
+			name:          "nil args",
+			args:          nil,
+			expectedError: "NUMAAwareArgs must not be nil",
+		},
+		{
+			name: "invalid maxPodCPUs",
+			args: &deschedulerconfig.NUMAAwareArgs{
+				MaxPodCPUs:            0,
+				MaxPodsToEvictPerNode: 4,
+			},
+			expectedError: "maxPodCPUs",
+		},
+		{
+			name: "invalid maxPodsToEvictPerNode",
+			args: &deschedulerconfig.NUMAAwareArgs{
+				MaxPodCPUs:            4,
+				MaxPodsToEvictPerNode: -1,
+			},
+			expectedError: "maxPodsToEvictPerNode",
+		},
+		{
+			name: "invalid pod selector",
+			args: &deschedulerconfig.NUMAAwareArgs{
+				MaxPodCPUs:            4,
+				MaxPodsToEvictPerNode: 4,
+				PodSelectors: []deschedulerconfig.NUMAAwarePodSelector{
+					{
+						Selector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{
+									Operator: "invalid-op",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedError: "invalid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNUMAAwareArgs(nil, tc.args)
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
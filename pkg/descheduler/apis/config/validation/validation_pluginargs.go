@@ -18,9 +18,13 @@ package validation
 
 import (
 	"fmt"
+	"net/url"
+	"time"
 
+	"github.com/robfig/cron/v3"
 	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
@@ -36,6 +40,8 @@ func ValidateMigrationControllerArgs(path *field.Path, args *deschedulerconfig.M
 		deschedulerconfig.EvictionGateMaxMigratingPerNode:       {},
 		deschedulerconfig.EvictionGateMaxMigratingPerNamespace:  {},
 		deschedulerconfig.EvictionGateMaxMigratingGlobally:      {},
+		deschedulerconfig.EvictionGatePodDisruptionBudget:       {},
+		deschedulerconfig.EvictionGateMigrationCooldown:         {},
 		deschedulerconfig.EvictionGateExpectedReplicas:          {},
 		deschedulerconfig.EvictionGatePVC:                       {},
 		deschedulerconfig.EvictionGateBarePods:                  {},
@@ -105,8 +111,94 @@ func ValidateMigrationControllerArgs(path *field.Path, args *deschedulerconfig.M
 		allErrs = append(allErrs, field.Invalid(path.Child("defaultJobTTL"), args.DefaultJobTTL, "defaultJobTTL should be positive or zero"))
 	}
 
+	if args.PodMigrationCooldown.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("podMigrationCooldown"), args.PodMigrationCooldown, "podMigrationCooldown should be positive or zero"))
+	}
+	if args.WorkloadMigrationCooldown.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("workloadMigrationCooldown"), args.WorkloadMigrationCooldown, "workloadMigrationCooldown should be positive or zero"))
+	}
+
+	for i, window := range args.MigrationWindows {
+		allErrs = append(allErrs, validateMigrationWindow(path.Child("migrationWindows").Index(i), &window)...)
+	}
+
+	switch args.GangMigrationPolicy {
+	case deschedulerconfig.GangMigrationPolicyIgnore, deschedulerconfig.GangMigrationPolicySkip, deschedulerconfig.GangMigrationPolicyAtomic:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("gangMigrationPolicy"), args.GangMigrationPolicy,
+			[]string{string(deschedulerconfig.GangMigrationPolicyIgnore), string(deschedulerconfig.GangMigrationPolicySkip), string(deschedulerconfig.GangMigrationPolicyAtomic)}))
+	}
+
+	hookNames := sets.NewString()
+	for i := range args.PreEvictionHooks {
+		hookPath := path.Child("preEvictionHooks").Index(i)
+		if hookNames.Has(args.PreEvictionHooks[i].Name) {
+			allErrs = append(allErrs, field.Duplicate(hookPath.Child("name"), args.PreEvictionHooks[i].Name))
+		}
+		hookNames.Insert(args.PreEvictionHooks[i].Name)
+		allErrs = append(allErrs, validatePreEvictionHook(hookPath, &args.PreEvictionHooks[i])...)
+	}
+
+	if args.ArbitrationArgs != nil {
+		costPluginNames := sets.NewString()
+		for i, plugin := range args.ArbitrationArgs.CostPlugins {
+			pluginPath := path.Child("arbitrationArgs", "costPlugins").Index(i)
+			if plugin.Name == "" {
+				allErrs = append(allErrs, field.Required(pluginPath.Child("name"), "name of costPlugin is required"))
+			} else if costPluginNames.Has(plugin.Name) {
+				allErrs = append(allErrs, field.Duplicate(pluginPath.Child("name"), plugin.Name))
+			}
+			costPluginNames.Insert(plugin.Name)
+			if plugin.Weight <= 0 {
+				allErrs = append(allErrs, field.Invalid(pluginPath.Child("weight"), plugin.Weight, "weight should be greater than 0"))
+			}
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return allErrs.ToAggregate()
 }
+
+func validatePreEvictionHook(path *field.Path, hook *deschedulerconfig.PreEvictionHook) field.ErrorList {
+	var allErrs field.ErrorList
+	if hook.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), "name of preEvictionHook is required"))
+	}
+	if hook.PodSelector == nil {
+		allErrs = append(allErrs, field.Required(path.Child("podSelector"), "podSelector of preEvictionHook is required"))
+	} else {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(hook.PodSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("podSelector"))...)
+	}
+	if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("url"), hook.URL, "url should be a valid HTTP(S) address"))
+	}
+	if hook.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeout"), hook.Timeout, "timeout should be greater than 0"))
+	}
+	if hook.FailurePolicy != deschedulerconfig.PreEvictionHookFailurePolicyFail && hook.FailurePolicy != deschedulerconfig.PreEvictionHookFailurePolicyIgnore {
+		allErrs = append(allErrs, field.NotSupported(path.Child("failurePolicy"), hook.FailurePolicy,
+			[]string{string(deschedulerconfig.PreEvictionHookFailurePolicyFail), string(deschedulerconfig.PreEvictionHookFailurePolicyIgnore)}))
+	}
+	return allErrs
+}
+
+func validateMigrationWindow(path *field.Path, window *deschedulerconfig.MigrationWindow) field.ErrorList {
+	var allErrs field.ErrorList
+	if _, err := cron.ParseStandard(window.Schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("schedule"), window.Schedule, fmt.Sprintf("schedule is invalid, err: %v", err)))
+	}
+	if window.Duration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("duration"), window.Duration, "duration should be greater than 0"))
+	}
+	if window.TimeZone != "" {
+		if _, err := time.LoadLocation(window.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), window.TimeZone, fmt.Sprintf("timeZone is invalid, err: %v", err)))
+		}
+	}
+	if window.PodSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(window.PodSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("podSelector"))...)
+	}
+	return allErrs
+}
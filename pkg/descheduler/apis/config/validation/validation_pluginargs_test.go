@@ -100,6 +100,191 @@ func TestValidateMigrationControllerArgs(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid migrationWindows",
+			args: &v1alpha2.MigrationControllerArgs{
+				MigrationWindows: []v1alpha2.MigrationWindow{
+					{
+						Schedule:   "0 2 * * *",
+						Duration:   metav1.Duration{Duration: 2 * time.Hour},
+						TimeZone:   "Asia/Shanghai",
+						Namespaces: []string{"default"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid migrationWindows schedule",
+			args: &v1alpha2.MigrationControllerArgs{
+				MigrationWindows: []v1alpha2.MigrationWindow{
+					{
+						Schedule: "every night",
+						Duration: metav1.Duration{Duration: 2 * time.Hour},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid migrationWindows duration",
+			args: &v1alpha2.MigrationControllerArgs{
+				MigrationWindows: []v1alpha2.MigrationWindow{
+					{
+						Schedule: "0 2 * * *",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid preEvictionHooks",
+			args: &v1alpha2.MigrationControllerArgs{
+				PreEvictionHooks: []v1alpha2.PreEvictionHook{
+					{
+						Name:        "kafka",
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+						URL:         "http://kafka-operator.default.svc:8080/pre-evict",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid preEvictionHooks without podSelector",
+			args: &v1alpha2.MigrationControllerArgs{
+				PreEvictionHooks: []v1alpha2.PreEvictionHook{
+					{
+						Name: "kafka",
+						URL:  "http://kafka-operator.default.svc:8080/pre-evict",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid preEvictionHooks url",
+			args: &v1alpha2.MigrationControllerArgs{
+				PreEvictionHooks: []v1alpha2.PreEvictionHook{
+					{
+						Name:        "kafka",
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+						URL:         "kafka-operator:8080",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated preEvictionHooks",
+			args: &v1alpha2.MigrationControllerArgs{
+				PreEvictionHooks: []v1alpha2.PreEvictionHook{
+					{
+						Name:        "kafka",
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+						URL:         "http://kafka-operator.default.svc:8080/pre-evict",
+					},
+					{
+						Name:        "kafka",
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+						URL:         "http://kafka-operator.default.svc:8080/pre-evict",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid preEvictionHooks failurePolicy",
+			args: &v1alpha2.MigrationControllerArgs{
+				PreEvictionHooks: []v1alpha2.PreEvictionHook{
+					{
+						Name:          "kafka",
+						PodSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+						URL:           "http://kafka-operator.default.svc:8080/pre-evict",
+						FailurePolicy: "Unknown",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid migration cooldown",
+			args: &v1alpha2.MigrationControllerArgs{
+				PodMigrationCooldown:      &metav1.Duration{Duration: time.Hour},
+				WorkloadMigrationCooldown: &metav1.Duration{Duration: 10 * time.Minute},
+				SkipEvictionGates:         []deschedulerconfig.EvictionGate{deschedulerconfig.EvictionGateMigrationCooldown},
+			},
+		},
+		{
+			name: "invalid podMigrationCooldown",
+			args: &v1alpha2.MigrationControllerArgs{
+				PodMigrationCooldown: &metav1.Duration{Duration: -time.Hour},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid gangMigrationPolicy",
+			args: &v1alpha2.MigrationControllerArgs{
+				GangMigrationPolicy: deschedulerconfig.GangMigrationPolicyAtomic,
+			},
+		},
+		{
+			name: "invalid gangMigrationPolicy",
+			args: &v1alpha2.MigrationControllerArgs{
+				GangMigrationPolicy: "Unknown",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid costPlugins",
+			args: &v1alpha2.MigrationControllerArgs{
+				ArbitrationArgs: &v1alpha2.ArbitrationArgs{
+					Enabled: true,
+					CostPlugins: []v1alpha2.MigrationCostPlugin{
+						{Name: "RestartCost", Weight: 2},
+						{Name: "PodAge", Weight: 1},
+					},
+				},
+			},
+		},
+		{
+			name: "duplicated costPlugins",
+			args: &v1alpha2.MigrationControllerArgs{
+				ArbitrationArgs: &v1alpha2.ArbitrationArgs{
+					Enabled: true,
+					CostPlugins: []v1alpha2.MigrationCostPlugin{
+						{Name: "RestartCost", Weight: 2},
+						{Name: "RestartCost", Weight: 1},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid costPlugins weight",
+			args: &v1alpha2.MigrationControllerArgs{
+				ArbitrationArgs: &v1alpha2.ArbitrationArgs{
+					Enabled: true,
+					CostPlugins: []v1alpha2.MigrationCostPlugin{
+						{Name: "RestartCost", Weight: 0},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid migrationWindows timeZone",
+			args: &v1alpha2.MigrationControllerArgs{
+				MigrationWindows: []v1alpha2.MigrationWindow{
+					{
+						Schedule: "0 2 * * *",
+						Duration: metav1.Duration{Duration: 2 * time.Hour},
+						TimeZone: "No/Such/Zone",
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -27,11 +27,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/sorter"
 )
@@ -50,6 +52,8 @@ type MigrationFilter interface {
 
 type Arbitrator interface {
 	MigrationFilter
+	// FilterGang checks if all the members of a gang can be migrated together within the migration limits.
+	FilterGang(members []*corev1.Pod) bool
 	AddPodMigrationJob(job *v1alpha1.PodMigrationJob)
 	DeletePodMigrationJob(job *v1alpha1.PodMigrationJob)
 }
@@ -62,8 +66,10 @@ type arbitratorImpl struct {
 	waitingCollection map[types.UID]*v1alpha1.PodMigrationJob
 	interval          time.Duration
 
-	sorts  []SortFn
-	filter *filter
+	sorts   []SortFn
+	filter  *filter
+	windows *migrationWindows
+	clock   clock.PassiveClock
 
 	client        client.Client
 	eventRecorder events.EventRecorder
@@ -76,20 +82,35 @@ func New(args *config.MigrationControllerArgs, options Options) (Arbitrator, err
 	if err != nil {
 		return nil, err
 	}
+	windows, err := newMigrationWindows(args.MigrationWindows)
+	if err != nil {
+		return nil, err
+	}
+	costPlugins, err := newWeightedCostPlugins(args.ArbitrationArgs.CostPlugins, CostPluginHandle{
+		Client:           options.Client,
+		ControllerFinder: f.controllerFinder,
+		MigrationHistory: f.history,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	podSorter := SortJobsByPod(sorter.PodSorter().Sort)
+	if len(costPlugins) > 0 {
+		podSorter = SortJobsByPodAndMigrationCost(costPlugins)
+	}
+	sorts := []SortFn{SortJobsByCreationTime(), podSorter, SortJobsByController(), SortJobsByMigratingNum(options.Client)}
 
 	arbitrator := &arbitratorImpl{
 		waitingCollection: map[types.UID]*v1alpha1.PodMigrationJob{},
 		interval:          args.ArbitrationArgs.Interval.Duration,
-		sorts: []SortFn{
-			SortJobsByCreationTime(),
-			SortJobsByPod(sorter.PodSorter().Sort),
-			SortJobsByController(),
-			SortJobsByMigratingNum(options.Client),
-		},
-		filter:        f,
-		client:        options.Client,
-		eventRecorder: options.EventRecorder,
-		mu:            sync.Mutex{},
+		sorts:             sorts,
+		filter:            f,
+		windows:           windows,
+		clock:             clock.RealClock{},
+		client:            options.Client,
+		eventRecorder:     options.EventRecorder,
+		mu:                sync.Mutex{},
 	}
 
 	err = options.Manager.Add(arbitrator)
@@ -102,14 +123,17 @@ func New(args *config.MigrationControllerArgs, options Options) (Arbitrator, err
 // AddPodMigrationJob adds a PodMigrationJob waiting to be arbitrated to Arbitrator.
 // It is safe to be called concurrently by multiple goroutines.
 func (a *arbitratorImpl) AddPodMigrationJob(job *v1alpha1.PodMigrationJob) {
+	a.filter.recordMigration(job)
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.waitingCollection[job.UID] = job.DeepCopy()
 }
 
-// DeletePodMigrationJob removes a deleted PodMigrationJob from Arbitrator.
+// DeletePodMigrationJob removes a deleted or finished PodMigrationJob from Arbitrator, and records the succeeded one
+// into the migration history.
 // It is safe to be called concurrently by multiple goroutines.
 func (a *arbitratorImpl) DeletePodMigrationJob(job *v1alpha1.PodMigrationJob) {
+	a.filter.recordMigration(job)
 	a.filter.removeJobPassedArbitration(job.UID)
 }
 
@@ -141,6 +165,11 @@ func (a *arbitratorImpl) Filter(pod *corev1.Pod) bool {
 	return true
 }
 
+// FilterGang checks if all the members of a gang can be migrated together within the migration limits.
+func (a *arbitratorImpl) FilterGang(members []*corev1.Pod) bool {
+	return a.filter.filterGangWithinLimits(members)
+}
+
 func (a *arbitratorImpl) PreEvictionFilter(pod *corev1.Pod) bool {
 	return a.filter.defaultFilterPlugin.PreEvictionFilter(pod)
 }
@@ -205,6 +234,9 @@ func (a *arbitratorImpl) doOnceArbitrate() {
 	// filter
 	for _, job := range jobs {
 		pod := podOfJob[job]
+		if !a.checkMigrationWindow(job, pod) {
+			continue
+		}
 		isFailed, isPassed := a.filtering(pod)
 		if isFailed {
 			a.updateFailedJob(job, pod)
@@ -243,6 +275,54 @@ func (a *arbitratorImpl) updateFailedJob(job *v1alpha1.PodMigrationJob, pod *cor
 	a.mu.Unlock()
 }
 
+// checkMigrationWindow checks if the Pod of the PodMigrationJob is in its migration windows. The PodMigrationJob
+// outside the windows is kept in the waitingCollection and marked with the condition MigrationWindow=False.
+func (a *arbitratorImpl) checkMigrationWindow(job *v1alpha1.PodMigrationJob, pod *corev1.Pod) bool {
+	if a.windows == nil || pod == nil {
+		return true
+	}
+	open, nextOpen := a.windows.isOpen(pod, a.clock.Now())
+	if open {
+		if util.IsWaitingForMigrationWindow(job) {
+			a.updateJobCondition(job, &v1alpha1.PodMigrationJobCondition{
+				Type:    v1alpha1.PodMigrationJobConditionMigrationWindow,
+				Status:  v1alpha1.PodMigrationJobConditionStatusTrue,
+				Reason:  v1alpha1.PodMigrationJobReasonMigrationWindowOpened,
+				Message: "The migration window is open",
+			})
+		}
+		return true
+	}
+	a.updateJobCondition(job, &v1alpha1.PodMigrationJobCondition{
+		Type:    v1alpha1.PodMigrationJobConditionMigrationWindow,
+		Status:  v1alpha1.PodMigrationJobConditionStatusFalse,
+		Reason:  v1alpha1.PodMigrationJobReasonWaitForMigrationWindow,
+		Message: fmt.Sprintf("Pod %q is waiting for the migration window opened at %s", klog.KObj(pod), nextOpen.Format(time.RFC3339)),
+	})
+	return false
+}
+
+func (a *arbitratorImpl) updateJobCondition(job *v1alpha1.PodMigrationJob, cond *v1alpha1.PodMigrationJobCondition) {
+	// update a copy so that the condition is not kept in the cached job if the update fails, and it can be retried
+	newJob := job.DeepCopy()
+	if !util.UpdateCondition(&newJob.Status, cond) {
+		return
+	}
+	if newJob.Status.Phase == "" {
+		newJob.Status.Phase = v1alpha1.PodMigrationJobPending
+	}
+	newJob.Status.Status = string(cond.Type)
+	newJob.Status.Reason = cond.Reason
+	newJob.Status.Message = cond.Message
+	err := a.client.Status().Update(context.TODO(), newJob)
+	if err != nil {
+		klog.ErrorS(err, "failed to update job condition", "job", klog.KObj(job), "condition", cond.Type)
+		return
+	}
+	*job = *newJob
+	a.eventRecorder.Eventf(job, nil, corev1.EventTypeNormal, cond.Reason, "Migrating", cond.Message)
+}
+
 type Options struct {
 	Client        client.Client
 	EventRecorder events.EventRecorder
@@ -32,6 +32,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -39,6 +40,8 @@ import (
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
 )
 
 func TestSingleSortFn(t *testing.T) {
@@ -537,6 +540,90 @@ func TestDoOnceArbitrate(t *testing.T) {
 	}
 }
 
+func TestDoOnceArbitrateWithMigrationWindow(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithStatusSubresource(&v1alpha1.PodMigrationJob{}).WithScheme(scheme).Build()
+
+	pod := makePod("test-pod", 0, extension.QoSNone, corev1.PodQOSBestEffort, time.Now())
+	job := makePodMigrationJob("test-job", time.Now(), pod)
+	assert.Nil(t, fakeClient.Create(context.TODO(), pod))
+	assert.Nil(t, fakeClient.Create(context.TODO(), job))
+
+	windows, err := newMigrationWindows([]config.MigrationWindow{
+		{
+			Schedule: "0 2 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+			TimeZone: "UTC",
+		},
+	})
+	assert.NoError(t, err)
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	a := &arbitratorImpl{
+		waitingCollection: map[types.UID]*v1alpha1.PodMigrationJob{job.UID: job},
+		filter: &filter{
+			arbitratedPodMigrationJobs: map[types.UID]bool{},
+		},
+		windows:       windows,
+		clock:         fakeClock,
+		client:        fakeClient,
+		mu:            sync.Mutex{},
+		eventRecorder: &events.FakeRecorder{},
+	}
+
+	// the job is kept in the queue outside the window
+	a.doOnceArbitrate()
+	assert.Contains(t, a.waitingCollection, job.UID)
+	got := &v1alpha1.PodMigrationJob{}
+	assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, got))
+	assert.Empty(t, got.Annotations[AnnotationPassedArbitration])
+	assert.Equal(t, v1alpha1.PodMigrationJobPending, got.Status.Phase)
+	assert.Equal(t, v1alpha1.PodMigrationJobReasonWaitForMigrationWindow, got.Status.Reason)
+	assert.True(t, util.IsWaitingForMigrationWindow(got))
+
+	// the job passes the arbitration once the window opens
+	fakeClock.SetTime(time.Date(2024, 1, 2, 2, 30, 0, 0, time.UTC))
+	a.doOnceArbitrate()
+	assert.NotContains(t, a.waitingCollection, job.UID)
+	assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, got))
+	assert.Equal(t, "true", got.Annotations[AnnotationPassedArbitration])
+	assert.Equal(t, v1alpha1.PodMigrationJobReasonMigrationWindowOpened, got.Status.Reason)
+	assert.False(t, util.IsWaitingForMigrationWindow(got))
+}
+
+func TestUpdateJobCondition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithStatusSubresource(&v1alpha1.PodMigrationJob{}).WithScheme(scheme).Build()
+
+	pod := makePod("test-pod", 0, extension.QoSNone, corev1.PodQOSBestEffort, time.Now())
+	job := makePodMigrationJob("test-job", time.Now(), pod)
+	a := &arbitratorImpl{
+		client:        fakeClient,
+		eventRecorder: &events.FakeRecorder{},
+	}
+	cond := &v1alpha1.PodMigrationJobCondition{
+		Type:   v1alpha1.PodMigrationJobConditionMigrationWindow,
+		Status: v1alpha1.PodMigrationJobConditionStatusFalse,
+		Reason: v1alpha1.PodMigrationJobReasonWaitForMigrationWindow,
+	}
+
+	// the job is not changed if the update fails
+	a.updateJobCondition(job, cond)
+	assert.Empty(t, job.Status.Conditions)
+	assert.Empty(t, job.Status.Reason)
+
+	assert.Nil(t, fakeClient.Create(context.TODO(), job))
+	a.updateJobCondition(job, cond)
+	assert.Len(t, job.Status.Conditions, 1)
+	assert.Equal(t, v1alpha1.PodMigrationJobReasonWaitForMigrationWindow, job.Status.Reason)
+	got := &v1alpha1.PodMigrationJob{}
+	assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, got))
+	assert.True(t, util.IsWaitingForMigrationWindow(got))
+}
+
 func TestArbitrate(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = v1alpha1.AddToScheme(scheme)
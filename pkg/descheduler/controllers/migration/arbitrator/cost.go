@@ -0,0 +1,312 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/controllerfinder"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/history"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/sorter"
+)
+
+const (
+	// MaxMigrationCost is the max normalized score of a CostPlugin.
+	MaxMigrationCost = 100
+
+	CostPluginRestartCost      = "RestartCost"
+	CostPluginWorkloadReplicas = "WorkloadReplicas"
+	CostPluginPodAge           = "PodAge"
+	CostPluginLocalPV          = "LocalPV"
+	CostPluginRecentMigrations = "RecentMigrations"
+
+	recentMigrationsWindow = time.Hour
+)
+
+// CostPlugin scores the cost of migrating a Pod, the higher the score the more expensive to migrate the Pod.
+// The raw scores of a plugin can be in any range, they are normalized into [0, MaxMigrationCost] among
+// the PodMigrationJobs being arbitrated.
+type CostPlugin interface {
+	Name() string
+	Score(pod *corev1.Pod) int64
+}
+
+// CostPluginHandle provides the data the CostPlugins may need.
+type CostPluginHandle struct {
+	Client           client.Client
+	ControllerFinder controllerfinder.Interface
+	MigrationHistory *history.Store
+}
+
+type CostPluginFactory func(handle CostPluginHandle) (CostPlugin, error)
+
+var costPluginRegistry = map[string]CostPluginFactory{
+	CostPluginRestartCost:      newRestartCostPlugin,
+	CostPluginWorkloadReplicas: newWorkloadReplicasPlugin,
+	CostPluginPodAge:           newPodAgePlugin,
+	CostPluginLocalPV:          newLocalPVPlugin,
+	CostPluginRecentMigrations: newRecentMigrationsPlugin,
+}
+
+// RegisterCostPlugin registers a CostPlugin which can be enabled in ArbitrationArgs.CostPlugins.
+func RegisterCostPlugin(name string, factory CostPluginFactory) {
+	costPluginRegistry[name] = factory
+}
+
+// WeightedCostPlugin is a CostPlugin with its weight in the migration cost.
+type WeightedCostPlugin struct {
+	CostPlugin
+	Weight int64
+}
+
+func newWeightedCostPlugins(args []config.MigrationCostPlugin, handle CostPluginHandle) ([]WeightedCostPlugin, error) {
+	var plugins []WeightedCostPlugin
+	for _, v := range args {
+		factory, ok := costPluginRegistry[v.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cost plugin %q", v.Name)
+		}
+		plugin, err := factory(handle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build cost plugin %q, err: %w", v.Name, err)
+		}
+		plugins = append(plugins, WeightedCostPlugin{CostPlugin: plugin, Weight: int64(v.Weight)})
+	}
+	return plugins, nil
+}
+
+// SortJobsByMigrationCost returns a SortFn that stably sorts PodMigrationJobs in ascending order of the weighted
+// migration cost of their Pods, so that the Pods cheaper to migrate are arbitrated first.
+func SortJobsByMigrationCost(plugins []WeightedCostPlugin) SortFn {
+	return func(jobs []*v1alpha1.PodMigrationJob, podOfJob map[*v1alpha1.PodMigrationJob]*corev1.Pod) []*v1alpha1.PodMigrationJob {
+		costOfJob := scoreMigrationCost(plugins, jobs, podOfJob)
+		sort.SliceStable(jobs, func(i, j int) bool {
+			return costOfJob[jobs[i]] < costOfJob[jobs[j]]
+		})
+		return jobs
+	}
+}
+
+// SortJobsByPodAndMigrationCost returns a SortFn that sorts PodMigrationJobs by the PodSorter, which compares the
+// weighted migration cost of the Pods before their creation time.
+func SortJobsByPodAndMigrationCost(plugins []WeightedCostPlugin) SortFn {
+	return func(jobs []*v1alpha1.PodMigrationJob, podOfJob map[*v1alpha1.PodMigrationJob]*corev1.Pod) []*v1alpha1.PodMigrationJob {
+		costOfJob := scoreMigrationCost(plugins, jobs, podOfJob)
+		costOfPod := make(map[*corev1.Pod]int64, len(costOfJob))
+		for job, cost := range costOfJob {
+			if pod := podOfJob[job]; pod != nil {
+				costOfPod[pod] = cost
+			}
+		}
+		migrationCost := func(p1, p2 *corev1.Pod) int {
+			if costOfPod[p1] == costOfPod[p2] {
+				return 0
+			}
+			if costOfPod[p1] < costOfPod[p2] {
+				return -1
+			}
+			return 1
+		}
+		return SortJobsByPod(sorter.PodSorter(migrationCost).Sort)(jobs, podOfJob)
+	}
+}
+
+func scoreMigrationCost(plugins []WeightedCostPlugin, jobs []*v1alpha1.PodMigrationJob, podOfJob map[*v1alpha1.PodMigrationJob]*corev1.Pod) map[*v1alpha1.PodMigrationJob]int64 {
+	costOfJob := make(map[*v1alpha1.PodMigrationJob]int64, len(jobs))
+	var totalWeight int64
+	for _, plugin := range plugins {
+		totalWeight += plugin.Weight
+	}
+	if totalWeight == 0 {
+		return costOfJob
+	}
+
+	scores := make(map[*v1alpha1.PodMigrationJob]int64, len(jobs))
+	for _, plugin := range plugins {
+		var minScore, maxScore int64
+		first := true
+		for _, job := range jobs {
+			pod := podOfJob[job]
+			if pod == nil {
+				continue
+			}
+			score := plugin.Score(pod)
+			scores[job] = score
+			if first || score < minScore {
+				minScore = score
+			}
+			if first || score > maxScore {
+				maxScore = score
+			}
+			first = false
+		}
+		if maxScore == minScore {
+			continue
+		}
+		for job, score := range scores {
+			costOfJob[job] += plugin.Weight * (score - minScore) * MaxMigrationCost / (maxScore - minScore)
+		}
+	}
+	for job := range costOfJob {
+		costOfJob[job] /= totalWeight
+	}
+	return costOfJob
+}
+
+// restartCostPlugin scores the Pod by the eviction cost annotated by the users.
+type restartCostPlugin struct{}
+
+func newRestartCostPlugin(handle CostPluginHandle) (CostPlugin, error) {
+	return &restartCostPlugin{}, nil
+}
+
+func (p *restartCostPlugin) Name() string {
+	return CostPluginRestartCost
+}
+
+func (p *restartCostPlugin) Score(pod *corev1.Pod) int64 {
+	cost, _ := extension.GetEvictionCost(pod.Annotations)
+	return int64(cost)
+}
+
+// workloadReplicasPlugin scores the Pod higher if its workload has fewer replicas, since migrating one of the few
+// replicas hurts the availability more.
+type workloadReplicasPlugin struct {
+	controllerFinder controllerfinder.Interface
+}
+
+func newWorkloadReplicasPlugin(handle CostPluginHandle) (CostPlugin, error) {
+	if handle.ControllerFinder == nil {
+		return nil, fmt.Errorf("controllerFinder is required")
+	}
+	return &workloadReplicasPlugin{controllerFinder: handle.ControllerFinder}, nil
+}
+
+func (p *workloadReplicasPlugin) Name() string {
+	return CostPluginWorkloadReplicas
+}
+
+func (p *workloadReplicasPlugin) Score(pod *corev1.Pod) int64 {
+	ownerRef := metav1.GetControllerOf(pod)
+	if ownerRef == nil {
+		// the bare Pod is a single replica
+		return -1
+	}
+	_, expectedReplicas, err := p.controllerFinder.GetPodsForRef(ownerRef, pod.Namespace, nil, false)
+	if err != nil || expectedReplicas <= 0 {
+		klog.V(4).InfoS("Failed to get expected replicas of workload", "pod", klog.KObj(pod), "err", err)
+		return -1
+	}
+	return -int64(expectedReplicas)
+}
+
+// podAgePlugin scores the Pod higher if it runs longer, since the long-running Pods usually have warmed up caches.
+type podAgePlugin struct {
+	clock clock.PassiveClock
+}
+
+func newPodAgePlugin(handle CostPluginHandle) (CostPlugin, error) {
+	return &podAgePlugin{clock: clock.RealClock{}}, nil
+}
+
+func (p *podAgePlugin) Name() string {
+	return CostPluginPodAge
+}
+
+func (p *podAgePlugin) Score(pod *corev1.Pod) int64 {
+	startTime := pod.CreationTimestamp.Time
+	if pod.Status.StartTime != nil {
+		startTime = pod.Status.StartTime.Time
+	}
+	return int64(p.clock.Since(startTime).Seconds())
+}
+
+// localPVPlugin scores the Pod higher if it is holding a local PersistentVolume, since the data will be lost
+// after the migration.
+type localPVPlugin struct {
+	client client.Client
+}
+
+func newLocalPVPlugin(handle CostPluginHandle) (CostPlugin, error) {
+	if handle.Client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	return &localPVPlugin{client: handle.Client}, nil
+}
+
+func (p *localPVPlugin) Name() string {
+	return CostPluginLocalPV
+}
+
+func (p *localPVPlugin) Score(pod *corev1.Pod) int64 {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := p.client.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}, pvc)
+		if err != nil || pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv := &corev1.PersistentVolume{}
+		if err = p.client.Get(context.TODO(), types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+			continue
+		}
+		if pv.Spec.Local != nil || pv.Spec.HostPath != nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+// recentMigrationsPlugin scores the Pod higher if its workload has been migrated more times recently, which are
+// counted from the succeeded PodMigrationJobs in the migration history.
+type recentMigrationsPlugin struct {
+	history *history.Store
+}
+
+func newRecentMigrationsPlugin(handle CostPluginHandle) (CostPlugin, error) {
+	if handle.MigrationHistory == nil {
+		return nil, fmt.Errorf("migrationHistory is required")
+	}
+	return &recentMigrationsPlugin{history: handle.MigrationHistory}, nil
+}
+
+func (p *recentMigrationsPlugin) Name() string {
+	return CostPluginRecentMigrations
+}
+
+func (p *recentMigrationsPlugin) Score(pod *corev1.Pod) int64 {
+	ownerRef := metav1.GetControllerOf(pod)
+	if ownerRef == nil {
+		return 0
+	}
+	return int64(p.history.CountMigrationsOfWorkload(ownerRef.UID, recentMigrationsWindow))
+}
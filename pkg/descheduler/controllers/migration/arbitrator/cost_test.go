@@ -0,0 +1,248 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/history"
+)
+
+type fakeCostPlugin struct {
+	name   string
+	scores map[string]int64
+}
+
+func (p *fakeCostPlugin) Name() string {
+	return p.name
+}
+
+func (p *fakeCostPlugin) Score(pod *corev1.Pod) int64 {
+	return p.scores[pod.Name]
+}
+
+func TestSortJobsByMigrationCost(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []WeightedCostPlugin
+		want    []string
+	}{
+		{
+			name: "single plugin",
+			plugins: []WeightedCostPlugin{
+				{CostPlugin: &fakeCostPlugin{name: "a", scores: map[string]int64{"pod-1": 30, "pod-2": 10, "pod-3": 20}}, Weight: 1},
+			},
+			want: []string{"pod-2", "pod-3", "pod-1"},
+		},
+		{
+			name: "plugin with the same scores keeps the order",
+			plugins: []WeightedCostPlugin{
+				{CostPlugin: &fakeCostPlugin{name: "a", scores: map[string]int64{"pod-1": 5, "pod-2": 5, "pod-3": 5}}, Weight: 1},
+			},
+			want: []string{"pod-1", "pod-2", "pod-3"},
+		},
+		{
+			name: "weighted plugins",
+			plugins: []WeightedCostPlugin{
+				// pod-1: 0, pod-2: 100, pod-3: 50
+				{CostPlugin: &fakeCostPlugin{name: "a", scores: map[string]int64{"pod-1": 0, "pod-2": 10, "pod-3": 5}}, Weight: 1},
+				// pod-1: 100, pod-2: 0, pod-3: 0
+				{CostPlugin: &fakeCostPlugin{name: "b", scores: map[string]int64{"pod-1": 1000, "pod-2": 0, "pod-3": 0}}, Weight: 3},
+			},
+			want: []string{"pod-3", "pod-2", "pod-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jobs []*v1alpha1.PodMigrationJob
+			podOfJob := map[*v1alpha1.PodMigrationJob]*corev1.Pod{}
+			for _, name := range []string{"pod-1", "pod-2", "pod-3"} {
+				job := &v1alpha1.PodMigrationJob{ObjectMeta: metav1.ObjectMeta{Name: name}}
+				jobs = append(jobs, job)
+				podOfJob[job] = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			}
+			jobs = SortJobsByMigrationCost(tt.plugins)(jobs, podOfJob)
+			var got []string
+			for _, job := range jobs {
+				got = append(got, job.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSortJobsByPodAndMigrationCost(t *testing.T) {
+	now := time.Now()
+	plugins := []WeightedCostPlugin{
+		{CostPlugin: &fakeCostPlugin{name: "a", scores: map[string]int64{"pod-1": 30, "pod-2": 10, "pod-3": 20, "pod-4": 0}}, Weight: 1},
+	}
+	var jobs []*v1alpha1.PodMigrationJob
+	podOfJob := map[*v1alpha1.PodMigrationJob]*corev1.Pod{}
+	for i, name := range []string{"pod-1", "pod-2", "pod-3", "pod-4"} {
+		job := &v1alpha1.PodMigrationJob{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		jobs = append(jobs, job)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(time.Duration(i) * time.Minute)),
+			},
+		}
+		if name == "pod-4" {
+			// the pod sorter still takes precedence over the migration cost
+			pod.Spec.Priority = pointer.Int32(100)
+		}
+		podOfJob[job] = pod
+	}
+
+	// the Pods are created at different times, and the migration cost decides the order before the creation time
+	jobs = SortJobsByPodAndMigrationCost(plugins)(jobs, podOfJob)
+	var got []string
+	for _, job := range jobs {
+		got = append(got, job.Name)
+	}
+	assert.Equal(t, []string{"pod-2", "pod-3", "pod-1", "pod-4"}, got)
+}
+
+func TestNewWeightedCostPlugins(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	handle := CostPluginHandle{
+		Client:           fake.NewClientBuilder().WithScheme(scheme).Build(),
+		ControllerFinder: &fakeControllerFinder{},
+		MigrationHistory: history.NewStore(time.Hour),
+	}
+	plugins, err := newWeightedCostPlugins([]config.MigrationCostPlugin{
+		{Name: CostPluginRestartCost, Weight: 1},
+		{Name: CostPluginWorkloadReplicas, Weight: 2},
+		{Name: CostPluginPodAge, Weight: 3},
+		{Name: CostPluginLocalPV, Weight: 4},
+		{Name: CostPluginRecentMigrations, Weight: 5},
+	}, handle)
+	assert.NoError(t, err)
+	assert.Len(t, plugins, 5)
+	for i, plugin := range plugins {
+		assert.Equal(t, int64(i+1), plugin.Weight)
+	}
+
+	_, err = newWeightedCostPlugins([]config.MigrationCostPlugin{{Name: "Unknown", Weight: 1}}, handle)
+	assert.Error(t, err)
+	_, err = newWeightedCostPlugins([]config.MigrationCostPlugin{{Name: CostPluginLocalPV, Weight: 1}}, CostPluginHandle{})
+	assert.Error(t, err)
+}
+
+func TestBuiltinCostPlugins(t *testing.T) {
+	now := time.Now()
+	owner := metav1.OwnerReference{
+		APIVersion: "apps/v1",
+		Kind:       "ReplicaSet",
+		Name:       "test-rs",
+		UID:        "test-rs-uid",
+		Controller: pointer.Bool(true),
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "test-pod",
+			CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+			OwnerReferences:   []metav1.OwnerReference{owner},
+			Annotations:       map[string]string{extension.AnnotationEvictionCost: "100"},
+		},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			StartTime: &metav1.Time{Time: now.Add(-time.Hour)},
+		},
+	}
+	barePod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "bare-pod",
+			CreationTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "local-pv"},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "local-pv"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					Local: &corev1.LocalVolumeSource{Path: "/mnt/data"},
+				},
+			},
+		},
+	).Build()
+
+	fakeClock := clocktesting.NewFakeClock(now)
+	migrationHistory := history.NewStoreWithClock(time.Hour, fakeClock)
+	migrationHistory.Add(&history.Record{JobUID: "job-1", OwnerUID: owner.UID, Timestamp: fakeClock.Now()})
+	fakeClock.Step(30 * time.Minute)
+	migrationHistory.Add(&history.Record{JobUID: "job-2", OwnerUID: owner.UID, Timestamp: fakeClock.Now()})
+
+	handle := CostPluginHandle{
+		Client:           fakeClient,
+		ControllerFinder: &fakeControllerFinder{replicas: 3},
+		MigrationHistory: migrationHistory,
+	}
+
+	restartCost, _ := newRestartCostPlugin(handle)
+	assert.Equal(t, int64(100), restartCost.Score(pod))
+	assert.Equal(t, int64(0), restartCost.Score(barePod))
+
+	workloadReplicas, _ := newWorkloadReplicasPlugin(handle)
+	assert.Equal(t, int64(-3), workloadReplicas.Score(pod))
+	assert.Equal(t, int64(-1), workloadReplicas.Score(barePod))
+
+	podAge, _ := newPodAgePlugin(handle)
+	podAge.(*podAgePlugin).clock = fakeClock
+	assert.Equal(t, int64(5400), podAge.Score(pod))
+	assert.Equal(t, int64(1860), podAge.Score(barePod))
+
+	localPV, _ := newLocalPVPlugin(handle)
+	assert.Equal(t, int64(1), localPV.Score(pod))
+	assert.Equal(t, int64(0), localPV.Score(barePod))
+
+	recentMigrations, _ := newRecentMigrationsPlugin(handle)
+	assert.Equal(t, int64(2), recentMigrations.Score(pod))
+	assert.Equal(t, int64(0), recentMigrations.Score(barePod))
+}
@@ -20,10 +20,13 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
@@ -36,14 +39,17 @@ import (
 	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/controllerfinder"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/gang"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/options"
 	evictionsutil "github.com/koordinator-sh/koordinator/pkg/descheduler/evictions"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/fieldindex"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/kubernetes/defaultevictor"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/metrics"
 	nodeutil "github.com/koordinator-sh/koordinator/pkg/descheduler/node"
 	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/history"
 	pkgutil "github.com/koordinator-sh/koordinator/pkg/util"
 	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
 )
@@ -62,6 +68,8 @@ type filter struct {
 
 	arbitratedPodMigrationJobs map[types.UID]bool
 	arbitratedMapLock          sync.Mutex
+
+	history *history.Store
 }
 
 func newEvictionGateSet(gates []deschedulerconfig.EvictionGate) map[deschedulerconfig.EvictionGate]struct{} {
@@ -96,6 +104,18 @@ func newFilter(args *deschedulerconfig.MigrationControllerArgs, handle framework
 		arbitratedPodMigrationJobs: map[types.UID]bool{},
 		skipEvictionGates:          newEvictionGateSet(args.SkipEvictionGates),
 	}
+	// the migration history serves the migration cooldowns and the RecentMigrations cost plugin
+	ttl := maxDuration(args.PodMigrationCooldown.Duration, args.WorkloadMigrationCooldown.Duration)
+	if args.ArbitrationArgs != nil {
+		for _, plugin := range args.ArbitrationArgs.CostPlugins {
+			if plugin.Name == CostPluginRecentMigrations {
+				ttl = maxDuration(ttl, recentMigrationsWindow)
+			}
+		}
+	}
+	if ttl > 0 {
+		f.history = history.NewStore(ttl)
+	}
 	if err := f.initFilters(args, handle); err != nil {
 		return nil, err
 	}
@@ -185,6 +205,9 @@ func (f *filter) initFilters(args *deschedulerconfig.MigrationControllerArgs, ha
 	if !f.isEvictionGateSkipped(deschedulerconfig.EvictionGateExpectedReplicas) {
 		wrapFilterFuncs = podutil.WrapFilterFuncs(wrapFilterFuncs, f.filterExpectedReplicas)
 	}
+	if args.GangMigrationPolicy == deschedulerconfig.GangMigrationPolicySkip {
+		wrapFilterFuncs = podutil.WrapFilterFuncs(wrapFilterFuncs, filterGangMember)
+	}
 	podFilter, err := podutil.NewOptions().
 		WithFilter(wrapFilterFuncs).
 		WithNamespaces(includedNamespaces).
@@ -207,6 +230,12 @@ func (f *filter) initFilters(args *deschedulerconfig.MigrationControllerArgs, ha
 		!f.isEvictionGateSkipped(deschedulerconfig.EvictionGateMaxUnavailablePerWorkload) {
 		retryableFilterFuncs = append(retryableFilterFuncs, f.filterMaxMigratingOrUnavailablePerWorkload)
 	}
+	if !f.isEvictionGateSkipped(deschedulerconfig.EvictionGatePodDisruptionBudget) {
+		retryableFilterFuncs = append(retryableFilterFuncs, f.filterPodDisruptionBudget)
+	}
+	if f.history != nil && !f.isEvictionGateSkipped(deschedulerconfig.EvictionGateMigrationCooldown) {
+		retryableFilterFuncs = append(retryableFilterFuncs, f.filterMigrationCooldown)
+	}
 
 	retryablePodFilters := podutil.WrapFilterFuncs(retryableFilterFuncs...)
 	f.retryablePodFilter = func(pod *corev1.Pod) bool {
@@ -493,6 +522,94 @@ func (f *filter) filterMaxMigratingOrUnavailablePerWorkload(pod *corev1.Pod) boo
 	return true
 }
 
+// filterPodDisruptionBudget reserves the disruption budget of the PDBs covering the Pod for the in-flight
+// PodMigrationJobs, so that the job which is bound to be rejected by the eviction API is not admitted.
+func (f *filter) filterPodDisruptionBudget(pod *corev1.Pod) bool {
+	if f.isEvictionGateSkipped(deschedulerconfig.EvictionGatePodDisruptionBudget) {
+		return true
+	}
+
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	err := f.client.List(context.TODO(), pdbList, client.InNamespace(pod.Namespace), utilclient.DisableDeepCopy)
+	if err != nil {
+		klog.Errorf("Failed to list PodDisruptionBudgets in namespace %s, err: %v", pod.Namespace, err)
+		return true
+	}
+	var pdbs []*policyv1.PodDisruptionBudget
+	var selectors []labels.Selector
+	for i := range pdbList.Items {
+		pdb := &pdbList.Items[i]
+		// an empty selector of policy/v1 matches all the Pods, while a nil selector matches none
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		pdbs = append(pdbs, pdb)
+		selectors = append(selectors, selector)
+	}
+	if len(pdbs) == 0 {
+		return true
+	}
+
+	var expectedPhaseContext []phaseContext
+	if checkPodArbitrating(pod) {
+		expectedPhaseContext = []phaseContext{
+			{phase: sev1alpha1.PodMigrationJobRunning, checkArbitration: false},
+			{phase: sev1alpha1.PodMigrationJobPending, checkArbitration: true},
+		}
+	}
+	migratingPods := sets.New[string]()
+	opts := &client.ListOptions{FieldSelector: fields.OneTermEqualSelector(fieldindex.IndexJobByPodNamespace, pod.Namespace)}
+	f.forEachAvailableMigrationJobs(opts, func(job *sev1alpha1.PodMigrationJob) bool {
+		podRef := job.Spec.PodRef
+		if podRef == nil || podRef.UID == pod.UID {
+			return true
+		}
+		migratingPods.Insert(podRef.Name)
+		return true
+	}, expectedPhaseContext...)
+
+	for i, pdb := range pdbs {
+		reserved := 0
+		if migratingPods.Len() > 0 {
+			reserved = f.countReservedDisruptions(pdb, selectors[i], migratingPods)
+		}
+		if int(pdb.Status.DisruptionsAllowed)-reserved <= 0 {
+			klog.V(4).InfoS("Pod fails the following checks", "pod", klog.KObj(pod), "checks", "podDisruptionBudget",
+				"pdb", klog.KObj(pdb), "disruptionsAllowed", pdb.Status.DisruptionsAllowed, "reserved", reserved)
+			return false
+		}
+	}
+	return true
+}
+
+// countReservedDisruptions counts the healthy Pods covered by the PDB which are being migrated. The Pods are
+// listed from the informer cache by the selector of the PDB rather than fetched one by one for each job.
+func (f *filter) countReservedDisruptions(pdb *policyv1.PodDisruptionBudget, selector labels.Selector, migratingPods sets.Set[string]) int {
+	podList := &corev1.PodList{}
+	err := f.client.List(context.TODO(), podList, client.InNamespace(pdb.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}, utilclient.DisableDeepCopy)
+	if err != nil {
+		klog.Errorf("Failed to list Pods of PodDisruptionBudget %s, err: %v", klog.KObj(pdb), err)
+		return 0
+	}
+	reserved := 0
+	for i := range podList.Items {
+		p := &podList.Items[i]
+		if !migratingPods.Has(p.Name) {
+			continue
+		}
+		// the Pods already evicted or unhealthy are accounted in the status of the PDB
+		if _, disrupted := pdb.Status.DisruptedPods[p.Name]; disrupted {
+			continue
+		}
+		if p.DeletionTimestamp == nil && kubecontroller.IsPodActive(p) && k8spodutil.IsPodReady(p) {
+			reserved++
+		}
+	}
+	return reserved
+}
+
 func (f *filter) filterExpectedReplicas(pod *corev1.Pod) bool {
 	if f.isEvictionGateSkipped(deschedulerconfig.EvictionGateExpectedReplicas) {
 		return true
@@ -550,6 +667,174 @@ func mergeUnavailableAndMigratingPods(unavailablePods, migratingPods map[types.N
 	}
 }
 
+// filterGangMember rejects the members of the coscheduling gangs, so that the gangs are not broken by the migrations.
+func filterGangMember(pod *corev1.Pod) bool {
+	if gang.GetGangID(pod) != "" {
+		klog.V(4).InfoS("Pod fails the following checks", "pod", klog.KObj(pod), "checks", "gangMember")
+		return false
+	}
+	return true
+}
+
+// filterGangWithinLimits rejects the gang whose members exceed the migration limits when they are migrated together.
+// The PodMigrationJobs of a gang migration are arbitrated one by one and none of the members is evicted until all of
+// them pass the arbitration, so such a gang would wait for the Reservations of the rejected members until the TTL.
+func (f *filter) filterGangWithinLimits(members []*corev1.Pod) bool {
+	if len(members) == 0 {
+		return true
+	}
+	exceeds := func(limit *int32, count int) bool {
+		return limit != nil && *limit > 0 && count > int(*limit)
+	}
+	if !f.isEvictionGateSkipped(deschedulerconfig.EvictionGateMaxMigratingGlobally) &&
+		exceeds(f.args.MaxMigratingGlobally, len(members)) {
+		klog.V(4).InfoS("Gang fails the following checks", "pod", klog.KObj(members[0]), "checks", "maxMigratingGlobally", "members", len(members))
+		return false
+	}
+	if !f.isEvictionGateSkipped(deschedulerconfig.EvictionGateMaxMigratingPerNamespace) &&
+		exceeds(f.args.MaxMigratingPerNamespace, len(members)) {
+		klog.V(4).InfoS("Gang fails the following checks", "pod", klog.KObj(members[0]), "checks", "maxMigratingPerNamespace", "members", len(members))
+		return false
+	}
+
+	membersOfNode := map[string]int{}
+	membersOfWorkload := map[types.UID][]*corev1.Pod{}
+	for _, member := range members {
+		membersOfNode[member.Spec.NodeName]++
+		if ownerRef := metav1.GetControllerOf(member); ownerRef != nil {
+			membersOfWorkload[ownerRef.UID] = append(membersOfWorkload[ownerRef.UID], member)
+		}
+	}
+	if !f.isEvictionGateSkipped(deschedulerconfig.EvictionGateMaxMigratingPerNode) {
+		for nodeName, count := range membersOfNode {
+			if exceeds(f.args.MaxMigratingPerNode, count) {
+				klog.V(4).InfoS("Gang fails the following checks", "pod", klog.KObj(members[0]), "checks", "maxMigratingPerNode", "node", nodeName, "members", count)
+				return false
+			}
+		}
+	}
+	for _, workloadMembers := range membersOfWorkload {
+		if !f.checkGangWithinWorkloadLimits(workloadMembers) {
+			return false
+		}
+	}
+	return f.checkGangWithinPodDisruptionBudgets(members)
+}
+
+func (f *filter) checkGangWithinWorkloadLimits(members []*corev1.Pod) bool {
+	skipMaxMigratingPerWorkload := f.isEvictionGateSkipped(deschedulerconfig.EvictionGateMaxMigratingPerWorkload)
+	skipMaxUnavailablePerWorkload := f.isEvictionGateSkipped(deschedulerconfig.EvictionGateMaxUnavailablePerWorkload)
+	if skipMaxMigratingPerWorkload && skipMaxUnavailablePerWorkload {
+		return true
+	}
+	ownerRef := metav1.GetControllerOf(members[0])
+	pods, expectedReplicas, err := f.controllerFinder.GetPodsForRef(ownerRef, members[0].Namespace, nil, false)
+	if err != nil {
+		klog.Errorf("Failed to get Pods of workload %s/%s, err: %v", ownerRef.Kind, ownerRef.Name, err)
+		return false
+	}
+	if !skipMaxMigratingPerWorkload {
+		maxMigrating, err := util.GetMaxMigrating(int(expectedReplicas), f.args.MaxMigratingPerWorkload)
+		if err != nil {
+			return false
+		}
+		if len(members) > maxMigrating {
+			klog.V(4).InfoS("Gang fails the following checks", "pod", klog.KObj(members[0]), "checks", "maxMigratingPerWorkload",
+				"owner", fmt.Sprintf("%s/%s/%s(%s)", ownerRef.Name, ownerRef.Kind, ownerRef.APIVersion, ownerRef.UID),
+				"members", len(members), "maxMigratingPerWorkload", maxMigrating)
+			return false
+		}
+	}
+	if !skipMaxUnavailablePerWorkload {
+		maxUnavailable, err := util.GetMaxUnavailable(int(expectedReplicas), f.args.MaxUnavailablePerWorkload)
+		if err != nil {
+			return false
+		}
+		unavailablePods := f.getUnavailablePods(pods)
+		for _, member := range members {
+			unavailablePods[types.NamespacedName{Namespace: member.Namespace, Name: member.Name}] = struct{}{}
+		}
+		if len(unavailablePods) > maxUnavailable {
+			klog.V(4).InfoS("Gang fails the following checks", "pod", klog.KObj(members[0]), "checks", "maxUnavailablePerWorkload",
+				"owner", fmt.Sprintf("%s/%s/%s(%s)", ownerRef.Name, ownerRef.Kind, ownerRef.APIVersion, ownerRef.UID),
+				"unavailablePods", len(unavailablePods), "maxUnavailablePerWorkload", maxUnavailable)
+			return false
+		}
+	}
+	return true
+}
+
+func (f *filter) checkGangWithinPodDisruptionBudgets(members []*corev1.Pod) bool {
+	if f.isEvictionGateSkipped(deschedulerconfig.EvictionGatePodDisruptionBudget) {
+		return true
+	}
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	err := f.client.List(context.TODO(), pdbList, client.InNamespace(members[0].Namespace), utilclient.DisableDeepCopy)
+	if err != nil {
+		klog.Errorf("Failed to list PodDisruptionBudgets in namespace %s, err: %v", members[0].Namespace, err)
+		return true
+	}
+	for i := range pdbList.Items {
+		pdb := &pdbList.Items[i]
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		count := 0
+		for _, member := range members {
+			if _, disrupted := pdb.Status.DisruptedPods[member.Name]; !disrupted && selector.Matches(labels.Set(member.Labels)) {
+				count++
+			}
+		}
+		if count > 0 && count > int(pdb.Status.DisruptionsAllowed) {
+			klog.V(4).InfoS("Gang fails the following checks", "pod", klog.KObj(members[0]), "checks", "podDisruptionBudget",
+				"pdb", klog.KObj(pdb), "disruptionsAllowed", pdb.Status.DisruptionsAllowed, "members", count)
+			return false
+		}
+	}
+	return true
+}
+
+// filterMigrationCooldown rejects the Pod if the Pod or its workload was migrated within the cooldown, so that
+// the same Pods are not moved repeatedly.
+func (f *filter) filterMigrationCooldown(pod *corev1.Pod) bool {
+	if f.history == nil {
+		return true
+	}
+	if cooldown := f.args.PodMigrationCooldown.Duration; cooldown > 0 {
+		if last, ok := f.history.LastMigrationOfPod(pod); ok && f.clock.Since(last) < cooldown {
+			klog.V(4).InfoS("Pod fails the following checks", "pod", klog.KObj(pod), "checks", "podMigrationCooldown", "lastMigration", last)
+			metrics.MigrationCooldownHits.WithLabelValues(metrics.MigrationCooldownKindPod).Inc()
+			return false
+		}
+	}
+	if cooldown := f.args.WorkloadMigrationCooldown.Duration; cooldown > 0 {
+		if ownerRef := metav1.GetControllerOf(pod); ownerRef != nil {
+			if last, ok := f.history.LastMigrationOfWorkload(ownerRef.UID); ok && f.clock.Since(last) < cooldown {
+				klog.V(4).InfoS("Pod fails the following checks", "pod", klog.KObj(pod), "checks", "workloadMigrationCooldown",
+					"owner", fmt.Sprintf("%s/%s/%s", ownerRef.Kind, ownerRef.Name, ownerRef.UID), "lastMigration", last)
+				metrics.MigrationCooldownHits.WithLabelValues(metrics.MigrationCooldownKindWorkload).Inc()
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// recordMigration records the succeeded PodMigrationJob into the migration history.
+func (f *filter) recordMigration(job *sev1alpha1.PodMigrationJob) {
+	if f != nil && f.history != nil {
+		f.history.AddPodMigrationJob(job)
+	}
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func (f *filter) checkJobPassedArbitration(uid types.UID) bool {
 	f.arbitratedMapLock.Lock()
 	defer f.arbitratedMapLock.Unlock()
@@ -24,6 +24,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -34,8 +35,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/history"
 )
 
 func TestFilterExistingMigrationJob(t *testing.T) {
@@ -1429,6 +1432,202 @@ func TestFilterExpectedReplicas(t *testing.T) {
 	}
 }
 
+func TestFilterPodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		name               string
+		noPDB              bool
+		emptySelector      bool
+		nilSelector        bool
+		disruptionsAllowed int32
+		numMigratingPods   int
+		migratingLabels    map[string]string
+		migratingNotReady  bool
+		disrupted          bool
+		skipGate           bool
+		want               bool
+	}{
+		{
+			name:  "no PDB",
+			noPDB: true,
+			want:  true,
+		},
+		{
+			name:               "no disruptions allowed",
+			disruptionsAllowed: 0,
+			want:               false,
+		},
+		{
+			name:               "disruptionsAllowed=1 no migrating Pods",
+			disruptionsAllowed: 1,
+			want:               true,
+		},
+		{
+			name:               "disruptionsAllowed=1 one migrating Pod reserves the budget",
+			disruptionsAllowed: 1,
+			numMigratingPods:   1,
+			want:               false,
+		},
+		{
+			name:               "disruptionsAllowed=2 one migrating Pod",
+			disruptionsAllowed: 2,
+			numMigratingPods:   1,
+			want:               true,
+		},
+		{
+			name:               "disruptionsAllowed=1 migrating Pod not covered by the PDB",
+			disruptionsAllowed: 1,
+			numMigratingPods:   1,
+			migratingLabels:    map[string]string{"app": "other"},
+			want:               true,
+		},
+		{
+			name:               "disruptionsAllowed=1 migrating Pod already disrupted",
+			disruptionsAllowed: 1,
+			numMigratingPods:   1,
+			disrupted:          true,
+			want:               true,
+		},
+		{
+			name:               "disruptionsAllowed=1 migrating Pod not ready",
+			disruptionsAllowed: 1,
+			numMigratingPods:   1,
+			migratingNotReady:  true,
+			want:               true,
+		},
+		{
+			name:               "skip gate PodDisruptionBudget",
+			disruptionsAllowed: 0,
+			skipGate:           true,
+			want:               true,
+		},
+		{
+			name:               "empty selector covers all Pods",
+			emptySelector:      true,
+			disruptionsAllowed: 0,
+			want:               false,
+		},
+		{
+			name:               "empty selector one migrating Pod reserves the budget",
+			emptySelector:      true,
+			disruptionsAllowed: 1,
+			numMigratingPods:   1,
+			migratingLabels:    map[string]string{"app": "other"},
+			want:               false,
+		},
+		{
+			name:               "nil selector covers no Pod",
+			nilSelector:        true,
+			disruptionsAllowed: 0,
+			want:               true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+			_ = clientgoscheme.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithIndex(&v1alpha1.PodMigrationJob{}, "job.pod.uid", func(obj client.Object) []string {
+					pmj := obj.(*v1alpha1.PodMigrationJob)
+					return []string{string(pmj.Spec.PodRef.UID)}
+				}).
+				WithIndex(&v1alpha1.PodMigrationJob{}, "job.pod.namespace", func(obj client.Object) []string {
+					pmj := obj.(*v1alpha1.PodMigrationJob)
+					return []string{pmj.Spec.PodRef.Namespace}
+				}).
+				Build()
+			a := filter{client: fakeClient, args: &config.MigrationControllerArgs{}, arbitratedPodMigrationJobs: map[types.UID]bool{}}
+			if tt.skipGate {
+				a.skipEvictionGates = map[config.EvictionGate]struct{}{config.EvictionGatePodDisruptionBudget: {}}
+			}
+
+			pdb := &policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pdb",
+				},
+				Spec: policyv1.PodDisruptionBudgetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "test"},
+					},
+				},
+				Status: policyv1.PodDisruptionBudgetStatus{
+					DisruptionsAllowed: tt.disruptionsAllowed,
+					DisruptedPods:      map[string]metav1.Time{},
+				},
+			}
+			if tt.emptySelector {
+				pdb.Spec.Selector = &metav1.LabelSelector{}
+			} else if tt.nilSelector {
+				pdb.Spec.Selector = nil
+			}
+
+			for i := 0; i < tt.numMigratingPods; i++ {
+				podLabels := tt.migratingLabels
+				if podLabels == nil {
+					podLabels = map[string]string{"app": "test"}
+				}
+				readyStatus := corev1.ConditionTrue
+				if tt.migratingNotReady {
+					readyStatus = corev1.ConditionFalse
+				}
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "default",
+						Name:      fmt.Sprintf("test-migrating-pod-%d", i),
+						UID:       uuid.NewUUID(),
+						Labels:    podLabels,
+					},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodRunning,
+						Conditions: []corev1.PodCondition{
+							{Type: corev1.PodReady, Status: readyStatus},
+						},
+					},
+				}
+				assert.Nil(t, a.client.Create(context.TODO(), pod))
+				if tt.disrupted {
+					pdb.Status.DisruptedPods[pod.Name] = metav1.Now()
+				}
+
+				job := &v1alpha1.PodMigrationJob{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              fmt.Sprintf("test-%d", i),
+						CreationTimestamp: metav1.Time{Time: time.Now()},
+						UID:               uuid.NewUUID(),
+					},
+					Spec: v1alpha1.PodMigrationJobSpec{
+						PodRef: &corev1.ObjectReference{
+							Namespace: pod.Namespace,
+							Name:      pod.Name,
+							UID:       pod.UID,
+						},
+					},
+				}
+				assert.Nil(t, a.client.Create(context.TODO(), job))
+			}
+			if !tt.noPDB {
+				assert.Nil(t, a.client.Create(context.TODO(), pdb))
+			}
+
+			filterPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+					UID:       uuid.NewUUID(),
+					Labels:    map[string]string{"app": "test"},
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+				},
+			}
+			got := a.filterPodDisruptionBudget(filterPod)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestArbitratedMap(t *testing.T) {
 	f := filter{
 		arbitratedPodMigrationJobs: map[types.UID]bool{},
@@ -1448,3 +1647,223 @@ func TestArbitratedMap(t *testing.T) {
 	f.removeJobPassedArbitration(job.UID)
 	assert.False(t, f.checkJobPassedArbitration(job.UID))
 }
+
+func TestFilterMigrationCooldown(t *testing.T) {
+	owner := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test-rs", UID: "rs-uid", Controller: ptr.To(true)}
+	newSucceededJob := func(podName string, podUID types.UID, ownerUID types.UID, completedAgo time.Duration) *v1alpha1.PodMigrationJob {
+		job := &v1alpha1.PodMigrationJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        string(uuid.NewUUID()),
+				UID:         uuid.NewUUID(),
+				Annotations: map[string]string{},
+			},
+			Spec: v1alpha1.PodMigrationJobSpec{
+				PodRef: &corev1.ObjectReference{Namespace: "default", Name: podName, UID: podUID},
+			},
+			Status: v1alpha1.PodMigrationJobStatus{
+				Phase:    v1alpha1.PodMigrationJobSucceeded,
+				NodeName: "node-2",
+				PodRef:   &corev1.ObjectReference{Namespace: "default", Name: podName + "-new", UID: podUID + "-new"},
+				Conditions: []v1alpha1.PodMigrationJobCondition{
+					{
+						Type:               v1alpha1.PodMigrationJobConditionEviction,
+						Status:             v1alpha1.PodMigrationJobConditionStatusTrue,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-completedAgo)),
+					},
+				},
+			},
+		}
+		if ownerUID != "" {
+			job.Annotations[history.AnnotationPodOwnerUID] = string(ownerUID)
+		}
+		return job
+	}
+
+	tests := []struct {
+		name             string
+		podCooldown      time.Duration
+		workloadCooldown time.Duration
+		jobs             []*v1alpha1.PodMigrationJob
+		pod              *corev1.Pod
+		want             bool
+	}{
+		{
+			name:        "no migration history",
+			podCooldown: time.Hour,
+			pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1", UID: "pod-1-uid"}},
+			want:        true,
+		},
+		{
+			name:        "pod created by a recent migration",
+			podCooldown: time.Hour,
+			jobs:        []*v1alpha1.PodMigrationJob{newSucceededJob("pod-1", "pod-1-uid", "", 10*time.Minute)},
+			pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1-new", UID: "pod-1-uid-new"}},
+			want:        false,
+		},
+		{
+			name:        "pod with the same name as a recently migrated pod",
+			podCooldown: time.Hour,
+			jobs:        []*v1alpha1.PodMigrationJob{newSucceededJob("web-0", "web-0-uid", "", 10*time.Minute)},
+			pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-0", UID: "web-0-uid-2"}},
+			want:        false,
+		},
+		{
+			name:        "pod cooldown expired",
+			podCooldown: 5 * time.Minute,
+			jobs:        []*v1alpha1.PodMigrationJob{newSucceededJob("pod-1", "pod-1-uid", "", 10*time.Minute)},
+			pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1-new", UID: "pod-1-uid-new"}},
+			want:        true,
+		},
+		{
+			name:             "another pod of the workload migrated recently",
+			workloadCooldown: time.Hour,
+			jobs:             []*v1alpha1.PodMigrationJob{newSucceededJob("pod-1", "pod-1-uid", "rs-uid", 10*time.Minute)},
+			pod:              &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-2", UID: "pod-2-uid", OwnerReferences: []metav1.OwnerReference{owner}}},
+			want:             false,
+		},
+		{
+			name:             "pod of another workload",
+			workloadCooldown: time.Hour,
+			jobs:             []*v1alpha1.PodMigrationJob{newSucceededJob("pod-1", "pod-1-uid", "other-rs-uid", 10*time.Minute)},
+			pod:              &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-2", UID: "pod-2-uid", OwnerReferences: []metav1.OwnerReference{owner}}},
+			want:             true,
+		},
+		{
+			name:             "workload cooldown expired while pod cooldown is longer",
+			podCooldown:      time.Hour,
+			workloadCooldown: 5 * time.Minute,
+			jobs:             []*v1alpha1.PodMigrationJob{newSucceededJob("pod-1", "pod-1-uid", "rs-uid", 10*time.Minute)},
+			pod:              &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-2", UID: "pod-2-uid", OwnerReferences: []metav1.OwnerReference{owner}}},
+			want:             true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := &config.MigrationControllerArgs{
+				PodMigrationCooldown:      metav1.Duration{Duration: tt.podCooldown},
+				WorkloadMigrationCooldown: metav1.Duration{Duration: tt.workloadCooldown},
+			}
+			f := &filter{
+				args:                       args,
+				arbitratedPodMigrationJobs: map[types.UID]bool{},
+				history:                    history.NewStore(maxDuration(tt.podCooldown, tt.workloadCooldown)),
+			}
+			for _, job := range tt.jobs {
+				f.recordMigration(job)
+			}
+			assert.Equal(t, tt.want, f.filterMigrationCooldown(tt.pod))
+		})
+	}
+}
+
+func TestFilterGangMember(t *testing.T) {
+	assert.True(t, filterGangMember(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"}}))
+	assert.False(t, filterGangMember(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "test-pod",
+			Annotations: map[string]string{extension.AnnotationGangName: "test-gang"},
+		},
+	}))
+}
+
+func TestFilterGangWithinLimits(t *testing.T) {
+	newMember := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				UID:         uuid.NewUUID(),
+				Labels:      map[string]string{"app": "test"},
+				Annotations: map[string]string{extension.AnnotationGangName: "test-gang"},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+	members := []*corev1.Pod{
+		newMember("worker-0", "node-1"),
+		newMember("worker-1", "node-1"),
+		newMember("worker-2", "node-2"),
+	}
+
+	tests := []struct {
+		name                     string
+		maxMigratingPerNamespace *int32
+		maxMigratingPerNode      *int32
+		disruptionsAllowed       *int32
+		pdbSelector              *metav1.LabelSelector
+		skipEvictionGates        []config.EvictionGate
+		want                     bool
+	}{
+		{
+			name: "no limits",
+			want: true,
+		},
+		{
+			name:                     "within maxMigratingPerNamespace",
+			maxMigratingPerNamespace: ptr.To[int32](3),
+			want:                     true,
+		},
+		{
+			name:                     "exceed maxMigratingPerNamespace",
+			maxMigratingPerNamespace: ptr.To[int32](2),
+			want:                     false,
+		},
+		{
+			name:                     "skip gate MaxMigratingPerNamespace",
+			maxMigratingPerNamespace: ptr.To[int32](2),
+			skipEvictionGates:        []config.EvictionGate{config.EvictionGateMaxMigratingPerNamespace},
+			want:                     true,
+		},
+		{
+			name:                "exceed maxMigratingPerNode",
+			maxMigratingPerNode: ptr.To[int32](1),
+			want:                false,
+		},
+		{
+			name:               "within PDB",
+			disruptionsAllowed: ptr.To[int32](3),
+			want:               true,
+		},
+		{
+			name:               "exceed PDB",
+			disruptionsAllowed: ptr.To[int32](2),
+			want:               false,
+		},
+		{
+			name:               "exceed PDB with empty selector",
+			disruptionsAllowed: ptr.To[int32](2),
+			pdbSelector:        &metav1.LabelSelector{},
+			want:               false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			if tt.disruptionsAllowed != nil {
+				selector := tt.pdbSelector
+				if selector == nil {
+					selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}}
+				}
+				assert.NoError(t, fakeClient.Create(context.TODO(), &policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pdb"},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						Selector: selector,
+					},
+					Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: *tt.disruptionsAllowed},
+				}))
+			}
+			f := &filter{
+				client: fakeClient,
+				args: &config.MigrationControllerArgs{
+					MaxMigratingPerNamespace: tt.maxMigratingPerNamespace,
+					MaxMigratingPerNode:      tt.maxMigratingPerNode,
+				},
+				skipEvictionGates: newEvictionGateSet(tt.skipEvictionGates),
+			}
+			assert.Equal(t, tt.want, f.filterGangWithinLimits(members))
+		})
+	}
+}
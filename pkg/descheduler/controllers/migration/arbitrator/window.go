@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+)
+
+type migrationWindowScope int
+
+const (
+	migrationWindowScopeCluster migrationWindowScope = iota
+	migrationWindowScopeNamespace
+	migrationWindowScopeWorkload
+)
+
+type migrationWindow struct {
+	schedule   cron.Schedule
+	duration   time.Duration
+	location   *time.Location
+	namespaces sets.String
+	selector   labels.Selector
+}
+
+// migrationWindows decides whether a Pod is allowed to be migrated at a given time.
+type migrationWindows struct {
+	windows []*migrationWindow
+}
+
+func newMigrationWindows(args []deschedulerconfig.MigrationWindow) (*migrationWindows, error) {
+	w := &migrationWindows{}
+	for i := range args {
+		schedule, err := cron.ParseStandard(args[i].Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q of migration window, err: %w", args[i].Schedule, err)
+		}
+		location := time.Local
+		if args[i].TimeZone != "" {
+			location, err = time.LoadLocation(args[i].TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeZone %q of migration window, err: %w", args[i].TimeZone, err)
+			}
+		}
+		window := &migrationWindow{
+			schedule: schedule,
+			duration: args[i].Duration.Duration,
+			location: location,
+		}
+		if len(args[i].Namespaces) > 0 {
+			window.namespaces = sets.NewString(args[i].Namespaces...)
+		}
+		if args[i].PodSelector != nil {
+			window.selector, err = metav1.LabelSelectorAsSelector(args[i].PodSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid podSelector of migration window, err: %w", err)
+			}
+		}
+		w.windows = append(w.windows, window)
+	}
+	return w, nil
+}
+
+func (w *migrationWindow) scope() migrationWindowScope {
+	if w.selector != nil {
+		return migrationWindowScopeWorkload
+	}
+	if w.namespaces != nil {
+		return migrationWindowScopeNamespace
+	}
+	return migrationWindowScopeCluster
+}
+
+func (w *migrationWindow) matches(pod *corev1.Pod) bool {
+	if w.namespaces != nil && !w.namespaces.Has(pod.Namespace) {
+		return false
+	}
+	if w.selector != nil && !w.selector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+	return true
+}
+
+// isOpen checks if the window is open at now, and returns the next open time if not.
+func (w *migrationWindow) isOpen(now time.Time) (bool, time.Time) {
+	now = now.In(w.location)
+	// the latest activation before now should be within the duration
+	if start := w.schedule.Next(now.Add(-w.duration)); !start.After(now) {
+		return true, time.Time{}
+	}
+	return false, w.schedule.Next(now)
+}
+
+// isOpen checks if the most specific windows matched the Pod are open at now. It returns true if no window matches
+// the Pod. Otherwise, it returns the earliest next open time of the matched windows.
+func (w *migrationWindows) isOpen(pod *corev1.Pod, now time.Time) (bool, time.Time) {
+	if w == nil || len(w.windows) == 0 {
+		return true, time.Time{}
+	}
+	var matched []*migrationWindow
+	scope := migrationWindowScopeCluster
+	for _, window := range w.windows {
+		if !window.matches(pod) {
+			continue
+		}
+		if s := window.scope(); s > scope {
+			scope = s
+			matched = matched[:0]
+		} else if s < scope {
+			continue
+		}
+		matched = append(matched, window)
+	}
+	if len(matched) == 0 {
+		return true, time.Time{}
+	}
+
+	var nextOpen time.Time
+	for _, window := range matched {
+		open, next := window.isOpen(now)
+		if open {
+			return true, time.Time{}
+		}
+		if nextOpen.IsZero() || next.Before(nextOpen) {
+			nextOpen = next
+		}
+	}
+	return false, nextOpen
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+)
+
+func TestMigrationWindowsIsOpen(t *testing.T) {
+	now := time.Date(2024, 1, 1, 3, 30, 0, 0, time.UTC)
+	clusterWindow := deschedulerconfig.MigrationWindow{
+		Schedule: "0 2 * * *",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone: "UTC",
+	}
+	namespaceWindow := deschedulerconfig.MigrationWindow{
+		Schedule:   "0 22 * * *",
+		Duration:   metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone:   "UTC",
+		Namespaces: []string{"prod"},
+	}
+	workloadWindow := deschedulerconfig.MigrationWindow{
+		Schedule:    "0 3 * * *",
+		Duration:    metav1.Duration{Duration: time.Hour},
+		TimeZone:    "UTC",
+		Namespaces:  []string{"prod"},
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "batch"}},
+	}
+	tests := []struct {
+		name         string
+		windows      []deschedulerconfig.MigrationWindow
+		namespace    string
+		labels       map[string]string
+		wantOpen     bool
+		wantNextOpen time.Time
+	}{
+		{
+			name:     "no windows",
+			wantOpen: true,
+		},
+		{
+			name:      "cluster window is open",
+			windows:   []deschedulerconfig.MigrationWindow{clusterWindow},
+			namespace: "default",
+			wantOpen:  true,
+		},
+		{
+			name: "cluster window is closed",
+			windows: []deschedulerconfig.MigrationWindow{
+				{
+					Schedule: "0 2 * * *",
+					Duration: metav1.Duration{Duration: time.Hour},
+					TimeZone: "UTC",
+				},
+			},
+			namespace:    "default",
+			wantOpen:     false,
+			wantNextOpen: time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "schedule is interpreted in the time zone",
+			windows: []deschedulerconfig.MigrationWindow{
+				{
+					Schedule: "0 2 * * *",
+					Duration: metav1.Duration{Duration: 2 * time.Hour},
+					TimeZone: "Asia/Shanghai",
+				},
+			},
+			namespace:    "default",
+			wantOpen:     false,
+			wantNextOpen: time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "namespace window overrides cluster window",
+			windows:      []deschedulerconfig.MigrationWindow{clusterWindow, namespaceWindow},
+			namespace:    "prod",
+			wantOpen:     false,
+			wantNextOpen: time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "namespace window does not apply to other namespaces",
+			windows:   []deschedulerconfig.MigrationWindow{clusterWindow, namespaceWindow},
+			namespace: "default",
+			wantOpen:  true,
+		},
+		{
+			name:      "workload window overrides namespace window",
+			windows:   []deschedulerconfig.MigrationWindow{clusterWindow, namespaceWindow, workloadWindow},
+			namespace: "prod",
+			labels:    map[string]string{"app": "batch"},
+			wantOpen:  true,
+		},
+		{
+			name: "any of the most specific windows is open",
+			windows: []deschedulerconfig.MigrationWindow{
+				namespaceWindow,
+				{
+					Schedule:   "30 3 * * 1",
+					Duration:   metav1.Duration{Duration: time.Minute},
+					TimeZone:   "UTC",
+					Namespaces: []string{"prod"},
+				},
+			},
+			namespace: "prod",
+			wantOpen:  true,
+		},
+		{
+			name:      "no window matches the pod",
+			windows:   []deschedulerconfig.MigrationWindow{namespaceWindow, workloadWindow},
+			namespace: "default",
+			labels:    map[string]string{"app": "batch"},
+			wantOpen:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := newMigrationWindows(tt.windows)
+			assert.NoError(t, err)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: tt.namespace,
+					Name:      "test-pod",
+					Labels:    tt.labels,
+				},
+			}
+			open, nextOpen := windows.isOpen(pod, now)
+			assert.Equal(t, tt.wantOpen, open)
+			assert.True(t, tt.wantNextOpen.Equal(nextOpen), "want next open %v, got %v", tt.wantNextOpen, nextOpen)
+		})
+	}
+}
+
+func TestNewMigrationWindowsInvalid(t *testing.T) {
+	_, err := newMigrationWindows([]deschedulerconfig.MigrationWindow{{Schedule: "invalid"}})
+	assert.Error(t, err)
+	_, err = newMigrationWindows([]deschedulerconfig.MigrationWindow{{Schedule: "0 2 * * *", TimeZone: "No/Such/Zone"}})
+	assert.Error(t, err)
+}
@@ -51,6 +51,8 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/arbitrator"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/controllerfinder"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/gang"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/prehook"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/reservation"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/names"
@@ -78,6 +80,7 @@ type Reconciler struct {
 	eventRecorder          events.EventRecorder
 	reservationInterpreter reservation.Interpreter
 	evictorInterpreter     evictor.Interpreter
+	preEvictionHooks       prehook.Interface
 	controllerFinder       controllerfinder.Interface
 	assumedCache           *assumedCache
 	clock                  clock.Clock
@@ -156,6 +159,13 @@ func newReconciler(args *deschedulerconfig.MigrationControllerArgs, handle frame
 	if err != nil {
 		return nil, err
 	}
+	var preEvictionHooks prehook.Interface
+	if len(args.PreEvictionHooks) > 0 {
+		preEvictionHooks, err = prehook.New(args.PreEvictionHooks)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	r := &Reconciler{
 		Client:                 manager.GetClient(),
@@ -163,6 +173,7 @@ func newReconciler(args *deschedulerconfig.MigrationControllerArgs, handle frame
 		eventRecorder:          handle.EventRecorder(),
 		reservationInterpreter: reservationInterpreter,
 		evictorInterpreter:     evictorInterpreter,
+		preEvictionHooks:       preEvictionHooks,
 		controllerFinder:       controllerFinder,
 		assumedCache:           newAssumedCache(),
 		clock:                  clock.RealClock{},
@@ -209,7 +220,7 @@ func (r *Reconciler) doScavenge() {
 		if v.Spec.TTL != nil && v.Spec.TTL.Duration > 0 {
 			timeoutDuration = v.Spec.TTL.Duration + 5*time.Minute
 		}
-		if r.clock.Since(v.CreationTimestamp.Time) < timeoutDuration {
+		if util.IsWaitingForMigrationWindow(v) || r.clock.Since(util.GetMigrationStartTime(v)) < timeoutDuration {
 			continue
 		}
 		if err := r.deleteReservation(context.TODO(), v); err != nil {
@@ -229,6 +240,7 @@ func (r *Reconciler) doScavenge() {
 // +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=podmigrationjobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=podmigrationjobs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=reservations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a PodMigrationJob object and makes changes based on the state read
 // and what is in the Spec
@@ -288,6 +300,10 @@ func (r *Reconciler) doMigrate(ctx context.Context, job *sev1alpha1.PodMigration
 	if job.Spec.Paused {
 		return reconcile.Result{}, nil
 	}
+	if util.IsWaitingForMigrationWindow(job) {
+		klog.V(4).Infof("MigrationJob %s is waiting for the migration window", job.Name)
+		return reconcile.Result{}, nil
+	}
 
 	if job.Status.Phase != "" &&
 		job.Status.Phase != sev1alpha1.PodMigrationJobPending &&
@@ -378,6 +394,20 @@ func (r *Reconciler) doMigrate(ctx context.Context, job *sev1alpha1.PodMigration
 	}
 
 	klog.V(4).Infof("MigrationJob %s processes scheduled Pod %s/%s", job.Name, job.Spec.PodRef.Namespace, job.Spec.PodRef.Name)
+	gangReady, result, err := r.waitForGangReservations(ctx, job)
+	if err != nil {
+		return result, err
+	} else if !gangReady {
+		return result, nil
+	}
+
+	hookReady, result, err := r.runPreEvictionHook(ctx, job)
+	if err != nil {
+		return result, err
+	} else if !hookReady {
+		return result, nil
+	}
+
 	evictComplete, result, err := r.evictPod(ctx, job)
 	if err != nil {
 		return result, err
@@ -547,7 +577,7 @@ func (r *Reconciler) abortJobIfTimeout(ctx context.Context, job *sev1alpha1.PodM
 	}
 
 	timeout := job.Spec.TTL.Duration
-	elapsed := r.clock.Since(job.CreationTimestamp.Time)
+	elapsed := r.clock.Since(util.GetMigrationStartTime(job))
 	if elapsed < timeout {
 		return false, nil
 	}
@@ -765,6 +795,13 @@ func (r *Reconciler) waitForPodReady(ctx context.Context, job *sev1alpha1.PodMig
 func (r *Reconciler) evictPodDirectly(ctx context.Context, job *sev1alpha1.PodMigrationJob) (reconcile.Result, error) {
 	podNamespacedName := types.NamespacedName{Namespace: job.Spec.PodRef.Namespace, Name: job.Spec.PodRef.Name}
 	klog.V(4).Infof("MigrationJob %s try to evict Pod %q directly", job.Name, podNamespacedName)
+	hookReady, result, err := r.runPreEvictionHook(ctx, job)
+	if err != nil {
+		return result, err
+	} else if !hookReady {
+		return result, nil
+	}
+
 	complete, result, err := r.evictPod(ctx, job)
 	if err != nil {
 		return result, err
@@ -780,6 +817,179 @@ func (r *Reconciler) evictPodDirectly(ctx context.Context, job *sev1alpha1.PodMi
 	return reconcile.Result{}, err
 }
 
+// waitForGangReservations holds the eviction of a gang member until the Reservations of all the members of the gang
+// migration are scheduled, and aborts the job if the migration of any other member has failed.
+func (r *Reconciler) waitForGangReservations(ctx context.Context, job *sev1alpha1.PodMigrationJob) (bool, reconcile.Result, error) {
+	migrationID, members := gang.GetGangMigration(job)
+	if migrationID == "" {
+		return true, reconcile.Result{}, nil
+	}
+	_, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionGangReservationScheduled)
+	if cond != nil && cond.Status == sev1alpha1.PodMigrationJobConditionStatusTrue {
+		return true, reconcile.Result{}, nil
+	}
+	if _, evictionCond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction); evictionCond != nil {
+		return true, reconcile.Result{}, nil
+	}
+
+	jobList := &sev1alpha1.PodMigrationJobList{}
+	if err := r.Client.List(ctx, jobList, utilclient.DisableDeepCopy); err != nil {
+		return false, reconcile.Result{}, err
+	}
+	jobs := []*sev1alpha1.PodMigrationJob{job}
+	for i := range jobList.Items {
+		v := &jobList.Items[i]
+		if id, _ := gang.GetGangMigration(v); id == migrationID && v.UID != job.UID {
+			jobs = append(jobs, v)
+		}
+	}
+	scheduled, failed := gang.CheckReservations(jobs)
+	if failed != nil {
+		err := r.abortJobByGangMigrationAborted(ctx, job, failed)
+		return false, reconcile.Result{}, err
+	}
+	if scheduled < members {
+		cond = &sev1alpha1.PodMigrationJobCondition{
+			Type:    sev1alpha1.PodMigrationJobConditionGangReservationScheduled,
+			Status:  sev1alpha1.PodMigrationJobConditionStatusFalse,
+			Reason:  sev1alpha1.PodMigrationJobReasonWaitForGangReservations,
+			Message: fmt.Sprintf("Waiting for the Reservations of gang migration %q scheduled, scheduled: %d, members: %d", migrationID, scheduled, members),
+		}
+		err := r.updateCondition(ctx, job, cond)
+		return false, reconcile.Result{RequeueAfter: defaultRequeueAfter}, err
+	}
+
+	cond = &sev1alpha1.PodMigrationJobCondition{
+		Type:    sev1alpha1.PodMigrationJobConditionGangReservationScheduled,
+		Status:  sev1alpha1.PodMigrationJobConditionStatusTrue,
+		Reason:  sev1alpha1.PodMigrationJobReasonGangReservationsScheduled,
+		Message: fmt.Sprintf("The Reservations of all %d members of gang migration %q are scheduled", members, migrationID),
+	}
+	err := r.updateCondition(ctx, job, cond)
+	if err == nil {
+		r.eventRecorder.Eventf(job, nil, corev1.EventTypeNormal, sev1alpha1.PodMigrationJobReasonGangReservationsScheduled, "Migrating", "%s", cond.Message)
+	}
+	return err == nil, reconcile.Result{}, err
+}
+
+func (r *Reconciler) abortJobByGangMigrationAborted(ctx context.Context, job *sev1alpha1.PodMigrationJob, failed *sev1alpha1.PodMigrationJob) error {
+	if err := r.deleteReservation(ctx, job); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	message := fmt.Sprintf("Abort job since MigrationJob %q of the same gang migration is %s", failed.Name, failed.Status.Phase)
+	util.UpdateCondition(&job.Status, &sev1alpha1.PodMigrationJobCondition{
+		Type:    sev1alpha1.PodMigrationJobConditionGangReservationScheduled,
+		Status:  sev1alpha1.PodMigrationJobConditionStatusFalse,
+		Reason:  sev1alpha1.PodMigrationJobReasonGangMigrationAborted,
+		Message: message,
+	})
+	job.Status.Phase = sev1alpha1.PodMigrationJobFailed
+	job.Status.Reason = sev1alpha1.PodMigrationJobReasonGangMigrationAborted
+	job.Status.Message = message
+	err := r.Client.Status().Update(ctx, job)
+	if err == nil {
+		r.eventRecorder.Eventf(job, nil, corev1.EventTypeWarning, sev1alpha1.PodMigrationJobReasonGangMigrationAborted, "Migrating", job.Status.Message)
+	}
+	return err
+}
+
+// runPreEvictionHook calls the pre-eviction hook matched the Pod and records the response in the condition
+// PreEvictionHook. It returns true if the Pod can be evicted.
+func (r *Reconciler) runPreEvictionHook(ctx context.Context, job *sev1alpha1.PodMigrationJob) (bool, reconcile.Result, error) {
+	if r.preEvictionHooks == nil {
+		return true, reconcile.Result{}, nil
+	}
+	_, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionPreEvictionHook)
+	if cond != nil && cond.Status == sev1alpha1.PodMigrationJobConditionStatusTrue {
+		return true, reconcile.Result{}, nil
+	}
+	if _, evictionCond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction); evictionCond != nil {
+		return true, reconcile.Result{}, nil
+	}
+
+	pod := &corev1.Pod{}
+	podNamespacedName := types.NamespacedName{Namespace: job.Spec.PodRef.Namespace, Name: job.Spec.PodRef.Name}
+	err := r.Client.Get(ctx, podNamespacedName, pod)
+	if errors.IsNotFound(err) {
+		// the missing Pod is handled by evictPod
+		return true, reconcile.Result{}, nil
+	}
+	if err != nil {
+		return false, reconcile.Result{}, err
+	}
+
+	hookName, resp, err := r.preEvictionHooks.Run(ctx, job, pod)
+	if err != nil {
+		klog.Errorf("Failed to call preEvictionHook %s for Pod %q, MigrationJob: %s, err: %v", hookName, podNamespacedName, job.Name, err)
+		cond = &sev1alpha1.PodMigrationJobCondition{
+			Type:    sev1alpha1.PodMigrationJobConditionPreEvictionHook,
+			Status:  sev1alpha1.PodMigrationJobConditionStatusFalse,
+			Reason:  sev1alpha1.PodMigrationJobReasonPreEvictionHookFailed,
+			Message: fmt.Sprintf("Failed to call preEvictionHook %q, err: %v", hookName, err),
+		}
+		err = r.updateCondition(ctx, job, cond)
+		return false, reconcile.Result{RequeueAfter: defaultRequeueAfter}, err
+	}
+	if hookName == "" {
+		return true, reconcile.Result{}, nil
+	}
+
+	switch resp.Result {
+	case prehook.ResultReady:
+		cond = &sev1alpha1.PodMigrationJobCondition{
+			Type:    sev1alpha1.PodMigrationJobConditionPreEvictionHook,
+			Status:  sev1alpha1.PodMigrationJobConditionStatusTrue,
+			Reason:  sev1alpha1.PodMigrationJobReasonPreEvictionHookReady,
+			Message: fmt.Sprintf("PreEvictionHook %q is ready: %s", hookName, resp.Message),
+		}
+		err = r.updateCondition(ctx, job, cond)
+		if err == nil {
+			r.eventRecorder.Eventf(job, nil, corev1.EventTypeNormal, sev1alpha1.PodMigrationJobReasonPreEvictionHookReady, "Migrating", "%s", cond.Message)
+		}
+		return err == nil, reconcile.Result{}, err
+	case prehook.ResultDeny:
+		err = r.abortJobByPreEvictionHookDenied(ctx, job, hookName, resp.Message)
+		return false, reconcile.Result{}, err
+	default:
+		requeueAfter := defaultRequeueAfter
+		if resp.RetryAfterSeconds > 0 {
+			requeueAfter = time.Duration(resp.RetryAfterSeconds) * time.Second
+		}
+		cond = &sev1alpha1.PodMigrationJobCondition{
+			Type:    sev1alpha1.PodMigrationJobConditionPreEvictionHook,
+			Status:  sev1alpha1.PodMigrationJobConditionStatusFalse,
+			Reason:  sev1alpha1.PodMigrationJobReasonWaitForPreEvictionHook,
+			Message: fmt.Sprintf("Waiting for preEvictionHook %q: %s", hookName, resp.Message),
+		}
+		err = r.updateCondition(ctx, job, cond)
+		return false, reconcile.Result{RequeueAfter: requeueAfter}, err
+	}
+}
+
+func (r *Reconciler) abortJobByPreEvictionHookDenied(ctx context.Context, job *sev1alpha1.PodMigrationJob, hookName, message string) error {
+	if err := r.deleteReservation(ctx, job); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	util.UpdateCondition(&job.Status, &sev1alpha1.PodMigrationJobCondition{
+		Type:    sev1alpha1.PodMigrationJobConditionPreEvictionHook,
+		Status:  sev1alpha1.PodMigrationJobConditionStatusFalse,
+		Reason:  sev1alpha1.PodMigrationJobReasonPreEvictionHookDenied,
+		Message: message,
+	})
+	job.Status.Phase = sev1alpha1.PodMigrationJobFailed
+	job.Status.Reason = sev1alpha1.PodMigrationJobReasonPreEvictionHookDenied
+	job.Status.Message = fmt.Sprintf("Abort job caused by preEvictionHook %q denied: %s", hookName, message)
+	err := r.Client.Status().Update(ctx, job)
+	if err == nil {
+		r.eventRecorder.Eventf(job, nil, corev1.EventTypeWarning, sev1alpha1.PodMigrationJobReasonPreEvictionHookDenied, "Migrating", job.Status.Message)
+	}
+	return err
+}
+
 func (r *Reconciler) evictPod(ctx context.Context, job *sev1alpha1.PodMigrationJob) (bool, reconcile.Result, error) {
 	_, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction)
 	if cond != nil && cond.Status == sev1alpha1.PodMigrationJobConditionStatusTrue {
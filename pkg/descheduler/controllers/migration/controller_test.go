@@ -19,6 +19,8 @@ package migration
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -45,6 +47,8 @@ import (
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/v1alpha2"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/controllerfinder"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/gang"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/prehook"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/reservation"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
 	evictionsutil "github.com/koordinator-sh/koordinator/pkg/descheduler/evictions"
@@ -187,6 +191,79 @@ func TestAbortJobIfTimeout(t *testing.T) {
 	assert.Equal(t, sev1alpha1.PodMigrationJobReasonTimeout, job.Status.Reason)
 }
 
+func TestAbortJobIfTimeoutAfterMigrationWindow(t *testing.T) {
+	reconciler := newTestReconciler()
+	now := time.Now()
+	job := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test",
+			CreationTimestamp: metav1.Time{Time: now.Add(-2 * time.Hour)},
+		},
+		Spec: sev1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{
+				Namespace: "default",
+				Name:      "test-pod",
+			},
+			TTL: &metav1.Duration{Duration: 30 * time.Minute},
+		},
+		Status: sev1alpha1.PodMigrationJobStatus{
+			Conditions: []sev1alpha1.PodMigrationJobCondition{
+				{
+					Type:               sev1alpha1.PodMigrationJobConditionMigrationWindow,
+					Status:             sev1alpha1.PodMigrationJobConditionStatusTrue,
+					Reason:             sev1alpha1.PodMigrationJobReasonMigrationWindowOpened,
+					LastTransitionTime: metav1.Time{Time: now.Add(-10 * time.Minute)},
+				},
+			},
+		},
+	}
+	assert.Nil(t, reconciler.Client.Create(context.TODO(), job))
+
+	// the TTL starts when the migration window opened
+	reconciler.clock = fakceclock.NewFakeClock(now)
+	timeout, err := reconciler.abortJobIfTimeout(context.TODO(), job)
+	assert.False(t, timeout)
+	assert.Nil(t, err)
+
+	reconciler.clock = fakceclock.NewFakeClock(now.Add(30 * time.Minute))
+	timeout, err = reconciler.abortJobIfTimeout(context.TODO(), job)
+	assert.True(t, timeout)
+	assert.Nil(t, err)
+}
+
+func TestDoMigrateWaitingForMigrationWindow(t *testing.T) {
+	reconciler := newTestReconciler()
+	job := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test",
+			CreationTimestamp: metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
+		},
+		Spec: sev1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{
+				Namespace: "default",
+				Name:      "test-pod",
+			},
+			TTL: &metav1.Duration{Duration: 30 * time.Minute},
+		},
+		Status: sev1alpha1.PodMigrationJobStatus{
+			Phase: sev1alpha1.PodMigrationJobPending,
+			Conditions: []sev1alpha1.PodMigrationJobCondition{
+				{
+					Type:   sev1alpha1.PodMigrationJobConditionMigrationWindow,
+					Status: sev1alpha1.PodMigrationJobConditionStatusFalse,
+					Reason: sev1alpha1.PodMigrationJobReasonWaitForMigrationWindow,
+				},
+			},
+		},
+	}
+	assert.Nil(t, reconciler.Client.Create(context.TODO(), job))
+
+	result, err := reconciler.doMigrate(context.TODO(), job)
+	assert.Nil(t, err)
+	assert.True(t, result.IsZero())
+	assert.Equal(t, sev1alpha1.PodMigrationJobPending, job.Status.Phase)
+}
+
 func TestAbortJobByMissingPod(t *testing.T) {
 	reconciler := newTestReconciler()
 	job := &sev1alpha1.PodMigrationJob{
@@ -488,6 +565,106 @@ func TestEvictPodDirectly(t *testing.T) {
 	assert.Equal(t, "", job.Status.Reason)
 }
 
+func TestEvictPodDirectlyWithPreEvictionHook(t *testing.T) {
+	tests := []struct {
+		name             string
+		response         string
+		wantPhase        sev1alpha1.PodMigrationJobPhase
+		wantReason       string
+		wantRequeueAfter time.Duration
+		wantCondition    sev1alpha1.PodMigrationJobConditionStatus
+	}{
+		{
+			name:          "hook is ready",
+			response:      `{"result":"Ready"}`,
+			wantPhase:     sev1alpha1.PodMigrationJobSucceeded,
+			wantCondition: sev1alpha1.PodMigrationJobConditionStatusTrue,
+		},
+		{
+			name:          "hook denies",
+			response:      `{"result":"Deny","message":"under-replicated partitions"}`,
+			wantPhase:     sev1alpha1.PodMigrationJobFailed,
+			wantReason:    sev1alpha1.PodMigrationJobReasonPreEvictionHookDenied,
+			wantCondition: sev1alpha1.PodMigrationJobConditionStatusFalse,
+		},
+		{
+			name:             "hook asks to retry",
+			response:         `{"result":"RetryAfter","retryAfterSeconds":30}`,
+			wantPhase:        sev1alpha1.PodMigrationJobRunning,
+			wantReason:       sev1alpha1.PodMigrationJobReasonWaitForPreEvictionHook,
+			wantRequeueAfter: 30 * time.Second,
+			wantCondition:    sev1alpha1.PodMigrationJobConditionStatusFalse,
+		},
+		{
+			name:             "hook fails",
+			response:         `not json`,
+			wantPhase:        sev1alpha1.PodMigrationJobRunning,
+			wantReason:       sev1alpha1.PodMigrationJobReasonPreEvictionHookFailed,
+			wantRequeueAfter: defaultRequeueAfter,
+			wantCondition:    sev1alpha1.PodMigrationJobConditionStatusFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			reconciler := newTestReconciler()
+			reconciler.args.DefaultJobMode = string(sev1alpha1.PodMigrationJobModeEvictionDirectly)
+			reconciler.evictorInterpreter = &FakeInterpreter{Client: reconciler.Client}
+			hooks, err := prehook.New([]deschedulerconfig.PreEvictionHook{
+				{
+					Name:          "kafka",
+					PodSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+					URL:           server.URL,
+					Timeout:       metav1.Duration{Duration: 5 * time.Second},
+					FailurePolicy: deschedulerconfig.PreEvictionHookFailurePolicyFail,
+				},
+			})
+			assert.NoError(t, err)
+			reconciler.preEvictionHooks = hooks
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+					Labels:    map[string]string{"app": "kafka"},
+				},
+			}
+			assert.NoError(t, reconciler.Create(context.TODO(), pod))
+
+			job := &sev1alpha1.PodMigrationJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test",
+					CreationTimestamp: metav1.Time{Time: time.Now()},
+				},
+				Spec: sev1alpha1.PodMigrationJobSpec{
+					PodRef: &corev1.ObjectReference{
+						Namespace: pod.Namespace,
+						Name:      pod.Name,
+					},
+				},
+			}
+			assert.Nil(t, reconciler.Create(context.TODO(), job))
+			result, err := reconciler.doMigrate(context.TODO(), job)
+			assert.Nil(t, err)
+			if tt.wantPhase == sev1alpha1.PodMigrationJobSucceeded {
+				// wait for the eviction to complete
+				result, err = reconciler.doMigrate(context.TODO(), job)
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.wantRequeueAfter, result.RequeueAfter)
+			assert.Equal(t, tt.wantPhase, job.Status.Phase)
+			assert.Equal(t, tt.wantReason, job.Status.Reason)
+			_, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionPreEvictionHook)
+			assert.NotNil(t, cond)
+			assert.Equal(t, tt.wantCondition, cond.Status)
+		})
+	}
+}
+
 func TestEvictPod(t *testing.T) {
 	reconciler := newTestReconciler()
 
@@ -2104,6 +2281,7 @@ func TestRequeueJobIfObjectLimiterFailedWithNamespace(t *testing.T) {
 type fakeArbitrator struct {
 	filter            framework.FilterFunc
 	preEvictionFilter framework.FilterFunc
+	filterGang        func([]*corev1.Pod) bool
 	add               func(*sev1alpha1.PodMigrationJob)
 	delete            func(types.UID)
 }
@@ -2120,6 +2298,93 @@ func (f *fakeArbitrator) PreEvictionFilter(pod *corev1.Pod) bool {
 	return f.preEvictionFilter(pod)
 }
 
+func (f *fakeArbitrator) FilterGang(members []*corev1.Pod) bool {
+	if f.filterGang == nil {
+		return true
+	}
+	return f.filterGang(members)
+}
+
 func (f *fakeArbitrator) AddPodMigrationJob(job *sev1alpha1.PodMigrationJob) {
 	f.add(job)
 }
+
+func TestWaitForGangReservations(t *testing.T) {
+	newGangJob := func(name string, phase sev1alpha1.PodMigrationJobPhase, reservationScheduled bool) *sev1alpha1.PodMigrationJob {
+		job := &sev1alpha1.PodMigrationJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				UID:  types.UID(name + "-uid"),
+			},
+			Status: sev1alpha1.PodMigrationJobStatus{
+				Phase: phase,
+			},
+		}
+		gang.SetPodMigrationJobAnnotations(job, "test-migration", 2)
+		if reservationScheduled {
+			job.Status.Conditions = append(job.Status.Conditions, sev1alpha1.PodMigrationJobCondition{
+				Type:   sev1alpha1.PodMigrationJobConditionReservationScheduled,
+				Status: sev1alpha1.PodMigrationJobConditionStatusTrue,
+			})
+		}
+		return job
+	}
+
+	tests := []struct {
+		name       string
+		sibling    *sev1alpha1.PodMigrationJob
+		wantReady  bool
+		wantResult reconcile.Result
+		wantPhase  sev1alpha1.PodMigrationJobPhase
+		wantReason string
+	}{
+		{
+			name:       "wait for the missing sibling",
+			wantResult: reconcile.Result{RequeueAfter: defaultRequeueAfter},
+			wantPhase:  sev1alpha1.PodMigrationJobRunning,
+			wantReason: sev1alpha1.PodMigrationJobReasonWaitForGangReservations,
+		},
+		{
+			name:       "wait for the reservation of the sibling",
+			sibling:    newGangJob("job-2", sev1alpha1.PodMigrationJobRunning, false),
+			wantResult: reconcile.Result{RequeueAfter: defaultRequeueAfter},
+			wantPhase:  sev1alpha1.PodMigrationJobRunning,
+			wantReason: sev1alpha1.PodMigrationJobReasonWaitForGangReservations,
+		},
+		{
+			name:       "all the reservations are scheduled",
+			sibling:    newGangJob("job-2", sev1alpha1.PodMigrationJobRunning, true),
+			wantReady:  true,
+			wantPhase:  sev1alpha1.PodMigrationJobRunning,
+			wantReason: sev1alpha1.PodMigrationJobReasonGangReservationsScheduled,
+		},
+		{
+			name:       "abort if the sibling failed",
+			sibling:    newGangJob("job-2", sev1alpha1.PodMigrationJobFailed, false),
+			wantPhase:  sev1alpha1.PodMigrationJobFailed,
+			wantReason: sev1alpha1.PodMigrationJobReasonGangMigrationAborted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := newTestReconciler()
+			job := newGangJob("job-1", sev1alpha1.PodMigrationJobRunning, true)
+			assert.NoError(t, reconciler.Client.Create(context.TODO(), job))
+			if tt.sibling != nil {
+				assert.NoError(t, reconciler.Client.Create(context.TODO(), tt.sibling))
+			}
+
+			ready, result, err := reconciler.waitForGangReservations(context.TODO(), job)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantReady, ready)
+			assert.Equal(t, tt.wantResult, result)
+
+			got := &sev1alpha1.PodMigrationJob{}
+			assert.NoError(t, reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: job.Name}, got))
+			assert.Equal(t, tt.wantPhase, got.Status.Phase)
+			_, cond := util.GetCondition(&got.Status, sev1alpha1.PodMigrationJobConditionGangReservationScheduled)
+			assert.NotNil(t, cond)
+			assert.Equal(t, tt.wantReason, cond.Reason)
+		})
+	}
+}
@@ -18,8 +18,10 @@ package migration
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
@@ -28,7 +30,9 @@ import (
 	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/gang"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/history"
 )
 
 const (
@@ -59,11 +63,96 @@ func (r *Reconciler) Evict(ctx context.Context, pod *corev1.Pod, evictOptions fr
 		return false
 	}
 
+	if r.args.GangMigrationPolicy == deschedulerconfig.GangMigrationPolicyAtomic && gang.GetGangID(pod) != "" {
+		return r.evictGang(ctx, pod, evictOptions)
+	}
+
 	err := CreatePodMigrationJob(ctx, pod, evictOptions, r.Client, r.args, r.reconcilerUID)
 	return err == nil
 }
 
+// evictGang migrates all the members of the gang which the Pod belongs to. The PodMigrationJobs are created only if
+// all the members can be migrated and the gang fits in the migration limits as a whole, and they are created in
+// ReservationFirst mode, so that none of the members is evicted until the Reservations of all the members are scheduled.
+func (r *Reconciler) evictGang(ctx context.Context, pod *corev1.Pod, evictOptions framework.EvictOptions) bool {
+	gangID := gang.GetGangID(pod)
+	members, err := gang.ListMembers(ctx, r.Client, pod)
+	if err != nil {
+		klog.Errorf("Failed to list members of gang %s, err: %v", gangID, err)
+		return false
+	}
+	found := false
+	for _, member := range members {
+		if member.UID == pod.UID {
+			found = true
+			continue
+		}
+		if !r.Filter(member) {
+			klog.Errorf("Pod %q cannot be evicted since member %q of gang %s failed to filter", klog.KObj(pod), klog.KObj(member), gangID)
+			return false
+		}
+	}
+	if !found {
+		members = append(members, pod)
+	}
+	if !r.arbitrator.FilterGang(members) {
+		klog.Errorf("Pod %q cannot be evicted since the %d members of gang %s exceed the migration limits", klog.KObj(pod), len(members), gangID)
+		return false
+	}
+
+	migrationID := string(UUIDGenerateFn())
+	var jobs []*sev1alpha1.PodMigrationJob
+	for _, member := range members {
+		memberEvictOptions := evictOptions
+		if member.UID != pod.UID {
+			memberEvictOptions.Reason = fmt.Sprintf("migrate with gang %s member %s: %s", gangID, pod.Name, evictOptions.Reason)
+		}
+		job, err := newPodMigrationJob(ctx, member, memberEvictOptions, r.args, r.reconcilerUID)
+		if err != nil {
+			klog.Errorf("Failed to build PodMigrationJob for Pod %q of gang %s, err: %v", klog.KObj(member), gangID, err)
+			return false
+		}
+		job.Spec.Mode = sev1alpha1.PodMigrationJobModeReservationFirst
+		gang.SetPodMigrationJobAnnotations(job, migrationID, len(members))
+		jobs = append(jobs, job)
+	}
+
+	for i, job := range jobs {
+		if err := r.Client.Create(ctx, job); err != nil {
+			klog.Errorf("Failed to create PodMigrationJob for Pod %s/%s of gang %s, err: %v", job.Spec.PodRef.Namespace, job.Spec.PodRef.Name, gangID, err)
+			for _, created := range jobs[:i] {
+				if err := r.Client.Delete(ctx, created); err != nil && !errors.IsNotFound(err) {
+					klog.Errorf("Failed to delete PodMigrationJob %s of gang %s, err: %v", created.Name, gangID, err)
+				}
+			}
+			return false
+		}
+	}
+	klog.V(4).Infof("Created %d PodMigrationJobs to migrate gang %s", len(jobs), gangID)
+	return true
+}
+
 func CreatePodMigrationJob(ctx context.Context, pod *corev1.Pod, evictOptions framework.EvictOptions, client client.Client, args *deschedulerconfig.MigrationControllerArgs, reconcilerUID types.UID) error {
+	job, err := newPodMigrationJob(ctx, pod, evictOptions, args, reconcilerUID)
+	if err != nil {
+		return err
+	}
+
+	err = client.Create(ctx, job)
+	if err != nil {
+		klog.Errorf("Failed to create PodMigrationJob for Pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+		return err
+	}
+	return nil
+}
+
+// NewPodMigrationJob builds the PodMigrationJob which migrates the Pod as requested by the evictOptions without
+// creating it.
+func NewPodMigrationJob(ctx context.Context, pod *corev1.Pod, evictOptions framework.EvictOptions, args *deschedulerconfig.MigrationControllerArgs) (*sev1alpha1.PodMigrationJob, error) {
+	return newPodMigrationJob(ctx, pod, evictOptions, args, "")
+}
+
+func newPodMigrationJob(ctx context.Context, pod *corev1.Pod, evictOptions framework.EvictOptions, args *deschedulerconfig.MigrationControllerArgs, reconcilerUID types.UID) (*sev1alpha1.PodMigrationJob, error) {
 	if evictOptions.DeleteOptions == nil {
 		evictOptions.DeleteOptions = args.DefaultDeleteOptions
 	}
@@ -90,16 +179,11 @@ func CreatePodMigrationJob(ctx context.Context, pod *corev1.Pod, evictOptions fr
 			Phase: sev1alpha1.PodMigrationJobPending,
 		},
 	}
+	history.SetPodMigrationJobAnnotations(job, pod)
 
 	if err := applyJobContextFn(ctx, job); err != nil {
 		klog.Errorf("Failed to apply JobContext to PodMigrationJob for Pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
-		return err
-	}
-
-	err := client.Create(ctx, job)
-	if err != nil {
-		klog.Errorf("Failed to create PodMigrationJob for Pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
-		return err
+		return nil, err
 	}
-	return nil
+	return job, nil
 }
@@ -28,12 +28,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/gang"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/history"
 )
 
 type errorClient struct {
@@ -186,6 +190,12 @@ func TestReconcilerEvictSuccess(t *testing.T) {
 			Namespace: "default",
 			Name:      "test-pod",
 			UID:       types.UID("pod-uid"),
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test-rs", UID: "rs-uid", Controller: pointer.Bool(true)},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node",
 		},
 	}
 
@@ -202,6 +212,8 @@ func TestReconcilerEvictSuccess(t *testing.T) {
 	assert.Equal(t, pod.Name, job.Spec.PodRef.Name)
 	assert.Equal(t, pod.UID, job.Spec.PodRef.UID)
 	assert.Equal(t, "reconciler-uid", job.Annotations[AnnotationJobCreatedBy])
+	assert.Equal(t, "rs-uid", job.Annotations[history.AnnotationPodOwnerUID])
+	assert.Equal(t, "test-node", job.Annotations[history.AnnotationPodNodeName])
 }
 
 func TestCreatePodMigrationJob(t *testing.T) {
@@ -301,3 +313,90 @@ func TestCreatePodMigrationJobApplyContextError(t *testing.T) {
 	assert.NoError(t, listErr)
 	assert.Len(t, jobList.Items, 0)
 }
+
+func TestReconcilerEvictGang(t *testing.T) {
+	newGangPod := func(name, gangName, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				UID:         types.UID(name + "-uid"),
+				Annotations: map[string]string{extension.AnnotationGangName: gangName},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		filter      func(pod *corev1.Pod) bool
+		filterGang  func(members []*corev1.Pod) bool
+		wantEvicted bool
+		wantPods    []string
+	}{
+		{
+			name:        "migrate all the running members",
+			filter:      func(pod *corev1.Pod) bool { return true },
+			wantEvicted: true,
+			wantPods:    []string{"worker-0", "worker-1"},
+		},
+		{
+			name:   "no member is migrated if any member fails to filter",
+			filter: func(pod *corev1.Pod) bool { return pod.Name != "worker-1" },
+		},
+		{
+			name:       "no member is migrated if the gang exceeds the migration limits",
+			filter:     func(pod *corev1.Pod) bool { return true },
+			filterGang: func(members []*corev1.Pod) bool { return len(members) < 2 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := newTestReconciler()
+			reconciler.args.GangMigrationPolicy = deschedulerconfig.GangMigrationPolicyAtomic
+			reconciler.args.DefaultJobMode = string(sev1alpha1.PodMigrationJobModeEvictionDirectly)
+			reconciler.arbitrator = &fakeArbitrator{
+				filter:            tt.filter,
+				preEvictionFilter: func(*corev1.Pod) bool { return true },
+				filterGang:        tt.filterGang,
+				add:               func(*sev1alpha1.PodMigrationJob) {},
+				delete:            func(types.UID) {},
+			}
+
+			pods := []*corev1.Pod{
+				newGangPod("worker-0", "test-gang", "node-1"),
+				newGangPod("worker-1", "test-gang", "node-2"),
+				newGangPod("worker-2", "test-gang", ""),
+				newGangPod("other-0", "other-gang", "node-1"),
+			}
+			for _, pod := range pods {
+				assert.NoError(t, reconciler.Client.Create(context.Background(), pod))
+			}
+
+			ok := reconciler.Evict(context.Background(), pods[0], framework.EvictOptions{
+				PluginName: "test-plugin",
+				Reason:     "gang",
+			})
+			assert.Equal(t, tt.wantEvicted, ok)
+
+			jobList := &sev1alpha1.PodMigrationJobList{}
+			assert.NoError(t, reconciler.Client.List(context.Background(), jobList))
+			var gotPods []string
+			migrationIDs := map[string]struct{}{}
+			for _, job := range jobList.Items {
+				gotPods = append(gotPods, job.Spec.PodRef.Name)
+				assert.Equal(t, sev1alpha1.PodMigrationJobModeReservationFirst, job.Spec.Mode)
+				migrationID, members := gang.GetGangMigration(&job)
+				assert.NotEmpty(t, migrationID)
+				assert.Equal(t, 2, members)
+				migrationIDs[migrationID] = struct{}{}
+			}
+			assert.ElementsMatch(t, tt.wantPods, gotPods)
+			if len(gotPods) > 0 {
+				assert.Len(t, migrationIDs, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gang
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
+	pkgutil "github.com/koordinator-sh/koordinator/pkg/util"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+const (
+	// AnnotationGangMigration records the unique ID of the gang migration on the PodMigrationJobs which migrate
+	// the members of a gang together.
+	AnnotationGangMigration = "descheduler.koordinator.sh/gang-migration"
+	// AnnotationGangMigrationMembers records the number of the PodMigrationJobs of the gang migration.
+	AnnotationGangMigrationMembers = "descheduler.koordinator.sh/gang-migration-members"
+)
+
+// GetGangID returns the gang which the Pod belongs to in the format of <namespace>/<gang name>,
+// or empty if the Pod is not a gang member.
+func GetGangID(pod *corev1.Pod) string {
+	gangName := extension.GetGangName(pod)
+	if gangName == "" {
+		return ""
+	}
+	return pod.Namespace + "/" + gangName
+}
+
+// ListMembers returns the running members of the gang which the Pod belongs to, including the Pod itself.
+func ListMembers(ctx context.Context, c client.Client, pod *corev1.Pod) ([]*corev1.Pod, error) {
+	gangName := extension.GetGangName(pod)
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, client.InNamespace(pod.Namespace), utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	var members []*corev1.Pod
+	for i := range podList.Items {
+		member := &podList.Items[i]
+		if member.Spec.NodeName == "" || member.DeletionTimestamp != nil || pkgutil.IsPodTerminated(member) ||
+			extension.GetGangName(member) != gangName {
+			continue
+		}
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+	return members, nil
+}
+
+// SetPodMigrationJobAnnotations records the gang migration on the PodMigrationJob.
+func SetPodMigrationJobAnnotations(job *sev1alpha1.PodMigrationJob, migrationID string, members int) {
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[AnnotationGangMigration] = migrationID
+	job.Annotations[AnnotationGangMigrationMembers] = strconv.Itoa(members)
+}
+
+// GetGangMigration returns the ID and the number of the members of the gang migration which the PodMigrationJob
+// belongs to. The ID is empty if the PodMigrationJob does not migrate a gang.
+func GetGangMigration(job *sev1alpha1.PodMigrationJob) (string, int) {
+	migrationID := job.Annotations[AnnotationGangMigration]
+	if migrationID == "" {
+		return "", 0
+	}
+	members, err := strconv.Atoi(job.Annotations[AnnotationGangMigrationMembers])
+	if err != nil || members <= 0 {
+		members = 1
+	}
+	return migrationID, members
+}
+
+// CheckReservations checks the PodMigrationJobs of a gang migration. It returns the failed PodMigrationJob if any
+// of them has failed or aborted, otherwise it returns the number of the members whose Reservations are scheduled.
+func CheckReservations(jobs []*sev1alpha1.PodMigrationJob) (int, *sev1alpha1.PodMigrationJob) {
+	scheduled := 0
+	for _, job := range jobs {
+		switch job.Status.Phase {
+		case sev1alpha1.PodMigrationJobFailed, sev1alpha1.PodMigrationJobAborted:
+			return 0, job
+		case sev1alpha1.PodMigrationJobSucceeded:
+			scheduled++
+			continue
+		}
+		if _, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionReservationScheduled); cond != nil &&
+			cond.Status == sev1alpha1.PodMigrationJobConditionStatusTrue {
+			scheduled++
+		}
+	}
+	return scheduled, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gang
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func newGangPod(name, gangName, nodeName string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+	if gangName != "" {
+		pod.Annotations = map[string]string{extension.AnnotationGangName: gangName}
+	}
+	return pod
+}
+
+func TestGetGangID(t *testing.T) {
+	assert.Equal(t, "default/test-gang", GetGangID(newGangPod("worker-0", "test-gang", "node-1")))
+	assert.Equal(t, "", GetGangID(newGangPod("web-0", "", "node-1")))
+}
+
+func TestListMembers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	succeeded := newGangPod("worker-3", "test-gang", "node-1")
+	succeeded.Status.Phase = corev1.PodSucceeded
+	otherNamespace := newGangPod("worker-4", "test-gang", "node-1")
+	otherNamespace.Namespace = "other"
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newGangPod("worker-1", "test-gang", "node-2"),
+		newGangPod("worker-0", "test-gang", "node-1"),
+		newGangPod("worker-2", "test-gang", ""),
+		succeeded,
+		otherNamespace,
+		newGangPod("other-0", "other-gang", "node-1"),
+		newGangPod("web-0", "", "node-1"),
+	).Build()
+
+	members, err := ListMembers(context.TODO(), fakeClient, newGangPod("worker-0", "test-gang", "node-1"))
+	assert.NoError(t, err)
+	var got []string
+	for _, member := range members {
+		got = append(got, member.Name)
+	}
+	assert.Equal(t, []string{"worker-0", "worker-1"}, got)
+}
+
+func TestGetGangMigration(t *testing.T) {
+	job := &sev1alpha1.PodMigrationJob{}
+	migrationID, members := GetGangMigration(job)
+	assert.Equal(t, "", migrationID)
+	assert.Equal(t, 0, members)
+
+	SetPodMigrationJobAnnotations(job, "test-migration", 3)
+	migrationID, members = GetGangMigration(job)
+	assert.Equal(t, "test-migration", migrationID)
+	assert.Equal(t, 3, members)
+
+	job.Annotations[AnnotationGangMigrationMembers] = "invalid"
+	_, members = GetGangMigration(job)
+	assert.Equal(t, 1, members)
+}
+
+func TestCheckReservations(t *testing.T) {
+	scheduled := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "scheduled"},
+		Status: sev1alpha1.PodMigrationJobStatus{
+			Phase: sev1alpha1.PodMigrationJobRunning,
+			Conditions: []sev1alpha1.PodMigrationJobCondition{
+				{
+					Type:   sev1alpha1.PodMigrationJobConditionReservationScheduled,
+					Status: sev1alpha1.PodMigrationJobConditionStatusTrue,
+				},
+			},
+		},
+	}
+	succeeded := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "succeeded"},
+		Status:     sev1alpha1.PodMigrationJobStatus{Phase: sev1alpha1.PodMigrationJobSucceeded},
+	}
+	pending := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+		Status:     sev1alpha1.PodMigrationJobStatus{Phase: sev1alpha1.PodMigrationJobPending},
+	}
+	aborted := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "aborted"},
+		Status:     sev1alpha1.PodMigrationJobStatus{Phase: sev1alpha1.PodMigrationJobAborted},
+	}
+
+	count, failed := CheckReservations([]*sev1alpha1.PodMigrationJob{scheduled, succeeded, pending})
+	assert.Equal(t, 2, count)
+	assert.Nil(t, failed)
+
+	_, failed = CheckReservations([]*sev1alpha1.PodMigrationJob{scheduled, aborted, pending})
+	assert.Equal(t, aborted, failed)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prehook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+)
+
+// Result is the decision of the pre-eviction hook.
+type Result string
+
+const (
+	// ResultReady means the workload is ready for the eviction.
+	ResultReady Result = "Ready"
+	// ResultDeny means the workload refuses the eviction and the PodMigrationJob should be aborted.
+	ResultDeny Result = "Deny"
+	// ResultRetryAfter means the workload is preparing for the eviction and the hook should be called again later.
+	ResultRetryAfter Result = "RetryAfter"
+)
+
+// Request is posted to the pre-eviction hook in JSON.
+type Request struct {
+	// Hook is the name of the hook.
+	Hook string `json:"hook"`
+	// PodMigrationJob is the name of the PodMigrationJob.
+	PodMigrationJob string `json:"podMigrationJob"`
+	// UID is the UID of the PodMigrationJob, the hook can use it to deduplicate the requests.
+	UID types.UID `json:"uid"`
+	// Namespace is the namespace of the Pod to be evicted.
+	Namespace string `json:"namespace"`
+	// Name is the name of the Pod to be evicted.
+	Name string `json:"name"`
+	// PodUID is the UID of the Pod to be evicted.
+	PodUID types.UID `json:"podUID"`
+	// NodeName is the node where the Pod is running.
+	NodeName string `json:"nodeName,omitempty"`
+	// TargetNodeName is the node where the Reservation is scheduled.
+	TargetNodeName string `json:"targetNodeName,omitempty"`
+	// Reason is the reason of the eviction.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Response is returned by the pre-eviction hook in JSON.
+type Response struct {
+	// Result is the decision of the hook.
+	Result Result `json:"result"`
+	// RetryAfterSeconds indicates when to call the hook again if the Result is RetryAfter.
+	RetryAfterSeconds int32 `json:"retryAfterSeconds,omitempty"`
+	// Message is a human-readable description of the Result.
+	Message string `json:"message,omitempty"`
+}
+
+type Interface interface {
+	// Run calls the first pre-eviction hook matched the Pod and returns the name of the hook and its response.
+	// It returns an empty name if no hook matches the Pod.
+	Run(ctx context.Context, job *sev1alpha1.PodMigrationJob, pod *corev1.Pod) (string, *Response, error)
+}
+
+type hook struct {
+	name          string
+	selector      labels.Selector
+	url           string
+	client        *http.Client
+	failurePolicy deschedulerconfig.PreEvictionHookFailurePolicy
+}
+
+type hooksImpl struct {
+	hooks []*hook
+}
+
+func New(args []deschedulerconfig.PreEvictionHook) (Interface, error) {
+	h := &hooksImpl{}
+	for i := range args {
+		selector, err := metav1.LabelSelectorAsSelector(args[i].PodSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid podSelector of preEvictionHook %q, err: %w", args[i].Name, err)
+		}
+		h.hooks = append(h.hooks, &hook{
+			name:          args[i].Name,
+			selector:      selector,
+			url:           args[i].URL,
+			client:        &http.Client{Timeout: args[i].Timeout.Duration},
+			failurePolicy: args[i].FailurePolicy,
+		})
+	}
+	return h, nil
+}
+
+func (h *hooksImpl) Run(ctx context.Context, job *sev1alpha1.PodMigrationJob, pod *corev1.Pod) (string, *Response, error) {
+	for _, v := range h.hooks {
+		if !v.selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		resp, err := v.call(ctx, job, pod)
+		if err != nil {
+			if v.failurePolicy == deschedulerconfig.PreEvictionHookFailurePolicyIgnore {
+				klog.ErrorS(err, "Failed to call preEvictionHook, ignore it", "hook", v.name, "job", klog.KObj(job), "pod", klog.KObj(pod))
+				return v.name, &Response{Result: ResultReady, Message: fmt.Sprintf("ignore the failure: %v", err)}, nil
+			}
+			return v.name, nil, err
+		}
+		return v.name, resp, nil
+	}
+	return "", nil, nil
+}
+
+func (h *hook) call(ctx context.Context, job *sev1alpha1.PodMigrationJob, pod *corev1.Pod) (*Response, error) {
+	_, reason := evictor.GetEvictionTriggerAndReason(job.Annotations)
+	request := &Request{
+		Hook:            h.name,
+		PodMigrationJob: job.Name,
+		UID:             job.UID,
+		Namespace:       pod.Namespace,
+		Name:            pod.Name,
+		PodUID:          pod.UID,
+		NodeName:        pod.Spec.NodeName,
+		TargetNodeName:  job.Status.NodeName,
+		Reason:          reason,
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpResponse, err := h.client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+	data, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResponse.StatusCode < http.StatusOK || httpResponse.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("unexpected status code %d: %s", httpResponse.StatusCode, string(data))
+	}
+
+	response := &Response{}
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, fmt.Errorf("invalid response, err: %w", err)
+	}
+	switch response.Result {
+	case ResultReady, ResultDeny, ResultRetryAfter:
+	default:
+		return nil, fmt.Errorf("unknown result %q", response.Result)
+	}
+	return response, nil
+}
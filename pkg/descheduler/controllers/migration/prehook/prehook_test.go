@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prehook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		podLabels     map[string]string
+		statusCode    int
+		response      string
+		failurePolicy deschedulerconfig.PreEvictionHookFailurePolicy
+		wantHook      string
+		wantResponse  *Response
+		wantErr       bool
+	}{
+		{
+			name:      "no hook matches the pod",
+			podLabels: map[string]string{"app": "web"},
+		},
+		{
+			name:         "ready",
+			podLabels:    map[string]string{"app": "kafka"},
+			statusCode:   http.StatusOK,
+			response:     `{"result":"Ready","message":"leadership transferred"}`,
+			wantHook:     "kafka",
+			wantResponse: &Response{Result: ResultReady, Message: "leadership transferred"},
+		},
+		{
+			name:         "deny",
+			podLabels:    map[string]string{"app": "kafka"},
+			statusCode:   http.StatusOK,
+			response:     `{"result":"Deny","message":"under-replicated partitions"}`,
+			wantHook:     "kafka",
+			wantResponse: &Response{Result: ResultDeny, Message: "under-replicated partitions"},
+		},
+		{
+			name:         "retry after",
+			podLabels:    map[string]string{"app": "kafka"},
+			statusCode:   http.StatusOK,
+			response:     `{"result":"RetryAfter","retryAfterSeconds":30}`,
+			wantHook:     "kafka",
+			wantResponse: &Response{Result: ResultRetryAfter, RetryAfterSeconds: 30},
+		},
+		{
+			name:       "unknown result",
+			podLabels:  map[string]string{"app": "kafka"},
+			statusCode: http.StatusOK,
+			response:   `{"result":"Maybe"}`,
+			wantHook:   "kafka",
+			wantErr:    true,
+		},
+		{
+			name:       "unexpected status code",
+			podLabels:  map[string]string{"app": "kafka"},
+			statusCode: http.StatusInternalServerError,
+			response:   `internal error`,
+			wantHook:   "kafka",
+			wantErr:    true,
+		},
+		{
+			name:          "ignore the failure",
+			podLabels:     map[string]string{"app": "kafka"},
+			statusCode:    http.StatusInternalServerError,
+			response:      `internal error`,
+			failurePolicy: deschedulerconfig.PreEvictionHookFailurePolicyIgnore,
+			wantHook:      "kafka",
+			wantResponse:  &Response{Result: ResultReady, Message: "ignore the failure: unexpected status code 500: internal error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRequest Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&gotRequest))
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			failurePolicy := tt.failurePolicy
+			if failurePolicy == "" {
+				failurePolicy = deschedulerconfig.PreEvictionHookFailurePolicyFail
+			}
+			hooks, err := New([]deschedulerconfig.PreEvictionHook{
+				{
+					Name:          "kafka",
+					PodSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+					URL:           server.URL,
+					Timeout:       metav1.Duration{Duration: 5 * time.Second},
+					FailurePolicy: failurePolicy,
+				},
+			})
+			assert.NoError(t, err)
+
+			job := &sev1alpha1.PodMigrationJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-job",
+					UID:         "test-job-uid",
+					Annotations: map[string]string{evictor.AnnotationEvictReason: "high load"},
+				},
+				Status: sev1alpha1.PodMigrationJobStatus{
+					NodeName: "node-2",
+				},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+					UID:       "test-pod-uid",
+					Labels:    tt.podLabels,
+				},
+				Spec: corev1.PodSpec{
+					NodeName: "node-1",
+				},
+			}
+			hookName, resp, err := hooks.Run(context.TODO(), job, pod)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantHook, hookName)
+			assert.Equal(t, tt.wantResponse, resp)
+			if hookName != "" {
+				assert.Equal(t, Request{
+					Hook:            "kafka",
+					PodMigrationJob: "test-job",
+					UID:             "test-job-uid",
+					Namespace:       "default",
+					Name:            "test-pod",
+					PodUID:          "test-pod-uid",
+					NodeName:        "node-1",
+					TargetNodeName:  "node-2",
+					Reason:          "high load",
+				}, gotRequest)
+			}
+		})
+	}
+}
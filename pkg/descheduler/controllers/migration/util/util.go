@@ -18,6 +18,7 @@ package util
 
 import (
 	"math"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -67,6 +68,23 @@ func UpdateCondition(status *sev1alpha1.PodMigrationJobStatus, condition *sev1al
 	return !isEqual
 }
 
+// IsWaitingForMigrationWindow checks if the PodMigrationJob is kept in the queue until its migration window opens.
+func IsWaitingForMigrationWindow(job *sev1alpha1.PodMigrationJob) bool {
+	_, cond := GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionMigrationWindow)
+	return cond != nil && cond.Status == sev1alpha1.PodMigrationJobConditionStatusFalse
+}
+
+// GetMigrationStartTime returns the time since which the PodMigrationJob is allowed to be executed. It is the time
+// the migration window opened if the PodMigrationJob has waited for it, otherwise the creation time.
+func GetMigrationStartTime(job *sev1alpha1.PodMigrationJob) time.Time {
+	startTime := job.CreationTimestamp.Time
+	_, cond := GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionMigrationWindow)
+	if cond != nil && cond.Status == sev1alpha1.PodMigrationJobConditionStatusTrue && cond.LastTransitionTime.After(startTime) {
+		startTime = cond.LastTransitionTime.Time
+	}
+	return startTime
+}
+
 func IsMigratePendingPod(reservationObj reservation.Object) bool {
 	pending := false
 	for _, v := range reservationObj.GetReservationOwners() {
@@ -34,6 +34,7 @@ import (
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/scheme"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/v1alpha2"
@@ -63,6 +64,7 @@ type Descheduler struct {
 type deschedulerOptions struct {
 	componentConfigVersion string
 	kubeConfig             *restclient.Config
+	koordClientSet         koordclientset.Interface
 	frameworkCapturer      FrameworkCapturer
 	podAssignedToNodeFn    PodAssignedToNodeFn
 	outOfTreeRegistry      frameworkruntime.Registry
@@ -93,6 +95,13 @@ func WithKubeConfig(cfg *restclient.Config) Option {
 	}
 }
 
+// WithKoordClientSet sets the koordinator clientSet used by the plugins instead of the one built from the kube config.
+func WithKoordClientSet(koordClientSet koordclientset.Interface) Option {
+	return func(o *deschedulerOptions) {
+		o.koordClientSet = koordClientSet
+	}
+}
+
 func WithProfiles(p ...deschedulerconfig.DeschedulerProfile) Option {
 	return func(o *deschedulerOptions) {
 		o.profiles = p
@@ -216,6 +225,7 @@ func New(client clientset.Interface,
 		frameworkruntime.WithDryRun(options.dryRun),
 		frameworkruntime.WithClientSet(client),
 		frameworkruntime.WithKubeConfig(options.kubeConfig),
+		frameworkruntime.WithKoordClientSet(options.koordClientSet),
 		frameworkruntime.WithSharedInformerFactory(informerFactory),
 		frameworkruntime.WithEvictionLimiter(options.evictionLimiter),
 		frameworkruntime.WithGetPodsAssignedToNodeFunc(podAssignedToNodeAdaptor(options.podAssignedToNodeFn)),
@@ -259,6 +269,11 @@ func (d *Descheduler) Start(ctx context.Context) error {
 	return nil
 }
 
+// RunOnce runs one descheduling cycle of all the profiles.
+func (d *Descheduler) RunOnce(ctx context.Context) error {
+	return d.deschedulerOnce(ctx)
+}
+
 func (d *Descheduler) deschedulerOnce(ctx context.Context) error {
 	nodes, err := nodeutil.ReadyNodes(ctx, d.clientSet, d.nodeInformer, d.nodeSelector)
 	if err != nil {
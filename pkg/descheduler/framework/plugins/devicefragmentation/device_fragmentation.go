@@ -0,0 +1,249 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devicefragmentation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	schedulinglisters "github.com/koordinator-sh/koordinator/pkg/client/listers/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	nodeutil "github.com/koordinator-sh/koordinator/pkg/descheduler/node"
+	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+)
+
+const (
+	DeviceFragmentationName = "DeviceFragmentation"
+)
+
+var _ framework.BalancePlugin = &DeviceFragmentation{}
+
+// DeviceFragmentation migrates the Pods sharing the partially used devices, e.g. GPUs, onto the other partially
+// used devices in the cluster, so that the whole devices are freed for the Pods which require full devices.
+// The Pods are migrated with ReservationFirst PodMigrationJobs to make sure the new Pods are scheduled before
+// the old Pods are evicted.
+type DeviceFragmentation struct {
+	handle       framework.Handle
+	podFilter    framework.FilterFunc
+	deviceLister schedulinglisters.DeviceLister
+	args         *deschedulerconfig.DeviceFragmentationArgs
+}
+
+func NewDeviceFragmentation(ctx context.Context, args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	pluginArgs, ok := args.(*deschedulerconfig.DeviceFragmentationArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type DeviceFragmentationArgs, got %T", args)
+	}
+
+	if err := validation.ValidateDeviceFragmentationArgs(nil, pluginArgs); err != nil {
+		return nil, err
+	}
+
+	podSelectorFn, err := filterPods(pluginArgs.PodSelectors)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod selector filter: %v", err)
+	}
+
+	var excludedNamespaces sets.String
+	var includedNamespaces sets.String
+	if pluginArgs.EvictableNamespaces != nil {
+		excludedNamespaces = sets.NewString(pluginArgs.EvictableNamespaces.Exclude...)
+		includedNamespaces = sets.NewString(pluginArgs.EvictableNamespaces.Include...)
+	}
+
+	podFilter, err := podutil.NewOptions().
+		WithFilter(podutil.WrapFilterFuncs(handle.Evictor().Filter, podSelectorFn)).
+		WithoutNamespaces(excludedNamespaces).
+		WithNamespaces(includedNamespaces).
+		BuildFilterFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod filter function: %v", err)
+	}
+
+	koordClientSet, ok := handle.(koordclientset.Interface)
+	if !ok {
+		kubeConfig := *handle.KubeConfig()
+		kubeConfig.ContentType = runtime.ContentTypeJSON
+		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		koordClientSet, err = koordclientset.NewForConfig(&kubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	koordSharedInformerFactory := koordinformers.NewSharedInformerFactory(koordClientSet, 0)
+	deviceInformer := koordSharedInformerFactory.Scheduling().V1alpha1().Devices()
+	deviceInformer.Informer()
+	koordSharedInformerFactory.Start(ctx.Done())
+	koordSharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	return &DeviceFragmentation{
+		handle:       handle,
+		args:         pluginArgs,
+		podFilter:    podFilter,
+		deviceLister: deviceInformer.Lister(),
+	}, nil
+}
+
+func filterPods(podSelectors []deschedulerconfig.DeviceFragmentationPodSelector) (framework.FilterFunc, error) {
+	var selectors []labels.Selector
+	for _, v := range podSelectors {
+		if v.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(v.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid labelSelector %w", err)
+			}
+			selectors = append(selectors, selector)
+		}
+	}
+
+	return func(pod *corev1.Pod) bool {
+		if len(selectors) == 0 {
+			return true
+		}
+		for _, v := range selectors {
+			if v.Matches(labels.Set(pod.Labels)) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (pl *DeviceFragmentation) Name() string {
+	return DeviceFragmentationName
+}
+
+func (pl *DeviceFragmentation) Balance(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	if pl.args.Paused {
+		klog.Infof("DeviceFragmentation is paused and will do nothing.")
+		return nil
+	}
+
+	ctx = framework.PluginNameWithContext(ctx, pl.Name())
+	candidateNodes, err := pl.filterNodesByNodeSelector(nodes)
+	if err != nil {
+		return &framework.Status{Err: err}
+	}
+
+	devices := map[string]*schedulingv1alpha1.Device{}
+	podsOnNode := map[string][]*corev1.Pod{}
+	for _, node := range candidateNodes {
+		device, err := pl.deviceLister.Get(node.Name)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to get Device", "node", node.Name)
+			}
+			continue
+		}
+		pods, err := podutil.ListPodsOnANode(node.Name, pl.handle.GetPodsAssignedToNodeFunc(), nil)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get pods assigned to node", "node", node.Name)
+			continue
+		}
+		devices[node.Name] = device
+		podsOnNode[node.Name] = pods
+	}
+
+	for _, deviceType := range pl.args.DeviceTypes {
+		var states []*nodeDeviceState
+		for _, node := range candidateNodes {
+			if device := devices[node.Name]; device != nil {
+				states = append(states, newNodeDeviceState(device, schedulingv1alpha1.DeviceType(deviceType), podsOnNode[node.Name]))
+			}
+		}
+		// the nodes with fewer free devices are defragmented first
+		sort.SliceStable(states, func(i, j int) bool {
+			return states[i].freeDevices() < states[j].freeDevices()
+		})
+
+		planner := newDefragmentPlanner(states)
+		for _, state := range states {
+			device, pods := planner.pickDeviceToFree(state, func(p *devicePod) bool {
+				return pl.isMigratable(p, candidateNodes)
+			}, int(pl.args.MaxPodsToEvictPerDevice))
+			if device == nil {
+				klog.V(5).InfoS("No device can be freed", "node", state.nodeName, "deviceType", deviceType)
+				continue
+			}
+			pl.evictPods(ctx, deviceType, device, pods)
+		}
+	}
+
+	return nil
+}
+
+func (pl *DeviceFragmentation) isMigratable(p *devicePod, candidateNodes []*corev1.Node) bool {
+	if !pl.podFilter(p.pod) {
+		return false
+	}
+	if pl.args.NodeFit && !nodeutil.PodFitsAnyOtherNode(pl.handle.GetPodsAssignedToNodeFunc(), p.pod, candidateNodes) {
+		return false
+	}
+	return pl.handle.Evictor().PreEvictionFilter(p.pod)
+}
+
+func (pl *DeviceFragmentation) filterNodesByNodeSelector(nodes []*corev1.Node) ([]*corev1.Node, error) {
+	if pl.args.NodeSelector == nil {
+		return nodes, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pl.args.NodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	var filtered []*corev1.Node
+	for _, node := range nodes {
+		if selector.Matches(labels.Set(node.Labels)) {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered, nil
+}
+
+func (pl *DeviceFragmentation) evictPods(ctx context.Context, deviceType string, device *deviceState, pods []*corev1.Pod) {
+	reason := fmt.Sprintf("free %s %d: usedRatio=%.2f", deviceType, device.minor, device.usedRatio())
+	// the new Pods must be scheduled onto the other devices before the old Pods are evicted
+	ctx = migration.WithContext(ctx, &migration.JobContext{Mode: schedulingv1alpha1.PodMigrationJobModeReservationFirst})
+	for _, pod := range pods {
+		if pl.args.DryRun {
+			klog.InfoS("Evict pod in dry run mode", "pod", klog.KObj(pod), "node", device.nodeName, "reason", reason)
+			continue
+		}
+		if !pl.handle.Evictor().Evict(ctx, pod, framework.EvictOptions{
+			PluginName: pl.Name(),
+			Reason:     reason,
+		}) {
+			klog.InfoS("Failed to evict pod to free device", "pod", klog.KObj(pod), "node", device.nodeName, "deviceType", deviceType, "minor", device.minor)
+			continue
+		}
+		klog.V(4).InfoS("Evicted pod to free device", "pod", klog.KObj(pod), "node", device.nodeName, "deviceType", deviceType, "minor", device.minor)
+	}
+}
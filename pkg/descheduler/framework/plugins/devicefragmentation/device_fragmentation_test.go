@@ -0,0 +1,287 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devicefragmentation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	schedulinglisters "github.com/koordinator-sh/koordinator/pkg/client/listers/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/test"
+)
+
+type fakeEvictor struct {
+	evicted []*corev1.Pod
+	modes   []schedulingv1alpha1.PodMigrationJobMode
+}
+
+func (e *fakeEvictor) Filter(pod *corev1.Pod) bool {
+	return true
+}
+
+func (e *fakeEvictor) PreEvictionFilter(pod *corev1.Pod) bool {
+	return true
+}
+
+func (e *fakeEvictor) Evict(ctx context.Context, pod *corev1.Pod, opts framework.EvictOptions) bool {
+	e.evicted = append(e.evicted, pod)
+	if jobCtx := migration.FromContext(ctx); jobCtx != nil {
+		e.modes = append(e.modes, jobCtx.Mode)
+	}
+	return true
+}
+
+type fakeHandle struct {
+	evictor *fakeEvictor
+	pods    []*corev1.Pod
+}
+
+func (h *fakeHandle) Evictor() framework.Evictor {
+	return h.evictor
+}
+
+func (h *fakeHandle) GetPodsAssignedToNodeFunc() framework.GetPodsAssignedToNodeFunc {
+	return func(nodeName string, filter framework.FilterFunc) ([]*corev1.Pod, error) {
+		var res []*corev1.Pod
+		for _, pod := range h.pods {
+			if pod.Spec.NodeName == nodeName {
+				if filter == nil || filter(pod) {
+					res = append(res, pod)
+				}
+			}
+		}
+		return res, nil
+	}
+}
+
+func (h *fakeHandle) ClientSet() clientset.Interface                         { return nil }
+func (h *fakeHandle) KubeConfig() *restclient.Config                         { return nil }
+func (h *fakeHandle) EventRecorder() events.EventRecorder                    { return nil }
+func (h *fakeHandle) IsDryRun() bool                                         { return false }
+func (h *fakeHandle) SharedInformerFactory() informers.SharedInformerFactory { return nil }
+func (h *fakeHandle) NodeSelector() *metav1.LabelSelector                    { return nil }
+func (h *fakeHandle) RunDeschedulePlugins(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	return nil
+}
+func (h *fakeHandle) RunBalancePlugins(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	return nil
+}
+
+// buildTestDevice builds a Device with the healthy GPUs, and each GPU has 100 cores and 16Gi memory.
+func buildTestDevice(nodeName string, gpus int) *schedulingv1alpha1.Device {
+	device := &schedulingv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+	}
+	for i := 0; i < gpus; i++ {
+		device.Spec.Devices = append(device.Spec.Devices, schedulingv1alpha1.DeviceInfo{
+			Type:   schedulingv1alpha1.GPU,
+			UUID:   fmt.Sprintf("%s-gpu-%d", nodeName, i),
+			Minor:  ptr.To[int32](int32(i)),
+			Health: true,
+			Resources: corev1.ResourceList{
+				extension.ResourceGPUCore:        resource.MustParse("100"),
+				extension.ResourceGPUMemory:      resource.MustParse("16Gi"),
+				extension.ResourceGPUMemoryRatio: resource.MustParse("100"),
+			},
+		})
+	}
+	return device
+}
+
+// buildGPUPod builds a Pod allocated the percent of each GPU in the minors.
+func buildGPUPod(name, nodeName string, percent int64, minors ...int32) *corev1.Pod {
+	return test.BuildTestPod(name, 1000, 0, nodeName, func(pod *corev1.Pod) {
+		test.SetRSOwnerRef(pod)
+		allocations := extension.DeviceAllocations{}
+		for _, minor := range minors {
+			allocations[schedulingv1alpha1.GPU] = append(allocations[schedulingv1alpha1.GPU], &extension.DeviceAllocation{
+				Minor: minor,
+				Resources: corev1.ResourceList{
+					extension.ResourceGPUCore:        *resource.NewQuantity(percent, resource.DecimalSI),
+					extension.ResourceGPUMemoryRatio: *resource.NewQuantity(percent, resource.DecimalSI),
+				},
+			})
+		}
+		data, _ := json.Marshal(allocations)
+		pod.Annotations = map[string]string{extension.AnnotationDeviceAllocated: string(data)}
+	})
+}
+
+func TestDeviceFragmentation(t *testing.T) {
+	node1 := test.BuildTestNode("node1", 8000, 8000, 10, nil)
+	node2 := test.BuildTestNode("node2", 8000, 8000, 10, nil)
+
+	defaultArgs := func() *deschedulerconfig.DeviceFragmentationArgs {
+		return &deschedulerconfig.DeviceFragmentationArgs{
+			DeviceTypes:             []string{"gpu"},
+			MaxPodsToEvictPerDevice: 4,
+		}
+	}
+
+	tests := []struct {
+		name            string
+		args            *deschedulerconfig.DeviceFragmentationArgs
+		devices         []*schedulingv1alpha1.Device
+		pods            []*corev1.Pod
+		expectedEvicted []string
+	}{
+		{
+			name: "paused plugin does nothing",
+			args: func() *deschedulerconfig.DeviceFragmentationArgs {
+				args := defaultArgs()
+				args.Paused = true
+				return args
+			}(),
+			devices: []*schedulingv1alpha1.Device{buildTestDevice("node1", 2), buildTestDevice("node2", 2)},
+			pods: []*corev1.Pod{
+				buildGPUPod("pod-a", "node1", 50, 0),
+				buildGPUPod("pod-b", "node1", 30, 1),
+				buildGPUPod("pod-c", "node2", 40, 0),
+			},
+		},
+		{
+			name:    "free the GPU with the least usage on the node without free GPUs",
+			args:    defaultArgs(),
+			devices: []*schedulingv1alpha1.Device{buildTestDevice("node1", 2), buildTestDevice("node2", 2)},
+			pods: []*corev1.Pod{
+				buildGPUPod("pod-a", "node1", 50, 0),
+				buildGPUPod("pod-b", "node1", 30, 1),
+				buildGPUPod("pod-c", "node2", 40, 0),
+			},
+			expectedEvicted: []string{"pod-b"},
+		},
+		{
+			name:    "migrate pods across nodes",
+			args:    defaultArgs(),
+			devices: []*schedulingv1alpha1.Device{buildTestDevice("node1", 1), buildTestDevice("node2", 2)},
+			pods: []*corev1.Pod{
+				buildGPUPod("pod-a", "node1", 20, 0),
+				buildGPUPod("pod-b", "node1", 20, 0),
+				buildGPUPod("pod-c", "node2", 50, 0),
+			},
+			expectedEvicted: []string{"pod-a", "pod-b"},
+		},
+		{
+			name:    "skip the pods allocated full GPUs",
+			args:    defaultArgs(),
+			devices: []*schedulingv1alpha1.Device{buildTestDevice("node1", 2), buildTestDevice("node2", 2)},
+			pods: []*corev1.Pod{
+				buildGPUPod("pod-a", "node1", 100, 0),
+				buildGPUPod("pod-b", "node1", 30, 1),
+				buildGPUPod("pod-c", "node1", 60, 1),
+				buildGPUPod("pod-d", "node2", 50, 0, 1),
+			},
+		},
+		{
+			name: "free the other GPU if the GPU requires too many migrations",
+			args: func() *deschedulerconfig.DeviceFragmentationArgs {
+				args := defaultArgs()
+				args.MaxPodsToEvictPerDevice = 1
+				return args
+			}(),
+			devices: []*schedulingv1alpha1.Device{buildTestDevice("node1", 1), buildTestDevice("node2", 2)},
+			pods: []*corev1.Pod{
+				buildGPUPod("pod-a", "node1", 20, 0),
+				buildGPUPod("pod-b", "node1", 20, 0),
+				buildGPUPod("pod-c", "node2", 50, 0),
+			},
+			expectedEvicted: []string{"pod-c"},
+		},
+		{
+			name:    "not fragmented",
+			args:    defaultArgs(),
+			devices: []*schedulingv1alpha1.Device{buildTestDevice("node1", 2), buildTestDevice("node2", 2)},
+			pods: []*corev1.Pod{
+				buildGPUPod("pod-a", "node1", 70, 0),
+				buildGPUPod("pod-b", "node1", 60, 1),
+			},
+		},
+		{
+			name:    "skip nodes without Device",
+			args:    defaultArgs(),
+			devices: []*schedulingv1alpha1.Device{buildTestDevice("node2", 2)},
+			pods: []*corev1.Pod{
+				buildGPUPod("pod-a", "node1", 50, 0),
+				buildGPUPod("pod-b", "node1", 30, 1),
+				buildGPUPod("pod-c", "node2", 40, 0),
+			},
+		},
+		{
+			name: "dry run does not evict",
+			args: func() *deschedulerconfig.DeviceFragmentationArgs {
+				args := defaultArgs()
+				args.DryRun = true
+				return args
+			}(),
+			devices: []*schedulingv1alpha1.Device{buildTestDevice("node1", 2), buildTestDevice("node2", 2)},
+			pods: []*corev1.Pod{
+				buildGPUPod("pod-a", "node1", 50, 0),
+				buildGPUPod("pod-b", "node1", 30, 1),
+				buildGPUPod("pod-c", "node2", 40, 0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, device := range tt.devices {
+				assert.NoError(t, indexer.Add(device))
+			}
+
+			evictor := &fakeEvictor{}
+			handle := &fakeHandle{evictor: evictor, pods: tt.pods}
+			pl := &DeviceFragmentation{
+				handle:       handle,
+				args:         tt.args,
+				podFilter:    func(pod *corev1.Pod) bool { return true },
+				deviceLister: schedulinglisters.NewDeviceLister(indexer),
+			}
+			status := pl.Balance(ctx, []*corev1.Node{node1, node2})
+			assert.Nil(t, status)
+
+			var evicted []string
+			for _, pod := range evictor.evicted {
+				evicted = append(evicted, pod.Name)
+			}
+			assert.Equal(t, tt.expectedEvicted, evicted)
+			for _, mode := range evictor.modes {
+				assert.Equal(t, schedulingv1alpha1.PodMigrationJobModeReservationFirst, mode)
+			}
+			assert.Len(t, evictor.modes, len(evictor.evicted))
+		})
+	}
+}
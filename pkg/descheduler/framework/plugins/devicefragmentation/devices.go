@@ -0,0 +1,248 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devicefragmentation
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+// deviceResourceNames are the resources of a device used to measure its allocation.
+var deviceResourceNames = map[schedulingv1alpha1.DeviceType][]corev1.ResourceName{
+	schedulingv1alpha1.GPU:  {extension.ResourceGPUCore, extension.ResourceGPUMemory, extension.ResourceGPUMemoryRatio},
+	schedulingv1alpha1.RDMA: {extension.ResourceRDMA},
+}
+
+// deviceState is the allocation state of a device.
+type deviceState struct {
+	nodeName string
+	minor    int32
+	// capacity only contains the resources in deviceResourceNames.
+	capacity corev1.ResourceList
+	used     corev1.ResourceList
+	pods     []*devicePod
+	// freeing indicates the Pods on the device are planned to migrate, so it can not hold other Pods.
+	freeing bool
+	// receiving indicates the device is planned to hold the migrated Pods, so it should not be freed.
+	receiving bool
+}
+
+func (d *deviceState) isFree() bool {
+	return len(d.pods) == 0
+}
+
+// usedRatio returns the max ratio of the used resources to the capacity.
+func (d *deviceState) usedRatio() float64 {
+	return maxResourceRatio(d.used, d.capacity)
+}
+
+func (d *deviceState) isPartial() bool {
+	return !d.isFree() && d.usedRatio() < 1
+}
+
+func (d *deviceState) fits(resources corev1.ResourceList) bool {
+	for name, capacity := range d.capacity {
+		request, ok := resources[name]
+		if !ok {
+			continue
+		}
+		used := d.used[name]
+		used.Add(request)
+		if used.Cmp(capacity) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// devicePod is a Pod with its allocation on a device.
+type devicePod struct {
+	pod *corev1.Pod
+	// resources are the resources allocated to the Pod on the device.
+	resources corev1.ResourceList
+	// shared indicates the Pod is allocated a part of a single device, and only the shared Pods are migrated.
+	shared bool
+}
+
+// nodeDeviceState is the allocation state of the devices of a type on a node.
+type nodeDeviceState struct {
+	nodeName string
+	devices  []*deviceState
+}
+
+// newNodeDeviceState builds the allocation state from the healthy devices reported by koordlet in Device,
+// and the allocations in the device allocation annotations of the Pods.
+func newNodeDeviceState(device *schedulingv1alpha1.Device, deviceType schedulingv1alpha1.DeviceType, pods []*corev1.Pod) *nodeDeviceState {
+	state := &nodeDeviceState{nodeName: device.Name}
+	devices := map[int32]*deviceState{}
+	for _, info := range device.Spec.Devices {
+		if info.Type != deviceType || !info.Health || info.Minor == nil {
+			continue
+		}
+		capacity := quotav1.Mask(info.Resources, deviceResourceNames[deviceType])
+		if len(capacity) == 0 {
+			continue
+		}
+		d := &deviceState{
+			nodeName: device.Name,
+			minor:    *info.Minor,
+			capacity: capacity,
+			used:     corev1.ResourceList{},
+		}
+		devices[d.minor] = d
+		state.devices = append(state.devices, d)
+	}
+	sort.Slice(state.devices, func(i, j int) bool {
+		return state.devices[i].minor < state.devices[j].minor
+	})
+
+	for _, pod := range pods {
+		allocations, err := extension.GetDeviceAllocations(pod.Annotations)
+		if err != nil {
+			klog.V(4).InfoS("Failed to get device allocations of pod", "pod", klog.KObj(pod), "err", err)
+			continue
+		}
+		for _, allocation := range allocations[deviceType] {
+			d := devices[allocation.Minor]
+			if d == nil {
+				continue
+			}
+			resources := quotav1.Mask(allocation.Resources, deviceResourceNames[deviceType])
+			p := &devicePod{pod: pod, resources: resources}
+			p.shared = len(allocations[deviceType]) == 1 && maxResourceRatio(resources, d.capacity) < 1
+			d.pods = append(d.pods, p)
+			d.used = quotav1.Add(d.used, resources)
+		}
+	}
+	return state
+}
+
+func maxResourceRatio(used, capacity corev1.ResourceList) float64 {
+	var ratio float64
+	for name, c := range capacity {
+		if c.IsZero() {
+			continue
+		}
+		u := used[name]
+		if r := float64(u.MilliValue()) / float64(c.MilliValue()); r > ratio {
+			ratio = r
+		}
+	}
+	return ratio
+}
+
+func (s *nodeDeviceState) freeDevices() int {
+	count := 0
+	for _, d := range s.devices {
+		if d.isFree() {
+			count++
+		}
+	}
+	return count
+}
+
+// defragmentPlanner plans the migrations to free the partially used devices. The Pods on a partially used device
+// are migrated only if they can be held by the free resources of the other partially used devices in the cluster,
+// so that the migrations do not occupy the free devices.
+type defragmentPlanner struct {
+	devices []*deviceState
+}
+
+func newDefragmentPlanner(states []*nodeDeviceState) *defragmentPlanner {
+	p := &defragmentPlanner{}
+	for _, state := range states {
+		p.devices = append(p.devices, state.devices...)
+	}
+	return p
+}
+
+// pickDeviceToFree picks the partially used device on the node which requires the fewest migrations, all its Pods
+// must be shared and migratable. It returns nil if no device can be freed.
+func (p *defragmentPlanner) pickDeviceToFree(state *nodeDeviceState, isMigratable func(p *devicePod) bool, maxPods int) (*deviceState, []*corev1.Pod) {
+	var candidates []*deviceState
+	for _, d := range state.devices {
+		if d.isPartial() && !d.freeing && !d.receiving && len(d.pods) <= maxPods {
+			candidates = append(candidates, d)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if len(candidates[i].pods) != len(candidates[j].pods) {
+			return len(candidates[i].pods) < len(candidates[j].pods)
+		}
+		return candidates[i].usedRatio() < candidates[j].usedRatio()
+	})
+
+	for _, d := range candidates {
+		migratable := true
+		for _, pod := range d.pods {
+			if !pod.shared || !isMigratable(pod) {
+				migratable = false
+				break
+			}
+		}
+		if !migratable || !p.reserve(d) {
+			continue
+		}
+		pods := make([]*corev1.Pod, 0, len(d.pods))
+		for _, pod := range d.pods {
+			pods = append(pods, pod.pod)
+		}
+		return d, pods
+	}
+	return nil, nil
+}
+
+// reserve places the Pods of the source device on the other partially used devices with the best fit. The
+// placements are kept if all the Pods are placed, otherwise they are reverted.
+func (p *defragmentPlanner) reserve(source *deviceState) bool {
+	type placement struct {
+		device    *deviceState
+		resources corev1.ResourceList
+	}
+	var placements []placement
+	for _, pod := range source.pods {
+		var best *deviceState
+		for _, d := range p.devices {
+			if d == source || d.freeing || !d.isPartial() || !d.fits(pod.resources) {
+				continue
+			}
+			if best == nil || d.usedRatio() > best.usedRatio() {
+				best = d
+			}
+		}
+		if best == nil {
+			for _, v := range placements {
+				v.device.used = quotav1.Subtract(v.device.used, v.resources)
+			}
+			return false
+		}
+		best.used = quotav1.Add(best.used, pod.resources)
+		placements = append(placements, placement{device: best, resources: pod.resources})
+	}
+
+	source.freeing = true
+	for _, v := range placements {
+		v.device.receiving = true
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devicefragmentation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/test"
+)
+
+func TestNewNodeDeviceState(t *testing.T) {
+	device := buildTestDevice("node1", 3)
+	device.Spec.Devices[2].Health = false
+	device.Spec.Devices = append(device.Spec.Devices, schedulingv1alpha1.DeviceInfo{
+		Type:      schedulingv1alpha1.RDMA,
+		Minor:     ptr.To[int32](0),
+		Health:    true,
+		Resources: corev1.ResourceList{extension.ResourceRDMA: resource.MustParse("100")},
+	})
+	rdmaPod := test.BuildTestPod("pod-rdma", 1000, 0, "node1", func(pod *corev1.Pod) {
+		data, _ := json.Marshal(extension.DeviceAllocations{
+			schedulingv1alpha1.RDMA: []*extension.DeviceAllocation{
+				{Minor: 0, Resources: corev1.ResourceList{extension.ResourceRDMA: resource.MustParse("1")}},
+			},
+		})
+		pod.Annotations = map[string]string{extension.AnnotationDeviceAllocated: string(data)}
+	})
+	pods := []*corev1.Pod{
+		buildGPUPod("pod-a", "node1", 30, 0),
+		buildGPUPod("pod-b", "node1", 20, 0),
+		buildGPUPod("pod-c", "node1", 100, 1),
+		buildGPUPod("pod-d", "node1", 50, 1, 2),
+		rdmaPod,
+	}
+
+	state := newNodeDeviceState(device, schedulingv1alpha1.GPU, pods)
+	assert.Equal(t, "node1", state.nodeName)
+	assert.Len(t, state.devices, 2)
+	assert.Equal(t, 0, state.freeDevices())
+
+	gpu0 := state.devices[0]
+	assert.Equal(t, int32(0), gpu0.minor)
+	assert.Len(t, gpu0.pods, 2)
+	assert.True(t, gpu0.pods[0].shared)
+	assert.True(t, gpu0.isPartial())
+	assert.InDelta(t, 0.5, gpu0.usedRatio(), 0.001)
+	assert.True(t, gpu0.fits(corev1.ResourceList{extension.ResourceGPUCore: resource.MustParse("50")}))
+	assert.False(t, gpu0.fits(corev1.ResourceList{extension.ResourceGPUCore: resource.MustParse("60")}))
+
+	gpu1 := state.devices[1]
+	assert.Len(t, gpu1.pods, 2)
+	assert.False(t, gpu1.pods[0].shared, "pod allocated a full GPU is not shared")
+	assert.False(t, gpu1.pods[1].shared, "pod allocated multiple GPUs is not shared")
+	assert.False(t, gpu1.isPartial())
+
+	rdmaState := newNodeDeviceState(device, schedulingv1alpha1.RDMA, pods)
+	assert.Len(t, rdmaState.devices, 1)
+	assert.Len(t, rdmaState.devices[0].pods, 1)
+	assert.True(t, rdmaState.devices[0].pods[0].shared)
+	assert.InDelta(t, 0.01, rdmaState.devices[0].usedRatio(), 0.001)
+}
+
+func TestDefragmentPlanner(t *testing.T) {
+	node1 := newNodeDeviceState(buildTestDevice("node1", 2), schedulingv1alpha1.GPU, []*corev1.Pod{
+		buildGPUPod("pod-a", "node1", 30, 0),
+		buildGPUPod("pod-b", "node1", 40, 1),
+	})
+	node2 := newNodeDeviceState(buildTestDevice("node2", 2), schedulingv1alpha1.GPU, []*corev1.Pod{
+		buildGPUPod("pod-c", "node2", 60, 0),
+	})
+	planner := newDefragmentPlanner([]*nodeDeviceState{node1, node2})
+	all := func(p *devicePod) bool { return true }
+
+	device, pods := planner.pickDeviceToFree(node1, func(p *devicePod) bool { return false }, 4)
+	assert.Nil(t, device)
+	assert.Nil(t, pods)
+	assert.InDelta(t, 0.6, node2.devices[0].usedRatio(), 0.001, "nothing is reserved if no pod is migratable")
+
+	device, pods = planner.pickDeviceToFree(node1, all, 4)
+	assert.Equal(t, node1.devices[0], device)
+	assert.Equal(t, "pod-a", pods[0].Name)
+	assert.True(t, device.freeing)
+	// pod-a is placed on the GPU with the highest usage
+	assert.True(t, node2.devices[0].receiving)
+	assert.InDelta(t, 0.9, node2.devices[0].usedRatio(), 0.001)
+
+	// the GPU of node2 is receiving pods and the other GPU of node1 can not be held by the freeing GPU
+	device, _ = planner.pickDeviceToFree(node2, all, 4)
+	assert.Nil(t, device)
+	device, _ = planner.pickDeviceToFree(node1, all, 4)
+	assert.Nil(t, device)
+}
@@ -30,16 +30,20 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	koordschedulinglisters "github.com/koordinator-sh/koordinator/pkg/client/listers/scheduling/v1alpha1"
 	koordslolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/metrics"
 	nodeutil "github.com/koordinator-sh/koordinator/pkg/descheduler/node"
 	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/anomaly"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/history"
 )
 
 const (
@@ -54,9 +58,12 @@ type LowNodeLoad struct {
 	handle               framework.Handle
 	podFilter            framework.FilterFunc
 	nodeMetricLister     koordslolisters.NodeMetricLister
+	reservationLister    koordschedulinglisters.ReservationLister
 	args                 *deschedulerconfig.LowNodeLoadArgs
 	nodeAnomalyDetectors *gocache.Cache
 	prodAnomalyDetectors *gocache.Cache
+	migrationHistory     *history.Store
+	clock                clock.PassiveClock
 }
 
 // NewLowNodeLoad builds plugin from its arguments while passing a handle
@@ -104,6 +111,21 @@ func NewLowNodeLoad(ctx context.Context, args runtime.Object, handle framework.H
 	koordSharedInformerFactory := koordinformers.NewSharedInformerFactory(koordClientSet, 0)
 	nodeMetricInformer := koordSharedInformerFactory.Slo().V1alpha1().NodeMetrics()
 	nodeMetricInformer.Informer()
+	var reservationLister koordschedulinglisters.ReservationLister
+	if loadLoadUtilizationArgs.Planning != nil && loadLoadUtilizationArgs.Planning.Enabled {
+		reservationInformer := koordSharedInformerFactory.Scheduling().V1alpha1().Reservations()
+		reservationInformer.Informer()
+		reservationLister = reservationInformer.Lister()
+	}
+	realClock := clock.RealClock{}
+	var migrationHistory *history.Store
+	if loadLoadUtilizationArgs.DestinationCooldown != nil && loadLoadUtilizationArgs.DestinationCooldown.Duration > 0 {
+		migrationHistory = history.NewStoreWithClock(loadLoadUtilizationArgs.DestinationCooldown.Duration, realClock)
+		podMigrationJobInformer := koordSharedInformerFactory.Scheduling().V1alpha1().PodMigrationJobs()
+		if _, err := podMigrationJobInformer.Informer().AddEventHandler(migrationHistory); err != nil {
+			return nil, err
+		}
+	}
 	koordSharedInformerFactory.Start(ctx.Done())
 	koordSharedInformerFactory.WaitForCacheSync(ctx.Done())
 
@@ -113,10 +135,13 @@ func NewLowNodeLoad(ctx context.Context, args runtime.Object, handle framework.H
 	return &LowNodeLoad{
 		handle:               handle,
 		nodeMetricLister:     nodeMetricInformer.Lister(),
+		reservationLister:    reservationLister,
 		args:                 loadLoadUtilizationArgs,
 		podFilter:            podFilter,
 		nodeAnomalyDetectors: nodeAnomalyDetectors,
 		prodAnomalyDetectors: prodAnomalyDetectors,
+		migrationHistory:     migrationHistory,
+		clock:                realClock,
 	}, nil
 }
 
@@ -178,6 +203,13 @@ func (pl *LowNodeLoad) processOneNodePool(ctx context.Context, nodePool *desched
 		return nil
 	}
 
+	sourceNodes = filterRecentDestinationNodes(sourceNodes, pl.migrationHistory, pl.args.DestinationCooldown, pl.clock)
+	prodHighNodes = filterRecentDestinationNodes(prodHighNodes, pl.migrationHistory, pl.args.DestinationCooldown, pl.clock)
+	if len(sourceNodes) == 0 && len(prodHighNodes) == 0 {
+		klog.V(4).InfoS("All overutilized nodes received migrated Pods recently, nothing to do here", "nodePool", nodePool.Name)
+		return nil
+	}
+
 	abnormalNodes := filterRealAbnormalNodes(sourceNodes, pl.nodeAnomalyDetectors, nodePool.AnomalyCondition)
 	abnormalProdNodes := filterRealAbnormalNodes(prodHighNodes, pl.prodAnomalyDetectors, nodePool.AnomalyCondition)
 	if len(abnormalNodes) == 0 && len(abnormalProdNodes) == 0 {
@@ -237,26 +269,41 @@ func (pl *LowNodeLoad) processOneNodePool(ctx context.Context, nodePool *desched
 	sortNodesByUsage(abnormalNodes, nodePool.ResourceWeights, false, false)
 	sortNodesByUsage(abnormalProdNodes, nodePool.ResourceWeights, false, true)
 
-	evictPodsFromSourceNodes(
-		ctx,
-		nodePool.Name,
-		abnormalNodes,
-		lowNodes,
-		abnormalProdNodes,
-		prodLowNodes,
-		bothLowNodes,
-		nodeUsages,
-		nodeThresholds,
-		pl.args.DryRun,
-		pl.args.NodeFit,
-		nodePool.ResourceWeights,
-		pl.handle.Evictor(),
-		pl.podFilter,
-		pl.handle.GetPodsAssignedToNodeFunc(),
-		resourceNames,
-		continueEvictionCond,
-		overUtilizedEvictionReason(highThresholds, prodHighThresholds),
-	)
+	if pl.args.Planning != nil && pl.args.Planning.Enabled {
+		pl.planAndMigrate(
+			ctx,
+			nodePool.Name,
+			abnormalNodes,
+			lowNodes,
+			abnormalProdNodes,
+			prodLowNodes,
+			bothLowNodes,
+			nodeUsages,
+			nodePool.ResourceWeights,
+			overUtilizedEvictionReason(highThresholds, prodHighThresholds),
+		)
+	} else {
+		evictPodsFromSourceNodes(
+			ctx,
+			nodePool.Name,
+			abnormalNodes,
+			lowNodes,
+			abnormalProdNodes,
+			prodLowNodes,
+			bothLowNodes,
+			nodeUsages,
+			nodeThresholds,
+			pl.args.DryRun,
+			pl.args.NodeFit,
+			nodePool.ResourceWeights,
+			pl.handle.Evictor(),
+			pl.podFilter,
+			pl.handle.GetPodsAssignedToNodeFunc(),
+			resourceNames,
+			continueEvictionCond,
+			overUtilizedEvictionReason(highThresholds, prodHighThresholds),
+		)
+	}
 	tryMarkNodesAsNormal(abnormalNodes, pl.nodeAnomalyDetectors)
 	tryMarkNodesAsNormal(abnormalProdNodes, pl.prodAnomalyDetectors)
 	for _, v := range sourceNodes {
@@ -311,6 +358,24 @@ func filterRealAbnormalNodes(sourceNodes []NodeInfo, nodeAnomalyDetectors *gocac
 	return abnormalNodes
 }
 
+// filterRecentDestinationNodes filters out the nodes which received migrated Pods within the cooldown, so that
+// the Pods just moved in are not moved out again.
+func filterRecentDestinationNodes(sourceNodes []NodeInfo, migrationHistory *history.Store, cooldown *metav1.Duration, clock clock.PassiveClock) []NodeInfo {
+	if migrationHistory == nil || cooldown == nil || cooldown.Duration <= 0 {
+		return sourceNodes
+	}
+	var nodes []NodeInfo
+	for _, v := range sourceNodes {
+		if last, ok := migrationHistory.LastMigrationToNode(v.node.Name); ok && clock.Since(last) < cooldown.Duration {
+			klog.V(4).InfoS("Node received migrated Pods recently, thus not considered as source node", "node", klog.KObj(v.node), "lastMigration", last)
+			metrics.MigrationCooldownHits.WithLabelValues(metrics.MigrationCooldownKindNode).Inc()
+			continue
+		}
+		nodes = append(nodes, v)
+	}
+	return nodes
+}
+
 func newThresholds(useDeviationThresholds bool, low, high, lowProd, highProd deschedulerconfig.ResourceThresholds) (thresholds, highThresholds, prodThreshold, highProdThreshold deschedulerconfig.ResourceThresholds) {
 	thresholds = low
 	highThresholds = high
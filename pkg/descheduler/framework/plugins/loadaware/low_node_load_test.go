@@ -34,6 +34,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	coretesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/events"
+	clocktesting "k8s.io/utils/clock/testing"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
@@ -48,6 +49,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/test"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/anomaly"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/history"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -2033,6 +2035,55 @@ func Test_filterRealAbnormalNodes(t *testing.T) {
 	}
 }
 
+func Test_filterRecentDestinationNodes(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	migrationHistory := history.NewStoreWithClock(time.Hour, fakeClock)
+	migrationHistory.Add(&history.Record{JobUID: "job-1", DestinationNode: "test-node-1", Timestamp: fakeClock.Now().Add(-10 * time.Minute)})
+	migrationHistory.Add(&history.Record{JobUID: "job-2", DestinationNode: "test-node-2", Timestamp: fakeClock.Now().Add(-40 * time.Minute)})
+
+	tests := []struct {
+		name             string
+		migrationHistory *history.Store
+		cooldown         *metav1.Duration
+		want             []string
+	}{
+		{
+			name:             "cooldown disabled",
+			migrationHistory: migrationHistory,
+			want:             []string{"test-node-1", "test-node-2", "test-node-3"},
+		},
+		{
+			name:             "filter out the nodes received migrated pods within the cooldown",
+			migrationHistory: migrationHistory,
+			cooldown:         &metav1.Duration{Duration: 30 * time.Minute},
+			want:             []string{"test-node-2", "test-node-3"},
+		},
+		{
+			name:             "longer cooldown",
+			migrationHistory: migrationHistory,
+			cooldown:         &metav1.Duration{Duration: time.Hour},
+			want:             []string{"test-node-3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sourceNodes []NodeInfo
+			for _, name := range []string{"test-node-1", "test-node-2", "test-node-3"} {
+				sourceNodes = append(sourceNodes, NodeInfo{
+					NodeUsage: &NodeUsage{
+						node: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}},
+					},
+				})
+			}
+			var got []string
+			for _, v := range filterRecentDestinationNodes(sourceNodes, tt.migrationHistory, tt.cooldown, fakeClock) {
+				got = append(got, v.node.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_GetNodeRawAllocatableForDescheduler(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,465 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	nodeutil "github.com/koordinator-sh/koordinator/pkg/descheduler/node"
+	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
+)
+
+const (
+	EventReasonMigrationPlanned       = "MigrationPlanned"
+	EventReasonMigrationPlanRejected  = "MigrationPlanRejected"
+	eventActionMigrationPlanReporting = "Descheduling"
+)
+
+// MigrationPlan is the what-if result of LowNodeLoad on a node pool.
+type MigrationPlan struct {
+	NodePool string `json:"nodePool"`
+	// Converged indicates all the source nodes are under the high thresholds after all the moves
+	Converged bool `json:"converged"`
+	// DryRun indicates the plan is reported only
+	DryRun bool            `json:"dryRun"`
+	Moves  []MigrationMove `json:"moves,omitempty"`
+	// UnresolvedNodes are the source nodes still overutilized after all the moves
+	UnresolvedNodes []string           `json:"unresolvedNodes,omitempty"`
+	Nodes           []PlannedNodeUsage `json:"nodes,omitempty"`
+}
+
+// MigrationMove is a Pod moved from the source node to the target node in the plan.
+type MigrationMove struct {
+	Pod        string              `json:"pod"`
+	Prod       bool                `json:"prod"`
+	SourceNode string              `json:"sourceNode"`
+	TargetNode string              `json:"targetNode"`
+	Usage      corev1.ResourceList `json:"usage,omitempty"`
+
+	pod        *corev1.Pod
+	sourceNode NodeInfo
+}
+
+// PlannedNodeUsage is the usage percentages of a node before and after the plan.
+type PlannedNodeUsage struct {
+	Node       string                          `json:"node"`
+	Source     bool                            `json:"source"`
+	Before     map[corev1.ResourceName]float64 `json:"before,omitempty"`
+	After      map[corev1.ResourceName]float64 `json:"after,omitempty"`
+	ProdBefore map[corev1.ResourceName]float64 `json:"prodBefore,omitempty"`
+	ProdAfter  map[corev1.ResourceName]float64 `json:"prodAfter,omitempty"`
+}
+
+// migrationPlanner simulates the migrations on the copies of the node usages, so that every move takes the previous
+// moves into account, and the nodes of the pool are never touched before the plan is accepted.
+type migrationPlanner struct {
+	nodePoolName    string
+	nodeFit         bool
+	maxMoves        int32
+	resourceWeights map[corev1.ResourceName]int64
+	podFilter       framework.FilterFunc
+	nodeIndexer     podutil.GetPodsAssignedToNodeFunc
+	// nodes are the simulated node infos, the usage of destination nodes includes the reserved resources
+	nodes       map[string]NodeInfo
+	sourceNodes sets.String
+	plannedPods sets.String
+	plan        *MigrationPlan
+}
+
+func newMigrationPlanner(
+	nodePoolName string,
+	nodeFit bool,
+	maxMoves int32,
+	resourceWeights map[corev1.ResourceName]int64,
+	podFilter framework.FilterFunc,
+	nodeIndexer podutil.GetPodsAssignedToNodeFunc,
+	reservedResources map[string]corev1.ResourceList,
+	sourceNodes, destinationNodes []NodeInfo,
+) *migrationPlanner {
+	p := &migrationPlanner{
+		nodePoolName:    nodePoolName,
+		nodeFit:         nodeFit,
+		maxMoves:        maxMoves,
+		resourceWeights: resourceWeights,
+		podFilter:       podFilter,
+		nodeIndexer:     nodeIndexer,
+		nodes:           map[string]NodeInfo{},
+		sourceNodes:     sets.NewString(),
+		plannedPods:     sets.NewString(),
+		plan:            &MigrationPlan{NodePool: nodePoolName},
+	}
+	for _, nodeInfo := range sourceNodes {
+		p.sourceNodes.Insert(nodeInfo.node.Name)
+		p.addNode(nodeInfo, nil)
+	}
+	for _, nodeInfo := range destinationNodes {
+		p.addNode(nodeInfo, reservedResources[nodeInfo.node.Name])
+	}
+	return p
+}
+
+func (p *migrationPlanner) addNode(nodeInfo NodeInfo, reserved corev1.ResourceList) {
+	if _, ok := p.nodes[nodeInfo.node.Name]; ok {
+		return
+	}
+	cloned := cloneNodeInfo(nodeInfo)
+	// the resources reserved but not allocated will be used by the pending pods
+	for resourceName, quantity := range reserved {
+		if used := cloned.usage[resourceName]; used != nil {
+			used.Add(quantity)
+		}
+		if used := cloned.prodUsage[resourceName]; used != nil {
+			used.Add(quantity)
+		}
+	}
+	p.nodes[nodeInfo.node.Name] = cloned
+}
+
+// planMoves picks the victims on the source nodes in order and assigns each of them to the least used destination node
+// which fits the pod, until the source node is not overutilized.
+func (p *migrationPlanner) planMoves(sourceNodes, destinationNodes []NodeInfo, prod bool) {
+	var targets []NodeInfo
+	for _, nodeInfo := range destinationNodes {
+		if p.sourceNodes.Has(nodeInfo.node.Name) {
+			continue
+		}
+		targets = append(targets, p.nodes[nodeInfo.node.Name])
+	}
+	if len(targets) == 0 {
+		klog.V(4).InfoS("No target nodes available for planning", "nodePool", p.nodePoolName, "prod", prod)
+		return
+	}
+
+	for _, original := range sourceNodes {
+		srcNode := p.nodes[original.node.Name]
+		allPods := srcNode.allPods
+		if prod {
+			allPods = srcNode.prodPods
+		}
+		var removablePods []*corev1.Pod
+		for _, pod := range allPods {
+			if p.plannedPods.Has(podKey(pod)) || !p.podFilter(pod) {
+				continue
+			}
+			if srcNode.podMetrics[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] == nil {
+				continue
+			}
+			removablePods = append(removablePods, pod)
+		}
+		sortPodsOnOneOverloadedNode(srcNode, removablePods, p.resourceWeights, prod)
+
+		for _, pod := range removablePods {
+			if !isPlannedNodeOverutilized(srcNode, prod) {
+				break
+			}
+			if p.maxMoves > 0 && len(p.plan.Moves) >= int(p.maxMoves) {
+				klog.V(4).InfoS("Planned moves reach the max", "nodePool", p.nodePoolName, "maxMoves", p.maxMoves)
+				return
+			}
+			podUsage := getPodUsage(srcNode, pod)
+			target := p.pickTarget(pod, podUsage, targets, prod)
+			if target == nil {
+				klog.V(4).InfoS("No target node fits the pod in planning", "pod", klog.KObj(pod), "node", klog.KObj(srcNode.node), "nodePool", p.nodePoolName)
+				continue
+			}
+			isProdPod := extension.GetPodPriorityClassWithDefault(pod) == extension.PriorityProd
+			applyPodUsage(srcNode, podUsage, isProdPod, false)
+			applyPodUsage(*target, podUsage, isProdPod, true)
+			p.plannedPods.Insert(podKey(pod))
+			p.plan.Moves = append(p.plan.Moves, MigrationMove{
+				Pod:        podKey(pod),
+				Prod:       prod,
+				SourceNode: srcNode.node.Name,
+				TargetNode: target.node.Name,
+				Usage:      podUsage,
+				pod:        pod,
+				sourceNode: original,
+			})
+			klog.V(4).InfoS("Planned to move pod", "pod", klog.KObj(pod), "node", klog.KObj(srcNode.node), "target", klog.KObj(target.node), "prod", prod, "nodePool", p.nodePoolName)
+		}
+	}
+}
+
+// pickTarget returns the least used target node which fits the pod and stays under the high thresholds after the pod
+// is moved to.
+func (p *migrationPlanner) pickTarget(pod *corev1.Pod, podUsage corev1.ResourceList, targets []NodeInfo, prod bool) *NodeInfo {
+	sortNodesByUsage(targets, p.resourceWeights, true, prod)
+	isProdPod := extension.GetPodPriorityClassWithDefault(pod) == extension.PriorityProd
+	for i := range targets {
+		target := &targets[i]
+		if p.nodeFit {
+			if errs := nodeutil.NodeFit(p.nodeIndexer, pod, target.node); len(errs) > 0 {
+				klog.V(5).InfoS("Pod does not fit on node in planning", "pod", klog.KObj(pod), "node", klog.KObj(target.node), "errors", utilerrors.NewAggregate(errs))
+				continue
+			}
+		}
+		if exceedsThresholds(target.usage, target.thresholds.highResourceThreshold, podUsage) {
+			continue
+		}
+		if isProdPod && exceedsThresholds(target.prodUsage, target.thresholds.prodHighResourceThreshold, podUsage) {
+			continue
+		}
+		return target
+	}
+	return nil
+}
+
+// finish checks whether the plan converges and fills the node usages of the plan.
+func (p *migrationPlanner) finish(sourceNodes, prodSourceNodes []NodeInfo, originalNodes map[string]*NodeUsage) *MigrationPlan {
+	unresolved := sets.NewString()
+	for _, nodeInfo := range sourceNodes {
+		if isPlannedNodeOverutilized(p.nodes[nodeInfo.node.Name], false) {
+			unresolved.Insert(nodeInfo.node.Name)
+		}
+	}
+	for _, nodeInfo := range prodSourceNodes {
+		if isPlannedNodeOverutilized(p.nodes[nodeInfo.node.Name], true) {
+			unresolved.Insert(nodeInfo.node.Name)
+		}
+	}
+	p.plan.UnresolvedNodes = unresolved.List()
+	p.plan.Converged = unresolved.Len() == 0
+
+	nodeNames := make([]string, 0, len(p.nodes))
+	for nodeName := range p.nodes {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		nodeUsage := PlannedNodeUsage{
+			Node:      nodeName,
+			Source:    p.sourceNodes.Has(nodeName),
+			After:     resourceUsagePercentages(p.nodes[nodeName].NodeUsage, false),
+			ProdAfter: resourceUsagePercentages(p.nodes[nodeName].NodeUsage, true),
+		}
+		if original := originalNodes[nodeName]; original != nil {
+			nodeUsage.Before = resourceUsagePercentages(original, false)
+			nodeUsage.ProdBefore = resourceUsagePercentages(original, true)
+		}
+		p.plan.Nodes = append(p.plan.Nodes, nodeUsage)
+	}
+	return p.plan
+}
+
+// planAndMigrate simulates the migrations of the node pool, reports the plan and evicts the planned pods only if the
+// plan converges and the plugin is not in dry-run mode.
+func (pl *LowNodeLoad) planAndMigrate(
+	ctx context.Context,
+	nodePoolName string,
+	sourceNodes, destinationNodes,
+	prodSourceNodes, prodDestinationNodes, bothDestinationNodes []NodeInfo,
+	nodeUsages map[string]*NodeUsage,
+	resourceWeights map[corev1.ResourceName]int64,
+	evictionReasonGenerator evictionReasonGeneratorFn,
+) {
+	var reservedResources map[string]corev1.ResourceList
+	if pl.reservationLister != nil {
+		reservations, err := pl.reservationLister.List(labels.Everything())
+		if err != nil {
+			klog.ErrorS(err, "Failed to list reservations for planning", "nodePool", nodePoolName)
+		} else {
+			reservedResources = getReservedResources(reservations)
+		}
+	}
+
+	targetNodes := append(append([]NodeInfo{}, destinationNodes...), bothDestinationNodes...)
+	prodTargetNodes := append(append([]NodeInfo{}, prodDestinationNodes...), bothDestinationNodes...)
+	allSourceNodes := append(append([]NodeInfo{}, sourceNodes...), prodSourceNodes...)
+	allTargetNodes := append(append([]NodeInfo{}, targetNodes...), prodTargetNodes...)
+	planner := newMigrationPlanner(nodePoolName, pl.args.NodeFit, pl.args.Planning.MaxMoves, resourceWeights,
+		pl.podFilter, pl.handle.GetPodsAssignedToNodeFunc(), reservedResources, allSourceNodes, allTargetNodes)
+	planner.planMoves(sourceNodes, targetNodes, false)
+	planner.planMoves(prodSourceNodes, prodTargetNodes, true)
+	plan := planner.finish(sourceNodes, prodSourceNodes, nodeUsages)
+	plan.DryRun = pl.args.DryRun
+
+	reportMigrationPlan(pl.handle.EventRecorder(), plan, nodeUsages)
+	if !plan.Converged {
+		klog.InfoS("Migration plan does not converge, skip evicting", "nodePool", nodePoolName, "unresolvedNodes", plan.UnresolvedNodes)
+		return
+	}
+	if plan.DryRun {
+		return
+	}
+	executeMigrationPlan(ctx, plan, pl.handle.Evictor(), evictionReasonGenerator)
+}
+
+// reportMigrationPlan reports the plan in JSON by the logs, and records an event on each source node.
+func reportMigrationPlan(eventRecorder events.EventRecorder, plan *MigrationPlan, nodes map[string]*NodeUsage) {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		klog.ErrorS(err, "Failed to marshal migration plan", "nodePool", plan.NodePool)
+	} else {
+		klog.InfoS("LowNodeLoad migration plan", "nodePool", plan.NodePool, "converged", plan.Converged, "dryRun", plan.DryRun, "plan", string(data))
+	}
+	if eventRecorder == nil {
+		return
+	}
+
+	movesBySource := map[string][]string{}
+	for _, move := range plan.Moves {
+		movesBySource[move.SourceNode] = append(movesBySource[move.SourceNode], fmt.Sprintf("%s->%s", move.Pod, move.TargetNode))
+	}
+	for _, nodeUsage := range plan.Nodes {
+		if !nodeUsage.Source || nodes[nodeUsage.Node] == nil {
+			continue
+		}
+		node := nodes[nodeUsage.Node].node
+		moves := movesBySource[nodeUsage.Node]
+		if plan.Converged {
+			eventRecorder.Eventf(node, nil, corev1.EventTypeNormal, EventReasonMigrationPlanned, eventActionMigrationPlanReporting,
+				"LowNodeLoad planned %d moves from the node in node pool %q, dryRun %v: %v", len(moves), plan.NodePool, plan.DryRun, moves)
+		} else {
+			eventRecorder.Eventf(node, nil, corev1.EventTypeWarning, EventReasonMigrationPlanRejected, eventActionMigrationPlanReporting,
+				"LowNodeLoad rejected the plan of node pool %q since nodes %v are still overutilized after %d moves", plan.NodePool, plan.UnresolvedNodes, len(plan.Moves))
+		}
+	}
+}
+
+// executeMigrationPlan evicts the pods of the converged plan.
+func executeMigrationPlan(ctx context.Context, plan *MigrationPlan, podEvictor framework.Evictor, evictionReasonGenerator evictionReasonGeneratorFn) {
+	for _, move := range plan.Moves {
+		evictionOptions := framework.EvictOptions{
+			Reason: evictionReasonGenerator(move.sourceNode, move.Prod),
+		}
+		if !podEvictor.Evict(ctx, move.pod, evictionOptions) {
+			klog.InfoS("Failed to Evict Pod", "pod", klog.KObj(move.pod), "node", move.SourceNode, "nodePool", plan.NodePool)
+			continue
+		}
+		klog.InfoS("Evicted Pod", "pod", klog.KObj(move.pod), "node", move.SourceNode, "target", move.TargetNode, "nodePool", plan.NodePool)
+	}
+}
+
+// getReservedResources returns the resources reserved but not allocated by the available reservations on each node.
+func getReservedResources(reservations []*schedulingv1alpha1.Reservation) map[string]corev1.ResourceList {
+	reserved := map[string]corev1.ResourceList{}
+	for _, r := range reservations {
+		if !reservationutil.IsReservationAvailable(r) {
+			continue
+		}
+		nodeName := reservationutil.GetReservationNodeName(r)
+		free := reserved[nodeName]
+		if free == nil {
+			free = corev1.ResourceList{}
+			reserved[nodeName] = free
+		}
+		for resourceName, allocatable := range r.Status.Allocatable {
+			quantity := allocatable.DeepCopy()
+			if allocated, ok := r.Status.Allocated[resourceName]; ok {
+				quantity.Sub(allocated)
+			}
+			if quantity.Sign() <= 0 {
+				continue
+			}
+			total := free[resourceName]
+			total.Add(quantity)
+			free[resourceName] = total
+		}
+	}
+	return reserved
+}
+
+func cloneNodeInfo(nodeInfo NodeInfo) NodeInfo {
+	nodeUsage := *nodeInfo.NodeUsage
+	nodeUsage.usage = cloneUsage(nodeInfo.usage)
+	nodeUsage.prodUsage = cloneUsage(nodeInfo.prodUsage)
+	return NodeInfo{NodeUsage: &nodeUsage, thresholds: nodeInfo.thresholds}
+}
+
+func cloneUsage(usage map[corev1.ResourceName]*resource.Quantity) map[corev1.ResourceName]*resource.Quantity {
+	cloned := make(map[corev1.ResourceName]*resource.Quantity, len(usage))
+	for resourceName, quantity := range usage {
+		q := quantity.DeepCopy()
+		cloned[resourceName] = &q
+	}
+	return cloned
+}
+
+func isPlannedNodeOverutilized(nodeInfo NodeInfo, prod bool) bool {
+	if prod {
+		_, overutilized := isNodeOverutilized(nodeInfo.prodUsage, nodeInfo.thresholds.prodHighResourceThreshold)
+		return overutilized
+	}
+	_, overutilized := isNodeOverutilized(nodeInfo.usage, nodeInfo.thresholds.highResourceThreshold)
+	return overutilized
+}
+
+func exceedsThresholds(usage, thresholds map[corev1.ResourceName]*resource.Quantity, podUsage corev1.ResourceList) bool {
+	for resourceName, threshold := range thresholds {
+		used := usage[resourceName]
+		if used == nil {
+			continue
+		}
+		q := used.DeepCopy()
+		q.Add(podUsage[resourceName])
+		if q.Cmp(*threshold) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func getPodUsage(nodeInfo NodeInfo, pod *corev1.Pod) corev1.ResourceList {
+	podUsage := corev1.ResourceList{}
+	if podMetric := nodeInfo.podMetrics[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]; podMetric != nil {
+		for resourceName, quantity := range podMetric.ResourceList {
+			podUsage[resourceName] = quantity.DeepCopy()
+		}
+	}
+	podUsage[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+	return podUsage
+}
+
+func applyPodUsage(nodeInfo NodeInfo, podUsage corev1.ResourceList, isProdPod, add bool) {
+	apply := func(usage map[corev1.ResourceName]*resource.Quantity) {
+		for resourceName, used := range usage {
+			quantity, ok := podUsage[resourceName]
+			if !ok {
+				continue
+			}
+			if add {
+				used.Add(quantity)
+			} else {
+				used.Sub(quantity)
+			}
+		}
+	}
+	apply(nodeInfo.usage)
+	if isProdPod {
+		apply(nodeInfo.prodUsage)
+	}
+}
+
+func podKey(pod *corev1.Pod) string {
+	return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+}
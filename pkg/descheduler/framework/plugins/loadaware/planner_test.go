@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/test"
+)
+
+func newPlanningNodeInfo(name string, cpuUsage int64, pods []*corev1.Pod, podCPUUsages []int64) NodeInfo {
+	node := test.BuildTestNode(name, 10000, 10000, 100, nil)
+	podMetrics := map[types.NamespacedName]*slov1alpha1.ResourceMap{}
+	for i, pod := range pods {
+		podMetrics[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = &slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewMilliQuantity(podCPUUsages[i], resource.DecimalSI),
+			},
+		}
+	}
+	return NodeInfo{
+		NodeUsage: &NodeUsage{
+			node:    node,
+			allPods: pods,
+			usage: map[corev1.ResourceName]*resource.Quantity{
+				corev1.ResourceCPU: resource.NewMilliQuantity(cpuUsage, resource.DecimalSI),
+			},
+			prodUsage: map[corev1.ResourceName]*resource.Quantity{
+				corev1.ResourceCPU: resource.NewMilliQuantity(0, resource.DecimalSI),
+			},
+			podMetrics: podMetrics,
+		},
+		thresholds: NodeThresholds{
+			highResourceThreshold: map[corev1.ResourceName]*resource.Quantity{
+				corev1.ResourceCPU: resource.NewMilliQuantity(7000, resource.DecimalSI),
+			},
+			prodHighResourceThreshold: map[corev1.ResourceName]*resource.Quantity{
+				corev1.ResourceCPU: resource.NewMilliQuantity(7000, resource.DecimalSI),
+			},
+		},
+	}
+}
+
+func TestMigrationPlanner(t *testing.T) {
+	pod1 := test.BuildTestPod("pod-1", 1000, 0, "node-src", test.SetRSOwnerRef)
+	pod2 := test.BuildTestPod("pod-2", 1000, 0, "node-src", test.SetRSOwnerRef)
+	pod3 := test.BuildTestPod("pod-3", 1000, 0, "node-src", test.SetRSOwnerRef)
+	tests := []struct {
+		name              string
+		sourceCPU         int64
+		targetCPUs        []int64
+		maxMoves          int32
+		reservedResources map[string]corev1.ResourceList
+		wantConverged     bool
+		wantMoves         []string
+		wantUnresolved    []string
+	}{
+		{
+			name:          "converge by moving pods to the least used targets",
+			sourceCPU:     10000,
+			targetCPUs:    []int64{3000, 500},
+			wantConverged: true,
+			wantMoves:     []string{"pod-3->node-dst-1", "pod-2->node-dst-1"},
+		},
+		{
+			name:           "reject the plan since targets cannot absorb the pods",
+			sourceCPU:      9000,
+			targetCPUs:     []int64{6500, 6600},
+			wantConverged:  false,
+			wantMoves:      []string{"pod-1->node-dst-0"},
+			wantUnresolved: []string{"node-src"},
+		},
+		{
+			name:       "reserved resources are taken into account on targets",
+			sourceCPU:  9000,
+			targetCPUs: []int64{3000},
+			reservedResources: map[string]corev1.ResourceList{
+				"node-dst-0": {corev1.ResourceCPU: resource.MustParse("3")},
+			},
+			wantConverged:  false,
+			wantMoves:      []string{"pod-1->node-dst-0"},
+			wantUnresolved: []string{"node-src"},
+		},
+		{
+			name:           "stop at max moves",
+			sourceCPU:      10000,
+			targetCPUs:     []int64{500},
+			maxMoves:       1,
+			wantConverged:  false,
+			wantMoves:      []string{"pod-3->node-dst-0"},
+			wantUnresolved: []string{"node-src"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newPlanningNodeInfo("node-src", tt.sourceCPU, []*corev1.Pod{pod1, pod2, pod3}, []int64{500, 1500, 2000})
+			var targets []NodeInfo
+			nodeUsages := map[string]*NodeUsage{source.node.Name: source.NodeUsage}
+			for i, cpu := range tt.targetCPUs {
+				target := newPlanningNodeInfo("node-dst-"+string(rune('0'+i)), cpu, nil, nil)
+				targets = append(targets, target)
+				nodeUsages[target.node.Name] = target.NodeUsage
+			}
+
+			planner := newMigrationPlanner("test-pool", false, tt.maxMoves, map[corev1.ResourceName]int64{corev1.ResourceCPU: 1},
+				func(*corev1.Pod) bool { return true }, nil, tt.reservedResources, []NodeInfo{source}, targets)
+			planner.planMoves([]NodeInfo{source}, targets, false)
+			plan := planner.finish([]NodeInfo{source}, nil, nodeUsages)
+
+			assert.Equal(t, tt.wantConverged, plan.Converged)
+			var moves []string
+			for _, move := range plan.Moves {
+				moves = append(moves, move.pod.Name+"->"+move.TargetNode)
+			}
+			assert.Equal(t, tt.wantMoves, moves)
+			if len(tt.wantUnresolved) == 0 {
+				assert.Empty(t, plan.UnresolvedNodes)
+			} else {
+				assert.Equal(t, tt.wantUnresolved, plan.UnresolvedNodes)
+			}
+			// the original node usages are never changed by the planning
+			assert.Equal(t, tt.sourceCPU, source.usage[corev1.ResourceCPU].MilliValue())
+			assert.Len(t, plan.Nodes, len(tt.targetCPUs)+1)
+		})
+	}
+}
+
+func TestGetReservedResources(t *testing.T) {
+	available := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{Name: "r-available"},
+		Status: schedulingv1alpha1.ReservationStatus{
+			Phase:    schedulingv1alpha1.ReservationAvailable,
+			NodeName: "node-1",
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+			Allocated: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		},
+	}
+	pending := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{Name: "r-pending"},
+		Status: schedulingv1alpha1.ReservationStatus{
+			Phase: schedulingv1alpha1.ReservationPending,
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("4"),
+			},
+		},
+	}
+	got := getReservedResources([]*schedulingv1alpha1.Reservation{available, pending})
+	assert.Len(t, got, 1)
+	free := got["node-1"]
+	assert.Len(t, free, 1)
+	assert.Equal(t, int64(3000), free.Cpu().MilliValue())
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numaaware
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	nrtclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	nrtinformers "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/informers/externalversions"
+	nrtlisters "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/listers/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	nodeutil "github.com/koordinator-sh/koordinator/pkg/descheduler/node"
+	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils"
+)
+
+const (
+	NUMAAwareName = "NUMAAware"
+)
+
+var _ framework.BalancePlugin = &NUMAAware{}
+
+// NUMAAware migrates the small Pods bound to a NUMA node so that the whole NUMA node is freed for the
+// LSE/LSR Pods which require a full NUMA node. A NUMA node is freed only for a pending Pod that can not be held
+// by any NUMA node of the node now, so the fragmentation alone never triggers the migrations.
+type NUMAAware struct {
+	handle    framework.Handle
+	podFilter framework.FilterFunc
+	podLister corelisters.PodLister
+	nrtLister nrtlisters.NodeResourceTopologyLister
+	args      *deschedulerconfig.NUMAAwareArgs
+}
+
+func NewNUMAAware(ctx context.Context, args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	pluginArgs, ok := args.(*deschedulerconfig.NUMAAwareArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type NUMAAwareArgs, got %T", args)
+	}
+
+	if err := validation.ValidateNUMAAwareArgs(nil, pluginArgs); err != nil {
+		return nil, err
+	}
+
+	podSelectorFn, err := filterPods(pluginArgs.PodSelectors)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod selector filter: %v", err)
+	}
+
+	var excludedNamespaces sets.String
+	var includedNamespaces sets.String
+	if pluginArgs.EvictableNamespaces != nil {
+		excludedNamespaces = sets.NewString(pluginArgs.EvictableNamespaces.Exclude...)
+		includedNamespaces = sets.NewString(pluginArgs.EvictableNamespaces.Include...)
+	}
+
+	podFilter, err := podutil.NewOptions().
+		WithFilter(podutil.WrapFilterFuncs(handle.Evictor().Filter, podSelectorFn)).
+		WithoutNamespaces(excludedNamespaces).
+		WithNamespaces(includedNamespaces).
+		BuildFilterFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod filter function: %v", err)
+	}
+
+	nrtClient, ok := handle.(nrtclientset.Interface)
+	if !ok {
+		kubeConfig := *handle.KubeConfig()
+		kubeConfig.ContentType = runtime.ContentTypeJSON
+		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		nrtClient, err = nrtclientset.NewForConfig(&kubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nrtInformerFactory := nrtinformers.NewSharedInformerFactory(nrtClient, 0)
+	nrtInformer := nrtInformerFactory.Topology().V1alpha1().NodeResourceTopologies()
+	nrtInformer.Informer()
+	nrtInformerFactory.Start(ctx.Done())
+	nrtInformerFactory.WaitForCacheSync(ctx.Done())
+
+	return &NUMAAware{
+		handle:    handle,
+		args:      pluginArgs,
+		podFilter: podFilter,
+		podLister: handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		nrtLister: nrtInformer.Lister(),
+	}, nil
+}
+
+func filterPods(podSelectors []deschedulerconfig.NUMAAwarePodSelector) (framework.FilterFunc, error) {
+	var selectors []labels.Selector
+	for _, v := range podSelectors {
+		if v.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(v.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid labelSelector %w", err)
+			}
+			selectors = append(selectors, selector)
+		}
+	}
+
+	return func(pod *corev1.Pod) bool {
+		if len(selectors) == 0 {
+			return true
+		}
+		for _, v := range selectors {
+			if v.Matches(labels.Set(pod.Labels)) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (pl *NUMAAware) Name() string {
+	return NUMAAwareName
+}
+
+func (pl *NUMAAware) Balance(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	if pl.args.Paused {
+		klog.Infof("NUMAAware is paused and will do nothing.")
+		return nil
+	}
+
+	ctx = framework.PluginNameWithContext(ctx, pl.Name())
+	candidateNodes, err := pl.filterNodesByNodeSelector(nodes)
+	if err != nil {
+		return &framework.Status{Err: err}
+	}
+
+	consumers, err := pl.listPendingConsumers()
+	if err != nil {
+		return &framework.Status{Err: err}
+	}
+	if len(consumers) == 0 {
+		klog.V(5).InfoS("No pending pod requires a full NUMA node")
+		return nil
+	}
+	// a pending Pod claims at most one freed NUMA node in a round
+	claimed := map[types.UID]struct{}{}
+
+	for _, node := range candidateNodes {
+		nrt, err := pl.nrtLister.Get(node.Name)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to get NodeResourceTopology", "node", node.Name)
+			}
+			continue
+		}
+
+		allPods, err := podutil.ListPodsOnANode(node.Name, pl.handle.GetPodsAssignedToNodeFunc(), nil)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get pods assigned to node", "node", node.Name)
+			continue
+		}
+
+		state, err := newNodeTopologyState(nrt, allPods)
+		if err != nil {
+			klog.V(4).InfoS("Failed to build NUMA topology state", "node", node.Name, "err", err)
+			continue
+		}
+		if !state.isFragmented() {
+			klog.V(5).InfoS("NUMA nodes are not fragmented", "node", node.Name)
+			continue
+		}
+
+		numaNode, pods := state.pickNUMANodeToFree(func(p *numaPod) bool {
+			return pl.isMigratable(p, candidateNodes)
+		}, int(pl.args.MaxPodsToEvictPerNode))
+		if numaNode == nil {
+			klog.V(4).InfoS("NUMA nodes are fragmented but no NUMA node can be freed", "node", node.Name)
+			continue
+		}
+
+		consumer := pl.findConsumer(consumers, claimed, node, state, numaNode)
+		if consumer == nil {
+			klog.V(4).InfoS("NUMA nodes are fragmented but no pending pod requires the freed NUMA node", "node", node.Name, "numaNode", numaNode.id)
+			continue
+		}
+		claimed[consumer.UID] = struct{}{}
+		klog.V(4).InfoS("Free NUMA node for pending pod", "node", node.Name, "numaNode", numaNode.id, "pod", klog.KObj(consumer))
+
+		pl.evictPods(ctx, node, numaNode, pods)
+	}
+
+	return nil
+}
+
+// listPendingConsumers returns the pending LSE/LSR Pods which require a single NUMA node, sorted by the creation
+// time so that the earlier Pods are served first.
+func (pl *NUMAAware) listPendingConsumers() ([]*corev1.Pod, error) {
+	pods, err := pl.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var consumers []*corev1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" || pod.DeletionTimestamp != nil ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		qosClass := extension.GetPodQoSClassRaw(pod)
+		if qosClass != extension.QoSLSE && qosClass != extension.QoSLSR {
+			continue
+		}
+		consumers = append(consumers, pod)
+	}
+	sort.SliceStable(consumers, func(i, j int) bool {
+		return consumers[i].CreationTimestamp.Before(&consumers[j].CreationTimestamp)
+	})
+	return consumers, nil
+}
+
+// findConsumer returns the first unclaimed pending Pod which can be scheduled to the node, requires a single NUMA
+// node, can not be held by any NUMA node now and fits the NUMA node to be freed.
+func (pl *NUMAAware) findConsumer(consumers []*corev1.Pod, claimed map[types.UID]struct{}, node *corev1.Node, state *nodeTopologyState, numaNode *numaNodeState) *corev1.Pod {
+	maxFreeMilliCPU := state.maxFreeMilliCPU()
+	for _, pod := range consumers {
+		if _, ok := claimed[pod.UID]; ok {
+			continue
+		}
+		milliCPU := utils.GetResourceRequest(pod, corev1.ResourceCPU)
+		if milliCPU <= maxFreeMilliCPU || milliCPU > numaNode.capacityMilliCPU() {
+			continue
+		}
+		if !requiresSingleNUMANode(pod, node) {
+			continue
+		}
+		if ok, err := utils.PodMatchNodeSelector(pod, node); err != nil || !ok {
+			continue
+		}
+		if !utils.TolerationsTolerateTaintsWithFilter(pod.Spec.Tolerations, node.Spec.Taints, func(taint *corev1.Taint) bool {
+			return taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute
+		}) {
+			continue
+		}
+		return pod
+	}
+	return nil
+}
+
+// requiresSingleNUMANode checks if the Pod must be allocated on a single NUMA node, by the NUMA topology policy of
+// the Pod or, if the Pod does not specify one, of the node.
+func requiresSingleNUMANode(pod *corev1.Pod, node *corev1.Node) bool {
+	numaSpec, err := extension.GetNUMATopologySpec(pod.Annotations)
+	if err != nil {
+		klog.V(4).InfoS("Failed to get NUMA topology spec of pod", "pod", klog.KObj(pod), "err", err)
+		return false
+	}
+	policy := numaSpec.NUMATopologyPolicy
+	if policy == extension.NUMATopologyPolicyNone {
+		policy = extension.GetNodeNUMATopologyPolicy(node.Labels)
+	}
+	return policy == extension.NUMATopologyPolicySingleNUMANode
+}
+
+// isMigratable checks if the Pod is small and bound to a single NUMA node, and it can be migrated.
+func (pl *NUMAAware) isMigratable(p *numaPod, candidateNodes []*corev1.Node) bool {
+	if len(p.numaNodes) != 1 || p.milliCPU > int64(pl.args.MaxPodCPUs)*1000 {
+		return false
+	}
+	if !pl.podFilter(p.pod) {
+		return false
+	}
+	if pl.args.NodeFit && !nodeutil.PodFitsAnyOtherNode(pl.handle.GetPodsAssignedToNodeFunc(), p.pod, candidateNodes) {
+		return false
+	}
+	return pl.handle.Evictor().PreEvictionFilter(p.pod)
+}
+
+func (pl *NUMAAware) filterNodesByNodeSelector(nodes []*corev1.Node) ([]*corev1.Node, error) {
+	if pl.args.NodeSelector == nil {
+		return nodes, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pl.args.NodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	var filtered []*corev1.Node
+	for _, node := range nodes {
+		if selector.Matches(labels.Set(node.Labels)) {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered, nil
+}
+
+func (pl *NUMAAware) evictPods(ctx context.Context, node *corev1.Node, numaNode *numaNodeState, pods []*corev1.Pod) {
+	reason := fmt.Sprintf("free NUMA node %d: usedCPUs=%dm capacity=%dm", numaNode.id, numaNode.usedMilliCPU, numaNode.capacityMilliCPU())
+	for _, pod := range pods {
+		if pl.args.DryRun {
+			klog.InfoS("Evict pod in dry run mode", "pod", klog.KObj(pod), "node", node.Name, "reason", reason)
+			continue
+		}
+		if !pl.handle.Evictor().Evict(ctx, pod, framework.EvictOptions{
+			PluginName: pl.Name(),
+			Reason:     reason,
+		}) {
+			klog.InfoS("Failed to evict pod to free NUMA node", "pod", klog.KObj(pod), "node", node.Name, "numaNode", numaNode.id)
+			continue
+		}
+		klog.V(4).InfoS("Evicted pod to free NUMA node", "pod", klog.KObj(pod), "node", node.Name, "numaNode", numaNode.id)
+	}
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numaaware
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	nrtlisters "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/listers/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/test"
+)
+
+type fakeEvictor struct {
+	evicted []*corev1.Pod
+}
+
+func (e *fakeEvictor) Filter(pod *corev1.Pod) bool {
+	return true
+}
+
+func (e *fakeEvictor) PreEvictionFilter(pod *corev1.Pod) bool {
+	return true
+}
+
+func (e *fakeEvictor) Evict(ctx context.Context, pod *corev1.Pod, opts framework.EvictOptions) bool {
+	e.evicted = append(e.evicted, pod)
+	return true
+}
+
+type fakeHandle struct {
+	evictor *fakeEvictor
+	pods    []*corev1.Pod
+}
+
+func (h *fakeHandle) Evictor() framework.Evictor {
+	return h.evictor
+}
+
+func (h *fakeHandle) GetPodsAssignedToNodeFunc() framework.GetPodsAssignedToNodeFunc {
+	return func(nodeName string, filter framework.FilterFunc) ([]*corev1.Pod, error) {
+		var res []*corev1.Pod
+		for _, pod := range h.pods {
+			if pod.Spec.NodeName == nodeName {
+				if filter == nil || filter(pod) {
+					res = append(res, pod)
+				}
+			}
+		}
+		return res, nil
+	}
+}
+
+func (h *fakeHandle) ClientSet() clientset.Interface                         { return nil }
+func (h *fakeHandle) KubeConfig() *restclient.Config                         { return nil }
+func (h *fakeHandle) EventRecorder() events.EventRecorder                    { return nil }
+func (h *fakeHandle) IsDryRun() bool                                         { return false }
+func (h *fakeHandle) SharedInformerFactory() informers.SharedInformerFactory { return nil }
+func (h *fakeHandle) NodeSelector() *metav1.LabelSelector                    { return nil }
+func (h *fakeHandle) RunDeschedulePlugins(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	return nil
+}
+func (h *fakeHandle) RunBalancePlugins(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	return nil
+}
+
+// buildTestNRT builds a NodeResourceTopology with 2 NUMA nodes, and each NUMA node has 4 CPUs.
+func buildTestNRT(nodeName string) *nrtv1alpha1.NodeResourceTopology {
+	cpuTopology := &extension.CPUTopology{}
+	for i := 0; i < 8; i++ {
+		cpuTopology.Detail = append(cpuTopology.Detail, extension.CPUInfo{
+			ID:     int32(i),
+			Core:   int32(i),
+			Socket: int32(i / 4),
+			Node:   int32(i / 4),
+		})
+	}
+	data, _ := json.Marshal(cpuTopology)
+	return &nrtv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeName,
+			Annotations: map[string]string{
+				extension.AnnotationNodeCPUTopology: string(data),
+			},
+		},
+	}
+}
+
+func buildCPUSetPod(name, nodeName, cpus string) *corev1.Pod {
+	return test.BuildTestPod(name, 1000, 0, nodeName, func(pod *corev1.Pod) {
+		test.SetRSOwnerRef(pod)
+		data, _ := json.Marshal(&extension.ResourceStatus{CPUSet: cpus})
+		pod.Annotations = map[string]string{extension.AnnotationResourceStatus: string(data)}
+	})
+}
+
+func buildNUMABoundPod(name, nodeName string, numaNode int32, milliCPU int64) *corev1.Pod {
+	return test.BuildTestPod(name, milliCPU, 0, nodeName, func(pod *corev1.Pod) {
+		test.SetRSOwnerRef(pod)
+		data, _ := json.Marshal(&extension.ResourceStatus{
+			NUMANodeResources: []extension.NUMANodeResource{
+				{
+					Node: numaNode,
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU: *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+					},
+				},
+			},
+		})
+		pod.Annotations = map[string]string{extension.AnnotationResourceStatus: string(data)}
+	})
+}
+
+// buildPendingPod builds a pending LSE Pod which requires a single NUMA node.
+func buildPendingPod(name string, milliCPU int64) *corev1.Pod {
+	return test.BuildTestPod(name, milliCPU, 0, "", func(pod *corev1.Pod) {
+		pod.UID = types.UID(name)
+		pod.Labels = map[string]string{extension.LabelPodQoS: string(extension.QoSLSE)}
+		data, _ := json.Marshal(&extension.NUMATopologySpec{NUMATopologyPolicy: extension.NUMATopologyPolicySingleNUMANode})
+		pod.Annotations = map[string]string{extension.AnnotationNUMATopologySpec: string(data)}
+	})
+}
+
+func TestNUMAAware(t *testing.T) {
+	node1 := test.BuildTestNode("node1", 8000, 8000, 10, nil)
+	node2 := test.BuildTestNode("node2", 8000, 8000, 10, nil)
+
+	defaultArgs := func() *deschedulerconfig.NUMAAwareArgs {
+		return &deschedulerconfig.NUMAAwareArgs{
+			MaxPodCPUs:            4,
+			MaxPodsToEvictPerNode: 4,
+		}
+	}
+
+	tests := []struct {
+		name            string
+		args            *deschedulerconfig.NUMAAwareArgs
+		nrts            []*nrtv1alpha1.NodeResourceTopology
+		pods            []*corev1.Pod
+		pendingPods     []*corev1.Pod
+		expectedEvicted []string
+	}{
+		{
+			name: "paused plugin does nothing",
+			args: func() *deschedulerconfig.NUMAAwareArgs {
+				args := defaultArgs()
+				args.Paused = true
+				return args
+			}(),
+			nrts:        []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 4000)},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+		},
+		{
+			name:        "free the NUMA node with the least pods",
+			args:        defaultArgs(),
+			nrts:        []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 4000)},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+			expectedEvicted: []string{"pod-a"},
+		},
+		{
+			name: "skip the large pods",
+			args: func() *deschedulerconfig.NUMAAwareArgs {
+				args := defaultArgs()
+				args.MaxPodCPUs = 1
+				return args
+			}(),
+			nrts:        []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 4000)},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildNUMABoundPod("pod-c", "node1", 1, 1000),
+			},
+			expectedEvicted: []string{"pod-b", "pod-c"},
+		},
+		{
+			name: "skip the NUMA node requires too many migrations",
+			args: func() *deschedulerconfig.NUMAAwareArgs {
+				args := defaultArgs()
+				args.MaxPodCPUs = 1
+				args.MaxPodsToEvictPerNode = 1
+				return args
+			}(),
+			nrts:        []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 4000)},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+		},
+		{
+			name:        "not fragmented",
+			args:        defaultArgs(),
+			nrts:        []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 4000)},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-2"),
+				buildCPUSetPod("pod-b", "node1", "4-6"),
+			},
+		},
+		{
+			name:        "skip nodes without NodeResourceTopology",
+			args:        defaultArgs(),
+			nrts:        []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node2")},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 4000)},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+		},
+		{
+			name: "no pending pod requires a full NUMA node",
+			args: defaultArgs(),
+			nrts: []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+		},
+		{
+			name: "pending pod can be held by a NUMA node now",
+			args: defaultArgs(),
+			nrts: []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 2000)},
+		},
+		{
+			name: "pending pod is larger than a NUMA node",
+			args: defaultArgs(),
+			nrts: []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 6000)},
+		},
+		{
+			name: "pending pod is not LSE or LSR",
+			args: defaultArgs(),
+			nrts: []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+			pendingPods: []*corev1.Pod{
+				func() *corev1.Pod {
+					pod := buildPendingPod("pending-a", 4000)
+					pod.Labels[extension.LabelPodQoS] = string(extension.QoSLS)
+					return pod
+				}(),
+			},
+		},
+		{
+			name: "pending pod does not require a single NUMA node",
+			args: defaultArgs(),
+			nrts: []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+			pendingPods: []*corev1.Pod{
+				func() *corev1.Pod {
+					pod := buildPendingPod("pending-a", 4000)
+					pod.Annotations = nil
+					return pod
+				}(),
+			},
+		},
+		{
+			name: "pending pod does not match the node",
+			args: defaultArgs(),
+			nrts: []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+			pendingPods: []*corev1.Pod{
+				func() *corev1.Pod {
+					pod := buildPendingPod("pending-a", 4000)
+					pod.Spec.NodeSelector = map[string]string{"pool": "gpu"}
+					return pod
+				}(),
+			},
+		},
+		{
+			name: "a pending pod claims only one freed NUMA node",
+			args: defaultArgs(),
+			nrts: []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1"), buildTestNRT("node2")},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+				buildCPUSetPod("pod-d", "node2", "0-1"),
+				buildCPUSetPod("pod-e", "node2", "4"),
+				buildCPUSetPod("pod-f", "node2", "5"),
+			},
+			pendingPods:     []*corev1.Pod{buildPendingPod("pending-a", 4000)},
+			expectedEvicted: []string{"pod-a"},
+		},
+		{
+			name: "dry run does not evict",
+			args: func() *deschedulerconfig.NUMAAwareArgs {
+				args := defaultArgs()
+				args.DryRun = true
+				return args
+			}(),
+			nrts:        []*nrtv1alpha1.NodeResourceTopology{buildTestNRT("node1")},
+			pendingPods: []*corev1.Pod{buildPendingPod("pending-a", 4000)},
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4"),
+				buildCPUSetPod("pod-c", "node1", "5"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, nrt := range tt.nrts {
+				assert.NoError(t, indexer.Add(nrt))
+			}
+
+			podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, pod := range append(append([]*corev1.Pod{}, tt.pods...), tt.pendingPods...) {
+				assert.NoError(t, podIndexer.Add(pod))
+			}
+
+			evictor := &fakeEvictor{}
+			handle := &fakeHandle{evictor: evictor, pods: tt.pods}
+			pl := &NUMAAware{
+				handle:    handle,
+				args:      tt.args,
+				podFilter: func(pod *corev1.Pod) bool { return true },
+				podLister: corelisters.NewPodLister(podIndexer),
+				nrtLister: nrtlisters.NewNodeResourceTopologyLister(indexer),
+			}
+			status := pl.Balance(ctx, []*corev1.Node{node1, node2})
+			assert.Nil(t, status)
+
+			var evicted []string
+			for _, pod := range evictor.evicted {
+				evicted = append(evicted, pod.Name)
+			}
+			assert.Equal(t, tt.expectedEvicted, evicted)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numaaware
+
+import (
+	"fmt"
+	"sort"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// numaNodeState is the CPU allocation state of a NUMA node.
+type numaNodeState struct {
+	id int
+	// cpus are the CPUs can be allocated to the Pods, the reserved CPUs are excluded.
+	cpus cpuset.CPUSet
+	// usedMilliCPU is the CPUs allocated to the Pods on the NUMA node, including the cpuset Pods and the Pods
+	// which are bound to the NUMA node but share the CPUs.
+	usedMilliCPU int64
+	pods         []*numaPod
+}
+
+func (n *numaNodeState) capacityMilliCPU() int64 {
+	return int64(n.cpus.Size()) * 1000
+}
+
+func (n *numaNodeState) freeMilliCPU() int64 {
+	free := n.capacityMilliCPU() - n.usedMilliCPU
+	if free < 0 {
+		return 0
+	}
+	return free
+}
+
+func (n *numaNodeState) isFree() bool {
+	return len(n.pods) == 0
+}
+
+// numaPod is a Pod with its allocations on the NUMA nodes.
+type numaPod struct {
+	pod       *corev1.Pod
+	numaNodes []int
+	milliCPU  int64
+}
+
+// nodeTopologyState is the CPU allocation state of the NUMA nodes of a node.
+type nodeTopologyState struct {
+	nodeName  string
+	numaNodes []*numaNodeState
+}
+
+// newNodeTopologyState builds the allocation state from the CPU topology and reserved CPUs reported by koordlet
+// in NodeResourceTopology, and the allocations in the ResourceStatus of the Pods.
+func newNodeTopologyState(nrt *nrtv1alpha1.NodeResourceTopology, pods []*corev1.Pod) (*nodeTopologyState, error) {
+	cpuTopology, err := extension.GetCPUTopology(nrt.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cpu topology, err: %w", err)
+	}
+	if cpuTopology == nil || len(cpuTopology.Detail) == 0 {
+		return nil, fmt.Errorf("cpu topology is not reported")
+	}
+
+	reservedCPUs := getReservedCPUs(nrt)
+	cpuToNUMANode := map[int]int{}
+	builders := map[int]*cpuset.CPUSetBuilder{}
+	for _, info := range cpuTopology.Detail {
+		cpuToNUMANode[int(info.ID)] = int(info.Node)
+		builder := builders[int(info.Node)]
+		if builder == nil {
+			builder = cpuset.NewCPUSetBuilder()
+			builders[int(info.Node)] = builder
+		}
+		if !reservedCPUs.Contains(int(info.ID)) {
+			builder.Add(int(info.ID))
+		}
+	}
+
+	state := &nodeTopologyState{nodeName: nrt.Name}
+	numaNodes := map[int]*numaNodeState{}
+	for id, builder := range builders {
+		numaNode := &numaNodeState{id: id, cpus: builder.Result()}
+		numaNodes[id] = numaNode
+		state.numaNodes = append(state.numaNodes, numaNode)
+	}
+	sort.Slice(state.numaNodes, func(i, j int) bool {
+		return state.numaNodes[i].id < state.numaNodes[j].id
+	})
+
+	for _, pod := range pods {
+		p := newNUMAPod(pod, cpuToNUMANode)
+		if p == nil {
+			continue
+		}
+		for _, id := range p.numaNodes {
+			numaNode := numaNodes[id]
+			if numaNode == nil {
+				continue
+			}
+			numaNode.pods = append(numaNode.pods, p)
+			numaNode.usedMilliCPU += p.milliCPU / int64(len(p.numaNodes))
+		}
+	}
+	return state, nil
+}
+
+// newNUMAPod returns the allocations of the Pod on the NUMA nodes, or nil if the Pod is not bound to any NUMA node.
+func newNUMAPod(pod *corev1.Pod, cpuToNUMANode map[int]int) *numaPod {
+	resourceStatus, err := extension.GetResourceStatus(pod.Annotations)
+	if err != nil {
+		klog.V(4).InfoS("Failed to get resource status of pod", "pod", klog.KObj(pod), "err", err)
+		return nil
+	}
+	if resourceStatus.CPUSet != "" {
+		cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+		if err != nil || cpus.IsEmpty() {
+			klog.V(4).InfoS("Failed to parse cpuset of pod", "pod", klog.KObj(pod), "cpuset", resourceStatus.CPUSet, "err", err)
+			return nil
+		}
+		ids := map[int]struct{}{}
+		for _, cpu := range cpus.ToSliceNoSort() {
+			if id, ok := cpuToNUMANode[cpu]; ok {
+				ids[id] = struct{}{}
+			}
+		}
+		p := &numaPod{pod: pod, milliCPU: int64(cpus.Size()) * 1000}
+		for id := range ids {
+			p.numaNodes = append(p.numaNodes, id)
+		}
+		sort.Ints(p.numaNodes)
+		return p
+	}
+
+	if len(resourceStatus.NUMANodeResources) == 0 {
+		return nil
+	}
+	p := &numaPod{pod: pod}
+	for _, numaNodeResource := range resourceStatus.NUMANodeResources {
+		p.numaNodes = append(p.numaNodes, int(numaNodeResource.Node))
+		p.milliCPU += numaNodeResource.Resources.Cpu().MilliValue()
+	}
+	sort.Ints(p.numaNodes)
+	return p
+}
+
+func getReservedCPUs(nrt *nrtv1alpha1.NodeResourceTopology) cpuset.CPUSet {
+	reservedCPUs := cpuset.NewCPUSet()
+	kubeletPolicy, err := extension.GetKubeletCPUManagerPolicy(nrt.Annotations)
+	if err == nil && kubeletPolicy != nil {
+		if cpus, err := cpuset.Parse(kubeletPolicy.ReservedCPUs); err == nil {
+			reservedCPUs = reservedCPUs.Union(cpus)
+		}
+	}
+	reservedCPUsString, _ := extension.GetReservedCPUs(nrt.Annotations)
+	if cpus, err := cpuset.Parse(reservedCPUsString); err == nil {
+		reservedCPUs = reservedCPUs.Union(cpus)
+	}
+	return reservedCPUs
+}
+
+// isFragmented returns true if the Pods on a NUMA node can be held by the free CPUs of the other busy NUMA nodes,
+// that is, the free CPUs of the node are enough to free one more NUMA node but they are scattered.
+func (s *nodeTopologyState) isFragmented() bool {
+	totalFreeMilliCPU := s.busyFreeMilliCPU()
+	for _, numaNode := range s.numaNodes {
+		if !numaNode.isFree() && numaNode.usedMilliCPU <= totalFreeMilliCPU-numaNode.freeMilliCPU() {
+			return true
+		}
+	}
+	return false
+}
+
+// busyFreeMilliCPU returns the free CPUs of the NUMA nodes which have Pods.
+func (s *nodeTopologyState) busyFreeMilliCPU() int64 {
+	var totalFreeMilliCPU int64
+	for _, numaNode := range s.numaNodes {
+		if !numaNode.isFree() {
+			totalFreeMilliCPU += numaNode.freeMilliCPU()
+		}
+	}
+	return totalFreeMilliCPU
+}
+
+// maxFreeMilliCPU returns the most free CPUs of a single NUMA node.
+func (s *nodeTopologyState) maxFreeMilliCPU() int64 {
+	var maxFreeMilliCPU int64
+	for _, numaNode := range s.numaNodes {
+		if free := numaNode.freeMilliCPU(); free > maxFreeMilliCPU {
+			maxFreeMilliCPU = free
+		}
+	}
+	return maxFreeMilliCPU
+}
+
+// pickNUMANodeToFree returns the NUMA node which is the cheapest to free and the Pods to be migrated. All the Pods
+// on the NUMA node must be migratable, otherwise the NUMA node can not be freed.
+func (s *nodeTopologyState) pickNUMANodeToFree(migratable func(p *numaPod) bool, maxPods int) (*numaNodeState, []*corev1.Pod) {
+	totalFreeMilliCPU := s.busyFreeMilliCPU()
+	var candidates []*numaNodeState
+	for _, numaNode := range s.numaNodes {
+		if !numaNode.isFree() {
+			candidates = append(candidates, numaNode)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if len(candidates[i].pods) != len(candidates[j].pods) {
+			return len(candidates[i].pods) < len(candidates[j].pods)
+		}
+		return candidates[i].usedMilliCPU < candidates[j].usedMilliCPU
+	})
+
+	for _, numaNode := range candidates {
+		if len(numaNode.pods) > maxPods {
+			continue
+		}
+		// the freed NUMA node is useful only if the rest NUMA nodes have enough free CPUs to hold the Pods
+		if numaNode.usedMilliCPU > totalFreeMilliCPU-numaNode.freeMilliCPU() {
+			continue
+		}
+		var pods []*corev1.Pod
+		for _, p := range numaNode.pods {
+			if !migratable(p) {
+				pods = nil
+				break
+			}
+			pods = append(pods, p.pod)
+		}
+		if len(pods) > 0 {
+			return numaNode, pods
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numaaware
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestNewNodeTopologyState(t *testing.T) {
+	nrt := buildTestNRT("node1")
+	data, _ := json.Marshal(&extension.KubeletCPUManagerPolicy{Policy: "static", ReservedCPUs: "0"})
+	nrt.Annotations[extension.AnnotationKubeletCPUManagerPolicy] = string(data)
+
+	pods := []*corev1.Pod{
+		buildCPUSetPod("pod-a", "node1", "1-2"),
+		buildCPUSetPod("pod-b", "node1", "3-4"),
+		buildNUMABoundPod("pod-c", "node1", 1, 1500),
+		buildCPUSetPod("pod-d", "node1", ""),
+	}
+	state, err := newNodeTopologyState(nrt, pods)
+	assert.NoError(t, err)
+	assert.Len(t, state.numaNodes, 2)
+
+	numaNode0 := state.numaNodes[0]
+	assert.Equal(t, "1-3", numaNode0.cpus.String())
+	assert.Equal(t, int64(3000), numaNode0.usedMilliCPU)
+	assert.Equal(t, int64(0), numaNode0.freeMilliCPU())
+	assert.Len(t, numaNode0.pods, 2)
+
+	numaNode1 := state.numaNodes[1]
+	assert.Equal(t, "4-7", numaNode1.cpus.String())
+	assert.Equal(t, int64(2500), numaNode1.usedMilliCPU)
+	assert.Len(t, numaNode1.pods, 2)
+	// pod-b spans over the NUMA nodes
+	assert.Equal(t, []int{0, 1}, numaNode1.pods[0].numaNodes)
+
+	_, err = newNodeTopologyState(buildTestNRT("node2"), nil)
+	assert.NoError(t, err)
+	nrt.Annotations = nil
+	_, err = newNodeTopologyState(nrt, pods)
+	assert.Error(t, err)
+}
+
+func TestIsFragmented(t *testing.T) {
+	tests := []struct {
+		name string
+		pods []*corev1.Pod
+		want bool
+	}{
+		{
+			name: "no pods",
+			want: false,
+		},
+		{
+			name: "a NUMA node is already free",
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+			},
+			want: false,
+		},
+		{
+			name: "free CPUs are scattered",
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-1"),
+				buildCPUSetPod("pod-b", "node1", "4-5"),
+			},
+			want: true,
+		},
+		{
+			name: "free CPUs are not enough to free a NUMA node",
+			pods: []*corev1.Pod{
+				buildCPUSetPod("pod-a", "node1", "0-2"),
+				buildCPUSetPod("pod-b", "node1", "4-6"),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := newNodeTopologyState(buildTestNRT("node1"), tt.pods)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, state.isFragmented())
+		})
+	}
+}
@@ -18,19 +18,23 @@ package plugins
 
 import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/custompriority"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/devicefragmentation"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/fragmentationaware"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/kubernetes"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/loadaware"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/numaaware"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/scaledownbinpack"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/runtime"
 )
 
 func NewInTreeRegistry() runtime.Registry {
 	registry := runtime.Registry{
-		loadaware.LowNodeLoadName:                 loadaware.NewLowNodeLoad,
-		custompriority.PluginCustomPriorityName:   custompriority.NewCustomPriority,
-		fragmentationaware.FragmentationAwareName: fragmentationaware.NewFragmentationAware,
-		scaledownbinpack.ScaleDownBinPackName:     scaledownbinpack.NewScaleDownBinPack,
+		loadaware.LowNodeLoadName:                   loadaware.NewLowNodeLoad,
+		custompriority.PluginCustomPriorityName:     custompriority.NewCustomPriority,
+		fragmentationaware.FragmentationAwareName:   fragmentationaware.NewFragmentationAware,
+		scaledownbinpack.ScaleDownBinPackName:       scaledownbinpack.NewScaleDownBinPack,
+		numaaware.NUMAAwareName:                     numaaware.NewNUMAAware,
+		devicefragmentation.DeviceFragmentationName: devicefragmentation.NewDeviceFragmentation,
 	}
 	kubernetes.SetupK8sDeschedulerPlugins(registry)
 	return registry
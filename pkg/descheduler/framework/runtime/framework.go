@@ -32,6 +32,7 @@ import (
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/events"
 
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
 )
@@ -39,6 +40,7 @@ import (
 type frameworkImpl struct {
 	dryRun                    bool
 	clientSet                 clientset.Interface
+	koordClientSet            koordclientset.Interface
 	kubeConfig                *restclient.Config
 	eventRecorder             events.EventRecorder
 	evictionLimiter           EvictionLimiter
@@ -57,6 +59,7 @@ type Option func(*frameworkOptions)
 type frameworkOptions struct {
 	dryRun                    bool
 	clientSet                 clientset.Interface
+	koordClientSet            koordclientset.Interface
 	kubeConfig                *restclient.Config
 	eventRecorder             events.EventRecorder
 	sharedInformerFactory     informers.SharedInformerFactory
@@ -78,6 +81,13 @@ func WithClientSet(clientSet clientset.Interface) Option {
 	}
 }
 
+// WithKoordClientSet sets the koordinator clientSet for the plugins, they build one from the kubeConfig if it is not set.
+func WithKoordClientSet(koordClientSet koordclientset.Interface) Option {
+	return func(o *frameworkOptions) {
+		o.koordClientSet = koordClientSet
+	}
+}
+
 // WithKubeConfig sets kubeConfig for the scheduling frameworkImpl.
 func WithKubeConfig(kubeConfig *restclient.Config) Option {
 	return func(o *frameworkOptions) {
@@ -129,6 +139,7 @@ func NewFramework(ctx context.Context, r Registry, profile *deschedulerconfig.De
 	f := &frameworkImpl{
 		dryRun:                    options.dryRun,
 		clientSet:                 options.clientSet,
+		koordClientSet:            options.koordClientSet,
 		kubeConfig:                options.kubeConfig,
 		eventRecorder:             options.eventRecorder,
 		evictionLimiter:           options.evictionLimiter,
@@ -190,6 +201,11 @@ func (f *frameworkImpl) initPlugins(ctx context.Context, r Registry, pluginConfi
 	pg := sets.NewString()
 	pluginsNeeded(pg, extensionPoints)
 
+	var handle framework.Handle = f
+	if f.koordClientSet != nil {
+		handle = &koordClientSetHandle{Handle: f, Interface: f.koordClientSet}
+	}
+
 	var outputPluginConfig []deschedulerconfig.PluginConfig
 	for name, factory := range r {
 		// initialize only needed plugins.
@@ -209,7 +225,7 @@ func (f *frameworkImpl) initPlugins(ctx context.Context, r Registry, pluginConfi
 				Args: args,
 			})
 		}
-		p, err := factory(ctx, args, f)
+		p, err := factory(ctx, args, handle)
 		if err != nil {
 			return nil, fmt.Errorf("initializing plugin %q: %w", name, err)
 		}
@@ -252,6 +268,13 @@ func updatePluginList(pluginList interface{}, pluginSet deschedulerconfig.Plugin
 	return nil
 }
 
+// koordClientSetHandle exposes the koordinator clientSet through the framework.Handle, the plugins watching
+// the koordinator objects prefer it to the clientSet built from the kubeConfig.
+type koordClientSetHandle struct {
+	framework.Handle
+	koordclientset.Interface
+}
+
 // extensionPoint encapsulates desired and applied set of plugins at a specific extension
 // point. This is used to simplify iterating over all extension points supported by the
 // frameworkImpl.
@@ -27,6 +27,13 @@ import (
 const (
 	// DeschedulerSubsystem - subsystem name used by descheduler
 	DeschedulerSubsystem = "descheduler"
+
+	// MigrationCooldownKindPod - the Pod was migrated recently
+	MigrationCooldownKindPod = "pod"
+	// MigrationCooldownKindWorkload - a Pod of the workload was migrated recently
+	MigrationCooldownKindWorkload = "workload"
+	// MigrationCooldownKindNode - the node received migrated Pods recently
+	MigrationCooldownKindNode = "node"
 )
 
 var (
@@ -38,8 +45,17 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		}, []string{"result", "strategy", "namespace", "node"})
 
+	MigrationCooldownHits = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "migration_cooldown_hits",
+			Help:           "Number of pods or nodes skipped because they are in the migration cooldown, by the kind of the cooldown (pod, workload or node)",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"kind"})
+
 	metricsList = []metrics.Registerable{
 		PodsEvicted,
+		MigrationCooldownHits,
 	}
 )
 
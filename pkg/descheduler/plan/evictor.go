@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	k8sdeschedulerapi "sigs.k8s.io/descheduler/pkg/api"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/gang"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/names"
+	evictionsutil "github.com/koordinator-sh/koordinator/pkg/descheduler/evictions"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/kubernetes/defaultevictor"
+	nodeutil "github.com/koordinator-sh/koordinator/pkg/descheduler/node"
+	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+)
+
+var _ framework.EvictPlugin = &evictor{}
+var _ framework.FilterPlugin = &evictor{}
+
+// evictor replaces the MigrationController in the plan. It filters the Pods with the static filters configured
+// in MigrationControllerArgs, and records the evictions instead of creating the PodMigrationJobs. The arbitration
+// which depends on the running PodMigrationJobs, e.g. the limits of migrating Pods, is not simulated.
+type evictor struct {
+	args                *deschedulerconfig.MigrationControllerArgs
+	recorder            *recorder
+	podFilter           framework.FilterFunc
+	defaultFilterPlugin framework.FilterPlugin
+}
+
+func (r *recorder) newEvictor(ctx context.Context, args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	controllerArgs, ok := args.(*deschedulerconfig.MigrationControllerArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type MigrationControllerArgs, got %T", args)
+	}
+	if err := validation.ValidateMigrationControllerArgs(nil, controllerArgs); err != nil {
+		return nil, err
+	}
+
+	var priority *int32
+	var priorityThreshold *k8sdeschedulerapi.PriorityThreshold
+	if controllerArgs.PriorityThreshold != nil {
+		priorityThreshold = &k8sdeschedulerapi.PriorityThreshold{
+			Name:  controllerArgs.PriorityThreshold.Name,
+			Value: controllerArgs.PriorityThreshold.Value,
+		}
+		priority = controllerArgs.PriorityThreshold.Value
+	}
+	defaultEvictor, err := defaultevictor.New(ctx, &defaultevictor.DefaultEvictorArgs{
+		NodeFit:                 controllerArgs.NodeFit,
+		NodeSelector:            controllerArgs.NodeSelector,
+		EvictLocalStoragePods:   controllerArgs.EvictLocalStoragePods,
+		EvictSystemCriticalPods: controllerArgs.EvictSystemCriticalPods,
+		IgnorePvcPods:           controllerArgs.IgnorePvcPods,
+		EvictFailedBarePods:     controllerArgs.EvictFailedBarePods,
+		LabelSelector:           controllerArgs.LabelSelector,
+		PriorityThreshold:       priorityThreshold,
+	}, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeGetter := func() ([]*corev1.Node, error) {
+		return nodeutil.ReadyNodes(ctx, handle.ClientSet(), handle.SharedInformerFactory().Core().V1().Nodes(), controllerArgs.NodeSelector)
+	}
+	filterPlugin, err := evictionsutil.NewEvictorFilter(nodeGetter, handle.GetPodsAssignedToNodeFunc(), controllerArgs.EvictLocalStoragePods,
+		controllerArgs.EvictSystemCriticalPods, controllerArgs.IgnorePvcPods, controllerArgs.EvictFailedBarePods, controllerArgs.EvictAllBarePods,
+		evictionsutil.WithLabelSelector(controllerArgs.LabelSelector), evictionsutil.WithPriorityThreshold(priority))
+	if err != nil {
+		return nil, err
+	}
+	filterFuncs := podutil.WrapFilterFuncs(util.FilterPodWithMaxEvictionCost, filterPlugin.Filter)
+	if controllerArgs.GangMigrationPolicy == deschedulerconfig.GangMigrationPolicySkip {
+		filterFuncs = podutil.WrapFilterFuncs(filterFuncs, func(pod *corev1.Pod) bool {
+			return gang.GetGangID(pod) == ""
+		})
+	}
+	var includedNamespaces, excludedNamespaces sets.String
+	if controllerArgs.Namespaces != nil {
+		includedNamespaces = sets.NewString(controllerArgs.Namespaces.Include...)
+		excludedNamespaces = sets.NewString(controllerArgs.Namespaces.Exclude...)
+	}
+	podFilter, err := podutil.NewOptions().
+		WithFilter(filterFuncs).
+		WithNamespaces(includedNamespaces).
+		WithoutNamespaces(excludedNamespaces).
+		BuildFilterFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	return &evictor{
+		args:     controllerArgs,
+		recorder: r,
+		podFilter: func(pod *corev1.Pod) bool {
+			return evictionsutil.HaveEvictAnnotation(pod) || podFilter(pod)
+		},
+		defaultFilterPlugin: defaultEvictor.(framework.FilterPlugin),
+	}, nil
+}
+
+func (e *evictor) Name() string {
+	return names.MigrationController
+}
+
+func (e *evictor) Filter(pod *corev1.Pod) bool {
+	return !e.recorder.isEvicted(pod) && e.podFilter(pod)
+}
+
+func (e *evictor) PreEvictionFilter(pod *corev1.Pod) bool {
+	return e.defaultFilterPlugin.PreEvictionFilter(pod)
+}
+
+func (e *evictor) Evict(ctx context.Context, pod *corev1.Pod, evictOptions framework.EvictOptions) bool {
+	if e.recorder.isEvicted(pod) {
+		return false
+	}
+	framework.FillEvictOptionsFromContext(ctx, &evictOptions)
+	job, err := migration.NewPodMigrationJob(ctx, pod, evictOptions, e.args)
+	if err != nil {
+		klog.ErrorS(err, "Failed to build PodMigrationJob", "pod", klog.KObj(pod))
+		return false
+	}
+	// Replace the random name with a generated one, so that the plan is stable across runs and can be created as is.
+	job.TypeMeta = metav1.TypeMeta{
+		APIVersion: sev1alpha1.SchemeGroupVersion.String(),
+		Kind:       "PodMigrationJob",
+	}
+	job.GenerateName = pod.Name + "-"
+	job.Name = ""
+	delete(job.Annotations, migration.AnnotationJobCreatedBy)
+
+	e.recorder.record(Eviction{
+		Plugin:    evictOptions.PluginName,
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		NodeName:  pod.Spec.NodeName,
+		Reason:    evictOptions.Reason,
+	}, pod, job)
+	klog.V(4).InfoS("Planned to evict pod", "pod", klog.KObj(pod), "plugin", evictOptions.PluginName, "reason", evictOptions.Reason)
+	return true
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/names"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/evictions"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/numaaware"
+	frameworkruntime "github.com/koordinator-sh/koordinator/pkg/descheduler/framework/runtime"
+)
+
+const podNodeNameIndex = "spec.nodeName"
+
+// Plan is the result of a descheduling cycle run without side effects.
+type Plan struct {
+	// Evictions are the evictions proposed by the descheduling plugins in order.
+	Evictions []Eviction `json:"evictions"`
+	// PodMigrationJobs are the PodMigrationJobs which the MigrationController would create for the evictions.
+	PodMigrationJobs []sev1alpha1.PodMigrationJob `json:"podMigrationJobs"`
+}
+
+// Eviction is an eviction proposed by a descheduling plugin.
+type Eviction struct {
+	Plugin    string `json:"plugin"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	NodeName  string `json:"nodeName,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Options configures the descheduling cycle run by Run.
+type Options struct {
+	// Config is the DeschedulerConfiguration whose profiles are run.
+	Config *deschedulerconfig.DeschedulerConfiguration
+	// ClientSet and KoordClientSet serve the cluster state, they are only read.
+	ClientSet      clientset.Interface
+	KoordClientSet koordclientset.Interface
+	// KubeConfig is optional, it is required by the plugins watching the objects out of the clientSets,
+	// e.g. NUMAAware watches the NodeResourceTopologies.
+	KubeConfig *restclient.Config
+	// OutOfTreeRegistry registers the out-of-tree descheduling plugins.
+	OutOfTreeRegistry frameworkruntime.Registry
+}
+
+// Run runs one descheduling cycle of all the profiles and returns the evictions proposed by the plugins.
+// The MigrationController is replaced by a recorder, so the evictions are recorded with the PodMigrationJobs
+// that would be created instead of being executed, and the cluster is never modified.
+func Run(ctx context.Context, opts Options) (*Plan, error) {
+	if opts.KubeConfig == nil {
+		if err := checkKubeConfigRequired(opts.Config.Profiles); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r := newRecorder()
+	registry := frameworkruntime.Registry{
+		names.MigrationController: r.newEvictor,
+	}
+	if err := registry.Merge(opts.OutOfTreeRegistry); err != nil {
+		return nil, err
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(opts.ClientSet, 0)
+	podAssignedToNodeFn, err := buildPodAssignedToNodeFn(informerFactory)
+	if err != nil {
+		return nil, err
+	}
+	recorderFactory := func(name string) events.EventRecorder {
+		return &events.FakeRecorder{}
+	}
+	cfg := opts.Config
+	evictionLimiter := evictions.NewEvictionLimiter(cfg.MaxNoOfPodsToEvictPerNode, cfg.MaxNoOfPodsToEvictPerNamespace, cfg.MaxNoOfPodsToEvictTotal)
+
+	desched, err := descheduler.New(
+		opts.ClientSet,
+		informerFactory,
+		nil,
+		recorderFactory,
+		ctx.Done(),
+		descheduler.WithComponentConfigVersion(cfg.TypeMeta.APIVersion),
+		descheduler.WithKubeConfig(opts.KubeConfig),
+		descheduler.WithKoordClientSet(opts.KoordClientSet),
+		descheduler.WithProfiles(cfg.Profiles...),
+		descheduler.WithFrameworkOutOfTreeRegistry(registry),
+		descheduler.WithNodeSelector(cfg.NodeSelector),
+		descheduler.WithEvictionLimiter(evictionLimiter),
+		descheduler.WithPodAssignedToNodeFn(podAssignedToNodeFn),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	informerFactory.Start(ctx.Done())
+	for informerType, synced := range informerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return nil, fmt.Errorf("failed to sync informer for %v", informerType)
+		}
+	}
+
+	if err = desched.RunOnce(ctx); err != nil {
+		return nil, err
+	}
+	return r.plan(), nil
+}
+
+func checkKubeConfigRequired(profiles []deschedulerconfig.DeschedulerProfile) error {
+	for _, profile := range profiles {
+		if profile.Plugins == nil {
+			continue
+		}
+		for _, pluginSet := range []deschedulerconfig.PluginSet{profile.Plugins.Deschedule, profile.Plugins.Balance} {
+			for _, plugin := range pluginSet.Enabled {
+				if plugin.Name == numaaware.NUMAAwareName {
+					return fmt.Errorf("plugin %s in profile %s requires a kubeconfig", plugin.Name, profile.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func buildPodAssignedToNodeFn(informerFactory informers.SharedInformerFactory) (descheduler.PodAssignedToNodeFn, error) {
+	podInformer := informerFactory.Core().V1().Pods().Informer()
+	err := podInformer.AddIndexers(cache.Indexers{
+		podNodeNameIndex: func(obj interface{}) ([]string, error) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok || pod.Spec.NodeName == "" {
+				return nil, nil
+			}
+			return []string{pod.Spec.NodeName}, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	indexer := podInformer.GetIndexer()
+	return func(nodeName string) ([]*corev1.Pod, error) {
+		objs, err := indexer.ByIndex(podNodeNameIndex, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		pods := make([]*corev1.Pod, 0, len(objs))
+		for _, obj := range objs {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				pods = append(pods, pod)
+			}
+		}
+		return pods, nil
+	}, nil
+}
+
+// recorder records the evictions of all the profiles.
+type recorder struct {
+	lock      sync.Mutex
+	evictions []Eviction
+	jobs      []sev1alpha1.PodMigrationJob
+	evicted   sets.Set[types.UID]
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		evicted: sets.New[types.UID](),
+	}
+}
+
+func (r *recorder) isEvicted(pod *corev1.Pod) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.evicted.Has(pod.UID)
+}
+
+func (r *recorder) record(eviction Eviction, pod *corev1.Pod, job *sev1alpha1.PodMigrationJob) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.evicted.Insert(pod.UID)
+	r.evictions = append(r.evictions, eviction)
+	r.jobs = append(r.jobs, *job)
+}
+
+func (r *recorder) plan() *Plan {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return &Plan{
+		Evictions:        append([]Eviction{}, r.evictions...),
+		PodMigrationJobs: append([]sev1alpha1.PodMigrationJob{}, r.jobs...),
+	}
+}
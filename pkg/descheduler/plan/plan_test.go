@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	deschedulerconfigscheme "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/scheme"
+	migrationevictor "github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+)
+
+const testConfig = `
+apiVersion: descheduler/v1alpha2
+kind: DeschedulerConfiguration
+profiles:
+- name: test
+  plugins:
+    deschedule:
+      enabled:
+      - name: RemovePodsViolatingNodeTaints
+`
+
+func newTestNode(name string, taints ...corev1.Taint) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("32"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+				corev1.ResourcePods:   resource.MustParse("100"),
+			},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func newTestPod(name, nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID("uid-" + name),
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test-rs", UID: "test-rs-uid", Controller: ptr.To(true)},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []corev1.Container{{Name: "main"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	obj, _, err := deschedulerconfigscheme.Codecs.UniversalDecoder().Decode([]byte(testConfig), nil, nil)
+	assert.NoError(t, err)
+	cfg := obj.(*deschedulerconfig.DeschedulerConfiguration)
+
+	objs := []runtime.Object{
+		newTestNode("node-1", corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}),
+		newTestNode("node-2"),
+		newTestPod("pod-1", "node-1"),
+		newTestPod("pod-2", "node-2"),
+	}
+	client, koordClient, err := NewSnapshotClientSets(objs)
+	assert.NoError(t, err)
+
+	p, err := Run(context.TODO(), Options{
+		Config:         cfg,
+		ClientSet:      client,
+		KoordClientSet: koordClient,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, p.Evictions, 1)
+	assert.Equal(t, "RemovePodsViolatingNodeTaints", p.Evictions[0].Plugin)
+	assert.Equal(t, "default", p.Evictions[0].Namespace)
+	assert.Equal(t, "pod-1", p.Evictions[0].Name)
+	assert.Equal(t, "node-1", p.Evictions[0].NodeName)
+
+	assert.Len(t, p.PodMigrationJobs, 1)
+	job := p.PodMigrationJobs[0]
+	assert.Equal(t, "PodMigrationJob", job.Kind)
+	assert.Equal(t, "", job.Name)
+	assert.Equal(t, "pod-1-", job.GenerateName)
+	assert.Equal(t, &corev1.ObjectReference{Namespace: "default", Name: "pod-1", UID: "uid-pod-1"}, job.Spec.PodRef)
+	assert.Equal(t, sev1alpha1.PodMigrationJobModeReservationFirst, job.Spec.Mode)
+	assert.Equal(t, "RemovePodsViolatingNodeTaints", job.Annotations[migrationevictor.AnnotationEvictTrigger])
+
+	// the snapshot is not modified
+	pods, err := client.CoreV1().Pods("default").List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, pods.Items, 2)
+	jobs, err := koordClient.SchedulingV1alpha1().PodMigrationJobs().List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+}
+
+func TestRunRequiresKubeConfig(t *testing.T) {
+	cfg := &deschedulerconfig.DeschedulerConfiguration{
+		Profiles: []deschedulerconfig.DeschedulerProfile{
+			{
+				Name: "numa",
+				Plugins: &deschedulerconfig.Plugins{
+					Balance: deschedulerconfig.PluginSet{
+						Enabled: []deschedulerconfig.Plugin{{Name: "NUMAAware"}},
+					},
+				},
+			},
+		},
+	}
+	_, err := Run(context.TODO(), Options{Config: cfg})
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientset "k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	koordscheme "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/scheme"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+var (
+	snapshotScheme = runtime.NewScheme()
+	snapshotCodecs = serializer.NewCodecFactory(snapshotScheme)
+)
+
+func init() {
+	_ = clientgoscheme.AddToScheme(snapshotScheme)
+	_ = koordscheme.AddToScheme(snapshotScheme)
+}
+
+// LoadSnapshot reads the objects in the YAML and JSON files under the directory, e.g. the output of
+// `kubectl get nodes,pods -A -o yaml`. A file can hold multiple YAML documents, and the Lists are flattened
+// into their items.
+func LoadSnapshot(dir string) ([]runtime.Object, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	var objs []runtime.Object
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		fileObjs, err := decodeObjects(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s, err: %w", file, err)
+		}
+		objs = append(objs, fileObjs...)
+	}
+	return objs, nil
+}
+
+func decodeObjects(data []byte) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		docObjs, err := decodeObject(doc)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, docObjs...)
+	}
+}
+
+func decodeObject(data []byte) ([]runtime.Object, error) {
+	obj, _, err := snapshotCodecs.UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := obj.(*corev1.List)
+	if !ok {
+		return []runtime.Object{obj}, nil
+	}
+	var objs []runtime.Object
+	for _, item := range list.Items {
+		itemObjs, err := decodeObject(item.Raw)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, itemObjs...)
+	}
+	return objs, nil
+}
+
+// NewSnapshotClientSets builds the fake clientSets serving the objects of a snapshot.
+func NewSnapshotClientSets(objs []runtime.Object) (clientset.Interface, koordclientset.Interface, error) {
+	var kubeObjs, koordObjs []runtime.Object
+	for _, obj := range objs {
+		if _, _, err := clientgoscheme.Scheme.ObjectKinds(obj); err == nil {
+			kubeObjs = append(kubeObjs, obj)
+		} else if _, _, err = koordscheme.Scheme.ObjectKinds(obj); err == nil {
+			koordObjs = append(koordObjs, obj)
+		} else {
+			return nil, nil, fmt.Errorf("unsupported object %T in snapshot", obj)
+		}
+	}
+	kubeClient := kubefake.NewSimpleClientset(kubeObjs...)
+	// the descheduler requires the eviction API to be served
+	kubeClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: policyv1.SchemeGroupVersion.String(),
+		},
+		{
+			GroupVersion: corev1.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{
+				{Name: util.EvictionSubResourceName, Kind: util.EvictionKind},
+			},
+		},
+	}
+	return kubeClient, koordfake.NewSimpleClientset(koordObjs...), nil
+}
+
+// ReadOnlyConfig returns a copy of the kube config which rejects all the requests except reads, so that the plan
+// never modifies the cluster even if a plugin tries to.
+func ReadOnlyConfig(config *restclient.Config) *restclient.Config {
+	config = restclient.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &readOnlyRoundTripper{delegate: rt}
+	})
+	return config
+}
+
+type readOnlyRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *readOnlyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return nil, fmt.Errorf("%s %s is rejected by the read-only client", req.Method, req.URL.Path)
+	}
+	return rt.delegate.RoundTrip(req)
+}
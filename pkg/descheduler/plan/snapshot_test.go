@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+const (
+	testNodesYAML = `
+apiVersion: v1
+kind: Node
+metadata:
+  name: node-1
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Node
+  metadata:
+    name: node-2
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    namespace: default
+    name: pod-1
+  spec:
+    nodeName: node-2
+`
+	testNodeMetricJSON = `{"apiVersion":"slo.koordinator.sh/v1alpha1","kind":"NodeMetric","metadata":{"name":"node-1"}}`
+)
+
+func TestLoadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "nodes.yaml"), []byte(testNodesYAML), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "metrics.json"), []byte(testNodeMetricJSON), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a snapshot"), 0644))
+
+	objs, err := LoadSnapshot(dir)
+	assert.NoError(t, err)
+	assert.Len(t, objs, 4)
+
+	client, koordClient, err := NewSnapshotClientSets(objs)
+	assert.NoError(t, err)
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, nodes.Items, 2)
+	pod, err := client.CoreV1().Pods("default").Get(context.TODO(), "pod-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "node-2", pod.Spec.NodeName)
+	nodeMetric, err := koordClient.SloV1alpha1().NodeMetrics().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "node-1", nodeMetric.Name)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("apiVersion: v1\nkind: Unknown\n"), 0644))
+	_, err = LoadSnapshot(dir)
+	assert.Error(t, err)
+}
+
+func TestReadOnlyConfig(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"namespace":"default","name":"pod-1"}}`))
+	}))
+	defer server.Close()
+
+	client, err := clientset.NewForConfig(ReadOnlyConfig(&restclient.Config{Host: server.URL}))
+	assert.NoError(t, err)
+	_, err = client.CoreV1().Pods("default").Get(context.TODO(), "pod-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	err = client.CoreV1().Pods("default").Delete(context.TODO(), "pod-1", metav1.DeleteOptions{})
+	assert.Error(t, err)
+	_, err = client.CoreV1().Pods("default").Create(context.TODO(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}}, metav1.CreateOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, []string{http.MethodGet}, methods)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+const (
+	// AnnotationPodOwnerUID records the UID of the controller owner of the migrated Pod on the PodMigrationJob,
+	// so that the migration can be attributed to the workload after the Pod is deleted.
+	AnnotationPodOwnerUID = "descheduler.koordinator.sh/pod-owner-uid"
+	// AnnotationPodNodeName records the node where the migrated Pod was running on the PodMigrationJob.
+	AnnotationPodNodeName = "descheduler.koordinator.sh/pod-node-name"
+)
+
+// Record is a completed migration.
+type Record struct {
+	// JobUID is the UID of the PodMigrationJob.
+	JobUID types.UID
+	// Namespace and Name are the namespace and name of the migrated Pod.
+	Namespace string
+	Name      string
+	// PodUID is the UID of the migrated Pod.
+	PodUID types.UID
+	// NewPodUID is the UID of the Pod created after the migration if known.
+	NewPodUID types.UID
+	// OwnerUID is the UID of the controller owner of the migrated Pod.
+	OwnerUID types.UID
+	// SourceNode is the node where the migrated Pod was running.
+	SourceNode string
+	// DestinationNode is the node where the Pod is migrated to if known.
+	DestinationNode string
+	// Timestamp is when the migration completed.
+	Timestamp time.Time
+}
+
+// Store is an in-memory history of the completed migrations. The records older than the TTL are pruned.
+// It can be fed by the PodMigrationJob informer, and the completed PodMigrationJobs are recorded.
+// It serves both the migration cooldowns and the migration cost of the recent migrations.
+type Store struct {
+	lock    sync.RWMutex
+	clock   clock.PassiveClock
+	ttl     time.Duration
+	records map[types.UID]*Record
+}
+
+var _ cache.ResourceEventHandler = &Store{}
+
+func NewStore(ttl time.Duration) *Store {
+	return NewStoreWithClock(ttl, clock.RealClock{})
+}
+
+// NewStoreWithClock creates a Store which expires the records with the clock.
+func NewStoreWithClock(ttl time.Duration, clock clock.PassiveClock) *Store {
+	return &Store{
+		clock:   clock,
+		ttl:     ttl,
+		records: map[types.UID]*Record{},
+	}
+}
+
+// Add adds a record into the Store, the record of the same PodMigrationJob is replaced.
+func (s *Store) Add(record *Record) {
+	if record == nil || s.clock.Since(record.Timestamp) > s.ttl {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records[record.JobUID] = record
+	s.pruneLocked()
+}
+
+// AddPodMigrationJob records the PodMigrationJob if it has succeeded.
+func (s *Store) AddPodMigrationJob(job *sev1alpha1.PodMigrationJob) {
+	if record := RecordFromPodMigrationJob(job, s.clock.Now()); record != nil {
+		s.Add(record)
+	}
+}
+
+// LastMigrationOfPod returns when the Pod was migrated last time. A Pod is considered as migrated if it is created
+// by a migration, or it has the same namespace and name with a migrated Pod, e.g. the Pods of StatefulSet.
+func (s *Store) LastMigrationOfPod(pod *corev1.Pod) (time.Time, bool) {
+	return s.lastMigration(func(r *Record) bool {
+		return (r.NewPodUID != "" && r.NewPodUID == pod.UID) ||
+			r.PodUID == pod.UID ||
+			(r.Namespace == pod.Namespace && r.Name == pod.Name)
+	})
+}
+
+// LastMigrationOfWorkload returns when a Pod of the workload was migrated last time.
+func (s *Store) LastMigrationOfWorkload(ownerUID types.UID) (time.Time, bool) {
+	if ownerUID == "" {
+		return time.Time{}, false
+	}
+	return s.lastMigration(func(r *Record) bool {
+		return r.OwnerUID == ownerUID
+	})
+}
+
+// LastMigrationToNode returns when the node received a migrated Pod last time.
+func (s *Store) LastMigrationToNode(nodeName string) (time.Time, bool) {
+	if nodeName == "" {
+		return time.Time{}, false
+	}
+	return s.lastMigration(func(r *Record) bool {
+		return r.DestinationNode == nodeName
+	})
+}
+
+// CountMigrationsOfWorkload returns how many Pods of the workload were migrated within the window, which is no longer
+// than the TTL of the Store.
+func (s *Store) CountMigrationsOfWorkload(ownerUID types.UID, window time.Duration) int {
+	if ownerUID == "" {
+		return 0
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	count := 0
+	for _, r := range s.records {
+		if since := s.clock.Since(r.Timestamp); since <= s.ttl && since <= window && r.OwnerUID == ownerUID {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Store) lastMigration(match func(r *Record) bool) (time.Time, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var last time.Time
+	found := false
+	for _, r := range s.records {
+		if s.clock.Since(r.Timestamp) > s.ttl || !match(r) {
+			continue
+		}
+		if !found || r.Timestamp.After(last) {
+			last = r.Timestamp
+			found = true
+		}
+	}
+	return last, found
+}
+
+func (s *Store) pruneLocked() {
+	for uid, r := range s.records {
+		if s.clock.Since(r.Timestamp) > s.ttl {
+			delete(s.records, uid)
+		}
+	}
+}
+
+// OnAdd implements cache.ResourceEventHandler.
+func (s *Store) OnAdd(obj interface{}, isInInitialList bool) {
+	if job, ok := obj.(*sev1alpha1.PodMigrationJob); ok {
+		s.AddPodMigrationJob(job)
+	}
+}
+
+// OnUpdate implements cache.ResourceEventHandler.
+func (s *Store) OnUpdate(oldObj, newObj interface{}) {
+	if job, ok := newObj.(*sev1alpha1.PodMigrationJob); ok {
+		s.AddPodMigrationJob(job)
+	}
+}
+
+// OnDelete implements cache.ResourceEventHandler. The records are kept until they expire even if the
+// PodMigrationJobs are deleted.
+func (s *Store) OnDelete(obj interface{}) {
+}
+
+// RecordFromPodMigrationJob builds a Record from the succeeded PodMigrationJob, it returns nil if the job has not
+// succeeded. The completion time is the latest transition time of the conditions, or now if there is none.
+func RecordFromPodMigrationJob(job *sev1alpha1.PodMigrationJob, now time.Time) *Record {
+	if job == nil || job.Status.Phase != sev1alpha1.PodMigrationJobSucceeded || job.Spec.PodRef == nil {
+		return nil
+	}
+	record := &Record{
+		JobUID:          job.UID,
+		Namespace:       job.Spec.PodRef.Namespace,
+		Name:            job.Spec.PodRef.Name,
+		PodUID:          job.Spec.PodRef.UID,
+		OwnerUID:        types.UID(job.Annotations[AnnotationPodOwnerUID]),
+		SourceNode:      job.Annotations[AnnotationPodNodeName],
+		DestinationNode: job.Status.NodeName,
+	}
+	if job.Status.PodRef != nil {
+		record.NewPodUID = job.Status.PodRef.UID
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.LastTransitionTime.After(record.Timestamp) {
+			record.Timestamp = cond.LastTransitionTime.Time
+		}
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = now
+	}
+	return record
+}
+
+// SetPodMigrationJobAnnotations records the owner and the node of the Pod on the PodMigrationJob.
+func SetPodMigrationJobAnnotations(job *sev1alpha1.PodMigrationJob, pod *corev1.Pod) {
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	if ownerRef := metav1.GetControllerOf(pod); ownerRef != nil {
+		job.Annotations[AnnotationPodOwnerUID] = string(ownerRef.UID)
+	}
+	if pod.Spec.NodeName != "" {
+		job.Annotations[AnnotationPodNodeName] = pod.Spec.NodeName
+	}
+}
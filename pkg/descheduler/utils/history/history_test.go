@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestRecordFromPodMigrationJob(t *testing.T) {
+	now := time.Now()
+	completed := now.Add(-time.Minute)
+	job := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-job",
+			UID:  "job-uid",
+			Annotations: map[string]string{
+				AnnotationPodOwnerUID: "rs-uid",
+				AnnotationPodNodeName: "node-1",
+			},
+		},
+		Spec: sev1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{Namespace: "default", Name: "test-pod", UID: "pod-uid"},
+		},
+		Status: sev1alpha1.PodMigrationJobStatus{
+			Phase:    sev1alpha1.PodMigrationJobRunning,
+			NodeName: "node-2",
+			PodRef:   &corev1.ObjectReference{Namespace: "default", Name: "test-pod-new", UID: "new-pod-uid"},
+			Conditions: []sev1alpha1.PodMigrationJobCondition{
+				{Type: sev1alpha1.PodMigrationJobConditionReservationScheduled, LastTransitionTime: metav1.NewTime(now.Add(-time.Hour))},
+				{Type: sev1alpha1.PodMigrationJobConditionEviction, LastTransitionTime: metav1.NewTime(completed)},
+			},
+		},
+	}
+	assert.Nil(t, RecordFromPodMigrationJob(job, now))
+
+	job.Status.Phase = sev1alpha1.PodMigrationJobSucceeded
+	assert.Equal(t, &Record{
+		JobUID:          "job-uid",
+		Namespace:       "default",
+		Name:            "test-pod",
+		PodUID:          "pod-uid",
+		NewPodUID:       "new-pod-uid",
+		OwnerUID:        "rs-uid",
+		SourceNode:      "node-1",
+		DestinationNode: "node-2",
+		Timestamp:       completed,
+	}, RecordFromPodMigrationJob(job, now))
+
+	job.Status.Conditions = nil
+	assert.Equal(t, now, RecordFromPodMigrationJob(job, now).Timestamp)
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	fakeClock := clocktesting.NewFakeClock(now)
+	s := NewStoreWithClock(time.Hour, fakeClock)
+
+	s.Add(&Record{
+		JobUID:          "job-1",
+		Namespace:       "default",
+		Name:            "web-0",
+		PodUID:          "web-0-uid",
+		OwnerUID:        "sts-uid",
+		DestinationNode: "node-1",
+		Timestamp:       now.Add(-30 * time.Minute),
+	})
+	s.Add(&Record{
+		JobUID:          "job-2",
+		Namespace:       "default",
+		Name:            "web-1",
+		PodUID:          "web-1-uid",
+		NewPodUID:       "web-1-new-uid",
+		OwnerUID:        "sts-uid",
+		DestinationNode: "node-2",
+		Timestamp:       now.Add(-10 * time.Minute),
+	})
+	// expired already
+	s.Add(&Record{JobUID: "job-3", DestinationNode: "node-3", Timestamp: now.Add(-2 * time.Hour)})
+
+	last, ok := s.LastMigrationOfPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-0", UID: "web-0-new-uid"}})
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(-30*time.Minute), last)
+	last, ok = s.LastMigrationOfPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-1-abcde", UID: "web-1-new-uid"}})
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(-10*time.Minute), last)
+	_, ok = s.LastMigrationOfPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "web-0", UID: "other-uid"}})
+	assert.False(t, ok)
+
+	last, ok = s.LastMigrationOfWorkload("sts-uid")
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(-10*time.Minute), last)
+	_, ok = s.LastMigrationOfWorkload("")
+	assert.False(t, ok)
+	assert.Equal(t, 2, s.CountMigrationsOfWorkload("sts-uid", time.Hour))
+	assert.Equal(t, 1, s.CountMigrationsOfWorkload("sts-uid", 20*time.Minute))
+	assert.Equal(t, 0, s.CountMigrationsOfWorkload("", time.Hour))
+
+	_, ok = s.LastMigrationToNode("node-1")
+	assert.True(t, ok)
+	_, ok = s.LastMigrationToNode("node-3")
+	assert.False(t, ok)
+
+	fakeClock.Step(40 * time.Minute)
+	_, ok = s.LastMigrationToNode("node-1")
+	assert.False(t, ok)
+	_, ok = s.LastMigrationToNode("node-2")
+	assert.True(t, ok)
+
+	fakeClock.Step(time.Hour)
+	s.Add(&Record{JobUID: "job-4", Timestamp: fakeClock.Now()})
+	assert.Len(t, s.records, 1)
+}
+
+func TestStoreEventHandler(t *testing.T) {
+	s := NewStore(time.Hour)
+	job := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{UID: "job-uid"},
+		Spec: sev1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{Namespace: "default", Name: "test-pod", UID: "pod-uid"},
+		},
+		Status: sev1alpha1.PodMigrationJobStatus{
+			Phase:    sev1alpha1.PodMigrationJobRunning,
+			NodeName: "node-1",
+		},
+	}
+	s.OnAdd(job, true)
+	_, ok := s.LastMigrationToNode("node-1")
+	assert.False(t, ok)
+
+	succeeded := job.DeepCopy()
+	succeeded.Status.Phase = sev1alpha1.PodMigrationJobSucceeded
+	s.OnUpdate(job, succeeded)
+	_, ok = s.LastMigrationToNode("node-1")
+	assert.True(t, ok)
+
+	s.OnDelete(succeeded)
+	_, ok = s.LastMigrationToNode("node-1")
+	assert.True(t, ok)
+}
+
+func TestSetPodMigrationJobAnnotations(t *testing.T) {
+	job := &sev1alpha1.PodMigrationJob{}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "test-rs", UID: types.UID("rs-uid"), Controller: ptr.To(true)},
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-1"},
+	}
+	SetPodMigrationJobAnnotations(job, pod)
+	assert.Equal(t, map[string]string{
+		AnnotationPodOwnerUID: "rs-uid",
+		AnnotationPodNodeName: "node-1",
+	}, job.Annotations)
+}
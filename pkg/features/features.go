@@ -103,6 +103,9 @@ const (
 	// ColocationProfileController enables the reconciliation for ClusterColocationProfile.
 	ColocationProfileController featuregate.Feature = "ColocationProfileController"
 
+	// RecommendationController enables the reconciliation for Recommendation.
+	RecommendationController featuregate.Feature = "RecommendationController"
+
 	// ValidatePodDeviceResource enables validate pod device resource
 	ValidatePodDeviceResource featuregate.Feature = "ValidatePodDeviceResource"
 
@@ -140,6 +143,7 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	EnableQuotaAdmissionOnUpdate:            {Default: false, PreRelease: featuregate.Alpha},
 	EnableSyncGPUSharedResource:             {Default: false, PreRelease: featuregate.Alpha},
 	ColocationProfileController:             {Default: false, PreRelease: featuregate.Alpha},
+	RecommendationController:                {Default: false, PreRelease: featuregate.Alpha},
 	ValidatePodDeviceResource:               {Default: false, PreRelease: featuregate.Alpha},
 	EnablePodEnhancedValidator:              {Default: false, PreRelease: featuregate.Alpha},
 	DisableExtendedResourceSpec:             {Default: false, PreRelease: featuregate.Alpha},
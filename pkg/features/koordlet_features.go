@@ -88,6 +88,24 @@ const (
 	// MemoryAllocatableEvict evicts those configured priority pods when node lack of allocatable memory.
 	MemoryAllocatableEvict featuregate.Feature = "MemoryAllocatableEvict"
 
+	// owner: @koordinator
+	// alpha: v1.9
+	//
+	// BEPSISuppress throttles and evicts best-effort pods according to the pressure stall information of non-BE pods.
+	BEPSISuppress featuregate.Feature = "BEPSISuppress"
+
+	// owner: @koordinator
+	// alpha: v1.9
+	//
+	// BEInterferenceEvict limits and evicts best-effort pods interfering non-BE pods according to CPI and resctrl metrics.
+	BEInterferenceEvict featuregate.Feature = "BEInterferenceEvict"
+
+	// owner: @koordinator
+	// alpha: v1.9
+	//
+	// BEMemoryHighTune tunes memory.high of best-effort pods according to the memory pressure, refaults and node memory usage.
+	BEMemoryHighTune featuregate.Feature = "BEMemoryHighTune"
+
 	// owner: @saintube @zwzhang0107
 	// alpha: v0.2
 	// beta: v1.1
@@ -235,6 +253,9 @@ var (
 		BEMemoryEvict:          {Default: false, PreRelease: featuregate.Alpha},
 		MemoryEvict:            {Default: false, PreRelease: featuregate.Alpha},
 		MemoryAllocatableEvict: {Default: false, PreRelease: featuregate.Alpha},
+		BEPSISuppress:          {Default: false, PreRelease: featuregate.Alpha},
+		BEInterferenceEvict:    {Default: false, PreRelease: featuregate.Alpha},
+		BEMemoryHighTune:       {Default: false, PreRelease: featuregate.Alpha},
 		CPUBurst:               {Default: true, PreRelease: featuregate.Beta},
 		SystemConfig:           {Default: false, PreRelease: featuregate.Alpha},
 		RdtResctrl:             {Default: true, PreRelease: featuregate.Beta},
@@ -264,7 +285,7 @@ func IsFeatureDisabled(nodeSLO *slov1alpha1.NodeSLO, feature featuregate.Feature
 
 	spec := nodeSLO.Spec
 	switch feature {
-	case BECPUSuppress, BEMemoryEvict, BECPUEvict, CPUEvict, MemoryEvict, CPUAllocatableEvict, MemoryAllocatableEvict:
+	case BECPUSuppress, BEMemoryEvict, BECPUEvict, CPUEvict, MemoryEvict, CPUAllocatableEvict, MemoryAllocatableEvict, BEPSISuppress, BEInterferenceEvict, BEMemoryHighTune:
 		if spec.ResourceUsedThresholdWithBE == nil || spec.ResourceUsedThresholdWithBE.Enable == nil {
 			return true, fmt.Errorf("cannot parse feature config for invalid nodeSLO %v", nodeSLO)
 		}
@@ -18,9 +18,20 @@ package metriccache
 
 import (
 	"flag"
+	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/prometheus/prometheus/tsdb"
+	cliflag "k8s.io/component-base/cli/flag"
+)
+
+const (
+	KVStorageTypeMemory = "memory"
+	KVStorageTypeFile   = "file"
+
+	// defaultKVStorageDir is the directory of the file kv storage under the TSDBPath if the KVStoragePath is not set
+	defaultKVStorageDir = "kv"
 )
 
 type Config struct {
@@ -39,6 +50,22 @@ type Config struct {
 	TSDBMinBlockDuration          time.Duration
 	TSDBMaxBlockDuration          time.Duration
 	TSDBHeadChunksWriteBufferSize int
+
+	KVStorageType             string
+	KVStoragePath             string
+	KVStorageCompactThreshold int
+
+	// RemoteWriteURL is the Prometheus remote-write endpoint to export the tsdb samples, disabled if empty
+	RemoteWriteURL                string
+	RemoteWriteTimeout            time.Duration
+	RemoteWriteBatchSize          int
+	RemoteWriteFlushInterval      time.Duration
+	RemoteWriteMaxBufferedSamples int
+	RemoteWriteBufferPath         string
+	RemoteWriteMaxRetries         int
+	RemoteWriteRetryBackoff       time.Duration
+	RemoteWriteExternalLabels     map[string]string
+	RemoteWriteRelabelConfigFile  string
 }
 
 func NewDefaultConfig() *Config {
@@ -57,9 +84,37 @@ func NewDefaultConfig() *Config {
 		TSDBMinBlockDuration:          10 * time.Minute, // 10 minutes
 		TSDBMaxBlockDuration:          10 * time.Minute, // 10 minutes
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,      // 1 MB
+
+		KVStorageType:             KVStorageTypeMemory,
+		KVStorageCompactThreshold: 100,
+
+		RemoteWriteTimeout:            30 * time.Second,
+		RemoteWriteBatchSize:          1000,
+		RemoteWriteFlushInterval:      10 * time.Second,
+		RemoteWriteMaxBufferedSamples: 100000,
+		RemoteWriteMaxRetries:         3,
+		RemoteWriteRetryBackoff:       500 * time.Millisecond,
+		RemoteWriteExternalLabels:     map[string]string{},
 	}
 }
 
+// GetRemoteWriteBufferPath returns the path of the remote-write buffer, which defaults to the directory under the
+// TSDBPath.
+func (c *Config) GetRemoteWriteBufferPath() string {
+	if c.RemoteWriteBufferPath != "" {
+		return c.RemoteWriteBufferPath
+	}
+	return filepath.Join(c.TSDBPath, defaultRemoteWriteBufferDir)
+}
+
+// GetKVStoragePath returns the path of the file kv storage, which defaults to the directory under the TSDBPath.
+func (c *Config) GetKVStoragePath() string {
+	if c.KVStoragePath != "" {
+		return c.KVStoragePath
+	}
+	return filepath.Join(c.TSDBPath, defaultKVStorageDir)
+}
+
 func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.MetricGCIntervalSeconds, "metric-gc-interval-seconds", c.MetricGCIntervalSeconds, "Collect node metrics interval by seconds")
 	fs.IntVar(&c.MetricExpireSeconds, "metric-expire-seconds", c.MetricExpireSeconds, "Collect pod metrics expire by seconds")
@@ -76,4 +131,19 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.TSDBMaxBlockDuration, "tsdb-max-block-duration", c.TSDBMaxBlockDuration, "The maximum timestamp range of compacted blocks, recommend >= 1h or this will cause chunks_head leak.")
 	fs.IntVar(&c.TSDBHeadChunksWriteBufferSize, "tsdb-head-chunks-write-buffer-size", c.TSDBHeadChunksWriteBufferSize, "Write buffer size used by the head chunks mapper.")
 
+	fs.StringVar(&c.KVStorageType, "kv-storage-type", c.KVStorageType, fmt.Sprintf("Type of the kv storage, supported types: %s, %s. The %s storage persists the node info to warm up the restart.", KVStorageTypeMemory, KVStorageTypeFile, KVStorageTypeFile))
+	fs.StringVar(&c.KVStoragePath, "kv-storage-path", c.KVStoragePath, fmt.Sprintf("Base path for the file kv storage. Defaults to the %s/ under the tsdb-path if empty.", defaultKVStorageDir))
+	fs.IntVar(&c.KVStorageCompactThreshold, "kv-storage-compact-threshold", c.KVStorageCompactThreshold, "Number of stale records in the file kv storage to trigger the compaction.")
+
+	fs.StringVar(&c.RemoteWriteURL, "remote-write-url", c.RemoteWriteURL, "URL of the Prometheus remote-write endpoint to export the tsdb samples. Disabled if empty.")
+	fs.DurationVar(&c.RemoteWriteTimeout, "remote-write-timeout", c.RemoteWriteTimeout, "Timeout of each remote-write request.")
+	fs.IntVar(&c.RemoteWriteBatchSize, "remote-write-batch-size", c.RemoteWriteBatchSize, "Max number of samples sent in one remote-write request.")
+	fs.DurationVar(&c.RemoteWriteFlushInterval, "remote-write-flush-interval", c.RemoteWriteFlushInterval, "Interval to send the buffered samples by remote-write.")
+	fs.IntVar(&c.RemoteWriteMaxBufferedSamples, "remote-write-max-buffered-samples", c.RemoteWriteMaxBufferedSamples, "Max number of samples buffered for remote-write, the oldest samples are dropped when it is full. The buffered samples are persisted under the remote-write-buffer-path.")
+	fs.StringVar(&c.RemoteWriteBufferPath, "remote-write-buffer-path", c.RemoteWriteBufferPath, fmt.Sprintf("Base path to persist the buffered samples of remote-write. Defaults to the %s/ under the tsdb-path if empty.", defaultRemoteWriteBufferDir))
+	fs.IntVar(&c.RemoteWriteMaxRetries, "remote-write-max-retries", c.RemoteWriteMaxRetries, "Max retries of a failed remote-write request before retrying in the next flush.")
+	fs.DurationVar(&c.RemoteWriteRetryBackoff, "remote-write-retry-backoff", c.RemoteWriteRetryBackoff, "Initial backoff of retrying a failed remote-write request, which is doubled for each retry.")
+	fs.Var(cliflag.NewMapStringString(&c.RemoteWriteExternalLabels), "remote-write-external-labels", "A set of key=value labels attached to the samples of remote-write, e.g. node=node-1.")
+	fs.StringVar(&c.RemoteWriteRelabelConfigFile, "remote-write-relabel-config-file", c.RemoteWriteRelabelConfigFile, "Path of the yaml file containing the Prometheus relabel configs applied to the samples of remote-write.")
+
 }
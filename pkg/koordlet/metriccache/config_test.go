@@ -41,11 +41,38 @@ func Test_NewDefaultConfig(t *testing.T) {
 		TSDBMinBlockDuration:          10 * time.Minute,
 		TSDBMaxBlockDuration:          10 * time.Minute,
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,
+
+		KVStorageType:             KVStorageTypeMemory,
+		KVStorageCompactThreshold: 100,
+
+		RemoteWriteTimeout:            30 * time.Second,
+		RemoteWriteBatchSize:          1000,
+		RemoteWriteFlushInterval:      10 * time.Second,
+		RemoteWriteMaxBufferedSamples: 100000,
+		RemoteWriteMaxRetries:         3,
+		RemoteWriteRetryBackoff:       500 * time.Millisecond,
+		RemoteWriteExternalLabels:     map[string]string{},
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
 }
 
+func Test_GetKVStoragePath(t *testing.T) {
+	c := NewDefaultConfig()
+	assert.Equal(t, "/metric-data/kv", c.GetKVStoragePath())
+	c.TSDBPath = "/test-tsdb-path/"
+	assert.Equal(t, "/test-tsdb-path/kv", c.GetKVStoragePath())
+	c.KVStoragePath = "/test-kv-path/"
+	assert.Equal(t, "/test-kv-path/", c.GetKVStoragePath())
+}
+
+func Test_GetRemoteWriteBufferPath(t *testing.T) {
+	c := NewDefaultConfig()
+	assert.Equal(t, "/metric-data/remote-write", c.GetRemoteWriteBufferPath())
+	c.RemoteWriteBufferPath = "/test-remote-write-path/"
+	assert.Equal(t, "/test-remote-write-path/", c.GetRemoteWriteBufferPath())
+}
+
 func Test_InitFlags(t *testing.T) {
 	cmdArgs := []string{
 		"",
@@ -63,6 +90,21 @@ func Test_InitFlags(t *testing.T) {
 		"--tsdb-min-block-duration=10m",
 		"--tsdb-max-block-duration=20m",
 		"--tsdb-head-chunks-write-buffer-size=512",
+
+		"--kv-storage-type=file",
+		"--kv-storage-path=/test-kv-path/",
+		"--kv-storage-compact-threshold=10",
+
+		"--remote-write-url=http://localhost:9090/api/v1/write",
+		"--remote-write-timeout=10s",
+		"--remote-write-batch-size=100",
+		"--remote-write-flush-interval=5s",
+		"--remote-write-max-buffered-samples=1000",
+		"--remote-write-buffer-path=/test-remote-write-path/",
+		"--remote-write-max-retries=5",
+		"--remote-write-retry-backoff=1s",
+		"--remote-write-external-labels=cluster=test,node=test-node",
+		"--remote-write-relabel-config-file=/test-relabel.yaml",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -81,6 +123,21 @@ func Test_InitFlags(t *testing.T) {
 		TSDBMinBlockDuration          time.Duration
 		TSDBMaxBlockDuration          time.Duration
 		TSDBHeadChunksWriteBufferSize int
+
+		KVStorageType             string
+		KVStoragePath             string
+		KVStorageCompactThreshold int
+
+		RemoteWriteURL                string
+		RemoteWriteTimeout            time.Duration
+		RemoteWriteBatchSize          int
+		RemoteWriteFlushInterval      time.Duration
+		RemoteWriteMaxBufferedSamples int
+		RemoteWriteBufferPath         string
+		RemoteWriteMaxRetries         int
+		RemoteWriteRetryBackoff       time.Duration
+		RemoteWriteExternalLabels     map[string]string
+		RemoteWriteRelabelConfigFile  string
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -105,6 +162,19 @@ func Test_InitFlags(t *testing.T) {
 				TSDBMinBlockDuration:          10 * time.Minute,
 				TSDBMaxBlockDuration:          20 * time.Minute,
 				TSDBHeadChunksWriteBufferSize: 512,
+				KVStorageType:                 KVStorageTypeFile,
+				KVStoragePath:                 "/test-kv-path/",
+				KVStorageCompactThreshold:     10,
+				RemoteWriteURL:                "http://localhost:9090/api/v1/write",
+				RemoteWriteTimeout:            10 * time.Second,
+				RemoteWriteBatchSize:          100,
+				RemoteWriteFlushInterval:      5 * time.Second,
+				RemoteWriteMaxBufferedSamples: 1000,
+				RemoteWriteBufferPath:         "/test-remote-write-path/",
+				RemoteWriteMaxRetries:         5,
+				RemoteWriteRetryBackoff:       time.Second,
+				RemoteWriteExternalLabels:     map[string]string{"cluster": "test", "node": "test-node"},
+				RemoteWriteRelabelConfigFile:  "/test-relabel.yaml",
 			},
 			args: args{fs: fs},
 		},
@@ -126,6 +196,21 @@ func Test_InitFlags(t *testing.T) {
 				TSDBMinBlockDuration:          tt.fields.TSDBMinBlockDuration,
 				TSDBMaxBlockDuration:          tt.fields.TSDBMaxBlockDuration,
 				TSDBHeadChunksWriteBufferSize: tt.fields.TSDBHeadChunksWriteBufferSize,
+
+				KVStorageType:             tt.fields.KVStorageType,
+				KVStoragePath:             tt.fields.KVStoragePath,
+				KVStorageCompactThreshold: tt.fields.KVStorageCompactThreshold,
+
+				RemoteWriteURL:                tt.fields.RemoteWriteURL,
+				RemoteWriteTimeout:            tt.fields.RemoteWriteTimeout,
+				RemoteWriteBatchSize:          tt.fields.RemoteWriteBatchSize,
+				RemoteWriteFlushInterval:      tt.fields.RemoteWriteFlushInterval,
+				RemoteWriteMaxBufferedSamples: tt.fields.RemoteWriteMaxBufferedSamples,
+				RemoteWriteBufferPath:         tt.fields.RemoteWriteBufferPath,
+				RemoteWriteMaxRetries:         tt.fields.RemoteWriteMaxRetries,
+				RemoteWriteRetryBackoff:       tt.fields.RemoteWriteRetryBackoff,
+				RemoteWriteExternalLabels:     tt.fields.RemoteWriteExternalLabels,
+				RemoteWriteRelabelConfigFile:  tt.fields.RemoteWriteRelabelConfigFile,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
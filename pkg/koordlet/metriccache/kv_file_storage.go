@@ -0,0 +1,281 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+const (
+	// kvFileStorageFormatVersion is the version of the log file layout. A log file of another format version is
+	// discarded on loading since it cannot be parsed safely.
+	kvFileStorageFormatVersion = 1
+
+	kvFileStorageLogName = "kv.log"
+	kvFileStorageTmpName = "kv.log.tmp"
+)
+
+// KVSchema describes how the value of a key is persisted.
+// The Version should be bumped when the value type of the key changes incompatibly, so that the values persisted
+// by an older koordlet are dropped and re-collected instead of being decoded into the wrong structure.
+type KVSchema struct {
+	Version int
+	// NewValue returns an empty pointer of the value type to decode into.
+	NewValue func() interface{}
+}
+
+var kvSchemas = map[string]KVSchema{
+	NodeCPUInfoKey: {
+		Version:  1,
+		NewValue: func() interface{} { return &NodeCPUInfo{} },
+	},
+	NodeNUMAInfoKey: {
+		Version:  1,
+		NewValue: func() interface{} { return &util.NodeNUMAInfo{} },
+	},
+	NodeLocalStorageInfoKey: {
+		Version:  1,
+		NewValue: func() interface{} { return &NodeLocalStorageInfo{} },
+	},
+}
+
+// kvLogHeader is the first line of the log file.
+type kvLogHeader struct {
+	FormatVersion int `json:"formatVersion"`
+}
+
+// kvLogRecord is a line of the log file recording the latest value of a key.
+type kvLogRecord struct {
+	Key     string          `json:"key"`
+	Version int             `json:"version"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// fileStorage is a KVStorage persisting the values with registered schemas into an append-only JSON log, so that
+// they can be restored after the koordlet restarts. The keys without schema are only kept in memory.
+// The log is compacted into one record per key when the stale records exceed the compact threshold.
+type fileStorage struct {
+	lock sync.RWMutex
+	// values keeps all values in memory, which is the source of Get
+	values map[interface{}]interface{}
+	// persisted records the encoded values in the log to skip the unchanged Set
+	persisted map[string][]byte
+
+	dir              string
+	compactThreshold int
+	file             *os.File
+	staleRecords     int
+}
+
+// NewFileStorage returns a KVStorage backed by the log file under the dir. The persisted values are loaded, and
+// the log is compacted before returning.
+func NewFileStorage(dir string, compactThreshold int) (KVStorage, error) {
+	return newFileStorage(dir, compactThreshold)
+}
+
+func newFileStorage(dir string, compactThreshold int) (*fileStorage, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create kv storage dir %s, err: %w", dir, err)
+	}
+	fs := &fileStorage{
+		values:           map[interface{}]interface{}{},
+		persisted:        map[string][]byte{},
+		dir:              dir,
+		compactThreshold: compactThreshold,
+	}
+	if err := fs.load(); err != nil {
+		return nil, err
+	}
+	if err := fs.compact(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (fs *fileStorage) Get(key interface{}) (interface{}, bool) {
+	fs.lock.RLock()
+	defer fs.lock.RUnlock()
+	value, ok := fs.values[key]
+	return value, ok
+}
+
+func (fs *fileStorage) Set(key, value interface{}) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	fs.values[key] = value
+
+	keyStr, ok := key.(string)
+	if !ok {
+		return
+	}
+	schema, ok := kvSchemas[keyStr]
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		klog.Warningf("failed to encode kv storage value of key %s, err: %v", keyStr, err)
+		return
+	}
+	if old, ok := fs.persisted[keyStr]; ok {
+		if bytes.Equal(old, data) {
+			return
+		}
+		fs.staleRecords++
+	}
+	if err = fs.appendRecord(&kvLogRecord{Key: keyStr, Version: schema.Version, Value: data}); err != nil {
+		klog.Warningf("failed to persist kv storage value of key %s, err: %v", keyStr, err)
+		return
+	}
+	fs.persisted[keyStr] = data
+
+	if fs.compactThreshold > 0 && fs.staleRecords >= fs.compactThreshold {
+		if err = fs.compact(); err != nil {
+			klog.Warningf("failed to compact kv storage, err: %v", err)
+		}
+	}
+}
+
+// Close closes the log file.
+func (fs *fileStorage) Close() error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.file.Close()
+	fs.file = nil
+	return err
+}
+
+func (fs *fileStorage) appendRecord(record *kvLogRecord) error {
+	if fs.file == nil {
+		return fmt.Errorf("kv storage log is not opened")
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err = fs.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return fs.file.Sync()
+}
+
+// load restores the values from the log. The records which are corrupted (e.g. the last line is partially written
+// when the koordlet crashed), or whose schema version is different from the current one are skipped.
+func (fs *fileStorage) load() error {
+	f, err := os.Open(filepath.Join(fs.dir, kvFileStorageLogName))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to open kv storage log, err: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		return nil
+	}
+	header := &kvLogHeader{}
+	if err = json.Unmarshal(scanner.Bytes(), header); err != nil || header.FormatVersion != kvFileStorageFormatVersion {
+		klog.Warningf("discard kv storage log of unknown format, header %q, err: %v", scanner.Text(), err)
+		return nil
+	}
+
+	for scanner.Scan() {
+		record := &kvLogRecord{}
+		if err = json.Unmarshal(scanner.Bytes(), record); err != nil {
+			klog.Warningf("skip corrupted kv storage record, err: %v", err)
+			continue
+		}
+		schema, ok := kvSchemas[record.Key]
+		if !ok || schema.Version != record.Version {
+			klog.V(4).Infof("skip kv storage record of key %s with version %d", record.Key, record.Version)
+			continue
+		}
+		value := schema.NewValue()
+		if err = json.Unmarshal(record.Value, value); err != nil {
+			klog.Warningf("skip kv storage record of key %s, failed to decode value, err: %v", record.Key, err)
+			continue
+		}
+		fs.values[record.Key] = value
+		fs.persisted[record.Key] = record.Value
+	}
+	if err = scanner.Err(); err != nil {
+		klog.Warningf("failed to read kv storage log completely, err: %v", err)
+	}
+	klog.V(4).Infof("kv storage loaded %d keys from %s", len(fs.persisted), fs.dir)
+	return nil
+}
+
+// compact rewrites the log with the latest record of each key, and then reopens it for appending.
+// The new log is written into a temporary file and renamed to keep the log consistent if the koordlet crashes.
+func (fs *fileStorage) compact() error {
+	tmpPath := filepath.Join(fs.dir, kvFileStorageTmpName)
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create kv storage log, err: %w", err)
+	}
+	w := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(w)
+	err = encoder.Encode(&kvLogHeader{FormatVersion: kvFileStorageFormatVersion})
+	for key, data := range fs.persisted {
+		if err != nil {
+			break
+		}
+		err = encoder.Encode(&kvLogRecord{Key: key, Version: kvSchemas[key].Version, Value: data})
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write kv storage log, err: %w", err)
+	}
+
+	logPath := filepath.Join(fs.dir, kvFileStorageLogName)
+	if err = os.Rename(tmpPath, logPath); err != nil {
+		return fmt.Errorf("failed to replace kv storage log, err: %w", err)
+	}
+	if fs.file != nil {
+		_ = fs.file.Close()
+	}
+	fs.file, err = os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open kv storage log, err: %w", err)
+	}
+	fs.staleRecords = 0
+	klog.V(5).Infof("kv storage compacted, %d keys persisted", len(fs.persisted))
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+func countLogLines(t *testing.T, dir string) int {
+	f, err := os.Open(filepath.Join(dir, kvFileStorageLogName))
+	assert.NoError(t, err)
+	defer f.Close()
+	count := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		count++
+	}
+	return count
+}
+
+func Test_fileStorage_Restore(t *testing.T) {
+	dir := t.TempDir()
+	cpuInfo := &NodeCPUInfo{
+		ProcessorInfos: []util.ProcessorInfo{
+			{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 1, CoreID: 0, SocketID: 0, NodeID: 0},
+		},
+		TotalInfo: util.CPUTotalInfo{NumberCPUs: 2},
+	}
+	numaInfo := &util.NodeNUMAInfo{
+		NUMAInfos: []util.NUMAInfo{{NUMANodeID: 0}},
+	}
+
+	s, err := NewFileStorage(dir, 100)
+	assert.NoError(t, err)
+	s.Set(NodeCPUInfoKey, cpuInfo)
+	s.Set(NodeNUMAInfoKey, numaInfo)
+	s.Set("memory-only", "test")
+	got, ok := s.Get(NodeCPUInfoKey)
+	assert.True(t, ok)
+	assert.Equal(t, cpuInfo, got)
+	assert.NoError(t, s.(*fileStorage).Close())
+
+	restored, err := NewFileStorage(dir, 100)
+	assert.NoError(t, err)
+	defer restored.(*fileStorage).Close()
+	got, ok = restored.Get(NodeCPUInfoKey)
+	assert.True(t, ok)
+	assert.Equal(t, cpuInfo, got)
+	got, ok = restored.Get(NodeNUMAInfoKey)
+	assert.True(t, ok)
+	assert.Equal(t, numaInfo, got)
+	_, ok = restored.Get("memory-only")
+	assert.False(t, ok)
+}
+
+func Test_fileStorage_Compact(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newFileStorage(dir, 3)
+	assert.NoError(t, err)
+	defer s.Close()
+	// header only
+	assert.Equal(t, 1, countLogLines(t, dir))
+
+	s.Set(NodeCPUInfoKey, &NodeCPUInfo{TotalInfo: util.CPUTotalInfo{NumberCPUs: 1}})
+	// unchanged value is not appended
+	s.Set(NodeCPUInfoKey, &NodeCPUInfo{TotalInfo: util.CPUTotalInfo{NumberCPUs: 1}})
+	assert.Equal(t, 2, countLogLines(t, dir))
+
+	s.Set(NodeCPUInfoKey, &NodeCPUInfo{TotalInfo: util.CPUTotalInfo{NumberCPUs: 2}})
+	s.Set(NodeCPUInfoKey, &NodeCPUInfo{TotalInfo: util.CPUTotalInfo{NumberCPUs: 3}})
+	assert.Equal(t, 4, countLogLines(t, dir))
+	assert.Equal(t, 2, s.staleRecords)
+
+	// compacted when the stale records reach the threshold
+	s.Set(NodeCPUInfoKey, &NodeCPUInfo{TotalInfo: util.CPUTotalInfo{NumberCPUs: 4}})
+	assert.Equal(t, 2, countLogLines(t, dir))
+	assert.Equal(t, 0, s.staleRecords)
+
+	s.Set(NodeCPUInfoKey, &NodeCPUInfo{TotalInfo: util.CPUTotalInfo{NumberCPUs: 5}})
+	assert.NoError(t, s.Close())
+	restored, err := newFileStorage(dir, 3)
+	assert.NoError(t, err)
+	defer restored.Close()
+	got, ok := restored.Get(NodeCPUInfoKey)
+	assert.True(t, ok)
+	assert.Equal(t, &NodeCPUInfo{TotalInfo: util.CPUTotalInfo{NumberCPUs: 5}}, got)
+	// compacted on loading
+	assert.Equal(t, 2, countLogLines(t, dir))
+}
+
+func Test_fileStorage_Load(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantCPU *NodeCPUInfo
+	}{
+		{
+			name:    "log not exist",
+			wantCPU: nil,
+		},
+		{
+			name: "skip corrupted and outdated records",
+			content: `{"formatVersion":1}
+{"key":"node_cpu_info","version":1,"value":{"totalInfo":{"numberCPUs":2}}}
+{"key":"node_numa_info","version":0,"value":{}}
+{"key":"unknown","version":1,"value":{}}
+{"key":"node_cpu_info","version":1,"val`,
+			wantCPU: &NodeCPUInfo{TotalInfo: util.CPUTotalInfo{NumberCPUs: 2}},
+		},
+		{
+			name: "discard log of unknown format",
+			content: `{"formatVersion":2}
+{"key":"node_cpu_info","version":1,"value":{"totalInfo":{"numberCPUs":2}}}
+`,
+			wantCPU: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.content != "" {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, kvFileStorageLogName), []byte(tt.content), 0644))
+			}
+			s, err := newFileStorage(dir, 100)
+			assert.NoError(t, err)
+			defer s.Close()
+			got, ok := s.Get(NodeCPUInfoKey)
+			assert.Equal(t, tt.wantCPU != nil, ok)
+			if tt.wantCPU != nil {
+				assert.Equal(t, tt.wantCPU, got)
+			}
+			_, ok = s.Get(NodeNUMAInfoKey)
+			assert.False(t, ok)
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package metriccache
 
 import (
+	"fmt"
+	"io"
 	"time"
 )
 
@@ -63,6 +65,7 @@ type metricCache struct {
 	config *Config
 	TSDBStorage
 	KVStorage
+	exporter *remoteWriteExporter
 }
 
 func NewMetricCache(cfg *Config) (MetricCache, error) {
@@ -70,16 +73,46 @@ func NewMetricCache(cfg *Config) (MetricCache, error) {
 	if err != nil {
 		return nil, err
 	}
-	kvdb := NewMemoryStorage()
+	var exporter *remoteWriteExporter
+	if cfg.RemoteWriteURL != "" {
+		if exporter, err = newRemoteWriteExporter(cfg); err != nil {
+			_ = tsdb.Close()
+			return nil, err
+		}
+		tsdb = &remoteWriteStorage{TSDBStorage: tsdb, exporter: exporter}
+	}
+	kvdb, err := newKVStorage(cfg)
+	if err != nil {
+		_ = tsdb.Close()
+		return nil, err
+	}
 	return &metricCache{
 		config:      cfg,
 		TSDBStorage: tsdb,
 		KVStorage:   kvdb,
+		exporter:    exporter,
 	}, nil
 }
 
 func (m *metricCache) Run(stopCh <-chan struct{}) error {
+	if m.exporter != nil {
+		go m.exporter.Run(stopCh)
+	}
 	<-stopCh
 	m.Close()
+	if closer, ok := m.KVStorage.(io.Closer); ok {
+		_ = closer.Close()
+	}
 	return nil
 }
+
+func newKVStorage(cfg *Config) (KVStorage, error) {
+	switch cfg.KVStorageType {
+	case "", KVStorageTypeMemory:
+		return NewMemoryStorage(), nil
+	case KVStorageTypeFile:
+		return NewFileStorage(cfg.GetKVStoragePath(), cfg.KVStorageCompactThreshold)
+	default:
+		return nil, fmt.Errorf("unsupported kv storage type %s", cfg.KVStorageType)
+	}
+}
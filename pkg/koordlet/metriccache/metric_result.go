@@ -80,6 +80,7 @@ const (
 	AggregationTypeP95   AggregationType = "P95"
 	AggregationTypeP90   AggregationType = "P90"
 	AggregationTypeP50   AggregationType = "p50"
+	AggregationTypeMax   AggregationType = "max"
 	AggregationTypeLast  AggregationType = "last"
 	AggregationTypeCount AggregationType = "count"
 )
@@ -165,6 +166,8 @@ func getAggregateFunc(aggregationType AggregationType) AggregationFunc {
 		return percentileFuncOfMetricList(0.9)
 	case AggregationTypeP50:
 		return percentileFuncOfMetricList(0.5)
+	case AggregationTypeMax:
+		return percentileFuncOfMetricList(1)
 	case AggregationTypeLast:
 		return fieldLastOfMetricList
 	case AggregationTypeCount:
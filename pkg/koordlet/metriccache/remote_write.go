@@ -0,0 +1,354 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/prometheus/prometheus/prompb"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+)
+
+const (
+	remoteWriteVersion   = "0.1.0"
+	remoteWriteUserAgent = "koordlet-remote-write"
+)
+
+// remoteWriteSample is a sample relabeled and waiting to be sent.
+type remoteWriteSample struct {
+	labels    labels.Labels
+	timestamp int64
+	value     float64
+}
+
+// remoteWriteExporter streams the samples appended into the TSDB to a Prometheus remote-write endpoint.
+// The samples are buffered in a bounded queue, where the oldest samples are dropped when it is full. They are sent in
+// batches periodically, and a batch failed with a recoverable error is kept in the buffer to retry in the next round.
+// The buffer is persisted into the segment files of a remoteWriteWAL, so the samples not sent yet are restored after
+// the koordlet restarts.
+type remoteWriteExporter struct {
+	url            string
+	client         *http.Client
+	batchSize      int
+	flushInterval  time.Duration
+	maxBuffered    int
+	maxRetries     int
+	retryBackoff   time.Duration
+	externalLabels labels.Labels
+	relabelConfigs []*relabel.Config
+
+	lock   sync.Mutex
+	buffer []remoteWriteSample
+	// removed is the number of samples ever removed from the head of the buffer, which is used to locate the
+	// samples being sent and persisted
+	removed uint64
+	wal     *remoteWriteWAL
+	// flushLock ensures only one batch of the buffer is sent at a time
+	flushLock sync.Mutex
+	// stopCh stops the retry backoff of the failed batches
+	stopCh <-chan struct{}
+}
+
+func newRemoteWriteExporter(cfg *Config) (*remoteWriteExporter, error) {
+	var relabelConfigs []*relabel.Config
+	if cfg.RemoteWriteRelabelConfigFile != "" {
+		content, err := os.ReadFile(cfg.RemoteWriteRelabelConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read remote write relabel config, err: %w", err)
+		}
+		if relabelConfigs, err = parseRelabelConfigs(content); err != nil {
+			return nil, err
+		}
+	}
+	wal, buffer, offset, err := openRemoteWriteWAL(cfg.GetRemoteWriteBufferPath(), cfg.RemoteWriteBatchSize, cfg.RemoteWriteMaxBufferedSamples)
+	if err != nil {
+		return nil, err
+	}
+	metrics.RecordRemoteWritePendingSamples(len(buffer))
+	return &remoteWriteExporter{
+		url:            cfg.RemoteWriteURL,
+		client:         &http.Client{Timeout: cfg.RemoteWriteTimeout},
+		batchSize:      cfg.RemoteWriteBatchSize,
+		flushInterval:  cfg.RemoteWriteFlushInterval,
+		maxBuffered:    cfg.RemoteWriteMaxBufferedSamples,
+		maxRetries:     cfg.RemoteWriteMaxRetries,
+		retryBackoff:   cfg.RemoteWriteRetryBackoff,
+		externalLabels: labels.FromMap(cfg.RemoteWriteExternalLabels),
+		relabelConfigs: relabelConfigs,
+		buffer:         buffer,
+		removed:        offset,
+		wal:            wal,
+	}, nil
+}
+
+func parseRelabelConfigs(content []byte) ([]*relabel.Config, error) {
+	var relabelConfigs []*relabel.Config
+	if err := yaml.UnmarshalStrict(content, &relabelConfigs); err != nil {
+		return nil, fmt.Errorf("failed to parse remote write relabel config, err: %w", err)
+	}
+	for i, c := range relabelConfigs {
+		if c == nil {
+			return nil, fmt.Errorf("empty remote write relabel config at index %d", i)
+		}
+	}
+	return relabelConfigs, nil
+}
+
+func (e *remoteWriteExporter) Run(stopCh <-chan struct{}) {
+	klog.V(4).Infof("starting remote write exporter to %s", e.url)
+	e.stopCh = stopCh
+	wait.Until(e.flush, e.flushInterval, stopCh)
+	// try to send the remaining samples before exiting
+	e.flush()
+	e.lock.Lock()
+	e.wal.close()
+	e.lock.Unlock()
+}
+
+// enqueue relabels the samples and adds them into the buffer.
+func (e *remoteWriteExporter) enqueue(samples []MetricSample) {
+	pending := make([]remoteWriteSample, 0, len(samples))
+	for _, s := range samples {
+		lb := labels.NewBuilder(labels.FromMap(s.GetProperties()))
+		lb.Set(metricLabelName, s.GetKind())
+		for _, l := range e.externalLabels {
+			lb.Set(l.Name, l.Value)
+		}
+		lset := relabel.Process(lb.Labels(nil), e.relabelConfigs...)
+		if lset == nil {
+			continue
+		}
+		pending = append(pending, remoteWriteSample{labels: lset, timestamp: s.timestamp(), value: s.value()})
+	}
+	if len(pending) <= 0 {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.wal.append(e.removed+uint64(len(e.buffer)), pending); err != nil {
+		klog.Warningf("failed to persist %d remote write samples, keep them in memory only, err: %v", len(pending), err)
+	}
+	e.buffer = append(e.buffer, pending...)
+	if dropped := len(e.buffer) - e.maxBuffered; e.maxBuffered > 0 && dropped > 0 {
+		klog.V(4).Infof("remote write buffer is full, drop %d oldest samples", dropped)
+		e.buffer = e.buffer[dropped:]
+		e.removed += uint64(dropped)
+		e.wal.truncate(e.removed)
+		metrics.RecordRemoteWriteSamples(metrics.StatusDropped, dropped)
+	}
+	metrics.RecordRemoteWritePendingSamples(len(e.buffer))
+}
+
+// flush sends the buffered samples in batches until the buffer is empty or a batch fails.
+// A batch failed with a recoverable error is retried with exponential backoff, and it is kept in the buffer to retry
+// in the next round if the retries are exhausted or the exporter is stopped. The flush lock is not held during the
+// backoff.
+func (e *remoteWriteExporter) flush() {
+	backoff := e.retryBackoff
+	retries := 0
+	for {
+		size, retry, err := e.sendBatch()
+		if size <= 0 {
+			return
+		}
+		if !retry {
+			backoff, retries = e.retryBackoff, 0
+			continue
+		}
+		if retries >= e.maxRetries {
+			klog.Warningf("failed to remote write %d samples, retry later, err: %v", size, err)
+			metrics.RecordRemoteWriteSamples(metrics.StatusFailed, size)
+			return
+		}
+		retries++
+		klog.V(5).Infof("failed to remote write, retry after %v, err: %v", backoff, err)
+		select {
+		case <-e.stopCh:
+			klog.V(4).Infof("remote write exporter is stopped, retry %d samples later", size)
+			metrics.RecordRemoteWriteSamples(metrics.StatusFailed, size)
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// sendBatch sends a batch from the head of the buffer and removes it from the buffer unless it fails with a
+// recoverable error. It returns the size of the batch and whether the batch should be retried.
+func (e *remoteWriteExporter) sendBatch() (int, bool, error) {
+	e.flushLock.Lock()
+	defer e.flushLock.Unlock()
+	batch, offset := e.peekBatch()
+	if len(batch) <= 0 {
+		return 0, false, nil
+	}
+	req, err := buildRemoteWriteRequest(batch)
+	recoverable := false
+	if err == nil {
+		recoverable, err = e.send(req)
+	}
+	if err != nil && recoverable {
+		return len(batch), true, err
+	}
+	if err != nil {
+		klog.Warningf("failed to remote write %d samples, drop them, err: %v", len(batch), err)
+		metrics.RecordRemoteWriteSamples(metrics.StatusDropped, len(batch))
+	} else {
+		klog.V(6).Infof("remote write %d samples successfully", len(batch))
+		metrics.RecordRemoteWriteSamples(metrics.StatusSucceed, len(batch))
+	}
+	e.popBatch(offset, len(batch))
+	return len(batch), false, nil
+}
+
+func (e *remoteWriteExporter) peekBatch() ([]remoteWriteSample, uint64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	n := len(e.buffer)
+	if e.batchSize > 0 && n > e.batchSize {
+		n = e.batchSize
+	}
+	batch := make([]remoteWriteSample, n)
+	copy(batch, e.buffer[:n])
+	return batch, e.removed
+}
+
+// popBatch removes the sent batch starting at the offset from the buffer. Since the oldest samples can be dropped by
+// enqueue during sending, only the samples still in the buffer are removed.
+func (e *remoteWriteExporter) popBatch(offset uint64, size int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if end := offset + uint64(size); end > e.removed {
+		n := int(end - e.removed)
+		if n > len(e.buffer) {
+			n = len(e.buffer)
+		}
+		e.buffer = e.buffer[n:]
+		e.removed += uint64(n)
+		e.wal.truncate(e.removed)
+	}
+	metrics.RecordRemoteWritePendingSamples(len(e.buffer))
+}
+
+func (e *remoteWriteExporter) send(data []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, e.url, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", remoteWriteUserAgent)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", remoteWriteVersion)
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	err = fmt.Errorf("server returned HTTP status %s: %s", resp.Status, bytes.TrimSpace(body))
+	// the server errors and throttling can be recovered by retrying
+	return resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+// buildRemoteWriteRequest encodes the samples into a snappy-compressed WriteRequest, where the samples of the same
+// series are grouped into one TimeSeries.
+func buildRemoteWriteRequest(batch []remoteWriteSample) ([]byte, error) {
+	seriesIndex := map[uint64]int{}
+	req := &prompb.WriteRequest{}
+	for i := range batch {
+		s := &batch[i]
+		hash := s.labels.Hash()
+		idx, ok := seriesIndex[hash]
+		if !ok {
+			ts := prompb.TimeSeries{Labels: make([]prompb.Label, 0, len(s.labels))}
+			for _, l := range s.labels {
+				ts.Labels = append(ts.Labels, prompb.Label{Name: l.Name, Value: l.Value})
+			}
+			req.Timeseries = append(req.Timeseries, ts)
+			idx = len(req.Timeseries) - 1
+			seriesIndex[hash] = idx
+		}
+		req.Timeseries[idx].Samples = append(req.Timeseries[idx].Samples, prompb.Sample{Timestamp: s.timestamp, Value: s.value})
+	}
+	data, err := req.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal remote write request, err: %w", err)
+	}
+	return snappy.Encode(nil, data), nil
+}
+
+var _ TSDBStorage = &remoteWriteStorage{}
+
+// remoteWriteStorage wraps a TSDBStorage to export the committed samples.
+type remoteWriteStorage struct {
+	TSDBStorage
+	exporter *remoteWriteExporter
+}
+
+func (r *remoteWriteStorage) Appender() Appender {
+	return &remoteWriteAppender{
+		Appender: r.TSDBStorage.Appender(),
+		exporter: r.exporter,
+	}
+}
+
+var _ Appender = &remoteWriteAppender{}
+
+type remoteWriteAppender struct {
+	Appender
+	exporter *remoteWriteExporter
+	samples  []MetricSample
+}
+
+// Append stages the samples to export if they are appended successfully. The samples staged before are kept when
+// the append fails, and they are exported or dropped by the Commit according to its result.
+func (a *remoteWriteAppender) Append(samples []MetricSample) error {
+	if err := a.Appender.Append(samples); err != nil {
+		return err
+	}
+	a.samples = append(a.samples, samples...)
+	return nil
+}
+
+func (a *remoteWriteAppender) Commit() error {
+	samples := a.samples
+	a.samples = nil
+	if err := a.Appender.Commit(); err != nil {
+		return err
+	}
+	a.exporter.enqueue(samples)
+	return nil
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRemoteWriteReceiver is a local remote-write endpoint recording the received time series.
+type testRemoteWriteReceiver struct {
+	lock       sync.Mutex
+	statusCode []int
+	requests   int
+	series     []prompb.TimeSeries
+}
+
+func (r *testRemoteWriteReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests++
+	if len(r.statusCode) > 0 {
+		code := r.statusCode[0]
+		r.statusCode = r.statusCode[1:]
+		if code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+	}
+	compressed, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	writeReq := &prompb.WriteRequest{}
+	if err = writeReq.Unmarshal(data); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.series = append(r.series, writeReq.Timeseries...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newTestRemoteWriteConfig(t *testing.T, url string) *Config {
+	conf := NewDefaultConfig()
+	conf.TSDBPath = t.TempDir()
+	conf.TSDBEnablePromMetrics = false
+	conf.RemoteWriteURL = url
+	conf.RemoteWriteRetryBackoff = time.Millisecond
+	conf.RemoteWriteExternalLabels = map[string]string{"node": "test-node"}
+	return conf
+}
+
+func generateTestPodCPUSamples(t *testing.T, podUIDs ...string) []MetricSample {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	var samples []MetricSample
+	for i, podUID := range podUIDs {
+		s, err := PodCPUUsageMetric.GenerateSample(map[MetricProperty]string{MetricPropertyPodUID: podUID}, now, float64(i+1))
+		assert.NoError(t, err)
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+func Test_remoteWriteStorage_Export(t *testing.T) {
+	receiver := &testRemoteWriteReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	conf := newTestRemoteWriteConfig(t, server.URL)
+	relabelFile := filepath.Join(t.TempDir(), "relabel.yaml")
+	assert.NoError(t, os.WriteFile(relabelFile, []byte(`
+- source_labels: [pod_uid]
+  regex: drop-.*
+  action: drop
+- regex: pod_uid
+  replacement: pod
+  action: labelmap
+- regex: pod_uid
+  action: labeldrop
+`), 0644))
+	conf.RemoteWriteRelabelConfigFile = relabelFile
+	m, err := NewMetricCache(conf)
+	assert.NoError(t, err)
+	defer m.Close()
+	mc := m.(*metricCache)
+
+	appender := mc.Appender()
+	assert.NoError(t, appender.Append(generateTestPodCPUSamples(t, "pod-1", "drop-pod", "pod-2")))
+	assert.NoError(t, appender.Commit())
+	// samples are still written into the tsdb
+	querier, err := mc.Querier(time.Now().Add(-time.Minute), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	queryMeta, err := PodCPUUsageMetric.BuildQueryMeta(map[MetricProperty]string{MetricPropertyPodUID: "drop-pod"})
+	assert.NoError(t, err)
+	result := newAggregateResult(queryMeta)
+	assert.NoError(t, querier.QueryAndClose(queryMeta, nil, result))
+	assert.Equal(t, 1, result.Count())
+
+	mc.exporter.flush()
+	receiver.lock.Lock()
+	defer receiver.lock.Unlock()
+	assert.Equal(t, 1, receiver.requests)
+	assert.Len(t, receiver.series, 2)
+	for i, s := range receiver.series {
+		assert.Equal(t, []prompb.Label{
+			{Name: metricLabelName, Value: string(PodMetricCPUUsage)},
+			{Name: "node", Value: "test-node"},
+			{Name: "pod", Value: []string{"pod-1", "pod-2"}[i]},
+		}, s.Labels)
+		assert.Len(t, s.Samples, 1)
+	}
+	assert.Equal(t, float64(3), receiver.series[1].Samples[0].Value)
+}
+
+func Test_remoteWriteExporter_Flush(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    []int
+		maxRetries    int
+		maxBuffered   int
+		batchSize     int
+		stopped       bool
+		podUIDs       []string
+		wantRequests  int
+		wantReceived  int
+		wantRemaining int
+	}{
+		{
+			name:          "send in batches",
+			batchSize:     2,
+			podUIDs:       []string{"pod-1", "pod-2", "pod-3"},
+			wantRequests:  2,
+			wantReceived:  3,
+			wantRemaining: 0,
+		},
+		{
+			name:          "retry on server error",
+			statusCode:    []int{http.StatusInternalServerError, http.StatusTooManyRequests},
+			maxRetries:    2,
+			podUIDs:       []string{"pod-1", "pod-2"},
+			wantRequests:  3,
+			wantReceived:  2,
+			wantRemaining: 0,
+		},
+		{
+			name:          "keep the samples if retries exhausted",
+			statusCode:    []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			maxRetries:    1,
+			podUIDs:       []string{"pod-1", "pod-2"},
+			wantRequests:  2,
+			wantReceived:  0,
+			wantRemaining: 2,
+		},
+		{
+			name:          "not retry if stopped",
+			statusCode:    []int{http.StatusInternalServerError},
+			maxRetries:    2,
+			stopped:       true,
+			podUIDs:       []string{"pod-1", "pod-2"},
+			wantRequests:  1,
+			wantReceived:  0,
+			wantRemaining: 2,
+		},
+		{
+			name:          "drop the samples on client error",
+			statusCode:    []int{http.StatusBadRequest},
+			maxRetries:    2,
+			podUIDs:       []string{"pod-1", "pod-2"},
+			wantRequests:  1,
+			wantReceived:  0,
+			wantRemaining: 0,
+		},
+		{
+			name:          "drop the oldest samples if buffer is full",
+			maxBuffered:   2,
+			podUIDs:       []string{"pod-1", "pod-2", "pod-3"},
+			wantRequests:  1,
+			wantReceived:  2,
+			wantRemaining: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &testRemoteWriteReceiver{statusCode: tt.statusCode}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+
+			conf := newTestRemoteWriteConfig(t, server.URL)
+			conf.RemoteWriteMaxRetries = tt.maxRetries
+			conf.RemoteWriteMaxBufferedSamples = tt.maxBuffered
+			conf.RemoteWriteBatchSize = tt.batchSize
+			e, err := newRemoteWriteExporter(conf)
+			assert.NoError(t, err)
+			if tt.stopped {
+				stopCh := make(chan struct{})
+				close(stopCh)
+				e.stopCh = stopCh
+			}
+
+			e.enqueue(generateTestPodCPUSamples(t, tt.podUIDs...))
+			e.flush()
+
+			receiver.lock.Lock()
+			defer receiver.lock.Unlock()
+			assert.Equal(t, tt.wantRequests, receiver.requests)
+			assert.Len(t, receiver.series, tt.wantReceived)
+			assert.Len(t, e.buffer, tt.wantRemaining)
+			if tt.maxBuffered > 0 && len(receiver.series) > 0 {
+				// the oldest sample of pod-1 is dropped
+				assert.Equal(t, "pod-2", receiver.series[0].Labels[2].Value)
+			}
+		})
+	}
+}
+
+func Test_parseRelabelConfigs(t *testing.T) {
+	_, err := parseRelabelConfigs([]byte(`- action: unknown`))
+	assert.Error(t, err)
+	_, err = parseRelabelConfigs([]byte(`- null`))
+	assert.Error(t, err)
+	got, err := parseRelabelConfigs([]byte(`- action: labeldrop
+  regex: pod_uid`))
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func Test_remoteWriteExporter_Restore(t *testing.T) {
+	receiver := &testRemoteWriteReceiver{statusCode: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	conf := newTestRemoteWriteConfig(t, server.URL)
+	conf.RemoteWriteBatchSize = 2
+	conf.RemoteWriteMaxBufferedSamples = 4
+	conf.RemoteWriteMaxRetries = 0
+	e, err := newRemoteWriteExporter(conf)
+	assert.NoError(t, err)
+	e.enqueue(generateTestPodCPUSamples(t, "pod-1", "pod-2", "pod-3"))
+	e.enqueue(generateTestPodCPUSamples(t, "pod-4", "pod-5"))
+	// the oldest sample is dropped, and the segment of pod-1 and pod-2 is kept for pod-2
+	assert.Len(t, e.buffer, 4)
+	assert.Len(t, e.wal.segments, 3)
+	e.flush()
+	assert.Len(t, e.buffer, 4)
+	e.wal.close()
+
+	// the samples not sent are restored after restarting
+	e, err = newRemoteWriteExporter(conf)
+	assert.NoError(t, err)
+	if assert.Len(t, e.buffer, 4) {
+		assert.Equal(t, "pod-2", e.buffer[0].labels.Get("pod_uid"))
+		assert.Equal(t, float64(2), e.buffer[0].value)
+	}
+	assert.Equal(t, uint64(5), e.removed)
+	assert.Equal(t, []remoteWriteSegment{{offset: 5, count: 4}}, e.wal.segments)
+	e.flush()
+	receiver.lock.Lock()
+	assert.Len(t, receiver.series, 4)
+	receiver.lock.Unlock()
+	assert.Len(t, e.buffer, 0)
+	assert.Len(t, e.wal.segments, 0)
+	e.enqueue(generateTestPodCPUSamples(t, "pod-6"))
+	assert.Equal(t, []remoteWriteSegment{{offset: 9, count: 1}}, e.wal.segments)
+	e.wal.close()
+
+	// the broken tail of a segment is skipped
+	f, err := os.OpenFile(e.wal.segmentPath(9), os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	_, err = f.Write([]byte{0x10, 0x01})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	e, err = newRemoteWriteExporter(conf)
+	assert.NoError(t, err)
+	if assert.Len(t, e.buffer, 1) {
+		assert.Equal(t, "pod-6", e.buffer[0].labels.Get("pod_uid"))
+	}
+	entries, err := os.ReadDir(conf.GetRemoteWriteBufferPath())
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+type testFailedAppender struct {
+	appendErr error
+}
+
+func (a *testFailedAppender) Append(s []MetricSample) error {
+	return a.appendErr
+}
+
+func (a *testFailedAppender) Commit() error {
+	return nil
+}
+
+func Test_remoteWriteAppender(t *testing.T) {
+	conf := newTestRemoteWriteConfig(t, "http://localhost:0")
+	e, err := newRemoteWriteExporter(conf)
+	assert.NoError(t, err)
+	inner := &testFailedAppender{}
+	a := &remoteWriteAppender{Appender: inner, exporter: e}
+	assert.NoError(t, a.Append(generateTestPodCPUSamples(t, "pod-1")))
+	inner.appendErr = fmt.Errorf("expected error")
+	// the samples staged before the failed append are kept
+	assert.Error(t, a.Append(generateTestPodCPUSamples(t, "pod-2")))
+	assert.Len(t, a.samples, 1)
+	assert.NoError(t, a.Commit())
+	if assert.Len(t, e.buffer, 1) {
+		assert.Equal(t, "pod-1", e.buffer[0].labels.Get("pod_uid"))
+	}
+	assert.Len(t, a.samples, 0)
+}
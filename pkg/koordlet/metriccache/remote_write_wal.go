@@ -0,0 +1,327 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultRemoteWriteBufferDir is the directory of the remote-write buffer under the TSDBPath if the
+	// RemoteWriteBufferPath is not set
+	defaultRemoteWriteBufferDir = "remote-write"
+	// defaultRemoteWriteSegmentSize is the max samples of a segment if the batch size is not limited
+	defaultRemoteWriteSegmentSize = 1000
+
+	remoteWriteSegmentSuffix  = ".seg"
+	remoteWriteSegmentTmpName = "compact.seg.tmp"
+	// remoteWriteMaxRecordSize guards the loading against the corrupted length of a record
+	remoteWriteMaxRecordSize = 1 << 20
+)
+
+var remoteWriteCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// remoteWriteSegment is a segment file of the remote-write buffer holding the samples from the offset.
+type remoteWriteSegment struct {
+	offset uint64
+	count  int
+}
+
+// remoteWriteWAL persists the remote-write buffer into the segment files under the dir, so that the samples not sent
+// yet can be restored after the koordlet restarts. Each segment is named by the offset of its first sample and holds
+// up to segmentSize samples, and each sample is a length-prefixed and checksummed record of a TimeSeries. A segment
+// is removed once all its samples are removed from the buffer, so the persisted samples are bounded by the buffer
+// size plus a segment.
+// The records are not synced on every append, so the samples still in the page cache are lost if the node crashes.
+// Besides, the samples of the first segment which have been sent are sent again after the koordlet restarts.
+type remoteWriteWAL struct {
+	dir         string
+	segmentSize int
+	segments    []remoteWriteSegment
+	// file is the last segment to append the samples
+	file *os.File
+}
+
+// openRemoteWriteWAL loads the samples persisted under the dir, where at most maxSamples newest samples are kept if
+// maxSamples is positive. The loaded samples are compacted into a new segment before returning, and the offset of
+// the first loaded sample is returned.
+func openRemoteWriteWAL(dir string, segmentSize, maxSamples int) (*remoteWriteWAL, []remoteWriteSample, uint64, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to create remote write buffer dir %s, err: %w", dir, err)
+	}
+	if segmentSize <= 0 {
+		segmentSize = defaultRemoteWriteSegmentSize
+	}
+	w := &remoteWriteWAL{dir: dir, segmentSize: segmentSize}
+	oldSegments, err := w.listSegments()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var samples []remoteWriteSample
+	var next uint64
+	for _, segment := range oldSegments {
+		loaded, err := readRemoteWriteSegment(w.segmentPath(segment.offset))
+		if err != nil {
+			klog.Warningf("failed to load remote write buffer segment %d completely, loaded %d samples, err: %v",
+				segment.offset, len(loaded), err)
+		}
+		samples = append(samples, loaded...)
+		if end := segment.offset + uint64(len(loaded)); end > next {
+			next = end
+		}
+	}
+	if dropped := len(samples) - maxSamples; maxSamples > 0 && dropped > 0 {
+		klog.V(4).Infof("remote write buffer is full, drop %d oldest samples loaded", dropped)
+		samples = samples[dropped:]
+	}
+
+	// the compacted segment follows the old ones, which are removed only after it is persisted
+	if len(samples) > 0 {
+		if err = w.writeCompacted(next, samples); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	for _, segment := range oldSegments {
+		if len(samples) > 0 && segment.offset == next {
+			// an empty segment replaced by the compacted one
+			continue
+		}
+		if err = os.Remove(w.segmentPath(segment.offset)); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("failed to remove remote write buffer segment %d, err: %v", segment.offset, err)
+		}
+	}
+	klog.V(4).Infof("remote write buffer loaded %d samples from %d segments", len(samples), len(oldSegments))
+	return w, samples, next, nil
+}
+
+func (w *remoteWriteWAL) listSegments() ([]remoteWriteSegment, error) {
+	if err := os.Remove(filepath.Join(w.dir, remoteWriteSegmentTmpName)); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove the remote write buffer tmp file, err: %w", err)
+	}
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote write buffer dir %s, err: %w", w.dir, err)
+	}
+	var segments []remoteWriteSegment
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, remoteWriteSegmentSuffix) {
+			continue
+		}
+		offset, err := strconv.ParseUint(strings.TrimSuffix(name, remoteWriteSegmentSuffix), 10, 64)
+		if err != nil {
+			klog.V(4).Infof("skip the unknown file %s in remote write buffer dir", name)
+			continue
+		}
+		segments = append(segments, remoteWriteSegment{offset: offset})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].offset < segments[j].offset
+	})
+	return segments, nil
+}
+
+func (w *remoteWriteWAL) segmentPath(offset uint64) string {
+	return filepath.Join(w.dir, fmt.Sprintf("%020d%s", offset, remoteWriteSegmentSuffix))
+}
+
+// writeCompacted writes the samples into a new segment at the offset, which is made visible by renaming after synced.
+func (w *remoteWriteWAL) writeCompacted(offset uint64, samples []remoteWriteSample) error {
+	tmpPath := filepath.Join(w.dir, remoteWriteSegmentTmpName)
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create remote write buffer segment, err: %w", err)
+	}
+	buf, err := encodeRemoteWriteRecords(nil, samples)
+	if err == nil {
+		_, err = f.Write(buf)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, w.segmentPath(offset))
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact remote write buffer segment, err: %w", err)
+	}
+	w.segments = []remoteWriteSegment{{offset: offset, count: len(samples)}}
+	return nil
+}
+
+// append persists the samples starting at the offset, where a new segment is started if the last one is full or
+// not continuous with the offset.
+func (w *remoteWriteWAL) append(offset uint64, samples []remoteWriteSample) error {
+	for len(samples) > 0 {
+		last := len(w.segments) - 1
+		if w.file == nil || last < 0 || w.segments[last].count >= w.segmentSize ||
+			w.segments[last].offset+uint64(w.segments[last].count) != offset {
+			if err := w.startSegment(offset); err != nil {
+				return err
+			}
+			last = len(w.segments) - 1
+		}
+		n := w.segmentSize - w.segments[last].count
+		if n > len(samples) {
+			n = len(samples)
+		}
+		buf, err := encodeRemoteWriteRecords(nil, samples[:n])
+		if err != nil {
+			return err
+		}
+		if _, err = w.file.Write(buf); err != nil {
+			// the segment may end with a partial record which is skipped on loading, start a new one next time
+			w.closeFile()
+			return fmt.Errorf("failed to write remote write buffer segment %d, err: %w", w.segments[last].offset, err)
+		}
+		w.segments[last].count += n
+		offset += uint64(n)
+		samples = samples[n:]
+	}
+	return nil
+}
+
+func (w *remoteWriteWAL) startSegment(offset uint64) error {
+	w.closeFile()
+	// a segment at the same offset must have no sample, e.g. its first write failed
+	if last := len(w.segments) - 1; last >= 0 && w.segments[last].offset == offset {
+		w.segments = w.segments[:last]
+	}
+	f, err := os.OpenFile(w.segmentPath(offset), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create remote write buffer segment %d, err: %w", offset, err)
+	}
+	w.file = f
+	w.segments = append(w.segments, remoteWriteSegment{offset: offset})
+	return nil
+}
+
+// truncate removes the segments whose samples are all before the offset.
+func (w *remoteWriteWAL) truncate(offset uint64) {
+	n := 0
+	for ; n < len(w.segments); n++ {
+		segment := w.segments[n]
+		if segment.offset+uint64(segment.count) > offset {
+			break
+		}
+		if n == len(w.segments)-1 {
+			w.closeFile()
+		}
+		if err := os.Remove(w.segmentPath(segment.offset)); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("failed to remove remote write buffer segment %d, err: %v", segment.offset, err)
+		}
+	}
+	w.segments = w.segments[n:]
+}
+
+func (w *remoteWriteWAL) closeFile() {
+	if w.file == nil {
+		return
+	}
+	if err := w.file.Close(); err != nil {
+		klog.V(4).Infof("failed to close remote write buffer segment, err: %v", err)
+	}
+	w.file = nil
+}
+
+func (w *remoteWriteWAL) close() {
+	w.closeFile()
+}
+
+// encodeRemoteWriteRecords appends the records of the samples to the buf. A record is the uvarint length and the
+// CRC32 of the payload, followed by the payload which is a TimeSeries of one sample.
+func encodeRemoteWriteRecords(buf []byte, samples []remoteWriteSample) ([]byte, error) {
+	for i := range samples {
+		s := &samples[i]
+		ts := prompb.TimeSeries{
+			Labels:  make([]prompb.Label, 0, len(s.labels)),
+			Samples: []prompb.Sample{{Timestamp: s.timestamp, Value: s.value}},
+		}
+		for _, l := range s.labels {
+			ts.Labels = append(ts.Labels, prompb.Label{Name: l.Name, Value: l.Value})
+		}
+		payload, err := ts.Marshal()
+		if err != nil {
+			return buf, fmt.Errorf("failed to marshal remote write sample, err: %w", err)
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(payload)))
+		buf = binary.BigEndian.AppendUint32(buf, crc32.Checksum(payload, remoteWriteCRCTable))
+		buf = append(buf, payload...)
+	}
+	return buf, nil
+}
+
+// readRemoteWriteSegment reads the samples of a segment until the end or the first broken record, and it returns
+// the samples read before the broken record with an error.
+func readRemoteWriteSegment(path string) ([]remoteWriteSample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var samples []remoteWriteSample
+	for {
+		size, err := binary.ReadUvarint(r)
+		if errors.Is(err, io.EOF) {
+			return samples, nil
+		} else if err != nil {
+			return samples, err
+		}
+		if size > remoteWriteMaxRecordSize {
+			return samples, fmt.Errorf("invalid record size %d", size)
+		}
+		record := make([]byte, 4+size)
+		if _, err = io.ReadFull(r, record); err != nil {
+			return samples, fmt.Errorf("partial record, err: %w", err)
+		}
+		payload := record[4:]
+		if crc32.Checksum(payload, remoteWriteCRCTable) != binary.BigEndian.Uint32(record[:4]) {
+			return samples, fmt.Errorf("record checksum mismatch")
+		}
+		ts := prompb.TimeSeries{}
+		if err = ts.Unmarshal(payload); err != nil {
+			return samples, fmt.Errorf("failed to unmarshal record, err: %w", err)
+		}
+		lset := make(labels.Labels, 0, len(ts.Labels))
+		for _, l := range ts.Labels {
+			lset = append(lset, labels.Label{Name: l.Name, Value: l.Value})
+		}
+		for _, s := range ts.Samples {
+			samples = append(samples, remoteWriteSample{labels: lset, timestamp: s.Timestamp, value: s.Value})
+		}
+	}
+}
@@ -48,4 +48,6 @@ func init() {
 	internalMustRegister(KubeletStubCollector...)
 	internalMustRegister(RuntimeHookCollectors...)
 	internalMustRegister(HostApplicationCollectors...)
+	internalMustRegister(RemoteWriteCollectors...)
+	internalMustRegister(MemoryHighCollectors...)
 }
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MemoryHighActionKey = "action"
+
+	MemoryHighActionLower = "lower"
+	MemoryHighActionRaise = "raise"
+	MemoryHighActionReset = "reset"
+)
+
+var (
+	BEPodMemoryHigh = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_pod_memory_high_bytes",
+		Help:      "The memory.high in bytes of the BE pod tuned by koordlet",
+	}, []string{NodeKey, PodNamespace, PodName})
+
+	BEPodMemoryHighAdjustments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_pod_memory_high_adjustments",
+		Help:      "Number of the memory.high adjustments of the BE pods by koordlet",
+	}, []string{NodeKey, PodNamespace, PodName, MemoryHighActionKey})
+
+	MemoryHighCollectors = []prometheus.Collector{
+		BEPodMemoryHigh,
+		BEPodMemoryHighAdjustments,
+	}
+)
+
+func RecordBEPodMemoryHigh(podNS, podName string, value int64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodNamespace] = podNS
+	labels[PodName] = podName
+	BEPodMemoryHigh.With(labels).Set(float64(value))
+}
+
+func RecordBEPodMemoryHighAdjustment(podNS, podName, action string) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodNamespace] = podNS
+	labels[PodName] = podName
+	labels[MemoryHighActionKey] = action
+	BEPodMemoryHighAdjustments.With(labels).Inc()
+}
+
+func ResetBEPodMemoryHigh() {
+	BEPodMemoryHigh.Reset()
+}
@@ -504,3 +504,40 @@ func TestCPUSetCollectors(t *testing.T) {
 		ResetCPUSetBESharePoolInfo()
 	})
 }
+
+func TestRemoteWriteCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+
+		RecordRemoteWriteSamples(StatusSucceed, 10)
+		RecordRemoteWriteSamples(StatusDropped, 1)
+		RecordRemoteWritePendingSamples(5)
+	})
+}
+
+func TestMemoryHighCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+
+		RecordBEPodMemoryHigh("default", "test-be-pod", 1<<30)
+		RecordBEPodMemoryHighAdjustment("default", "test-be-pod", MemoryHighActionLower)
+		RecordBEPodMemoryHighAdjustment("default", "test-be-pod", MemoryHighActionReset)
+		ResetBEPodMemoryHigh()
+	})
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// StatusDropped indicates the samples are dropped before sent, e.g. the buffer is full
+	StatusDropped = "dropped"
+)
+
+var (
+	RemoteWriteSamples = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "remote_write_samples",
+		Help:      "Number of tsdb samples exported by remote write",
+	}, []string{NodeKey, StatusKey})
+
+	RemoteWritePendingSamples = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "remote_write_pending_samples",
+		Help:      "Number of tsdb samples buffered and waiting for remote write",
+	}, []string{NodeKey})
+
+	RemoteWriteCollectors = []prometheus.Collector{
+		RemoteWriteSamples,
+		RemoteWritePendingSamples,
+	}
+)
+
+func RecordRemoteWriteSamples(status string, count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[StatusKey] = status
+	RemoteWriteSamples.With(labels).Add(float64(count))
+}
+
+func RecordRemoteWritePendingSamples(count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	RemoteWritePendingSamples.With(labels).Set(float64(count))
+}
@@ -48,6 +48,9 @@ func (m *mockStatesInformer) GetNodeSLO() *slov1alpha1.NodeSLO {
 	return nil
 }
 
+func (m *mockStatesInformer) SetNodeSLOStrategyResult(strategyName string, err error) {
+}
+
 func (m *mockStatesInformer) GetNodeMetricSpec() *slov1alpha1.NodeMetricSpec {
 	return nil
 }
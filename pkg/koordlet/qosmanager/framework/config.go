@@ -21,26 +21,36 @@ import (
 )
 
 type Config struct {
-	ReconcileIntervalSeconds   int
-	CPUSuppressIntervalSeconds int
-	CPUEvictIntervalSeconds    int
-	MemoryEvictIntervalSeconds int
-	MemoryEvictCoolTimeSeconds int
-	CPUEvictCoolTimeSeconds    int
-	OnlyEvictByAPI             bool
-	QOSExtensionCfg            *QOSExtensionConfig
+	ReconcileIntervalSeconds          int
+	CPUSuppressIntervalSeconds        int
+	CPUEvictIntervalSeconds           int
+	MemoryEvictIntervalSeconds        int
+	MemoryEvictCoolTimeSeconds        int
+	CPUEvictCoolTimeSeconds           int
+	PSISuppressIntervalSeconds        int
+	PSIEvictCoolTimeSeconds           int
+	InterferenceDetectIntervalSeconds int
+	InterferenceEvictCoolTimeSeconds  int
+	MemoryHighTuneIntervalSeconds     int
+	OnlyEvictByAPI                    bool
+	QOSExtensionCfg                   *QOSExtensionConfig
 }
 
 func NewDefaultConfig() *Config {
 	return &Config{
-		ReconcileIntervalSeconds:   1,
-		CPUSuppressIntervalSeconds: 1,
-		CPUEvictIntervalSeconds:    1,
-		MemoryEvictIntervalSeconds: 1,
-		MemoryEvictCoolTimeSeconds: 4,
-		CPUEvictCoolTimeSeconds:    20,
-		OnlyEvictByAPI:             false,
-		QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{}},
+		ReconcileIntervalSeconds:          1,
+		CPUSuppressIntervalSeconds:        1,
+		CPUEvictIntervalSeconds:           1,
+		MemoryEvictIntervalSeconds:        1,
+		MemoryEvictCoolTimeSeconds:        4,
+		CPUEvictCoolTimeSeconds:           20,
+		PSISuppressIntervalSeconds:        1,
+		PSIEvictCoolTimeSeconds:           20,
+		InterferenceDetectIntervalSeconds: 60,
+		InterferenceEvictCoolTimeSeconds:  60,
+		MemoryHighTuneIntervalSeconds:     5,
+		OnlyEvictByAPI:                    false,
+		QOSExtensionCfg:                   &QOSExtensionConfig{FeatureGates: map[string]bool{}},
 	}
 }
 
@@ -51,6 +61,11 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.MemoryEvictIntervalSeconds, "memory-evict-interval-seconds", c.MemoryEvictIntervalSeconds, "evict be pod(memory) interval by seconds")
 	fs.IntVar(&c.MemoryEvictCoolTimeSeconds, "memory-evict-cool-time-seconds", c.MemoryEvictCoolTimeSeconds, "cooling time: memory next evict time should after lastEvictTime + MemoryEvictCoolTimeSeconds")
 	fs.IntVar(&c.CPUEvictCoolTimeSeconds, "cpu-evict-cool-time-seconds", c.CPUEvictCoolTimeSeconds, "cooltime: CPU next evict time should after lastEvictTime + CPUEvictCoolTimeSeconds")
+	fs.IntVar(&c.PSISuppressIntervalSeconds, "psi-suppress-interval-seconds", c.PSISuppressIntervalSeconds, "suppress be pod by the pressure of non-be pods interval by seconds")
+	fs.IntVar(&c.PSIEvictCoolTimeSeconds, "psi-evict-cool-time-seconds", c.PSIEvictCoolTimeSeconds, "cooltime: PSI next evict time should after lastEvictTime + PSIEvictCoolTimeSeconds")
+	fs.IntVar(&c.InterferenceDetectIntervalSeconds, "interference-detect-interval-seconds", c.InterferenceDetectIntervalSeconds, "detect the interference of be pods by cpi and resctrl metrics interval by seconds")
+	fs.IntVar(&c.InterferenceEvictCoolTimeSeconds, "interference-evict-cool-time-seconds", c.InterferenceEvictCoolTimeSeconds, "cooltime: interference next evict time should after lastEvictTime + InterferenceEvictCoolTimeSeconds")
+	fs.IntVar(&c.MemoryHighTuneIntervalSeconds, "memory-high-tune-interval-seconds", c.MemoryHighTuneIntervalSeconds, "tune the memory.high of be pods by the memory reclaim feedback interval by seconds")
 	fs.BoolVar(&c.OnlyEvictByAPI, "only-evict-by-api", c.OnlyEvictByAPI, "only evict pod if call eviction api successed")
 	c.QOSExtensionCfg.InitFlags(fs)
 }
@@ -25,14 +25,19 @@ import (
 
 func Test_NewDefaultConfig(t *testing.T) {
 	expectConfig := &Config{
-		ReconcileIntervalSeconds:   1,
-		CPUSuppressIntervalSeconds: 1,
-		CPUEvictIntervalSeconds:    1,
-		MemoryEvictIntervalSeconds: 1,
-		MemoryEvictCoolTimeSeconds: 4,
-		CPUEvictCoolTimeSeconds:    20,
-		OnlyEvictByAPI:             false,
-		QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{}},
+		ReconcileIntervalSeconds:          1,
+		CPUSuppressIntervalSeconds:        1,
+		CPUEvictIntervalSeconds:           1,
+		MemoryEvictIntervalSeconds:        1,
+		MemoryEvictCoolTimeSeconds:        4,
+		CPUEvictCoolTimeSeconds:           20,
+		PSISuppressIntervalSeconds:        1,
+		PSIEvictCoolTimeSeconds:           20,
+		InterferenceDetectIntervalSeconds: 60,
+		InterferenceEvictCoolTimeSeconds:  60,
+		MemoryHighTuneIntervalSeconds:     5,
+		OnlyEvictByAPI:                    false,
+		QOSExtensionCfg:                   &QOSExtensionConfig{FeatureGates: map[string]bool{}},
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -47,20 +52,30 @@ func Test_InitFlags(t *testing.T) {
 		"--memory-evict-interval-seconds=2",
 		"--memory-evict-cool-time-seconds=8",
 		"--cpu-evict-cool-time-seconds=40",
+		"--psi-suppress-interval-seconds=2",
+		"--psi-evict-cool-time-seconds=40",
+		"--interference-detect-interval-seconds=120",
+		"--interference-evict-cool-time-seconds=120",
+		"--memory-high-tune-interval-seconds=10",
 		"--qos-extension-plugins=test-plugin=true",
 		"--only-evict-by-api=false",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
 	type fields struct {
-		ReconcileIntervalSeconds   int
-		CPUSuppressIntervalSeconds int
-		CPUEvictIntervalSeconds    int
-		MemoryEvictIntervalSeconds int
-		MemoryEvictCoolTimeSeconds int
-		CPUEvictCoolTimeSeconds    int
-		OnlyEvictByAPI             bool
-		QOSExtensionCfg            *QOSExtensionConfig
+		ReconcileIntervalSeconds          int
+		CPUSuppressIntervalSeconds        int
+		CPUEvictIntervalSeconds           int
+		MemoryEvictIntervalSeconds        int
+		MemoryEvictCoolTimeSeconds        int
+		CPUEvictCoolTimeSeconds           int
+		PSISuppressIntervalSeconds        int
+		PSIEvictCoolTimeSeconds           int
+		InterferenceDetectIntervalSeconds int
+		InterferenceEvictCoolTimeSeconds  int
+		MemoryHighTuneIntervalSeconds     int
+		OnlyEvictByAPI                    bool
+		QOSExtensionCfg                   *QOSExtensionConfig
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -73,14 +88,19 @@ func Test_InitFlags(t *testing.T) {
 		{
 			name: "not default",
 			fields: fields{
-				ReconcileIntervalSeconds:   2,
-				CPUSuppressIntervalSeconds: 2,
-				CPUEvictIntervalSeconds:    2,
-				MemoryEvictIntervalSeconds: 2,
-				MemoryEvictCoolTimeSeconds: 8,
-				CPUEvictCoolTimeSeconds:    40,
-				OnlyEvictByAPI:             false,
-				QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{"test-plugin": true}},
+				ReconcileIntervalSeconds:          2,
+				CPUSuppressIntervalSeconds:        2,
+				CPUEvictIntervalSeconds:           2,
+				MemoryEvictIntervalSeconds:        2,
+				MemoryEvictCoolTimeSeconds:        8,
+				CPUEvictCoolTimeSeconds:           40,
+				PSISuppressIntervalSeconds:        2,
+				PSIEvictCoolTimeSeconds:           40,
+				InterferenceDetectIntervalSeconds: 120,
+				InterferenceEvictCoolTimeSeconds:  120,
+				MemoryHighTuneIntervalSeconds:     10,
+				OnlyEvictByAPI:                    false,
+				QOSExtensionCfg:                   &QOSExtensionConfig{FeatureGates: map[string]bool{"test-plugin": true}},
 			},
 			args: args{fs: fs},
 		},
@@ -88,14 +108,19 @@ func Test_InitFlags(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			raw := &Config{
-				ReconcileIntervalSeconds:   tt.fields.ReconcileIntervalSeconds,
-				CPUSuppressIntervalSeconds: tt.fields.CPUSuppressIntervalSeconds,
-				CPUEvictIntervalSeconds:    tt.fields.CPUEvictIntervalSeconds,
-				MemoryEvictIntervalSeconds: tt.fields.MemoryEvictIntervalSeconds,
-				MemoryEvictCoolTimeSeconds: tt.fields.MemoryEvictCoolTimeSeconds,
-				CPUEvictCoolTimeSeconds:    tt.fields.CPUEvictCoolTimeSeconds,
-				OnlyEvictByAPI:             tt.fields.OnlyEvictByAPI,
-				QOSExtensionCfg:            tt.fields.QOSExtensionCfg,
+				ReconcileIntervalSeconds:          tt.fields.ReconcileIntervalSeconds,
+				CPUSuppressIntervalSeconds:        tt.fields.CPUSuppressIntervalSeconds,
+				CPUEvictIntervalSeconds:           tt.fields.CPUEvictIntervalSeconds,
+				MemoryEvictIntervalSeconds:        tt.fields.MemoryEvictIntervalSeconds,
+				MemoryEvictCoolTimeSeconds:        tt.fields.MemoryEvictCoolTimeSeconds,
+				CPUEvictCoolTimeSeconds:           tt.fields.CPUEvictCoolTimeSeconds,
+				PSISuppressIntervalSeconds:        tt.fields.PSISuppressIntervalSeconds,
+				PSIEvictCoolTimeSeconds:           tt.fields.PSIEvictCoolTimeSeconds,
+				InterferenceDetectIntervalSeconds: tt.fields.InterferenceDetectIntervalSeconds,
+				InterferenceEvictCoolTimeSeconds:  tt.fields.InterferenceEvictCoolTimeSeconds,
+				MemoryHighTuneIntervalSeconds:     tt.fields.MemoryHighTuneIntervalSeconds,
+				OnlyEvictByAPI:                    tt.fields.OnlyEvictByAPI,
+				QOSExtensionCfg:                   tt.fields.QOSExtensionCfg,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
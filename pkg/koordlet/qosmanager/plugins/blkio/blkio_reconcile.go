@@ -27,6 +27,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -125,6 +126,7 @@ func (b *blkIOReconcile) reconcile() {
 
 	// update node blk qos by strategy defined in nodeslo
 	strategy := nodeSLO.Spec.ResourceQOSStrategy
+	var errs []error
 	// lsr
 	if strategy.LSRClass != nil && strategy.LSRClass.BlkIOQOS != nil && *strategy.LSRClass.BlkIOQOS.Enable && len(strategy.LSRClass.BlkIOQOS.Blocks) != 0 {
 		klog.Warningf("%s: configuring blkio of LSRClass is not supported!", BlkIOReconcileName)
@@ -155,6 +157,7 @@ func (b *blkIOReconcile) reconcile() {
 		)
 		if err != nil {
 			klog.Errorf("%s: fail to update be class blkio config: %s", BlkIOReconcileName, err.Error())
+			errs = append(errs, fmt.Errorf("update be class blkio config failed, err: %w", err))
 		} else {
 			klog.V(4).Infof("%s: reconcile be class blkio config finished", BlkIOReconcileName)
 		}
@@ -181,6 +184,7 @@ func (b *blkIOReconcile) reconcile() {
 		)
 		if err != nil {
 			klog.Errorf("%s: fail to update root class blkio config: %s", BlkIOReconcileName, err.Error())
+			errs = append(errs, fmt.Errorf("update root class blkio config failed, err: %w", err))
 		} else {
 			klog.V(4).Infof("%s: reconcile root class blkio config finished", BlkIOReconcileName)
 		}
@@ -223,10 +227,12 @@ func (b *blkIOReconcile) reconcile() {
 		)
 		if err != nil {
 			klog.Errorf("%s: fail to update pod %s/%s blkio config: %s", BlkIOReconcileName, podMeta.Pod.Namespace, podMeta.Pod.Name, err.Error())
+			errs = append(errs, fmt.Errorf("update pod %s/%s blkio config failed, err: %w", podMeta.Pod.Namespace, podMeta.Pod.Name, err))
 		} else {
 			klog.V(4).Infof("%s: reconcile pod %s/%s blkio config finished", BlkIOReconcileName, podMeta.Pod.Namespace, podMeta.Pod.Name)
 		}
 	}
+	b.statesInformer.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameBlkIOQOS, utilerrors.NewAggregate(errs))
 }
 
 type blkioUpdater struct {
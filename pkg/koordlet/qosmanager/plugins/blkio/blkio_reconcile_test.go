@@ -126,6 +126,7 @@ func TestBlkIOReconcile_reconcile(t *testing.T) {
 		statesInformer.EXPECT().GetNodeSLO().Return(testingNodeSLO).AnyTimes()
 		statesInformer.EXPECT().GetVolumeName("default", PVCName).Return(PVName).AnyTimes()
 		statesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+		statesInformer.EXPECT().SetNodeSLOStrategyResult(slov1alpha1.StrategyNameBlkIOQOS, gomock.Any()).AnyTimes()
 
 		mockMetricCache := mock_metriccache.NewMockMetricCache(ctrl)
 		mockMetricCache.EXPECT().Get(metriccache.NodeLocalStorageInfoKey).Return(localStorageInfo, true).AnyTimes()
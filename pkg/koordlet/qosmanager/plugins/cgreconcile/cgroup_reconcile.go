@@ -139,7 +139,11 @@ func (m *cgroupResourcesReconcile) calculateAndUpdateResources(nodeSLO *slov1alp
 	// cgroup-level order.
 	// e.g. /kubepods.slice/memory.min, /kubepods.slice-podxxx/memory.min, /kubepods.slice-podxxx/docker-yyy/memory.min
 	leveledResources := [][]resourceexecutor.ResourceUpdater{qosResources, podResources, containerResources}
-	m.executor.LeveledUpdateBatch(leveledResources)
+	err := m.executor.LeveledUpdateBatch(leveledResources)
+	if err != nil {
+		klog.V(4).Infof("failed to update cgroup resources, err: %v", err)
+	}
+	m.statesInformer.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameMemoryQOS, err)
 }
 
 // calculateResources calculates qos-level, pod-level and container-level resources with nodeCfg and podMetas
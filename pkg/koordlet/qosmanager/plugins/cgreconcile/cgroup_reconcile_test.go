@@ -440,6 +440,7 @@ func Test_calculateAndUpdateResources(t *testing.T) {
 			}
 			statesInformer.EXPECT().GetNode().Return(testingNode).MaxTimes(1)
 			statesInformer.EXPECT().GetAllPods().Return(tt.podMetas).MaxTimes(1)
+			statesInformer.EXPECT().SetNodeSLOStrategyResult(slov1alpha1.StrategyNameMemoryQOS, nil).MaxTimes(1)
 
 			reconciler := newTestCgroupResourcesReconcile(opt)
 			stop := make(chan struct{})
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
@@ -221,6 +222,7 @@ func (b *cpuBurst) start() {
 	nodeState := b.getNodeStateForBurst(*b.nodeCPUBurstStrategy.SharePoolThresholdPercent, podsMeta)
 	klog.V(5).Infof("get node state %v for cpu burst", nodeState)
 
+	var errs []error
 	for _, podMeta := range podsMeta {
 		if podMeta == nil || podMeta.Pod == nil {
 			klog.Warningf("podMeta is illegal, detail %v", podMeta)
@@ -244,10 +246,15 @@ func (b *cpuBurst) start() {
 		}
 		klog.V(5).Infof("get pod %v/%v cpu burst config: %v", podMeta.Pod.Namespace, podMeta.Pod.Name, cpuBurstCfg)
 		// set cpu.cfs_burst_us for pod and containers
-		b.applyCPUBurst(cpuBurstCfg, podMeta)
+		if err := b.applyCPUBurst(cpuBurstCfg, podMeta); err != nil {
+			errs = append(errs, err)
+		}
 		// scale cpu.cfs_quota_us for pod and containers
-		b.applyCFSQuotaBurst(cpuBurstCfg, podMeta, nodeState)
+		if err := b.applyCFSQuotaBurst(cpuBurstCfg, podMeta, nodeState); err != nil {
+			errs = append(errs, err)
+		}
 	}
+	b.statesInformer.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameCPUBurst, utilerrors.NewAggregate(errs))
 	b.Recycle()
 }
 
@@ -336,7 +343,8 @@ func (b *cpuBurst) getNodeStateForBurst(sharePoolThresholdPercent int64,
 
 // scale cpu.cfs_quota_us for pod/containers by container throttled state and node state
 func (b *cpuBurst) applyCFSQuotaBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *statesinformer.PodMeta,
-	nodeState nodeStateForBurst) {
+	nodeState nodeStateForBurst) error {
+	var errs []error
 	pod := podMeta.Pod
 	containerMap := make(map[string]*corev1.Container)
 	for i := range pod.Spec.Containers {
@@ -415,12 +423,15 @@ func (b *cpuBurst) applyCFSQuotaBurst(burstCfg *slov1alpha1.CPUBurstConfig, podM
 		if err != nil {
 			klog.Infof("scale container %v/%v/%v cfs quota failed, operation %v, delta cfs quota %v, reason %v",
 				pod.Namespace, pod.Name, containerStat.Name, finalOperation, deltaContainerCFS, err)
+			errs = append(errs, fmt.Errorf("scale container %s/%s/%s cfs quota failed, err: %w",
+				pod.Namespace, pod.Name, containerStat.Name, err))
 			continue
 		}
 		metrics.RecordContainerScaledCFSQuotaUS(pod.Namespace, pod.Name, containerStat.ContainerID, containerStat.Name, float64(containerTargetCFS))
 		klog.Infof("scale container %v/%v/%v cfs quota success, operation %v, current cfs %v, target cfs %v",
 			pod.Namespace, pod.Name, containerStat.Name, finalOperation, containerCurCFS, containerTargetCFS)
 	} // end for containers
+	return utilerrors.NewAggregate(errs)
 }
 
 // check if cfs burst for container is allowed by limiter config, return true if allowed
@@ -561,7 +572,8 @@ func (b *cpuBurst) applyContainerCFSQuota(podMeta *statesinformer.PodMeta, conta
 }
 
 // set cpu.cfs_burst_us for containers
-func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *statesinformer.PodMeta) {
+func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *statesinformer.PodMeta) error {
+	var errs []error
 	pod := podMeta.Pod
 	containerMap := make(map[string]*corev1.Container)
 	for i := range pod.Spec.Containers {
@@ -608,6 +620,8 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 		} else if err != nil {
 			klog.V(4).Infof("update container %v/%v/%v cpu burst failed, dir %v, updated %v, err %v",
 				pod.Namespace, pod.Name, containerStat.Name, containerDir, updated, err)
+			errs = append(errs, fmt.Errorf("update container %s/%s/%s cpu burst failed, err: %w",
+				pod.Namespace, pod.Name, containerStat.Name, err))
 		} else {
 			metrics.RecordContainerScaledCFSBurstUS(pod.Namespace, pod.Name, containerStat.ContainerID, containerStat.Name, float64(containerCFSBurstVal))
 			klog.V(5).Infof("apply container %v/%v/%v cpu burst value successfully, dir %v, value %v",
@@ -622,7 +636,7 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 	if err != nil { // normally cpu burst resource not supported on current system
 		klog.V(5).Infof("get cpu burst updater for pod %s/%s failed, maybe system unsupported, err: %v",
 			pod.Namespace, pod.Name, err)
-		return
+		return utilerrors.NewAggregate(errs)
 	}
 	updated, err := b.executor.Update(true, updater)
 	if err != nil && system.IsResourceUnsupportedErr(err) {
@@ -631,10 +645,12 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 	} else if err != nil {
 		klog.V(4).Infof("update pod %v/%v cpu burst failed, dir %v, updated %v, err %v",
 			pod.Namespace, pod.Name, podDir, updated, err)
+		errs = append(errs, fmt.Errorf("update pod %s/%s cpu burst failed, err: %w", pod.Namespace, pod.Name, err))
 	} else {
 		klog.V(5).Infof("apply pod %v/%v cpu burst value successfully, dir %v, value %v",
 			pod.Namespace, pod.Name, podDir, podCFSBurstValStr)
 	}
+	return utilerrors.NewAggregate(errs)
 }
 
 func (b *cpuBurst) Recycle() {
@@ -1559,6 +1559,7 @@ func TestCPUBurst_start(t *testing.T) {
 			mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
 			mockStatesInformer.EXPECT().GetAllPods().Return(testutil.GetPodMetas(tt.fields.pods)).AnyTimes()
 			mockStatesInformer.EXPECT().GetNodeSLO().Return(tt.fields.nodeSLO).AnyTimes()
+			mockStatesInformer.EXPECT().SetNodeSLOStrategyResult(slov1alpha1.StrategyNameCPUBurst, nil).MaxTimes(1)
 
 			mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctl)
 			metriccache.DefaultAggregateResultFactory = mockResultFactory
@@ -0,0 +1,529 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interference
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/resctrl"
+	qosmanagerUtil "github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	InterferenceEvictName = "InterferenceEvict"
+
+	defaultCPIDegradationPercent         = 30
+	defaultMBPIDegradationPercent        = 30
+	defaultBEMemoryBandwidthSharePercent = 30
+	defaultBEMBAPercentStep              = 10
+	defaultBEMinMBAPercent               = 10
+	defaultEvictAfterRounds              = 3
+
+	// baselineMinSamples is the number of samples to learn before a baseline is used
+	baselineMinSamples = 5
+	// baselineDecay is the weight of the new sample in the exponential moving average of a baseline
+	baselineDecay = 0.1
+	// baselineRelearnTimeout is how long the samples keep degraded before the baseline is relearned, e.g. after the
+	// workload of the pod shifts
+	baselineRelearnTimeout = 10 * time.Minute
+
+	maxMBAPercent = 100
+)
+
+// qosResctrlGroups are the resctrl groups whose memory bandwidth is collected by QoS.
+var qosResctrlGroups = []string{resctrl.LSRResctrlGroup, resctrl.LSResctrlGroup, resctrl.BEResctrlGroup}
+
+var _ framework.QOSStrategy = &interferenceEvictor{}
+
+// interferenceEvictor detects the interference of the BE pods on the non-BE pods by CPI and resctrl metrics.
+// When the interference is detected, it limits the memory bandwidth of the BE resctrl group step by step, and evicts
+// the BE pods if the interference persists after the limit reaches the min.
+type interferenceEvictor struct {
+	interval               time.Duration
+	evictCoolingInterval   time.Duration
+	metricCollectInterval  time.Duration
+	cpiCollectInterval     time.Duration
+	cpiCollectTimeWindow   time.Duration
+	resctrlCollectInterval time.Duration
+	statesInformer         statesinformer.StatesInformer
+	metricCache            metriccache.MetricCache
+	evictExecutor          qosmanagerUtil.EvictionExecutor
+	lastEvictTime          time.Time
+
+	// cpiBaselines are the learned CPI baselines of the non-BE pods by pod uid
+	cpiBaselines map[string]*baseline
+	// mbpiBaseline is the learned MBPI baseline of the non-BE resctrl groups
+	mbpiBaseline *baseline
+	// lastMBStats records the memory bandwidth counters of the last round to calculate the rates
+	lastMBStats *mbStats
+	// interferedRounds is the number of consecutive interfered rounds after the BE MBA reaches the min
+	interferedRounds int64
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &interferenceEvictor{
+		interval:               time.Duration(opt.Config.InterferenceDetectIntervalSeconds) * time.Second,
+		evictCoolingInterval:   time.Duration(opt.Config.InterferenceEvictCoolTimeSeconds) * time.Second,
+		metricCollectInterval:  opt.MetricAdvisorConfig.CollectResUsedInterval,
+		cpiCollectInterval:     opt.MetricAdvisorConfig.CPICollectorInterval,
+		cpiCollectTimeWindow:   opt.MetricAdvisorConfig.CPICollectorTimeWindow,
+		resctrlCollectInterval: opt.MetricAdvisorConfig.ResctrlCollectorInterval,
+		statesInformer:         opt.StatesInformer,
+		metricCache:            opt.MetricCache,
+		cpiBaselines:           map[string]*baseline{},
+		mbpiBaseline:           &baseline{},
+	}
+}
+
+func (i *interferenceEvictor) Enabled() bool {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.BEInterferenceEvict) || i.interval <= 0 {
+		return false
+	}
+	if !features.DefaultKoordletFeatureGate.Enabled(features.RdtResctrl) {
+		klog.Warningf("interference evict is disabled since the featuregate %s is disabled and the BE MBA cannot be limited", features.RdtResctrl)
+		return false
+	}
+	return true
+}
+
+func (i *interferenceEvictor) Setup(ctx *framework.Context) {
+	i.evictExecutor = qosmanagerUtil.InitializeEvictionExecutor(ctx.Evictor, ctx.OnlyEvictByAPI)
+}
+
+func (i *interferenceEvictor) Run(stopCh <-chan struct{}) {
+	go wait.Until(i.detectAndEvict, i.interval, stopCh)
+}
+
+// baseline is the exponential moving average of the samples without interference.
+type baseline struct {
+	value   float64
+	samples int
+	// degradedSince is when the samples start to degrade, zero if the last sample is not degraded
+	degradedSince time.Time
+}
+
+func (b *baseline) ready() bool {
+	return b.samples >= baselineMinSamples
+}
+
+func (b *baseline) learn(value float64) {
+	if b.samples <= 0 {
+		b.value = value
+	} else {
+		b.value = baselineDecay*value + (1-baselineDecay)*b.value
+	}
+	b.samples++
+}
+
+func (b *baseline) isDegraded(value float64, degradationPercent int64) bool {
+	return b.ready() && value > b.value*(1+float64(degradationPercent)/100)
+}
+
+// check returns whether the value degrades from the baseline, and learns the value if it is not degraded.
+// The baseline is relearned from the value if the samples keep degraded longer than the baselineRelearnTimeout.
+func (b *baseline) check(value float64, degradationPercent int64, now time.Time) bool {
+	if !b.isDegraded(value, degradationPercent) {
+		b.degradedSince = time.Time{}
+		b.learn(value)
+		return false
+	}
+	if b.degradedSince.IsZero() {
+		b.degradedSince = now
+		return true
+	}
+	if now.Sub(b.degradedSince) < baselineRelearnTimeout {
+		return true
+	}
+	klog.V(4).Infof("relearn the baseline %.4f, the samples keep degraded since %v", b.value, b.degradedSince)
+	*b = baseline{}
+	b.learn(value)
+	return false
+}
+
+// mbStats are the total memory bandwidth counters of the resctrl groups.
+type mbStats struct {
+	timestamp time.Time
+	// groupBytes is the sum of the mbm_total_bytes over the caches of each resctrl group
+	groupBytes map[string]float64
+}
+
+// interferenceResult is the detection result of a round.
+type interferenceResult struct {
+	// interferedPods are the non-BE pods whose CPI degrades
+	interferedPods []string
+	// mbpi is the memory bandwidth per instruction of the non-BE groups, zero if unavailable
+	mbpi         float64
+	mbpiDegraded bool
+	// beShare is the share of the BE group in the node memory bandwidth, negative if unavailable
+	beShare float64
+}
+
+func (r *interferenceResult) interfered() bool {
+	return len(r.interferedPods) > 0 || r.mbpiDegraded
+}
+
+func (r *interferenceResult) String() string {
+	return fmt.Sprintf("interfered pods [%s], mbpi %.4f, mbpi degraded %v, be memory bandwidth share %.2f",
+		strings.Join(r.interferedPods, ","), r.mbpi, r.mbpiDegraded, r.beShare)
+}
+
+func (i *interferenceEvictor) detectAndEvict() {
+	klog.V(5).Infof("interference evict process start")
+	defer klog.V(5).Info("interference evict process finished.")
+
+	nodeSLO := i.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEInterferenceEvict); err != nil {
+		klog.Warningf("interference evict failed, cannot check the featuregate, err: %s", err)
+		return
+	} else if disabled || nodeSLO.Spec.ResourceUsedThresholdWithBE.Interference == nil {
+		i.recoverBEMBALimitIfNeed()
+		klog.V(5).Infof("interference evict skipped, nodeSLO disable the featuregate")
+		return
+	}
+	strategy := nodeSLO.Spec.ResourceUsedThresholdWithBE.Interference
+
+	node := i.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("interference evict failed, got nil node")
+		return
+	}
+	pods := i.statesInformer.GetAllPods()
+	var nonBEPods, bePods []*statesinformer.PodMeta
+	for _, podMeta := range pods {
+		if util.IsPodInactive(podMeta.Pod) {
+			continue
+		}
+		if helpers.NonBEPodFilter(podMeta.Pod) {
+			nonBEPods = append(nonBEPods, podMeta)
+		} else if apiext.GetPodQoSClassRaw(podMeta.Pod) == apiext.QoSBE {
+			bePods = append(bePods, podMeta)
+		}
+	}
+
+	result := i.detect(strategy, nonBEPods)
+	if !result.interfered() {
+		i.interferedRounds = 0
+		i.relaxBEMBALimit(strategy, result)
+		return
+	}
+	if len(bePods) <= 0 {
+		_ = audit.V(3).Node().Reason(resourceexecutor.AdjustBEByInterference).Message("interference detected but no BE pod is running, %s", result).Do()
+		klog.V(4).Infof("interference evict skipped, no BE pod is running, %s", result)
+		return
+	}
+	// the interference is attributed to the BE pods only if they take enough memory bandwidth, and nothing is done
+	// without the evidence of the resctrl metrics
+	if result.beShare < 0 {
+		_ = audit.V(3).Node().Reason(resourceexecutor.AdjustBEByInterference).Message("interference detected but the BE memory bandwidth share is unknown, %s", result).Do()
+		klog.V(4).Infof("interference evict skipped, the BE memory bandwidth share is unknown, %s", result)
+		return
+	}
+	if result.beShare*100 < float64(ptr.Deref(strategy.BEMemoryBandwidthSharePercent, defaultBEMemoryBandwidthSharePercent)) {
+		_ = audit.V(3).Node().Reason(resourceexecutor.AdjustBEByInterference).Message("interference detected but not attributed to BE pods, %s", result).Do()
+		klog.V(4).Infof("interference evict skipped, interference is not attributed to BE pods, %s", result)
+		return
+	}
+
+	if i.limitBEMBA(strategy, result) {
+		i.interferedRounds = 0
+		return
+	}
+	i.interferedRounds++
+	if i.interferedRounds < ptr.Deref(strategy.EvictAfterRounds, defaultEvictAfterRounds) {
+		klog.V(4).Infof("interference persists for %d rounds after BE MBA reaches the min, %s", i.interferedRounds, result)
+		return
+	}
+	i.evictBEPod(node, bePods, result)
+}
+
+// detect compares the CPI of the non-BE pods and the MBPI of the non-BE groups with the baselines, and learns the
+// baselines if they are not degraded.
+func (i *interferenceEvictor) detect(strategy *slov1alpha1.InterferenceStrategy, nonBEPods []*statesinformer.PodMeta) *interferenceResult {
+	result := &interferenceResult{beShare: -1}
+	now := time.Now()
+	cpiDegradationPercent := ptr.Deref(strategy.CPIDegradationPercent, defaultCPIDegradationPercent)
+
+	var totalInstructions float64
+	alivePods := map[string]bool{}
+	for _, podMeta := range nonBEPods {
+		pod := podMeta.Pod
+		podUID := string(pod.UID)
+		alivePods[podUID] = true
+		cycles, instructions, err := i.collectPodCyclesAndInstructions(pod)
+		if err != nil {
+			klog.V(5).Infof("skip detecting interference for pod %s, failed to get cpi, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		totalInstructions += instructions
+		cpi := cycles / instructions
+		b, ok := i.cpiBaselines[podUID]
+		if !ok {
+			b = &baseline{}
+			i.cpiBaselines[podUID] = b
+		}
+		if b.check(cpi, cpiDegradationPercent, now) {
+			klog.V(4).Infof("cpi of pod %s degrades, current %.4f, baseline %.4f", util.GetPodKey(pod), cpi, b.value)
+			result.interferedPods = append(result.interferedPods, util.GetPodKey(pod))
+		}
+	}
+	for podUID := range i.cpiBaselines {
+		if !alivePods[podUID] {
+			delete(i.cpiBaselines, podUID)
+		}
+	}
+
+	mbRates := i.collectMBRates()
+	if mbRates == nil {
+		return result
+	}
+	var totalRate, nonBERate float64
+	for group, rate := range mbRates {
+		totalRate += rate
+		if group != resctrl.BEResctrlGroup {
+			nonBERate += rate
+		}
+	}
+	if totalRate > 0 {
+		result.beShare = mbRates[resctrl.BEResctrlGroup] / totalRate
+	}
+	if totalInstructions > 0 && i.cpiCollectTimeWindow > 0 {
+		// the instructions are counted in the cpi collector time window
+		result.mbpi = nonBERate / (totalInstructions / i.cpiCollectTimeWindow.Seconds())
+		if i.mbpiBaseline.check(result.mbpi, ptr.Deref(strategy.MBPIDegradationPercent, defaultMBPIDegradationPercent), now) {
+			klog.V(4).Infof("mbpi of non-BE pods degrades, current %.4f, baseline %.4f", result.mbpi, i.mbpiBaseline.value)
+			result.mbpiDegraded = true
+		}
+	}
+	return result
+}
+
+func (i *interferenceEvictor) collectPodCyclesAndInstructions(pod *corev1.Pod) (float64, float64, error) {
+	var cycles, instructions float64
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.ContainerID == "" {
+			continue
+		}
+		c, err := i.collectContainerCPIResource(pod, containerStatus.ContainerID, metriccache.CPIResourceCycle)
+		if err != nil {
+			return 0, 0, err
+		}
+		ins, err := i.collectContainerCPIResource(pod, containerStatus.ContainerID, metriccache.CPIResourceInstruction)
+		if err != nil {
+			return 0, 0, err
+		}
+		cycles += c
+		instructions += ins
+	}
+	if instructions <= 0 {
+		return 0, 0, fmt.Errorf("no instruction counted")
+	}
+	return cycles, instructions, nil
+}
+
+func (i *interferenceEvictor) collectContainerCPIResource(pod *corev1.Pod, containerID string, cpiResource metriccache.MetricPropertyValue) (float64, error) {
+	queryMeta, err := metriccache.ContainerCPI.BuildQueryMeta(metriccache.MetricPropertiesFunc.ContainerCPI(string(pod.UID), containerID, string(cpiResource)))
+	if err != nil {
+		return 0, err
+	}
+	// the cpi is collected every cpi collector interval, so the last sample is looked up in the interval
+	return helpers.CollectContainerResMetricLast(i.metricCache, queryMeta, i.cpiCollectInterval)
+}
+
+// collectMBRates returns the memory bandwidth rates in bytes per second of each resctrl group since the last round.
+// It returns nil if the rates cannot be calculated, e.g. the first round or the resctrl metrics are unavailable.
+func (i *interferenceEvictor) collectMBRates() map[string]float64 {
+	nodeCPUInfoRaw, exist := i.metricCache.Get(metriccache.NodeCPUInfoKey)
+	if !exist {
+		klog.V(5).Infof("failed to get nodeCPUInfo for resctrl metrics, not exist")
+		return nil
+	}
+	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
+	if !ok || nodeCPUInfo == nil || len(nodeCPUInfo.TotalInfo.L3ToCPU) <= 0 {
+		klog.V(5).Infof("failed to get l3 caches from nodeCPUInfo for resctrl metrics")
+		return nil
+	}
+
+	current := &mbStats{timestamp: time.Now(), groupBytes: map[string]float64{}}
+	for _, group := range qosResctrlGroups {
+		var groupBytes float64
+		for cacheID := range nodeCPUInfo.TotalInfo.L3ToCPU {
+			queryMeta, err := metriccache.ResctrlMBMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.ResctrlMB(group, int(cacheID), system.ResctrlMBMTotalName))
+			if err != nil {
+				klog.V(5).Infof("failed to build resctrl mb query meta for group %s, err: %v", group, err)
+				return nil
+			}
+			value, err := helpers.CollectorNodeMetricLast(i.metricCache, queryMeta, i.resctrlCollectInterval)
+			if err != nil {
+				klog.V(5).Infof("failed to query resctrl mb for group %s cache %d, err: %v", group, cacheID, err)
+				return nil
+			}
+			groupBytes += value
+		}
+		current.groupBytes[group] = groupBytes
+	}
+
+	last := i.lastMBStats
+	i.lastMBStats = current
+	if last == nil {
+		return nil
+	}
+	duration := current.timestamp.Sub(last.timestamp).Seconds()
+	if duration <= 0 {
+		return nil
+	}
+	rates := map[string]float64{}
+	for group, bytes := range current.groupBytes {
+		delta := bytes - last.groupBytes[group]
+		if delta < 0 {
+			// the counter is reset, e.g. the resctrl group is recreated
+			return nil
+		}
+		rates[group] = delta / duration
+	}
+	return rates
+}
+
+// limitBEMBA lowers the MBA limit of the BE group by a step. It returns false if the limit already reaches the min.
+func (i *interferenceEvictor) limitBEMBA(strategy *slov1alpha1.InterferenceStrategy, result *interferenceResult) bool {
+	minPercent := ptr.Deref(strategy.BEMinMBAPercent, defaultBEMinMBAPercent)
+	current := resctrl.GetBEMBAPercentLimit()
+	if current <= 0 {
+		current = maxMBAPercent
+	}
+	if current <= minPercent {
+		return false
+	}
+	newLimit := current - ptr.Deref(strategy.BEMBAPercentStep, defaultBEMBAPercentStep)
+	if newLimit < minPercent {
+		newLimit = minPercent
+	}
+	resctrl.SetBEMBAPercentLimit(newLimit)
+	_ = audit.V(1).Group(resctrl.BEResctrlGroup).Reason(resourceexecutor.AdjustBEByInterference).Message("limit BE MBA percent from %d to %d, %s", current, newLimit, result).Do()
+	klog.V(4).Infof("interference detected, limit BE MBA percent from %d to %d, %s", current, newLimit, result)
+	return true
+}
+
+// relaxBEMBALimit raises the MBA limit of the BE group by a step until it is unlimited.
+func (i *interferenceEvictor) relaxBEMBALimit(strategy *slov1alpha1.InterferenceStrategy, result *interferenceResult) {
+	current := resctrl.GetBEMBAPercentLimit()
+	if current <= 0 {
+		return
+	}
+	newLimit := current + ptr.Deref(strategy.BEMBAPercentStep, defaultBEMBAPercentStep)
+	if newLimit >= maxMBAPercent {
+		newLimit = 0
+	}
+	resctrl.SetBEMBAPercentLimit(newLimit)
+	_ = audit.V(1).Group(resctrl.BEResctrlGroup).Reason(resourceexecutor.AdjustBEByInterference).Message("relax BE MBA percent from %d to %d, %s", current, newLimit, result).Do()
+	klog.V(4).Infof("no interference detected, relax BE MBA percent limit from %d to %d", current, newLimit)
+}
+
+func (i *interferenceEvictor) recoverBEMBALimitIfNeed() {
+	if current := resctrl.GetBEMBAPercentLimit(); current > 0 {
+		resctrl.SetBEMBAPercentLimit(0)
+		_ = audit.V(1).Group(resctrl.BEResctrlGroup).Reason(resourceexecutor.AdjustBEByInterference).Message("recover BE MBA percent limit from %d", current).Do()
+		klog.V(4).Infof("interference evict disabled, recover BE MBA percent limit from %d", current)
+	}
+	i.interferedRounds = 0
+}
+
+// evictBEPod evicts one BE pod at a time, since the interference cannot be translated into the resource to release.
+func (i *interferenceEvictor) evictBEPod(node *corev1.Node, bePods []*statesinformer.PodMeta, result *interferenceResult) {
+	if time.Since(i.lastEvictTime) < i.evictCoolingInterval {
+		klog.V(4).Infof("skip interference evict, still in evict cool time")
+		return
+	}
+	reason := fmt.Sprintf("%s%s, %s", qosmanagerUtil.EvictReasonPrefix, features.BEInterferenceEvict, result)
+	for _, podInfo := range i.getSortedBEPodInfos(bePods) {
+		pod := podInfo.Pod
+		if i.evictExecutor.IsPodEvicted(pod) {
+			// the pod is evicted in a previous round but still present
+			klog.V(4).Infof("pod %s was evicted but still present, skip evicting another pod", util.GetPodKey(pod))
+			return
+		}
+		if !i.evictExecutor.Evict(pod, node, qosmanagerUtil.EvictedStr, fmt.Sprintf("%v, kill pod: %v", reason, pod.Name)) {
+			klog.V(4).Infof("failed to pick pod %s to evict, release reason: %v", util.GetPodKey(pod), reason)
+			continue
+		}
+		i.lastEvictTime = time.Now()
+		i.interferedRounds = 0
+		_ = audit.V(0).Pod(pod.Namespace, pod.Name).Reason(resourceexecutor.EvictBEPodByInterference).Message("evict BE pod, %s", result).Do()
+		klog.V(4).Infof("successfully picked pod %s to evict, release reason: %v", util.GetPodKey(pod), reason)
+		return
+	}
+	klog.Warningf("interference evict failed, no BE pod is evicted, %s", result)
+}
+
+// getSortedBEPodInfos sorts the BE pods by eviction-priority > spec.priority > koordinator.sh/priority > cpu usage.
+func (i *interferenceEvictor) getSortedBEPodInfos(bePods []*statesinformer.PodMeta) []*qosmanagerUtil.PodEvictInfo {
+	podMetrics := helpers.CollectAllPodMetricsLast(i.statesInformer, i.metricCache, metriccache.PodCPUUsageMetric, i.metricCollectInterval)
+	var podInfos []*qosmanagerUtil.PodEvictInfo
+	for _, podMeta := range bePods {
+		pod := podMeta.Pod
+		if !qosmanagerUtil.IsEvictionPolicyAllowed(string(features.BEInterferenceEvict), pod) {
+			continue
+		}
+		podInfo := &qosmanagerUtil.PodEvictInfo{Pod: pod}
+		if priority := apiext.GetPodPriorityValueWithDefault(pod); priority != nil {
+			podInfo.Priority = *priority
+		}
+		evictionPriority, err := apiext.GetPodEvictionPriority(pod)
+		if err != nil {
+			klog.Warningf("failed to parse eviction priority of pod %s/%s, use the default 0, err: %v", pod.Namespace, pod.Name, err)
+		}
+		podInfo.EvictionPriority = evictionPriority
+		podInfo.LabelPriority = qosmanagerUtil.GetPodPriorityLabel(pod, int64(podInfo.Priority))
+		podInfo.MilliCPUUsed = int64(podMetrics[string(pod.UID)] * 1000)
+		podInfos = append(podInfos, podInfo)
+	}
+
+	sort.Slice(podInfos, func(a, b int) bool {
+		if podInfos[a].EvictionPriority != podInfos[b].EvictionPriority {
+			return podInfos[a].EvictionPriority < podInfos[b].EvictionPriority
+		}
+		if podInfos[a].Priority != podInfos[b].Priority {
+			return podInfos[a].Priority < podInfos[b].Priority
+		}
+		if podInfos[a].LabelPriority != podInfos[b].LabelPriority {
+			return podInfos[a].LabelPriority < podInfos[b].LabelPriority
+		}
+		if podInfos[a].MilliCPUUsed != podInfos[b].MilliCPUUsed {
+			return podInfos[a].MilliCPUUsed > podInfos[b].MilliCPUUsed
+		}
+		return podInfos[a].Pod.Name < podInfos[b].Pod.Name
+	})
+	return podInfos
+}
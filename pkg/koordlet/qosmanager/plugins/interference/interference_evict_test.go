@@ -0,0 +1,394 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interference
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/resctrl"
+	qosmanagerUtil "github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/util"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func newTestPod(name string, qosClass apiext.QoSClass, priority int32) *corev1.Pod {
+	kubeQoS := corev1.PodQOSBurstable
+	if qosClass == apiext.QoSBE {
+		kubeQoS = corev1.PodQOSBestEffort
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			Labels:    map[string]string{apiext.LabelPodQoS: string(qosClass)},
+		},
+		Spec: corev1.PodSpec{
+			Priority: ptr.To[int32](priority),
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: kubeQoS,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "main", ContainerID: "containerd://" + name},
+			},
+		},
+	}
+}
+
+func appendContainerCPI(t *testing.T, appender metriccache.Appender, pod *corev1.Pod, cycles, instructions float64) {
+	containerID := pod.Status.ContainerStatuses[0].ContainerID
+	for cpiResource, value := range map[metriccache.MetricPropertyValue]float64{
+		metriccache.CPIResourceCycle:       cycles,
+		metriccache.CPIResourceInstruction: instructions,
+	} {
+		s, err := metriccache.ContainerCPI.GenerateSample(metriccache.MetricPropertiesFunc.ContainerCPI(string(pod.UID), containerID, string(cpiResource)), time.Now(), value)
+		assert.NoError(t, err)
+		assert.NoError(t, appender.Append([]metriccache.MetricSample{s}))
+	}
+}
+
+func appendResctrlMB(t *testing.T, appender metriccache.Appender, group string, value float64) {
+	s, err := metriccache.ResctrlMBMetric.GenerateSample(metriccache.MetricPropertiesFunc.ResctrlMB(group, 0, system.ResctrlMBMTotalName), time.Now(), value)
+	assert.NoError(t, err)
+	assert.NoError(t, appender.Append([]metriccache.MetricSample{s}))
+}
+
+func Test_interferenceEvictor_Enabled(t *testing.T) {
+	tests := []struct {
+		name                string
+		interferenceEnabled bool
+		resctrlEnabled      bool
+		interval            time.Duration
+		want                bool
+	}{
+		{
+			name:                "enabled",
+			interferenceEnabled: true,
+			resctrlEnabled:      true,
+			interval:            time.Second,
+			want:                true,
+		},
+		{
+			name:                "featuregate disabled",
+			interferenceEnabled: false,
+			resctrlEnabled:      true,
+			interval:            time.Second,
+			want:                false,
+		},
+		{
+			name:                "resctrl disabled",
+			interferenceEnabled: true,
+			resctrlEnabled:      false,
+			interval:            time.Second,
+			want:                false,
+		},
+		{
+			name:                "interval is zero",
+			interferenceEnabled: true,
+			resctrlEnabled:      true,
+			interval:            0,
+			want:                false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BEInterferenceEvict, tt.interferenceEnabled)()
+			defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.RdtResctrl, tt.resctrlEnabled)()
+			i := &interferenceEvictor{interval: tt.interval}
+			assert.Equal(t, tt.want, i.Enabled())
+		})
+	}
+}
+
+func Test_interferenceEvictor_detectAndEvict(t *testing.T) {
+	lsPod := newTestPod("ls-pod", apiext.QoSLS, 9000)
+	beLowPod := newTestPod("be-low-pod", apiext.QoSBE, 5000)
+	beHighPod := newTestPod("be-high-pod", apiext.QoSBE, 5500)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+	strategy := &slov1alpha1.InterferenceStrategy{
+		CPIDegradationPercent:         ptr.To[int64](30),
+		MBPIDegradationPercent:        ptr.To[int64](30),
+		BEMemoryBandwidthSharePercent: ptr.To[int64](30),
+		BEMBAPercentStep:              ptr.To[int64](10),
+		BEMinMBAPercent:               ptr.To[int64](10),
+		EvictAfterRounds:              ptr.To[int64](3),
+	}
+	enabledConfig := &slov1alpha1.ResourceThresholdStrategy{
+		Enable:       ptr.To[bool](true),
+		Interference: strategy,
+	}
+	beHeavyMB := map[string]float64{resctrl.LSResctrlGroup: 4e9, resctrl.BEResctrlGroup: 6e9}
+	tests := []struct {
+		name            string
+		thresholdConfig *slov1alpha1.ResourceThresholdStrategy
+		// lsCPI is the current cpi of the ls pod, whose baseline is 1.0
+		lsCPI float64
+		// mbBytes are the current memory bandwidth counters of the groups, which are 0 in the last round
+		mbBytes          map[string]float64
+		mbpiBaseline     float64
+		preLimit         int64
+		preRounds        int64
+		inCoolTime       bool
+		wantLimit        int64
+		wantRounds       int64
+		wantBaselineSize int
+		wantEvicted      []string
+	}{
+		{
+			name: "recover the limit if the strategy is disabled",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:       ptr.To[bool](false),
+				Interference: strategy,
+			},
+			lsCPI:            2.0,
+			preLimit:         50,
+			wantLimit:        0,
+			wantBaselineSize: 5,
+		},
+		{
+			name:             "learn the baseline and relax the limit if no interference",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.1,
+			preLimit:         50,
+			preRounds:        2,
+			wantLimit:        60,
+			wantRounds:       0,
+			wantBaselineSize: 6,
+		},
+		{
+			name:             "unset the limit when fully relaxed",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.0,
+			preLimit:         95,
+			wantLimit:        0,
+			wantBaselineSize: 6,
+		},
+		{
+			name:             "limit be mba by a step if cpi degrades",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.5,
+			mbBytes:          beHeavyMB,
+			preLimit:         0,
+			wantLimit:        90,
+			wantBaselineSize: 5,
+		},
+		{
+			name:             "skip if the be memory bandwidth share is unknown",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.5,
+			preLimit:         0,
+			wantLimit:        0,
+			wantBaselineSize: 5,
+		},
+		{
+			name:             "skip if the interference is not attributed to be",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.5,
+			mbBytes:          map[string]float64{resctrl.LSResctrlGroup: 9e9, resctrl.BEResctrlGroup: 1e9},
+			preLimit:         0,
+			wantLimit:        0,
+			wantBaselineSize: 5,
+		},
+		{
+			name:             "limit be mba if be takes much memory bandwidth",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.5,
+			mbBytes:          map[string]float64{resctrl.LSResctrlGroup: 5e9, resctrl.BEResctrlGroup: 5e9},
+			preLimit:         50,
+			wantLimit:        40,
+			wantBaselineSize: 5,
+		},
+		{
+			name:            "limit be mba if mbpi degrades",
+			thresholdConfig: enabledConfig,
+			lsCPI:           1.0,
+			// 1e9 instructions in 10s, and 5e8 bytes/s of ls, so the mbpi is 5
+			mbBytes:          map[string]float64{resctrl.LSResctrlGroup: 5e9, resctrl.BEResctrlGroup: 5e9},
+			mbpiBaseline:     2,
+			preLimit:         0,
+			wantLimit:        90,
+			wantBaselineSize: 6,
+		},
+		{
+			name:             "wait for more rounds at the min limit",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.5,
+			mbBytes:          beHeavyMB,
+			preLimit:         10,
+			preRounds:        1,
+			wantLimit:        10,
+			wantRounds:       2,
+			wantBaselineSize: 5,
+		},
+		{
+			name:             "evict the be pod of the lowest priority",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.5,
+			mbBytes:          beHeavyMB,
+			preLimit:         10,
+			preRounds:        2,
+			wantLimit:        10,
+			wantRounds:       0,
+			wantBaselineSize: 5,
+			wantEvicted:      []string{string(beLowPod.UID)},
+		},
+		{
+			name:             "skip evicting in cool time",
+			thresholdConfig:  enabledConfig,
+			lsCPI:            1.5,
+			mbBytes:          beHeavyMB,
+			preLimit:         10,
+			preRounds:        2,
+			inCoolTime:       true,
+			wantLimit:        10,
+			wantRounds:       3,
+			wantBaselineSize: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BEInterferenceEvict, true)()
+			resctrl.SetBEMBAPercentLimit(tt.preLimit)
+			defer resctrl.SetBEMBAPercentLimit(0)
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			pods := []*corev1.Pod{lsPod, beLowPod, beHighPod}
+			si := mockstatesinformer.NewMockStatesInformer(ctl)
+			si.EXPECT().GetNode().Return(node).AnyTimes()
+			si.EXPECT().GetNodeSLO().Return(testutil.GetNodeSLOByThreshold(tt.thresholdConfig)).AnyTimes()
+			si.EXPECT().GetAllPods().Return(testutil.GetPodMetas(pods)).AnyTimes()
+
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              helper.TempDir,
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer metricCache.Close()
+			appender := metricCache.Appender()
+			appendContainerCPI(t, appender, lsPod, tt.lsCPI*1e9, 1e9)
+			for group, value := range tt.mbBytes {
+				appendResctrlMB(t, appender, group, value)
+			}
+			if tt.mbBytes != nil {
+				appendResctrlMB(t, appender, resctrl.LSRResctrlGroup, 0)
+				metricCache.Set(metriccache.NodeCPUInfoKey, &metriccache.NodeCPUInfo{
+					TotalInfo: koordletutil.CPUTotalInfo{
+						L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {{CPUID: 0}}},
+					},
+				})
+			}
+			assert.NoError(t, appender.Commit())
+
+			evicted := map[string]bool{}
+			evictExecutor := qosmanagerUtil.NewMockEvictionExecutor(ctl)
+			evictExecutor.EXPECT().Evict(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(pod *corev1.Pod, node *corev1.Node, releaseReason, message string) bool {
+					evicted[string(pod.UID)] = true
+					return true
+				}).AnyTimes()
+			evictExecutor.EXPECT().IsPodEvicted(gomock.Any()).DoAndReturn(func(pod *corev1.Pod) bool {
+				return evicted[string(pod.UID)]
+			}).AnyTimes()
+
+			p := New(&framework.Options{
+				Config:              framework.NewDefaultConfig(),
+				StatesInformer:      si,
+				MetricCache:         metricCache,
+				MetricAdvisorConfig: maframework.NewDefaultConfig(),
+			}).(*interferenceEvictor)
+			p.evictExecutor = evictExecutor
+			p.cpiBaselines[string(lsPod.UID)] = &baseline{value: 1.0, samples: 5}
+			p.cpiBaselines["deleted-pod"] = &baseline{value: 1.0, samples: 5}
+			if tt.mbpiBaseline > 0 {
+				p.mbpiBaseline = &baseline{value: tt.mbpiBaseline, samples: 5}
+			}
+			if tt.mbBytes != nil {
+				p.lastMBStats = &mbStats{
+					timestamp: time.Now().Add(-10 * time.Second),
+					groupBytes: map[string]float64{
+						resctrl.LSRResctrlGroup: 0,
+						resctrl.LSResctrlGroup:  0,
+						resctrl.BEResctrlGroup:  0,
+					},
+				}
+			}
+			p.interferedRounds = tt.preRounds
+			if tt.inCoolTime {
+				p.lastEvictTime = time.Now()
+			}
+
+			p.detectAndEvict()
+
+			assert.Equal(t, tt.wantLimit, resctrl.GetBEMBAPercentLimit())
+			assert.Equal(t, tt.wantRounds, p.interferedRounds)
+			assert.Equal(t, tt.wantBaselineSize, p.cpiBaselines[string(lsPod.UID)].samples)
+			var gotEvicted []string
+			for uid := range evicted {
+				gotEvicted = append(gotEvicted, uid)
+			}
+			assert.Equal(t, tt.wantEvicted, gotEvicted)
+		})
+	}
+}
+
+func Test_baseline(t *testing.T) {
+	b := &baseline{}
+	for i := 0; i < baselineMinSamples-1; i++ {
+		b.learn(1.0)
+	}
+	assert.False(t, b.ready())
+	assert.False(t, b.isDegraded(10, 30))
+	b.learn(2.0)
+	assert.True(t, b.ready())
+	assert.InDelta(t, 1.1, b.value, 1e-9)
+	assert.False(t, b.isDegraded(1.4, 30))
+	assert.True(t, b.isDegraded(1.5, 30))
+
+	// the degraded samples are not learned until the relearn timeout
+	now := time.Now()
+	assert.False(t, b.check(1.2, 30, now))
+	assert.Equal(t, baselineMinSamples+1, b.samples)
+	value := b.value
+	assert.True(t, b.check(3.0, 30, now))
+	assert.True(t, b.check(3.0, 30, now.Add(baselineRelearnTimeout/2)))
+	assert.Equal(t, value, b.value)
+	assert.False(t, b.check(3.0, 30, now.Add(baselineRelearnTimeout)))
+	assert.Equal(t, 3.0, b.value)
+	assert.Equal(t, 1, b.samples)
+	assert.False(t, b.ready())
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryhigh
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	MemoryHighTuneName = "MemoryHighTune"
+
+	defaultNodeMemoryTargetPercent = 70
+	defaultPSIThresholdPercent     = 20
+	defaultRefaultThreshold        = 1000
+	defaultStepPercent             = 10
+	defaultMinMemoryHighPercent    = 20
+
+	// the memory.high is relaxed without the pressure when the node memory usage is below the target by the percentage
+	relaxMarginPercent = 10
+
+	// writing MaxInt64 is equal to write "max"
+	memoryHighUnlimited = math.MaxInt64
+)
+
+var _ framework.QOSStrategy = &memoryHighTuner{}
+
+// memoryHighTuner tunes the memory.high of each BE pod in a closed loop. Unlike the static ThrottlingPercent of the
+// MemoryQOS, it lowers the memory.high when the node memory is tight and raises it when the BE pod is reclaimed too
+// hard, which is indicated by the memory pressure and the refaults of the pod.
+type memoryHighTuner struct {
+	interval              time.Duration
+	metricCollectInterval time.Duration
+	psiCollectInterval    time.Duration
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	executor              resourceexecutor.ResourceUpdateExecutor
+	cgroupReader          resourceexecutor.CgroupReader
+	// memoryHighs records the memory.high set on the BE pods by the strategy, the pod not in it is unlimited
+	memoryHighs map[string]*podMemoryHigh
+	// lastRefaults records the refault counter of the BE pods to calculate the refault rate
+	lastRefaults map[string]*refaultRecord
+}
+
+type podMemoryHigh struct {
+	namespace string
+	name      string
+	cgroupDir string
+	value     int64
+}
+
+type refaultRecord struct {
+	refault   int64
+	timestamp time.Time
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &memoryHighTuner{
+		interval:              time.Duration(opt.Config.MemoryHighTuneIntervalSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		psiCollectInterval:    opt.MetricAdvisorConfig.PSICollectorInterval,
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+		executor:              resourceexecutor.NewResourceUpdateExecutor(),
+		cgroupReader:          opt.CgroupReader,
+		memoryHighs:           map[string]*podMemoryHigh{},
+		lastRefaults:          map[string]*refaultRecord{},
+	}
+}
+
+func (m *memoryHighTuner) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BEMemoryHighTune) && m.interval > 0
+}
+
+func (m *memoryHighTuner) Setup(*framework.Context) {}
+
+func (m *memoryHighTuner) Run(stopCh <-chan struct{}) {
+	m.executor.Run(stopCh)
+	go wait.Until(m.tuneBEMemoryHigh, m.interval, stopCh)
+}
+
+func (m *memoryHighTuner) tuneBEMemoryHigh() {
+	klog.V(5).Infof("memory high tune process start")
+	defer klog.V(5).Info("memory high tune process finished.")
+
+	nodeSLO := m.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEMemoryHighTune); err != nil {
+		klog.Warningf("memory high tune failed, cannot check the featuregate, err: %s", err)
+		return
+	} else if disabled || nodeSLO.Spec.ResourceUsedThresholdWithBE.MemoryHighTune == nil {
+		m.recoverMemoryHighIfNeed()
+		klog.V(5).Infof("memory high tune skipped, nodeSLO disable the featuregate")
+		return
+	}
+	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+
+	node := m.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("memory high tune failed, got nil node")
+		return
+	}
+	memoryCapacity := node.Status.Capacity.Memory().Value()
+	if memoryCapacity <= 0 {
+		klog.Warningf("memory high tune failed, node memory capacity not valid, value: %d", memoryCapacity)
+		return
+	}
+	queryMeta, err := metriccache.NodeMemoryUsageMetric.BuildQueryMeta(nil)
+	if err != nil {
+		klog.Warningf("memory high tune failed, build node query meta error: %v", err)
+		return
+	}
+	nodeMemoryUsed, err := helpers.CollectorNodeMetricLast(m.metricCache, queryMeta, m.metricCollectInterval)
+	if err != nil {
+		klog.Warningf("memory high tune failed, get node metrics error: %v", err)
+		return
+	}
+
+	m.tunePods(thresholdConfig, nodeSLO.Spec.ResourceQOSStrategy, memoryCapacity, int64(nodeMemoryUsed), m.statesInformer.GetAllPods())
+}
+
+func (m *memoryHighTuner) tunePods(thresholdConfig *slov1alpha1.ResourceThresholdStrategy, qosStrategy *slov1alpha1.ResourceQOSStrategy,
+	memoryCapacity, nodeMemoryUsed int64, pods []*statesinformer.PodMeta) {
+	strategy := thresholdConfig.MemoryHighTune
+	targetUsed := memoryCapacity * getInt64OrDefault(strategy.NodeMemoryTargetPercent, defaultNodeMemoryTargetPercent) / 100
+	relaxUsed := targetUsed - memoryCapacity*relaxMarginPercent/100
+	// the hard backstop, the memory.high is never raised over the evict threshold
+	backstopUsed := memoryCapacity
+	if thresholdConfig.MemoryEvictThresholdPercent != nil {
+		backstopUsed = memoryCapacity * *thresholdConfig.MemoryEvictThresholdPercent / 100
+	}
+	overloaded := nodeMemoryUsed >= targetUsed || nodeMemoryUsed >= backstopUsed
+	klog.V(5).Infof("memory high tune got node memory used %d, target %d, backstop %d, overloaded %v",
+		nodeMemoryUsed, targetUsed, backstopUsed, overloaded)
+
+	podMetrics := helpers.CollectAllPodMetricsLast(m.statesInformer, m.metricCache, metriccache.PodMemUsageMetric, m.metricCollectInterval)
+	alivePods := map[string]struct{}{}
+	for _, podMeta := range pods {
+		pod := podMeta.Pod
+		if apiext.GetPodQoSClassRaw(pod) != apiext.QoSBE || util.IsPodInactive(pod) {
+			continue
+		}
+		uid := string(pod.UID)
+		alivePods[uid] = struct{}{}
+		if isMemoryHighManagedByMemoryQOS(pod, qosStrategy) {
+			// the MemoryQOS owns the memory.high of the pod, hand over the one set by the strategy
+			if _, ok := m.memoryHighs[uid]; ok && m.updatePodMemoryHigh(podMeta, memoryHighUnlimited) {
+				metrics.RecordBEPodMemoryHighAdjustment(pod.Namespace, pod.Name, metrics.MemoryHighActionReset)
+				delete(m.memoryHighs, uid)
+			}
+			klog.V(5).Infof("memory high tune skip pod %s, its memory.high is throttled by the memory qos", util.GetPodKey(pod))
+			continue
+		}
+
+		limit := util.GetPodBEMemoryByteLimit(pod)
+		if limit <= 0 || limit > memoryCapacity {
+			limit = memoryCapacity
+		}
+		// the memory.high is kept no lower than the floor, otherwise the pod is throttled into the reclaim
+		floor := limit * getInt64OrDefault(strategy.MinMemoryHighPercent, defaultMinMemoryHighPercent) / 100
+		if request := util.GetPodBEMemoryByteRequestIgnoreUnlimited(pod); request > floor {
+			floor = request
+		}
+		step := limit * getInt64OrDefault(strategy.StepPercent, defaultStepPercent) / 100
+		refaultRate, hasRefaultRate := m.collectRefaultRate(podMeta)
+
+		current := limit
+		if memoryHigh, ok := m.memoryHighs[uid]; ok {
+			current = memoryHigh.value
+		}
+		var newMemoryHigh int64
+		var action string
+		if overloaded {
+			// lower from the usage if the pod uses less than the current memory.high, so it takes effect in this round
+			base := current
+			if used, ok := podMetrics[uid]; ok && int64(used) < base {
+				base = int64(used)
+			}
+			newMemoryHigh = base - step
+			if newMemoryHigh < floor {
+				newMemoryHigh = floor
+			}
+			if newMemoryHigh < system.PageSize {
+				newMemoryHigh = system.PageSize
+			}
+			if newMemoryHigh >= current {
+				continue
+			}
+			action = metrics.MemoryHighActionLower
+		} else {
+			if current >= limit {
+				continue
+			}
+			pressured := m.isPodPressured(pod, strategy, refaultRate, hasRefaultRate)
+			if !pressured && nodeMemoryUsed >= relaxUsed {
+				continue
+			}
+			if nodeMemoryUsed+step >= backstopUsed {
+				klog.V(5).Infof("memory high tune skip raising pod %s, node memory used %d reaches the backstop %d",
+					util.GetPodKey(pod), nodeMemoryUsed, backstopUsed)
+				continue
+			}
+			newMemoryHigh = current + step
+			action = metrics.MemoryHighActionRaise
+			if newMemoryHigh >= limit {
+				newMemoryHigh = memoryHighUnlimited
+				action = metrics.MemoryHighActionReset
+			}
+		}
+		if newMemoryHigh != memoryHighUnlimited {
+			newMemoryHigh = newMemoryHigh / system.PageSize * system.PageSize
+		}
+
+		if !m.updatePodMemoryHigh(podMeta, newMemoryHigh) {
+			continue
+		}
+		metrics.RecordBEPodMemoryHighAdjustment(pod.Namespace, pod.Name, action)
+		if newMemoryHigh == memoryHighUnlimited {
+			delete(m.memoryHighs, uid)
+		} else {
+			m.memoryHighs[uid] = &podMemoryHigh{namespace: pod.Namespace, name: pod.Name, cgroupDir: podMeta.CgroupDir, value: newMemoryHigh}
+		}
+		klog.V(4).Infof("memory high tune %s pod %s memory.high from %d to %d, node memory used %d, refault rate %.2f",
+			action, util.GetPodKey(pod), current, newMemoryHigh, nodeMemoryUsed, refaultRate)
+	}
+
+	for uid := range m.memoryHighs {
+		if _, ok := alivePods[uid]; !ok {
+			delete(m.memoryHighs, uid)
+		}
+	}
+	for uid := range m.lastRefaults {
+		if _, ok := alivePods[uid]; !ok {
+			delete(m.lastRefaults, uid)
+		}
+	}
+	m.recordMemoryHighMetrics()
+}
+
+// isMemoryHighManagedByMemoryQOS returns whether the memory.high of the BE pod is throttled by the MemoryQOS, which is
+// reconciled by the CgroupReconcile with the ThrottlingPercent.
+func isMemoryHighManagedByMemoryQOS(pod *corev1.Pod, qosStrategy *slov1alpha1.ResourceQOSStrategy) bool {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.CgroupReconcile) {
+		return false
+	}
+	var throttlingPercent *int64
+	if podQOSCfg := helpers.GetPodResourceQoSByQoSClass(pod, qosStrategy); podQOSCfg != nil && podQOSCfg.MemoryQOS != nil {
+		throttlingPercent = podQOSCfg.MemoryQOS.ThrottlingPercent
+	}
+	podCfg, err := slov1alpha1.GetPodMemoryQoSConfig(pod)
+	if err == nil && podCfg != nil {
+		switch podCfg.Policy {
+		case slov1alpha1.PodMemoryQOSPolicyNone:
+			return false
+		case slov1alpha1.PodMemoryQOSPolicyAuto:
+			// the template of the auto policy does not throttle
+			throttlingPercent = nil
+		}
+		if podCfg.ThrottlingPercent != nil {
+			throttlingPercent = podCfg.ThrottlingPercent
+		}
+	}
+	return throttlingPercent != nil && *throttlingPercent > 0
+}
+
+// isPodPressured returns whether the BE pod is reclaimed too hard by its memory.high, which is indicated by the
+// memory pressure or the refault rate exceeding the threshold.
+func (m *memoryHighTuner) isPodPressured(pod *corev1.Pod, strategy *slov1alpha1.MemoryHighTuneStrategy, refaultRate float64, hasRefaultRate bool) bool {
+	if hasRefaultRate && refaultRate > float64(getInt64OrDefault(strategy.RefaultThreshold, defaultRefaultThreshold)) {
+		return true
+	}
+	queryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodPSI(string(pod.UID),
+		string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)))
+	if err != nil {
+		klog.Warningf("build pod %s psi query meta failed, error: %v", util.GetPodKey(pod), err)
+		return false
+	}
+	pressure, err := helpers.CollectPodMetricLast(m.metricCache, queryMeta, m.psiCollectInterval)
+	if err != nil {
+		klog.V(5).Infof("query pod %s memory psi failed, error: %v", util.GetPodKey(pod), err)
+		return false
+	}
+	return pressure > float64(getInt64OrDefault(strategy.PSIThresholdPercent, defaultPSIThresholdPercent))
+}
+
+// collectRefaultRate returns the refaulted pages per second of the pod since the last round.
+func (m *memoryHighTuner) collectRefaultRate(podMeta *statesinformer.PodMeta) (float64, bool) {
+	uid := string(podMeta.Pod.UID)
+	memStat, err := m.cgroupReader.ReadMemoryStat(podMeta.CgroupDir)
+	if err != nil {
+		klog.V(5).Infof("read pod %s memory.stat failed, error: %v", util.GetPodKey(podMeta.Pod), err)
+		return 0, false
+	}
+	cur := &refaultRecord{refault: memStat.WorkingsetRefault, timestamp: time.Now()}
+	last := m.lastRefaults[uid]
+	m.lastRefaults[uid] = cur
+	if last == nil || cur.refault < last.refault {
+		return 0, false
+	}
+	duration := cur.timestamp.Sub(last.timestamp).Seconds()
+	if duration <= 0 {
+		return 0, false
+	}
+	return float64(cur.refault-last.refault) / duration, true
+}
+
+func (m *memoryHighTuner) updatePodMemoryHigh(podMeta *statesinformer.PodMeta, value int64) bool {
+	pod := podMeta.Pod
+	eventHelper := audit.V(3).Pod(pod.Namespace, pod.Name).Reason(resourceexecutor.AdjustBEByMemoryReclaim).Message("update pod memory.high: %v", value)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.MemoryHighName, podMeta.CgroupDir, strconv.FormatInt(value, 10), eventHelper)
+	if err != nil {
+		klog.V(4).Infof("failed to get memory.high updater for pod %s, err: %v", util.GetPodKey(pod), err)
+		return false
+	}
+	if _, err = m.executor.Update(false, updater); err != nil {
+		klog.Errorf("memory high tune failed to write memory.high for pod %s, target: %d, error: %v", util.GetPodKey(pod), value, err)
+		return false
+	}
+	return true
+}
+
+func (m *memoryHighTuner) recoverMemoryHighIfNeed() {
+	m.lastRefaults = map[string]*refaultRecord{}
+	if len(m.memoryHighs) <= 0 {
+		return
+	}
+	for uid, memoryHigh := range m.memoryHighs {
+		eventHelper := audit.V(3).Pod(memoryHigh.namespace, memoryHigh.name).Reason(resourceexecutor.AdjustBEByMemoryReclaim).Message("recover pod memory.high")
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.MemoryHighName, memoryHigh.cgroupDir, strconv.FormatInt(memoryHighUnlimited, 10), eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to get memory.high updater for pod %s/%s, err: %v", memoryHigh.namespace, memoryHigh.name, err)
+			continue
+		}
+		if _, err = m.executor.Update(false, updater); err != nil {
+			klog.Errorf("memory high tune failed to recover memory.high for pod %s/%s, error: %v", memoryHigh.namespace, memoryHigh.name, err)
+			continue
+		}
+		metrics.RecordBEPodMemoryHighAdjustment(memoryHigh.namespace, memoryHigh.name, metrics.MemoryHighActionReset)
+		delete(m.memoryHighs, uid)
+	}
+	klog.V(5).Infof("memory high tune recovered the memory.high of the BE pods, %d pods left", len(m.memoryHighs))
+	m.recordMemoryHighMetrics()
+}
+
+func (m *memoryHighTuner) recordMemoryHighMetrics() {
+	metrics.ResetBEPodMemoryHigh()
+	for _, memoryHigh := range m.memoryHighs {
+		metrics.RecordBEPodMemoryHigh(memoryHigh.namespace, memoryHigh.name, memoryHigh.value)
+	}
+}
+
+func getInt64OrDefault(value *int64, defaultValue int64) int64 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
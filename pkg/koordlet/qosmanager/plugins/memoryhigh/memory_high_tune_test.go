@@ -0,0 +1,338 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryhigh
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const mib = 1024 * 1024
+
+func newTestBEPod(name string, requestMiB, limitMiB int64) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			Labels:    map[string]string{apiext.LabelPodQoS: string(apiext.QoSBE)},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{apiext.BatchMemory: *resource.NewQuantity(requestMiB*mib, resource.BinarySI)},
+						Limits:   corev1.ResourceList{apiext.BatchMemory: *resource.NewQuantity(limitMiB*mib, resource.BinarySI)},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSBestEffort,
+		},
+	}
+}
+
+func newMemoryStat(refault int64) string {
+	return fmt.Sprintf("total_cache 0\ntotal_rss 0\ntotal_inactive_file 0\ntotal_active_file 0\ntotal_inactive_anon 0\n"+
+		"total_active_anon 0\ntotal_unevictable 0\ntotal_workingset_refault_anon 0\ntotal_workingset_refault_file %d", refault)
+}
+
+func Test_memoryHighTuner_tuneBEMemoryHigh(t *testing.T) {
+	bePod := newTestBEPod("be-pod", 1000, 4000)
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceMemory: *resource.NewQuantity(10000*mib, resource.BinarySI)},
+		},
+	}
+	strategy := &slov1alpha1.MemoryHighTuneStrategy{}
+	unlimited := strconv.FormatInt(memoryHighUnlimited, 10)
+	tests := []struct {
+		name            string
+		thresholdConfig *slov1alpha1.ResourceThresholdStrategy
+		nodeUsedMiB     int64
+		podUsedMiB      int64
+		podPressure     float64
+		refault         int64
+		lastRefault     *int64
+		preMemoryHigh   int64
+		memoryQOS       bool
+		wantMemoryHigh  string
+		wantTuned       bool
+	}{
+		{
+			name: "recover the memory.high if the strategy is disabled",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](false),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    8000,
+			preMemoryHigh:  2000 * mib,
+			wantMemoryHigh: unlimited,
+			wantTuned:      false,
+		},
+		{
+			name: "lower the memory.high from the usage when node usage exceeds the target",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](true),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    8000,
+			podUsedMiB:     3000,
+			wantMemoryHigh: strconv.FormatInt(2600*mib, 10),
+			wantTuned:      true,
+		},
+		{
+			name: "lower the memory.high no less than the request",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](true),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    8000,
+			podUsedMiB:     1100,
+			preMemoryHigh:  1200 * mib,
+			wantMemoryHigh: strconv.FormatInt(1000*mib, 10),
+			wantTuned:      true,
+		},
+		{
+			name: "lower the memory.high no less than the min percent of the limit",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable: ptr.To[bool](true),
+				MemoryHighTune: &slov1alpha1.MemoryHighTuneStrategy{
+					MinMemoryHighPercent: ptr.To[int64](30),
+				},
+			},
+			nodeUsedMiB:    8000,
+			podUsedMiB:     1250,
+			preMemoryHigh:  1300 * mib,
+			wantMemoryHigh: strconv.FormatInt(1200*mib, 10),
+			wantTuned:      true,
+		},
+		{
+			name: "hand over the memory.high when the pod is throttled by the memory qos",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](true),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    8000,
+			podUsedMiB:     3000,
+			preMemoryHigh:  2000 * mib,
+			memoryQOS:      true,
+			wantMemoryHigh: unlimited,
+			wantTuned:      false,
+		},
+		{
+			name: "lower the memory.high when node usage exceeds the evict threshold",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                      ptr.To[bool](true),
+				MemoryEvictThresholdPercent: ptr.To[int64](60),
+				MemoryHighTune:              strategy,
+			},
+			nodeUsedMiB:    6500,
+			podUsedMiB:     3000,
+			preMemoryHigh:  2000 * mib,
+			wantMemoryHigh: strconv.FormatInt(1600*mib, 10),
+			wantTuned:      true,
+		},
+		{
+			name: "raise the memory.high when the pod is pressured",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](true),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    6500,
+			podUsedMiB:     2000,
+			podPressure:    30,
+			preMemoryHigh:  2000 * mib,
+			wantMemoryHigh: strconv.FormatInt(2400*mib, 10),
+			wantTuned:      true,
+		},
+		{
+			name: "raise the memory.high when the pod refaults",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](true),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    6500,
+			podUsedMiB:     2000,
+			refault:        100000,
+			lastRefault:    ptr.To[int64](0),
+			preMemoryHigh:  2000 * mib,
+			wantMemoryHigh: strconv.FormatInt(2400*mib, 10),
+			wantTuned:      true,
+		},
+		{
+			name: "keep the memory.high when the pod is not pressured",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](true),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    6500,
+			podUsedMiB:     2000,
+			podPressure:    10,
+			preMemoryHigh:  2000 * mib,
+			wantMemoryHigh: strconv.FormatInt(2000*mib, 10),
+			wantTuned:      true,
+		},
+		{
+			name: "relax the memory.high when the node has enough free memory",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](true),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    3000,
+			podUsedMiB:     2000,
+			preMemoryHigh:  2000 * mib,
+			wantMemoryHigh: strconv.FormatInt(2400*mib, 10),
+			wantTuned:      true,
+		},
+		{
+			name: "reset the memory.high when it reaches the limit",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:         ptr.To[bool](true),
+				MemoryHighTune: strategy,
+			},
+			nodeUsedMiB:    3000,
+			podUsedMiB:     3000,
+			preMemoryHigh:  3800 * mib,
+			wantMemoryHigh: unlimited,
+			wantTuned:      false,
+		},
+		{
+			name: "do not raise the memory.high over the evict threshold",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                      ptr.To[bool](true),
+				MemoryEvictThresholdPercent: ptr.To[int64](68),
+				MemoryHighTune:              strategy,
+			},
+			nodeUsedMiB:    6500,
+			podUsedMiB:     2000,
+			podPressure:    30,
+			preMemoryHigh:  2000 * mib,
+			wantMemoryHigh: strconv.FormatInt(2000*mib, 10),
+			wantTuned:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BEMemoryHighTune, true)()
+			defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.CgroupReconcile, tt.memoryQOS)()
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.WriteCgroupFileContents(system.CgroupPathFormatter.ParentDir, system.MemoryHigh, unlimited)
+			podDir := koordletutil.GetPodCgroupParentDir(bePod)
+			helper.WriteCgroupFileContents(podDir, system.MemoryStat, newMemoryStat(tt.refault))
+			preMemoryHigh := unlimited
+			if tt.preMemoryHigh > 0 {
+				preMemoryHigh = strconv.FormatInt(tt.preMemoryHigh, 10)
+			}
+			helper.WriteCgroupFileContents(podDir, system.MemoryHigh, preMemoryHigh)
+
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			si := mockstatesinformer.NewMockStatesInformer(ctl)
+			si.EXPECT().GetNode().Return(node).AnyTimes()
+			nodeSLO := testutil.GetNodeSLOByThreshold(tt.thresholdConfig)
+			if tt.memoryQOS {
+				nodeSLO.Spec.ResourceQOSStrategy = &slov1alpha1.ResourceQOSStrategy{
+					BEClass: &slov1alpha1.ResourceQOS{
+						MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+							Enable:    ptr.To[bool](true),
+							MemoryQOS: slov1alpha1.MemoryQOS{ThrottlingPercent: ptr.To[int64](80)},
+						},
+					},
+				}
+			}
+			si.EXPECT().GetNodeSLO().Return(nodeSLO).AnyTimes()
+			si.EXPECT().GetAllPods().Return(testutil.GetPodMetas([]*corev1.Pod{bePod})).AnyTimes()
+
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              helper.TempDir,
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer metricCache.Close()
+			appender := metricCache.Appender()
+			now := time.Now()
+			nodeSample, err := metriccache.NodeMemoryUsageMetric.GenerateSample(nil, now, float64(tt.nodeUsedMiB*mib))
+			assert.NoError(t, err)
+			podSample, err := metriccache.PodMemUsageMetric.GenerateSample(metriccache.MetricPropertiesFunc.Pod(string(bePod.UID)), now, float64(tt.podUsedMiB*mib))
+			assert.NoError(t, err)
+			psiSample, err := metriccache.PodPSIMetric.GenerateSample(metriccache.MetricPropertiesFunc.PodPSI(string(bePod.UID),
+				string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), now, tt.podPressure)
+			assert.NoError(t, err)
+			assert.NoError(t, appender.Append([]metriccache.MetricSample{nodeSample, podSample, psiSample}))
+			assert.NoError(t, appender.Commit())
+
+			m := New(&framework.Options{
+				Config:              framework.NewDefaultConfig(),
+				StatesInformer:      si,
+				MetricCache:         metricCache,
+				CgroupReader:        resourceexecutor.NewCgroupReader(),
+				MetricAdvisorConfig: maframework.NewDefaultConfig(),
+			}).(*memoryHighTuner)
+			m.executor = &resourceexecutor.ResourceUpdateExecutorImpl{
+				Config:        resourceexecutor.NewDefaultConfig(),
+				ResourceCache: cache.NewCacheDefault(),
+			}
+			if tt.preMemoryHigh > 0 {
+				m.memoryHighs[string(bePod.UID)] = &podMemoryHigh{
+					namespace: bePod.Namespace,
+					name:      bePod.Name,
+					cgroupDir: podDir,
+					value:     tt.preMemoryHigh,
+				}
+			}
+			if tt.lastRefault != nil {
+				m.lastRefaults[string(bePod.UID)] = &refaultRecord{refault: *tt.lastRefault, timestamp: now.Add(-10 * time.Second)}
+			}
+
+			m.tuneBEMemoryHigh()
+
+			assert.Equal(t, tt.wantMemoryHigh, helper.ReadCgroupFileContents(podDir, system.MemoryHigh))
+			_, gotTuned := m.memoryHighs[string(bePod.UID)]
+			assert.Equal(t, tt.wantTuned, gotTuned)
+		})
+	}
+}
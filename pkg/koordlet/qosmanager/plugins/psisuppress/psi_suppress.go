@@ -0,0 +1,391 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psisuppress
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	qosmanagerUtil "github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	PSISuppressName = "PSISuppress"
+
+	defaultThrottleStepPercent = 10
+	defaultThrottleMinPercent  = 10
+
+	beMinQuota   = 2000
+	beUnsetQuota = -1
+)
+
+// psiResources are the watched resources in order.
+var psiResources = []metriccache.MetricPropertyValue{
+	metriccache.PSIResourceCPU,
+	metriccache.PSIResourceMem,
+	metriccache.PSIResourceIO,
+}
+
+var _ framework.QOSStrategy = &psiSuppress{}
+
+// psiSuppress throttles and evicts the BE pods according to the pressure stall information of the non-BE pods.
+// Unlike the CPUSuppress which works on the node utilization, it reacts on the contention the non-BE pods actually
+// suffer from.
+type psiSuppress struct {
+	interval              time.Duration
+	evictCoolingInterval  time.Duration
+	metricCollectInterval time.Duration
+	psiCollectInterval    time.Duration
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	executor              resourceexecutor.ResourceUpdateExecutor
+	cgroupReader          resourceexecutor.CgroupReader
+	evictExecutor         qosmanagerUtil.EvictionExecutor
+	lastEvictTime         time.Time
+	// throttled indicates the BE cfs quota is set by the strategy and should be recovered
+	throttled bool
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &psiSuppress{
+		interval:              time.Duration(opt.Config.PSISuppressIntervalSeconds) * time.Second,
+		evictCoolingInterval:  time.Duration(opt.Config.PSIEvictCoolTimeSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		psiCollectInterval:    opt.MetricAdvisorConfig.PSICollectorInterval,
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+		executor:              resourceexecutor.NewResourceUpdateExecutor(),
+		cgroupReader:          opt.CgroupReader,
+	}
+}
+
+// Enabled returns true only if the PSICollector is also enabled, since the strategy has no PSI to react on without it.
+func (p *psiSuppress) Enabled() bool {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.BEPSISuppress) || p.interval <= 0 {
+		return false
+	}
+	if !features.DefaultKoordletFeatureGate.Enabled(features.PSICollector) {
+		klog.Warningf("psi suppress is disabled since the featuregate %s is disabled and no psi data is available", features.PSICollector)
+		return false
+	}
+	return true
+}
+
+func (p *psiSuppress) Setup(ctx *framework.Context) {
+	p.evictExecutor = qosmanagerUtil.InitializeEvictionExecutor(ctx.Evictor, ctx.OnlyEvictByAPI)
+}
+
+func (p *psiSuppress) Run(stopCh <-chan struct{}) {
+	p.executor.Run(stopCh)
+	go wait.Until(p.suppressBE, p.interval, stopCh)
+}
+
+func (p *psiSuppress) suppressBE() {
+	klog.V(5).Infof("psi suppress process start")
+	defer klog.V(5).Info("psi suppress process finished.")
+
+	nodeSLO := p.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEPSISuppress); err != nil {
+		klog.Warningf("psi suppress failed, cannot check the featuregate, err: %s", err)
+		return
+	} else if disabled || nodeSLO.Spec.ResourceUsedThresholdWithBE.PSISuppress == nil {
+		p.recoverCFSQuotaIfNeed()
+		klog.V(5).Infof("psi suppress skipped, nodeSLO disable the featuregate")
+		return
+	}
+	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	strategy := thresholdConfig.PSISuppress
+
+	node := p.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("psi suppress failed, got nil node")
+		return
+	}
+	if node.Status.Capacity.Cpu().MilliValue() <= 0 {
+		klog.Warningf("psi suppress failed, node cpu capacity not valid, value: %d", node.Status.Capacity.Cpu().MilliValue())
+		return
+	}
+	pods := p.statesInformer.GetAllPods()
+	pressures := p.collectNonBEPodsPressure(pods)
+
+	if features.DefaultKoordletFeatureGate.Enabled(features.BECPUSuppress) && thresholdConfig.CPUSuppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
+		// the BE cfs quota is managed by the CPUSuppress, do not throttle to avoid the conflicts
+		klog.V(5).Infof("psi suppress skip throttling, be cfs quota is managed by %s policy", slov1alpha1.CPUCfsQuotaPolicy)
+		p.throttled = false
+	} else {
+		_, _, overloaded := exceedThreshold(pressures, getThrottleThresholds(strategy))
+		p.adjustBECFSQuota(node, strategy, overloaded)
+	}
+
+	if psiResource, pressure, ok := exceedThreshold(pressures, getEvictThresholds(strategy)); ok {
+		p.evictBEPod(node, pods, psiResource, pressure)
+	}
+}
+
+// collectNonBEPodsPressure returns the max `some avg10` percentage of the non-BE pods on each resource.
+// The resource without any metric is not included.
+func (p *psiSuppress) collectNonBEPodsPressure(pods []*statesinformer.PodMeta) map[metriccache.MetricPropertyValue]float64 {
+	pressures := map[metriccache.MetricPropertyValue]float64{}
+	for _, podMeta := range pods {
+		pod := podMeta.Pod
+		if !helpers.NonBEPodFilter(pod) || util.IsPodInactive(pod) {
+			continue
+		}
+		for _, psiResource := range psiResources {
+			queryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodPSI(string(pod.UID),
+				string(psiResource), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)))
+			if err != nil {
+				klog.Warningf("build pod %s/%s psi query meta failed, resource: %s, error: %v", pod.Namespace, pod.Name, psiResource, err)
+				continue
+			}
+			value, err := helpers.CollectPodMetricLast(p.metricCache, queryMeta, p.psiCollectInterval)
+			if err != nil {
+				klog.V(5).Infof("query pod %s/%s psi failed, resource: %s, error: %v", pod.Namespace, pod.Name, psiResource, err)
+				continue
+			}
+			if old, ok := pressures[psiResource]; !ok || value > old {
+				pressures[psiResource] = value
+			}
+		}
+	}
+	klog.V(5).Infof("psi suppress got non-BE pods pressure %v", pressures)
+	return pressures
+}
+
+// adjustBECFSQuota decreases the BE cfs quota by a step when the pressure is overloaded, otherwise increases it by a
+// step until it is unset.
+func (p *psiSuppress) adjustBECFSQuota(node *corev1.Node, strategy *slov1alpha1.PSISuppressStrategy, overloaded bool) {
+	if !overloaded && !p.throttled {
+		return
+	}
+	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	currentBeQuota, err := p.cgroupReader.ReadCPUQuota(beCgroupPath)
+	if err != nil {
+		klog.Warningf("psi suppress failed to read be cfs quota, error: %v", err)
+		return
+	}
+
+	nodeQuota := node.Status.Capacity.Cpu().MilliValue() * system.DefaultCPUCFSPeriod / 1000
+	stepQuota := nodeQuota * getInt64OrDefault(strategy.ThrottleStepPercent, defaultThrottleStepPercent) / 100
+	if currentBeQuota == beUnsetQuota || currentBeQuota > nodeQuota {
+		currentBeQuota = nodeQuota
+	}
+	var newBeQuota int64
+	if overloaded {
+		minQuota := nodeQuota * getInt64OrDefault(strategy.ThrottleMinPercent, defaultThrottleMinPercent) / 100
+		if minQuota < beMinQuota {
+			minQuota = beMinQuota
+		}
+		newBeQuota = currentBeQuota - stepQuota
+		if newBeQuota < minQuota {
+			newBeQuota = minQuota
+		}
+		if newBeQuota >= currentBeQuota {
+			klog.V(5).Infof("psi suppress skip throttling, be cfs quota %d already reaches the min %d", currentBeQuota, minQuota)
+			p.throttled = true
+			return
+		}
+	} else {
+		newBeQuota = currentBeQuota + stepQuota
+		if newBeQuota >= nodeQuota {
+			newBeQuota = beUnsetQuota
+		}
+	}
+
+	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByPodPSI).Message("update BE group to cfs_quota: %v", newBeQuota)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, beCgroupPath, strconv.FormatInt(newBeQuota, 10), eventHelper)
+	if err != nil {
+		klog.V(4).Infof("failed to get be cfs quota updater, target quota: %d, err: %v", newBeQuota, err)
+		return
+	}
+	isUpdated, err := p.executor.Update(false, updater)
+	if err != nil {
+		klog.Errorf("psi suppress failed to write cfs_quota_us for be pods, target quota: %d, error: %v", newBeQuota, err)
+		return
+	}
+	p.throttled = newBeQuota != beUnsetQuota
+	klog.V(4).Infof("psi suppress succeeded to write cfs_quota_us for be pods, isUpdated %v, overloaded %v, new value: %d",
+		isUpdated, overloaded, newBeQuota)
+}
+
+func (p *psiSuppress) recoverCFSQuotaIfNeed() {
+	if !p.throttled {
+		return
+	}
+	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	eventHelper := audit.V(3).Reason(resourceexecutor.AdjustBEByPodPSI).Message("recover bestEffort cfsQuota, isUpdated %v", "-1")
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, beCgroupPath, strconv.FormatInt(beUnsetQuota, 10), eventHelper)
+	if err != nil {
+		klog.V(4).Infof("failed to get be cfs quota updater, err: %v", err)
+		return
+	}
+	isUpdated, err := p.executor.Update(false, updater)
+	if err != nil {
+		klog.Errorf("psi suppress failed to recover bestEffort cfsQuota, err: %v", err)
+		return
+	}
+	klog.V(5).Infof("psi suppress successfully recover bestEffort cfsQuota, isUpdated %v", isUpdated)
+	p.throttled = false
+}
+
+// evictBEPod evicts one BE pod at a time, since the pressure cannot be translated into the resource to release.
+func (p *psiSuppress) evictBEPod(node *corev1.Node, pods []*statesinformer.PodMeta, psiResource metriccache.MetricPropertyValue, pressure float64) {
+	if time.Since(p.lastEvictTime) < p.evictCoolingInterval {
+		klog.V(4).Infof("skip psi evict, still in evict cool time")
+		return
+	}
+	sortedPodInfos := p.getSortedBEPodInfos(pods, psiResource)
+	if len(sortedPodInfos) <= 0 {
+		klog.V(4).Infof("skip psi evict, no BE pod to evict")
+		return
+	}
+	podCount := corev1.ResourceList{corev1.ResourcePods: *resource.NewQuantity(1, resource.DecimalSI)}
+	task := &qosmanagerUtil.EvictTaskInfo{
+		Reason:            fmt.Sprintf("%s%s, %s pressure %.2f exceeds the threshold", qosmanagerUtil.EvictReasonPrefix, features.BEPSISuppress, psiResource, pressure),
+		SortedEvictPods:   sortedPodInfos,
+		ReleaseTarget:     qosmanagerUtil.ReleaseTargetTypePodCount,
+		ToReleaseResource: podCount,
+		GetPodResourceFunc: func(*qosmanagerUtil.PodEvictInfo) corev1.ResourceList {
+			return podCount
+		},
+	}
+	released, hasReleased := qosmanagerUtil.KillAndEvictPods(p.evictExecutor, node, []*qosmanagerUtil.EvictTaskInfo{task})
+	if hasReleased {
+		p.lastEvictTime = time.Now()
+	}
+	if succeed, _ := qosmanagerUtil.EvictTaskCheck(task, released); succeed {
+		klog.V(4).Infof("evict task %v succeed", task.Reason)
+	} else {
+		klog.Warningf("evict task %v failed, no BE pod is evicted", task.Reason)
+	}
+}
+
+// getSortedBEPodInfos sorts the BE pods by eviction-priority > spec.priority > koordinator.sh/priority > usage of the
+// pressured resource.
+func (p *psiSuppress) getSortedBEPodInfos(pods []*statesinformer.PodMeta, psiResource metriccache.MetricPropertyValue) []*qosmanagerUtil.PodEvictInfo {
+	var podMetrics map[string]float64
+	switch psiResource {
+	case metriccache.PSIResourceCPU:
+		podMetrics = helpers.CollectAllPodMetricsLast(p.statesInformer, p.metricCache, metriccache.PodCPUUsageMetric, p.metricCollectInterval)
+	case metriccache.PSIResourceMem:
+		podMetrics = helpers.CollectAllPodMetricsLast(p.statesInformer, p.metricCache, metriccache.PodMemUsageMetric, p.metricCollectInterval)
+	}
+
+	var podInfos []*qosmanagerUtil.PodEvictInfo
+	for _, podMeta := range pods {
+		pod := podMeta.Pod
+		if apiext.GetPodQoSClassRaw(pod) != apiext.QoSBE || util.IsPodInactive(pod) {
+			continue
+		}
+		if !qosmanagerUtil.IsEvictionPolicyAllowed(string(features.BEPSISuppress), pod) {
+			continue
+		}
+		podInfo := &qosmanagerUtil.PodEvictInfo{Pod: pod}
+		if priority := apiext.GetPodPriorityValueWithDefault(pod); priority != nil {
+			podInfo.Priority = *priority
+		}
+		evictionPriority, err := apiext.GetPodEvictionPriority(pod)
+		if err != nil {
+			klog.Warningf("failed to parse eviction priority of pod %s/%s, use the default 0, err: %v", pod.Namespace, pod.Name, err)
+		}
+		podInfo.EvictionPriority = evictionPriority
+		podInfo.LabelPriority = qosmanagerUtil.GetPodPriorityLabel(pod, int64(podInfo.Priority))
+		if psiResource == metriccache.PSIResourceMem {
+			podInfo.MemoryUsed = int64(podMetrics[string(pod.UID)])
+		} else {
+			podInfo.MilliCPUUsed = int64(podMetrics[string(pod.UID)] * 1000)
+		}
+		podInfos = append(podInfos, podInfo)
+	}
+
+	sort.Slice(podInfos, func(i, j int) bool {
+		if podInfos[i].EvictionPriority != podInfos[j].EvictionPriority {
+			return podInfos[i].EvictionPriority < podInfos[j].EvictionPriority
+		}
+		if podInfos[i].Priority != podInfos[j].Priority {
+			return podInfos[i].Priority < podInfos[j].Priority
+		}
+		if podInfos[i].LabelPriority != podInfos[j].LabelPriority {
+			return podInfos[i].LabelPriority < podInfos[j].LabelPriority
+		}
+		if psiResource == metriccache.PSIResourceMem && podInfos[i].MemoryUsed != podInfos[j].MemoryUsed {
+			return podInfos[i].MemoryUsed > podInfos[j].MemoryUsed
+		}
+		if podInfos[i].MilliCPUUsed != podInfos[j].MilliCPUUsed {
+			return podInfos[i].MilliCPUUsed > podInfos[j].MilliCPUUsed
+		}
+		return podInfos[i].Pod.Name < podInfos[j].Pod.Name
+	})
+	return podInfos
+}
+
+func getThrottleThresholds(strategy *slov1alpha1.PSISuppressStrategy) map[metriccache.MetricPropertyValue]*int64 {
+	return map[metriccache.MetricPropertyValue]*int64{
+		metriccache.PSIResourceCPU: strategy.CPUThrottleThresholdPercent,
+		metriccache.PSIResourceMem: strategy.MemoryThrottleThresholdPercent,
+		metriccache.PSIResourceIO:  strategy.IOThrottleThresholdPercent,
+	}
+}
+
+func getEvictThresholds(strategy *slov1alpha1.PSISuppressStrategy) map[metriccache.MetricPropertyValue]*int64 {
+	return map[metriccache.MetricPropertyValue]*int64{
+		metriccache.PSIResourceCPU: strategy.CPUEvictThresholdPercent,
+		metriccache.PSIResourceMem: strategy.MemoryEvictThresholdPercent,
+		metriccache.PSIResourceIO:  strategy.IOEvictThresholdPercent,
+	}
+}
+
+// exceedThreshold returns the first resource whose pressure exceeds the threshold.
+func exceedThreshold(pressures map[metriccache.MetricPropertyValue]float64, thresholds map[metriccache.MetricPropertyValue]*int64) (metriccache.MetricPropertyValue, float64, bool) {
+	for _, psiResource := range psiResources {
+		threshold := thresholds[psiResource]
+		pressure, ok := pressures[psiResource]
+		if threshold == nil || !ok {
+			continue
+		}
+		if pressure > float64(*threshold) {
+			return psiResource, pressure, true
+		}
+	}
+	return "", 0, false
+}
+
+func getInt64OrDefault(value *int64, defaultValue int64) int64 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
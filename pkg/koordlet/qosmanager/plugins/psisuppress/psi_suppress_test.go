@@ -0,0 +1,352 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psisuppress
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	qosmanagerUtil "github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func newTestPod(name string, qosClass apiext.QoSClass, priority int32) *corev1.Pod {
+	kubeQoS := corev1.PodQOSBurstable
+	if qosClass == apiext.QoSBE {
+		kubeQoS = corev1.PodQOSBestEffort
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			Labels:    map[string]string{apiext.LabelPodQoS: string(qosClass)},
+		},
+		Spec: corev1.PodSpec{
+			Priority: ptr.To[int32](priority),
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: kubeQoS,
+		},
+	}
+}
+
+func appendPodPSI(t *testing.T, appender metriccache.Appender, pod *corev1.Pod, psiResource metriccache.MetricPropertyValue, value float64) {
+	s, err := metriccache.PodPSIMetric.GenerateSample(metriccache.MetricPropertiesFunc.PodPSI(string(pod.UID),
+		string(psiResource), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), time.Now(), value)
+	assert.NoError(t, err)
+	assert.NoError(t, appender.Append([]metriccache.MetricSample{s}))
+}
+
+func Test_psiSuppress_Enabled(t *testing.T) {
+	tests := []struct {
+		name                string
+		psiSuppressEnabled  bool
+		psiCollectorEnabled bool
+		interval            time.Duration
+		want                bool
+	}{
+		{
+			name:                "enabled",
+			psiSuppressEnabled:  true,
+			psiCollectorEnabled: true,
+			interval:            time.Second,
+			want:                true,
+		},
+		{
+			name:                "featuregate disabled",
+			psiSuppressEnabled:  false,
+			psiCollectorEnabled: true,
+			interval:            time.Second,
+			want:                false,
+		},
+		{
+			name:                "psi collector disabled",
+			psiSuppressEnabled:  true,
+			psiCollectorEnabled: false,
+			interval:            time.Second,
+			want:                false,
+		},
+		{
+			name:                "interval is zero",
+			psiSuppressEnabled:  true,
+			psiCollectorEnabled: true,
+			interval:            0,
+			want:                false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BEPSISuppress, tt.psiSuppressEnabled)()
+			defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.PSICollector, tt.psiCollectorEnabled)()
+			p := &psiSuppress{interval: tt.interval}
+			assert.Equal(t, tt.want, p.Enabled())
+		})
+	}
+}
+
+func Test_psiSuppress_suppressBE(t *testing.T) {
+	lsPod := newTestPod("ls-pod", apiext.QoSLS, 9000)
+	beLowPod := newTestPod("be-low-pod", apiext.QoSBE, 5000)
+	beHighPod := newTestPod("be-high-pod", apiext.QoSBE, 5500)
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10")},
+		},
+	}
+	strategy := &slov1alpha1.PSISuppressStrategy{
+		CPUThrottleThresholdPercent: ptr.To[int64](20),
+		IOThrottleThresholdPercent:  ptr.To[int64](20),
+		CPUEvictThresholdPercent:    ptr.To[int64](50),
+	}
+	tests := []struct {
+		name            string
+		thresholdConfig *slov1alpha1.ResourceThresholdStrategy
+		lsPressure      map[metriccache.MetricPropertyValue]float64
+		bePressure      float64
+		preThrottled    bool
+		preBECFSQuota   int64
+		inCoolTime      bool
+		wantBECFSQuota  int64
+		wantThrottled   bool
+		wantEvicted     []string
+	}{
+		{
+			name: "recover the throttled quota if the strategy is disabled",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:      ptr.To[bool](false),
+				PSISuppress: strategy,
+			},
+			lsPressure:     map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 60},
+			preThrottled:   true,
+			preBECFSQuota:  500000,
+			wantBECFSQuota: -1,
+			wantThrottled:  false,
+		},
+		{
+			name: "do nothing if the pressure is low",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:      ptr.To[bool](true),
+				PSISuppress: strategy,
+			},
+			lsPressure:     map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 10},
+			bePressure:     90,
+			preBECFSQuota:  -1,
+			wantBECFSQuota: -1,
+			wantThrottled:  false,
+		},
+		{
+			name: "throttle be quota by a step",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:      ptr.To[bool](true),
+				PSISuppress: strategy,
+			},
+			lsPressure:     map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 10, metriccache.PSIResourceIO: 30},
+			preBECFSQuota:  -1,
+			wantBECFSQuota: 900000,
+			wantThrottled:  true,
+		},
+		{
+			name: "throttle be quota no less than the min",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable: ptr.To[bool](true),
+				PSISuppress: &slov1alpha1.PSISuppressStrategy{
+					CPUThrottleThresholdPercent: ptr.To[int64](20),
+					ThrottleStepPercent:         ptr.To[int64](30),
+					ThrottleMinPercent:          ptr.To[int64](20),
+				},
+			},
+			lsPressure:     map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 30},
+			preThrottled:   true,
+			preBECFSQuota:  400000,
+			wantBECFSQuota: 200000,
+			wantThrottled:  true,
+		},
+		{
+			name: "recover be quota by a step",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:      ptr.To[bool](true),
+				PSISuppress: strategy,
+			},
+			lsPressure:     map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 10},
+			preThrottled:   true,
+			preBECFSQuota:  500000,
+			wantBECFSQuota: 600000,
+			wantThrottled:  true,
+		},
+		{
+			name: "unset be quota when fully recovered",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:      ptr.To[bool](true),
+				PSISuppress: strategy,
+			},
+			preThrottled:   true,
+			preBECFSQuota:  900000,
+			wantBECFSQuota: -1,
+			wantThrottled:  false,
+		},
+		{
+			name: "skip throttling when cpu suppress uses cfs quota",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:            ptr.To[bool](true),
+				CPUSuppressPolicy: slov1alpha1.CPUCfsQuotaPolicy,
+				PSISuppress:       strategy,
+			},
+			lsPressure:     map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 30},
+			preBECFSQuota:  300000,
+			wantBECFSQuota: 300000,
+			wantThrottled:  false,
+		},
+		{
+			name: "evict the be pod of the lowest priority",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:      ptr.To[bool](true),
+				PSISuppress: strategy,
+			},
+			lsPressure:     map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 60},
+			preThrottled:   true,
+			preBECFSQuota:  500000,
+			wantBECFSQuota: 400000,
+			wantThrottled:  true,
+			wantEvicted:    []string{string(beLowPod.UID)},
+		},
+		{
+			name: "skip evicting in cool time",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:      ptr.To[bool](true),
+				PSISuppress: strategy,
+			},
+			lsPressure:     map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 60},
+			preBECFSQuota:  -1,
+			inCoolTime:     true,
+			wantBECFSQuota: 900000,
+			wantThrottled:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BEPSISuppress, true)()
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			beQOSDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+			helper.WriteCgroupFileContents(beQOSDir, system.CPUCFSQuota, strconv.FormatInt(tt.preBECFSQuota, 10))
+
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			pods := []*corev1.Pod{lsPod, beLowPod, beHighPod}
+			si := mockstatesinformer.NewMockStatesInformer(ctl)
+			si.EXPECT().GetNode().Return(node).AnyTimes()
+			si.EXPECT().GetNodeSLO().Return(testutil.GetNodeSLOByThreshold(tt.thresholdConfig)).AnyTimes()
+			si.EXPECT().GetAllPods().Return(testutil.GetPodMetas(pods)).AnyTimes()
+
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              helper.TempDir,
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer metricCache.Close()
+			appender := metricCache.Appender()
+			for psiResource, value := range tt.lsPressure {
+				appendPodPSI(t, appender, lsPod, psiResource, value)
+			}
+			// the pressure of be pods is ignored
+			appendPodPSI(t, appender, beLowPod, metriccache.PSIResourceCPU, tt.bePressure)
+			assert.NoError(t, appender.Commit())
+
+			evicted := map[string]bool{}
+			evictExecutor := qosmanagerUtil.NewMockEvictionExecutor(ctl)
+			evictExecutor.EXPECT().Evict(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(pod *corev1.Pod, node *corev1.Node, releaseReason, message string) bool {
+					evicted[string(pod.UID)] = true
+					return true
+				}).AnyTimes()
+			evictExecutor.EXPECT().IsPodEvicted(gomock.Any()).DoAndReturn(func(pod *corev1.Pod) bool {
+				return evicted[string(pod.UID)]
+			}).AnyTimes()
+
+			p := New(&framework.Options{
+				Config:              framework.NewDefaultConfig(),
+				StatesInformer:      si,
+				MetricCache:         metricCache,
+				CgroupReader:        resourceexecutor.NewCgroupReader(),
+				MetricAdvisorConfig: maframework.NewDefaultConfig(),
+			}).(*psiSuppress)
+			p.executor = &resourceexecutor.ResourceUpdateExecutorImpl{
+				Config:        resourceexecutor.NewDefaultConfig(),
+				ResourceCache: cache.NewCacheDefault(),
+			}
+			p.evictExecutor = evictExecutor
+			p.throttled = tt.preThrottled
+			if tt.inCoolTime {
+				p.lastEvictTime = time.Now()
+			}
+
+			p.suppressBE()
+
+			gotBECFSQuota := helper.ReadCgroupFileContents(beQOSDir, system.CPUCFSQuota)
+			assert.Equal(t, strconv.FormatInt(tt.wantBECFSQuota, 10), gotBECFSQuota)
+			assert.Equal(t, tt.wantThrottled, p.throttled)
+			var gotEvicted []string
+			for uid := range evicted {
+				gotEvicted = append(gotEvicted, uid)
+			}
+			assert.Equal(t, tt.wantEvicted, gotEvicted)
+		})
+	}
+}
+
+func Test_exceedThreshold(t *testing.T) {
+	thresholds := map[metriccache.MetricPropertyValue]*int64{
+		metriccache.PSIResourceCPU: ptr.To[int64](20),
+		metriccache.PSIResourceMem: nil,
+		metriccache.PSIResourceIO:  ptr.To[int64](30),
+	}
+	_, _, got := exceedThreshold(map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceMem: 90}, thresholds)
+	assert.False(t, got)
+	_, _, got = exceedThreshold(map[metriccache.MetricPropertyValue]float64{metriccache.PSIResourceCPU: 20}, thresholds)
+	assert.False(t, got)
+	psiResource, pressure, got := exceedThreshold(map[metriccache.MetricPropertyValue]float64{
+		metriccache.PSIResourceCPU: 10,
+		metriccache.PSIResourceIO:  40,
+	}, thresholds)
+	assert.True(t, got)
+	assert.Equal(t, metriccache.PSIResourceIO, psiResource)
+	assert.Equal(t, float64(40), pressure)
+}
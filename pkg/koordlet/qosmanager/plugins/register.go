@@ -23,7 +23,10 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuburst"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuevict"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpusuppress"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/interference"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memoryevict"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memoryhigh"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/psisuppress"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/sysreconcile"
 )
@@ -35,7 +38,10 @@ var (
 		cpuburst.CPUBurstName:                  cpuburst.New,
 		cpuevict.CPUEvictName:                  cpuevict.New,
 		cpusuppress.CPUSuppressName:            cpusuppress.New,
+		interference.InterferenceEvictName:     interference.New,
 		memoryevict.MemoryEvictName:            memoryevict.New,
+		memoryhigh.MemoryHighTuneName:          memoryhigh.New,
+		psisuppress.PSISuppressName:            psisuppress.New,
 		resctrl.ResctrlReconcileName:           resctrl.New,
 		sysreconcile.SystemConfigReconcileName: sysreconcile.New,
 	}
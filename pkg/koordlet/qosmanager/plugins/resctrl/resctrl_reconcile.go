@@ -22,10 +22,13 @@ import (
 	"strconv"
 	"time"
 
+	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
@@ -61,13 +64,52 @@ const (
 
 	// the AMD CPU use 2048 to express the unlimited memory bandwidth
 	AMDCCDUnlimitedMB = "2048"
+
+	// defaultBEMBAPercent is the MBA percent of the BE group if not configured, i.e. the full memory bandwidth
+	defaultBEMBAPercent int64 = 100
 )
 
 var (
 	// resctrlGroupList is the list of resctrl groups to be reconcile
 	resctrlGroupList = []string{LSRResctrlGroup, LSResctrlGroup, BEResctrlGroup}
+
+	// beMBAPercentLimit is the upper bound of the MBA percent of the BE group set by other strategies, e.g. the
+	// interference detection. Zero means unlimited.
+	beMBAPercentLimit = atomic.NewInt64(0)
 )
 
+// SetBEMBAPercentLimit limits the MBA percent of the BE group to no more than the percent. Zero means unlimited.
+// If the MBAPercent of the BE class is not configured in the NodeSLO, the limit applies on the full memory bandwidth,
+// which is restored after the limit is removed.
+func SetBEMBAPercentLimit(percent int64) {
+	beMBAPercentLimit.Store(percent)
+}
+
+// GetBEMBAPercentLimit returns the current limit of the MBA percent of the BE group.
+func GetBEMBAPercentLimit() int64 {
+	return beMBAPercentLimit.Load()
+}
+
+// getMbaPercentConfigForGroup returns the MBA percent of the group with the BE limit applied. If the MBA percent of the
+// BE group is not configured, it defaults to 100 (i.e. the full memory bandwidth) when the BE group is limited or
+// should be recovered from the limit, otherwise it keeps nil to leave the MBA unchanged.
+func getMbaPercentConfigForGroup(group string, mbaPercentConfig *int64, beLimited bool) *int64 {
+	if group != BEResctrlGroup {
+		return mbaPercentConfig
+	}
+	limit := beMBAPercentLimit.Load()
+	if mbaPercentConfig == nil {
+		if limit <= 0 && !beLimited {
+			return nil
+		}
+		mbaPercentConfig = ptr.To[int64](defaultBEMBAPercent)
+	}
+	if limit <= 0 || *mbaPercentConfig <= limit {
+		return mbaPercentConfig
+	}
+	return &limit
+}
+
 var _ framework.QOSStrategy = &resctrlReconcile{}
 
 type resctrlReconcile struct {
@@ -77,6 +119,8 @@ type resctrlReconcile struct {
 	metricCache       metriccache.MetricCache
 	cgroupReader      resourceexecutor.CgroupReader
 	eventRecorder     record.EventRecorder
+	// beMBALimited indicates the MBA of the BE group is applied with the BE limit
+	beMBALimited bool
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -327,13 +371,16 @@ func (r *resctrlReconcile) calculateAndApplyRDTL3PolicyForGroup(group string, cb
 }
 
 func (r *resctrlReconcile) calculateAndApplyRDTMbPolicyForGroup(group string, l3Num int, cpuBasicInfo extension.CPUBasicInfo, resourceQoS *slov1alpha1.ResourceQOS) error {
-	if resourceQoS == nil || resourceQoS.ResctrlQOS == nil {
+	var mbaPercentConfig *int64
+	if resourceQoS != nil && resourceQoS.ResctrlQOS != nil {
+		mbaPercentConfig = resourceQoS.ResctrlQOS.MBAPercent
+	} else if group != BEResctrlGroup {
 		klog.Warningf("skipped, since resourceQoS or ResctrlQOS is nil for group %v, "+
 			"resourceQoS %v", resourceQoS, group)
 		return nil
 	}
 
-	memBwPercent := calculateMbaPercentForGroup(group, resourceQoS.ResctrlQOS.MBAPercent, cpuBasicInfo)
+	memBwPercent := calculateMbaPercentForGroup(group, getMbaPercentConfigForGroup(group, mbaPercentConfig, r.beMBALimited), cpuBasicInfo)
 	if memBwPercent == "" {
 		return nil
 	}
@@ -352,6 +399,9 @@ func (r *resctrlReconcile) calculateAndApplyRDTMbPolicyForGroup(group string, l3
 		klog.V(6).Infof("apply mba policy for group %s finished, schemata %v, l3 number %v, isUpdated %v",
 			group, memBwPercent, l3Num, isUpdated)
 	}
+	if group == BEResctrlGroup {
+		r.beMBALimited = beMBAPercentLimit.Load() > 0
+	}
 
 	return nil
 }
@@ -384,7 +434,7 @@ func (r *resctrlReconcile) calculateAndApplyRDTL3GroupTasks(group string, taskId
 	return nil
 }
 
-func (r *resctrlReconcile) reconcileRDTResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
+func (r *resctrlReconcile) reconcileRDTResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) error {
 	// 1. retrieve rdt configs from nodeSLOSpec
 	// 2.1 get cbm and l3 numbers, which are general for all resctrl groups
 	// 2.2 calculate applying resctrl policies, like cat policy and so on, with each rdt config
@@ -395,7 +445,7 @@ func (r *resctrlReconcile) reconcileRDTResctrlPolicy(qosStrategy *slov1alpha1.Re
 	nodeCPUInfoRaw, exist := r.metricCache.Get(metriccache.NodeCPUInfoKey)
 	if !exist {
 		klog.Warning("failed to get nodeCPUInfo, not exist")
-		return
+		return fmt.Errorf("node cpu info not exist")
 	}
 	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
 	if !ok {
@@ -403,17 +453,17 @@ func (r *resctrlReconcile) reconcileRDTResctrlPolicy(qosStrategy *slov1alpha1.Re
 	}
 	if nodeCPUInfo == nil {
 		klog.Warning("failed to get nodeCPUInfo, the value is nil")
-		return
+		return fmt.Errorf("node cpu info is nil")
 	}
 	cbmStr := nodeCPUInfo.BasicInfo.CatL3CbmMask
 	if len(cbmStr) <= 0 {
 		klog.Warning("failed to get cat l3 cbm, cbm is empty")
-		return
+		return fmt.Errorf("cat l3 cbm is empty")
 	}
 	cbmValue, err := strconv.ParseUint(cbmStr, 16, 32)
 	if err != nil {
 		klog.Warningf("failed to parse cat l3 cbm %s, err: %v", cbmStr, err)
-		return
+		return fmt.Errorf("parse cat l3 cbm %s failed, err: %w", cbmStr, err)
 	}
 	cbm := uint(cbmValue)
 
@@ -421,24 +471,28 @@ func (r *resctrlReconcile) reconcileRDTResctrlPolicy(qosStrategy *slov1alpha1.Re
 	l3Num := len(nodeCPUInfo.TotalInfo.L3ToCPU)
 	if l3Num <= 0 {
 		klog.Warningf("failed to get the number of l3 caches, invalid value %v", l3Num)
-		return
+		return fmt.Errorf("invalid number of l3 caches %v", l3Num)
 	}
 
 	// calculate and apply l3 cat policy for each group
+	var errs []error
 	for _, group := range resctrlGroupList {
 		resQoSStrategy := getResourceQOSForResctrlGroup(qosStrategy, group)
 		err = r.calculateAndApplyRDTL3PolicyForGroup(group, cbm, l3Num, resQoSStrategy)
 		if err != nil {
 			klog.Warningf("failed to apply l3 cat policy for group %v, err: %v", group, err)
+			errs = append(errs, fmt.Errorf("apply l3 cat policy for group %s failed, err: %w", group, err))
 		}
 		err = r.calculateAndApplyRDTMbPolicyForGroup(group, l3Num, nodeCPUInfo.BasicInfo, resQoSStrategy)
 		if err != nil {
 			klog.Warningf("failed to apply cat MB policy for group %v, err: %v", group, err)
+			errs = append(errs, fmt.Errorf("apply cat MB policy for group %s failed, err: %w", group, err))
 		}
 	}
+	return utilerrors.NewAggregate(errs)
 }
 
-func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
+func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.ResourceQOSStrategy) error {
 	// 1. retrieve task ids for each slo by reading cgroup task file of every pod container
 	// 2. add the related task ids in resctrl groups
 
@@ -446,12 +500,14 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 	// the maximum pid on 32-bit/64-bit platforms is always less than 4194304, so the int type is bigger enough.
 	// here we only append the task ids which only appear in cgroup but not in resctrl to reduce resctrl writes
 	var err error
+	var errs []error
 
 	curTaskMaps := map[string]map[int32]struct{}{}
 	for _, group := range resctrlGroupList {
 		curTaskMaps[group], err = system.ReadResctrlTasksMap(group)
 		if err != nil {
 			klog.Warningf("failed to read Cat L3 tasks for resctrl group %s, err: %s", group, err)
+			errs = append(errs, fmt.Errorf("read l3 cat tasks for group %s failed, err: %w", group, err))
 		}
 	}
 
@@ -489,8 +545,10 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 		err = r.calculateAndApplyRDTL3GroupTasks(group, taskIds[group])
 		if err != nil {
 			klog.Warningf("failed to apply l3 cat tasks for group %s, err %s", group, err)
+			errs = append(errs, fmt.Errorf("apply l3 cat tasks for group %s failed, err: %w", group, err))
 		}
 	}
+	return utilerrors.NewAggregate(errs)
 }
 
 func (r *resctrlReconcile) reconcile() {
@@ -521,8 +579,16 @@ func (r *resctrlReconcile) reconcile() {
 
 	if err := initCatResctrl(); err != nil {
 		klog.V(4).Infof("resctrlReconcile failed, cannot initialize cat resctrl group, err: %s", err)
+		r.statesInformer.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameResctrlQOS, err)
 		return
 	}
-	r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
-	r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy)
+	// report the result after both the policies and the tasks are applied
+	var errs []error
+	if err := r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy); err != nil {
+		errs = append(errs, err)
+	}
+	r.statesInformer.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameResctrlQOS, utilerrors.NewAggregate(errs))
 }
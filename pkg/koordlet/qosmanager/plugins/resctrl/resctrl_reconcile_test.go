@@ -1170,7 +1170,8 @@ func TestResctrlReconcile_reconcileRDTResctrlPolicy(t *testing.T) {
 		defer func() { stop <- struct{}{} }()
 
 		// reconcile and check if the result is correct
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		err = r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		assert.NoError(t, err)
 
 		beSchemataPath := filepath.Join(resctrlDirPath, BEResctrlGroup, system.ResctrlSchemataName)
 		expectBESchemataStr := "L3:0=3f;1=3f;\n"
@@ -1189,7 +1190,8 @@ func TestResctrlReconcile_reconcileRDTResctrlPolicy(t *testing.T) {
 
 		// log error for invalid root resctrl path
 		system.Conf.SysFSRootDir = "invalidPath"
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		err = r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		assert.Error(t, err)
 		system.Conf.SysFSRootDir = validSysFSRootDir
 
 		// log error for invalid l3 number
@@ -1197,27 +1199,32 @@ func TestResctrlReconcile_reconcileRDTResctrlPolicy(t *testing.T) {
 			BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "7ff"},
 			TotalInfo: koordletutil.CPUTotalInfo{},
 		}, true).Times(1)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		err = r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		assert.Error(t, err)
 
 		// log error for invalid l3 cbm
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(&metriccache.NodeCPUInfo{
 			BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "invalid"},
 			TotalInfo: koordletutil.CPUTotalInfo{L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}, 1: {}}},
 		}, true).Times(1)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		err = r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		assert.Error(t, err)
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(&metriccache.NodeCPUInfo{
 			BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: ""},
 			TotalInfo: koordletutil.CPUTotalInfo{L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}, 1: {}}},
 		}, true).Times(1)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		err = r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		assert.Error(t, err)
 
 		// log error for invalid nodeCPUInfo
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(nil, false)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		err = r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		assert.Error(t, err)
 
 		// log error for get nodeCPUInfo failed
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(nil, false)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		err = r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		assert.Error(t, err)
 	})
 }
 
@@ -1418,6 +1425,7 @@ func TestResctrlReconcile_reconcile(t *testing.T) {
 		metricCache := mock_metriccache.NewMockMetricCache(ctrl)
 		statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testingPodMeta}).AnyTimes()
 		statesInformer.EXPECT().GetNodeSLO().Return(testingNodeSLO).AnyTimes()
+		statesInformer.EXPECT().SetNodeSLOStrategyResult(slov1alpha1.StrategyNameResctrlQOS, gomock.Any()).AnyTimes()
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(testingNodeCPUInfo, true).AnyTimes()
 		opt := &framework.Options{
 			StatesInformer: statesInformer,
@@ -1543,3 +1551,19 @@ func Test_calculateMbaPercentForGroup(t *testing.T) {
 		})
 	}
 }
+
+func Test_getMbaPercentConfigForGroup(t *testing.T) {
+	defer SetBEMBAPercentLimit(0)
+	assert.Equal(t, ptr.To[int64](100), getMbaPercentConfigForGroup(BEResctrlGroup, ptr.To[int64](100), false))
+	assert.Nil(t, getMbaPercentConfigForGroup(BEResctrlGroup, nil, false))
+	// recover the full memory bandwidth after the limit is removed
+	assert.Equal(t, ptr.To[int64](100), getMbaPercentConfigForGroup(BEResctrlGroup, nil, true))
+
+	SetBEMBAPercentLimit(30)
+	assert.Equal(t, int64(30), GetBEMBAPercentLimit())
+	assert.Equal(t, ptr.To[int64](30), getMbaPercentConfigForGroup(BEResctrlGroup, ptr.To[int64](100), false))
+	assert.Equal(t, ptr.To[int64](20), getMbaPercentConfigForGroup(BEResctrlGroup, ptr.To[int64](20), false))
+	assert.Equal(t, ptr.To[int64](30), getMbaPercentConfigForGroup(BEResctrlGroup, nil, false))
+	assert.Nil(t, getMbaPercentConfigForGroup(LSResctrlGroup, nil, false))
+	assert.Equal(t, ptr.To[int64](100), getMbaPercentConfigForGroup(LSResctrlGroup, ptr.To[int64](100), false))
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package sysreconcile
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
@@ -90,7 +92,14 @@ func (s *systemConfig) reconcile() {
 	var resources []resourceexecutor.ResourceUpdater
 	resources = append(resources, calculateMemoryConfig(nodeSLO.Spec.SystemStrategy, memoryCapacity)...)
 
-	s.executor.UpdateBatch(true, resources...)
+	var errs []error
+	for _, resource := range resources {
+		if _, err := s.executor.Update(true, resource); err != nil {
+			klog.V(4).Infof("failed to update system config %s to %v, err: %v", resource.Key(), resource.Value(), err)
+			errs = append(errs, fmt.Errorf("failed to update %s, err: %w", resource.Key(), err))
+		}
+	}
+	s.statesInformer.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameSystem, utilerrors.NewAggregate(errs))
 	klog.V(5).Infof("finish to reconcile system config!")
 }
 
@@ -177,6 +177,7 @@ func Test_systemConfig_reconcile(t *testing.T) {
 			mockstatesinformer := mock_statesinformer.NewMockStatesInformer(ctl)
 			mockstatesinformer.EXPECT().GetNode().Return(tt.node).AnyTimes()
 			mockstatesinformer.EXPECT().GetNodeSLO().Return(getNodeSLOBySystemStrategy(tt.newStrategy)).AnyTimes()
+			mockstatesinformer.EXPECT().SetNodeSLOStrategyResult(slov1alpha1.StrategyNameSystem, nil).AnyTimes()
 
 			reconcile := &systemConfig{
 				statesInformer: mockstatesinformer,
@@ -37,6 +37,7 @@ const (
 	ReleaseTargetTypeBatchResourceRequest ReleaseTargetType = "podBatchResourceRequest"
 	ReleaseTargetTypeResourceUsed         ReleaseTargetType = "podUsed"
 	ReleaseTargetTypeResourceRequest      ReleaseTargetType = "podResourceRequest"
+	ReleaseTargetTypePodCount             ReleaseTargetType = "podCount"
 	EvictedStr                                              = "evicted"
 	EvictReasonPrefix                                       = "trigger by koordlet feature "
 )
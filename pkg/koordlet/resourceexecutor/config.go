@@ -26,6 +26,10 @@ const (
 
 	EvictBEPodByNodeMemoryUsage = "EvictBEPodByNodeMemoryUsage"
 	AdjustBEByNodeCPUUsage      = "AdjustBEByNodeCPUUsage"
+	AdjustBEByPodPSI            = "AdjustBEByPodPSI"
+	AdjustBEByInterference      = "AdjustBEByInterference"
+	EvictBEPodByInterference    = "EvictBEPodByInterference"
+	AdjustBEByMemoryReclaim     = "AdjustBEByMemoryReclaim"
 )
 
 var Conf = NewDefaultConfig()
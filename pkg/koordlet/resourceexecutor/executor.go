@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
@@ -39,7 +40,8 @@ type ResourceUpdateExecutor interface {
 	// 1. update batch of cgroup resources group by cgroup interface, i.e. cgroup filename.
 	// 2. update each cgroup resource by the order of layers: firstly update resources from upper to lower by merging
 	//    the new value with old value; then update resources from lower to upper with the new value.
-	LeveledUpdateBatch(updaters [][]ResourceUpdater)
+	// It returns the aggregated errors of the resources failed to update.
+	LeveledUpdateBatch(updaters [][]ResourceUpdater) error
 	Run(stopCh <-chan struct{})
 }
 
@@ -111,15 +113,16 @@ func (e *ResourceUpdateExecutorImpl) UpdateBatch(cacheable bool, updaters ...Res
 		cacheable, len(updaters), failures)
 }
 
-func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpdater) {
+func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpdater) error {
 	e.LeveledUpdateLock.Lock()
 	defer e.LeveledUpdateLock.Unlock()
 	if !e.gcStarted {
 		klog.Error("failed to cacheable level update resources, err: cache GC is not started")
-		return
+		return fmt.Errorf("cache GC is not started")
 	}
 
 	var err error
+	var errs []error
 	skipMerge := map[string]bool{}
 	for i := 0; i < len(updaters); i++ {
 		for _, updater := range updaters[i] {
@@ -173,6 +176,7 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 			}
 			if err != nil {
 				klog.V(4).Infof("failed update resource %s, err: %v", updater.Key(), err)
+				errs = append(errs, fmt.Errorf("update resource %s failed, err: %w", updater.Key(), err))
 				continue
 			}
 			klog.V(6).Infof("successfully update resource %s to %v", updater.Key(), updater.Value())
@@ -185,6 +189,8 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 			}
 		}
 	}
+
+	return utilerrors.NewAggregate(errs)
 }
 
 // Run runs the ResourceUpdateExecutor.
@@ -75,9 +75,10 @@ type Plugin struct {
 	cookieCacheRWMutex sync.RWMutex
 	groupCache         *gocache.Cache // pod-uid+container-id -> core-sched-group-id (note that it caches the last state); if the container has had cookie of the group
 
-	reader   resourceexecutor.CgroupReader
-	executor resourceexecutor.ResourceUpdateExecutor
-	cse      sysutil.CoreSchedExtendedInterface
+	reader         resourceexecutor.CgroupReader
+	executor       resourceexecutor.ResourceUpdateExecutor
+	cse            sysutil.CoreSchedExtendedInterface
+	statesInformer statesinformer.StatesInformer
 }
 
 var singleton *Plugin
@@ -126,6 +127,7 @@ func (p *Plugin) Setup(op hooks.Options) {
 	p.reader = op.Reader
 	p.executor = op.Executor
 	p.cse = sysutil.NewCoreSchedExtended()
+	p.statesInformer = op.StatesInformer
 }
 
 func (p *Plugin) SystemSupported() bool {
@@ -23,6 +23,7 @@ import (
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
@@ -200,6 +201,7 @@ func (p *Plugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 
 	if err := p.initSystem(p.rule.IsEnabled()); err != nil {
 		klog.Warningf("plugin %s failed to initialize system, err: %s", name, err)
+		p.reportResult(fmt.Errorf("initialize system failed, err: %w", err))
 		return nil
 	}
 	klog.V(6).Infof("plugin %s initialize system successfully", name)
@@ -209,10 +211,21 @@ func (p *Plugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 		return nil
 	}
 
-	return p.refreshForAllPods(podMetas)
+	// the failures of the pods are logged and reported in the strategy result rather than failing the callback
+	p.reportResult(p.refreshForAllPods(podMetas))
+	return nil
+}
+
+// reportResult reports the apply result of the CPU QoS when the core scheduling is the enabled policy.
+func (p *Plugin) reportResult(err error) {
+	if p.statesInformer == nil || !p.rule.IsEnabled() {
+		return
+	}
+	p.statesInformer.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameCPUQOS, err)
 }
 
 func (p *Plugin) refreshForAllPods(podMetas []*statesinformer.PodMeta) error {
+	var errs []error
 	for _, kubeQOS := range []corev1.PodQOSClass{
 		corev1.PodQOSGuaranteed, corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
 		kubeQOSCtx := &protocol.KubeQOSContext{}
@@ -220,6 +233,7 @@ func (p *Plugin) refreshForAllPods(podMetas []*statesinformer.PodMeta) error {
 
 		if err := p.SetKubeQOSCPUIdle(kubeQOSCtx); err != nil {
 			klog.V(4).Infof("callback %s set cpu idle for kube qos %s failed, err: %v", name, kubeQOS, err)
+			errs = append(errs, fmt.Errorf("set cpu idle for kube qos %s failed, err: %w", kubeQOS, err))
 		} else {
 			kubeQOSCtx.ReconcilerDone(p.executor)
 			klog.V(5).Infof("callback %s set cpu idle for kube qos %s finished", name, kubeQOS)
@@ -248,6 +262,7 @@ func (p *Plugin) refreshForAllPods(podMetas []*statesinformer.PodMeta) error {
 		sandboxContainerCtx.FromReconciler(podMeta, "", true)
 		if err := p.SetContainerCookie(sandboxContainerCtx); err != nil {
 			klog.Warningf("failed to set core sched cookie for pod sandbox %v, err: %s", podMeta.Key(), err)
+			errs = append(errs, fmt.Errorf("set core sched cookie for pod sandbox %s failed, err: %w", podMeta.Key(), err))
 		} else {
 			klog.V(5).Infof("set core sched cookie for pod sandbox %v finished", podMeta.Key())
 		}
@@ -259,6 +274,8 @@ func (p *Plugin) refreshForAllPods(podMetas []*statesinformer.PodMeta) error {
 			if err := p.SetContainerCookie(containerCtx); err != nil {
 				klog.Warningf("failed to set core sched cookie for container %s/%s, err: %s",
 					podMeta.Key(), containerStat.Name, err)
+				errs = append(errs, fmt.Errorf("set core sched cookie for container %s/%s failed, err: %w",
+					podMeta.Key(), containerStat.Name, err))
 				continue
 			} else {
 				klog.V(5).Infof("set core sched cookie for container %s/%s finished",
@@ -267,5 +284,5 @@ func (p *Plugin) refreshForAllPods(podMetas []*statesinformer.PodMeta) error {
 		}
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
 }
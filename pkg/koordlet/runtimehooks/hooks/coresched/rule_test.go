@@ -17,9 +17,11 @@ limitations under the License.
 package coresched
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +31,7 @@ import (
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
@@ -1700,6 +1703,28 @@ func Test_ruleUpdateCb(t *testing.T) {
 	}
 }
 
+func TestPlugin_reportResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+
+	// skip if the states informer is not set
+	p := newPlugin()
+	p.rule = testGetEnabledRule()
+	p.reportResult(nil)
+
+	// skip if the core sched is disabled
+	p.statesInformer = si
+	p.rule = testGetDisabledRule()
+	p.reportResult(nil)
+
+	// report if the core sched is enabled
+	p.rule = testGetEnabledRule()
+	testErr := fmt.Errorf("expected error")
+	si.EXPECT().SetNodeSLOStrategyResult(slov1alpha1.StrategyNameCPUQOS, testErr).Times(1)
+	p.reportResult(testErr)
+}
+
 func testGetDisabledRuleParam() Param {
 	return Param{}
 }
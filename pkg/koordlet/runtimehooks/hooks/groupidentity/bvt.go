@@ -47,7 +47,8 @@ type bvtPlugin struct {
 	hasKernelEnabled         *bool // whether kernel is configurable for enabling bvt (via `kernel.sched_group_identity_enabled`)
 	coreSchedSysctlSupported *bool // whether core sched is supported by the sysctl
 
-	executor resourceexecutor.ResourceUpdateExecutor
+	executor       resourceexecutor.ResourceUpdateExecutor
+	statesInformer statesinformer.StatesInformer
 }
 
 func (b *bvtPlugin) Register(op hooks.Options) {
@@ -64,6 +65,7 @@ func (b *bvtPlugin) Register(op hooks.Options) {
 	reconciler.RegisterHostAppReconciler(sysutil.CPUBVTWarpNs, "reconcile host application cpu bvt value",
 		b.SetHostAppBvtValue, &reconciler.ReconcilerOption{})
 	b.executor = op.Executor
+	b.statesInformer = op.StatesInformer
 }
 
 func (b *bvtPlugin) SystemSupported() bool {
@@ -22,6 +22,7 @@ import (
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
 	ext "github.com/koordinator-sh/koordinator/apis/extension"
@@ -165,6 +166,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 	if isEnabled {
 		if err := b.initSysctl(); err != nil {
 			klog.Warningf("failed to initialize system config for plugin %s, err: %s", name, err)
+			b.reportResult(fmt.Errorf("initialize system config failed, err: %w", err))
 			return nil
 		}
 	} else {
@@ -179,6 +181,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 		}
 	}
 
+	var errs []error
 	qosCgroupMap := map[string]struct{}{}
 	for _, kubeQOS := range []corev1.PodQOSClass{
 		corev1.PodQOSGuaranteed, corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
@@ -192,6 +195,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 		}
 		if _, err = b.executor.Update(true, bvtUpdater); err != nil {
 			klog.Infof("update kube qos %v cpu bvt failed, dir %v, error %v", kubeQOS, kubeQOSCgroupPath, err)
+			errs = append(errs, fmt.Errorf("update kube qos %s cpu bvt failed, err: %w", kubeQOS, err))
 		}
 		qosCgroupMap[kubeQOSCgroupPath] = struct{}{}
 	}
@@ -236,6 +240,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 		if _, err = b.executor.Update(true, bvtUpdater); err != nil {
 			klog.Infof("update pod %s cpu bvt failed, dir %v, error %v",
 				util.GetPodKey(podMeta.Pod), podCgroupPath, err)
+			errs = append(errs, fmt.Errorf("update pod %s cpu bvt failed, err: %w", util.GetPodKey(podMeta.Pod), err))
 		}
 		delete(podCgroupMap, podCgroupPath)
 
@@ -255,6 +260,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 			}
 			if _, err = b.executor.Update(true, bvtUpdater); err != nil {
 				klog.Infof("update container cpu bvt failed, dir %v, error %v", cgroupDir, err)
+				errs = append(errs, fmt.Errorf("update container cpu bvt failed, dir %s, err: %w", cgroupDir, err))
 			}
 		}
 	}
@@ -262,6 +268,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 		hostCtx := protocol.HooksProtocolBuilder.HostApp(&hostApp)
 		if err := b.SetHostAppBvtValue(hostCtx); err != nil {
 			klog.Warningf("set host application %v bvt value failed, error %v", hostApp.Name, err)
+			errs = append(errs, fmt.Errorf("set host application %s bvt value failed, err: %w", hostApp.Name, err))
 		} else {
 			hostCtx.ReconcilerDone(b.executor)
 			klog.V(5).Infof("set host application %v bvt value finished", hostApp.Name)
@@ -297,9 +304,21 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 		}
 	}
 
+	b.reportResult(utilerrors.NewAggregate(errs))
 	return nil
 }
 
+// reportResult reports the apply result of the CPU QoS when the group identity is the enabled policy.
+func (b *bvtPlugin) reportResult(err error) {
+	if b.statesInformer == nil {
+		return
+	}
+	if r := b.getRule(); r == nil || !r.getEnable() {
+		return
+	}
+	b.statesInformer.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameCPUQOS, err)
+}
+
 func (b *bvtPlugin) getRule() *bvtRule {
 	b.ruleRWMutex.RLock()
 	defer b.ruleRWMutex.RUnlock()
@@ -17,11 +17,13 @@ limitations under the License.
 package groupidentity
 
 import (
+	"fmt"
 	"path/filepath"
 	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
@@ -30,6 +32,7 @@ import (
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
@@ -1075,6 +1078,28 @@ func Test_bvtPlugin_ruleUpdateCbForPods(t *testing.T) {
 	}
 }
 
+func Test_bvtPlugin_reportResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+
+	// skip if the states informer is not set
+	b := &bvtPlugin{rule: &bvtRule{enable: true}}
+	b.reportResult(nil)
+
+	// skip if the rule is nil or the group identity is disabled
+	b = &bvtPlugin{statesInformer: si}
+	b.reportResult(nil)
+	b.rule = &bvtRule{enable: false}
+	b.reportResult(nil)
+
+	// report if the group identity is enabled
+	b.rule = &bvtRule{enable: true}
+	testErr := fmt.Errorf("expected error")
+	si.EXPECT().SetNodeSLOStrategyResult(slov1alpha1.StrategyNameCPUQOS, testErr).Times(1)
+	b.reportResult(testErr)
+}
+
 func Test_bvtPlugin_ruleUpdateCbForHostApp(t *testing.T) {
 	type fields struct {
 		rule *bvtRule
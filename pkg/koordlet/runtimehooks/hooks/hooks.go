@@ -44,6 +44,8 @@ type Options struct {
 	StatesInformer                   statesinformer.StatesInformer
 	EventRecorder                    record.EventRecorder
 	DisableUnsetCPUQuotaForCPUSetPod bool
+	// StopCh is closed when the runtime hook stops, so the plugins can stop their background routines.
+	StopCh <-chan struct{}
 }
 
 type HookFn func(protocol.HooksProtocol) error
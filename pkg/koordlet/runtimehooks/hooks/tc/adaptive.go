@@ -0,0 +1,177 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tc
+
+import (
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/klog/v2"
+)
+
+const (
+	ADAPTIVE_BE_CEIL_INTERVAL = 10 * time.Second
+	// the bandwidth reserved for the LS class is the measured LS throughput plus the headroom percentage
+	ADAPTIVE_BE_CEIL_HEADROOM_PERCENTAGE = 10
+	// the BE ceil is not changed if the difference is less than the percentage of the total bandwidth
+	ADAPTIVE_BE_CEIL_TOLERANCE_PERCENTAGE = 5
+	// the BE ceil is no less than the percentage of the total bandwidth, so the BE traffic is never stopped
+	ADAPTIVE_BE_CEIL_FLOOR_PERCENTAGE = 1
+)
+
+// classBytes records the sent bytes of a class at a time.
+type classBytes struct {
+	bytes     uint64
+	timestamp time.Time
+}
+
+// adjustBECeil raises or lowers the ceil of the BE class according to the LS throughput measured from the class
+// statistics, so that the BE class can borrow the bandwidth left by the LS class, and it gives the bandwidth back
+// when the LS traffic grows.
+func (p *tcPlugin) adjustBECeil() {
+	p.linkMutex.Lock()
+	defer p.linkMutex.Unlock()
+	r := p.getRule()
+	if r == nil || !r.enable || !r.adaptiveBECeil || r.netCfg == nil || p.interfLink == nil || p.lsClassBytes == nil {
+		p.lsClassBytes = map[string]*classBytes{}
+		return
+	}
+
+	p.adjustBECeilForLink(p.interfLink, r.netCfg.HwTxBpsMax, r.netCfg.L2TxBpsMin, r.netCfg.L2TxBpsMax)
+	if !r.ingress {
+		return
+	}
+	ifbLink, err := netlink.LinkByName(IFB_INTERFACE_NAME)
+	if err != nil {
+		klog.V(5).Infof("failed to get ifb device %s for adjusting be ceil, err=%v", IFB_INTERFACE_NAME, err)
+		return
+	}
+	p.adjustBECeilForLink(ifbLink, r.netCfg.HwRxBpsMax, r.netCfg.L2RxBpsMin, r.netCfg.L2RxBpsMax)
+}
+
+func (p *tcPlugin) adjustBECeilForLink(link netlink.Link, total, beMin, beMax uint64) {
+	linkName := link.Attrs().Name
+	classes, err := netlink.ClassList(link, 0)
+	if err != nil {
+		klog.V(4).Infof("failed to get tc class on %s, err=%v", linkName, err)
+		return
+	}
+	var lsHtbClass, beHtbClass *netlink.HtbClass
+	for _, class := range classes {
+		htbClass, ok := class.(*netlink.HtbClass)
+		if !ok || htbClass.Parent != rootClass {
+			continue
+		}
+		switch htbClass.Handle {
+		case lsClass:
+			lsHtbClass = htbClass
+		case beClass:
+			beHtbClass = htbClass
+		}
+	}
+	if lsHtbClass == nil || beHtbClass == nil || lsHtbClass.Statistics == nil || lsHtbClass.Statistics.Basic == nil {
+		klog.V(5).Infof("ls or be class not found on %s, skip adjusting be ceil", linkName)
+		return
+	}
+
+	cur := &classBytes{bytes: lsHtbClass.Statistics.Basic.Bytes, timestamp: time.Now()}
+	last := p.lsClassBytes[linkName]
+	p.lsClassBytes[linkName] = cur
+	lsThroughput, ok := calculateThroughput(last, cur)
+	if !ok {
+		return
+	}
+
+	currentCeil := htbClassCeil(beHtbClass)
+	expectCeil := calculateBECeil(total, lsThroughput, beMin, beMax)
+	if absDiff(expectCeil, currentCeil) < total*ADAPTIVE_BE_CEIL_TOLERANCE_PERCENTAGE/100 {
+		return
+	}
+	if err := p.ensureClass(link, newClass(link.Attrs().Index, rootClass, beClass, beMin, expectCeil, BE_CLASS_PRIO)); err != nil {
+		klog.Warningf("failed to adjust be ceil on %s, err=%v", linkName, err)
+		return
+	}
+	klog.V(4).Infof("adjust be ceil on %s from %d to %d, ls throughput %d, total %d", linkName, currentCeil, expectCeil, lsThroughput, total)
+}
+
+// getBECeil returns the ceil in bits of the BE class on the link.
+func getBECeil(link netlink.Link) (uint64, bool) {
+	classes, err := netlink.ClassList(link, 0)
+	if err != nil {
+		return 0, false
+	}
+	for _, class := range classes {
+		htbClass, ok := class.(*netlink.HtbClass)
+		if ok && htbClass.Parent == rootClass && htbClass.Handle == beClass {
+			return htbClassCeil(htbClass), true
+		}
+	}
+	return 0, false
+}
+
+// htbClassCeil returns the ceil of the htb class in bits, since the ceil of the htb class is in bytes.
+func htbClassCeil(class *netlink.HtbClass) uint64 {
+	return BytesToBits(uint64(class.Ceil))
+}
+
+// calculateThroughput returns the throughput in bits per second between the two records.
+func calculateThroughput(last, cur *classBytes) (uint64, bool) {
+	if last == nil || cur == nil || cur.bytes < last.bytes {
+		// the counter is reset when the class is recreated
+		return 0, false
+	}
+	duration := cur.timestamp.Sub(last.timestamp).Seconds()
+	if duration <= 0 {
+		return 0, false
+	}
+	return uint64(float64(BytesToBits(cur.bytes-last.bytes)) / duration), true
+}
+
+// calculateBECeil returns the BE ceil which leaves the total bandwidth minus the LS throughput with headroom to the BE,
+// and it is kept between the BE request and limit.
+func calculateBECeil(total, lsThroughput, beMin, beMax uint64) uint64 {
+	if beMax <= 0 || beMax > total {
+		beMax = total
+	}
+	floor := total * ADAPTIVE_BE_CEIL_FLOOR_PERCENTAGE / 100
+	if beMin > floor {
+		floor = beMin
+	}
+
+	reserved := lsThroughput * (100 + ADAPTIVE_BE_CEIL_HEADROOM_PERCENTAGE) / 100
+	var ceil uint64
+	if reserved < total {
+		ceil = total - reserved
+	}
+	if ceil > beMax {
+		ceil = beMax
+	}
+	if ceil < floor {
+		ceil = floor
+	}
+	return ceil
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
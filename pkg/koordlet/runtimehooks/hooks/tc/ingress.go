@@ -0,0 +1,244 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	apierror "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/exec"
+)
+
+const (
+	// IFB_INTERFACE_NAME is the ifb device which the ingress traffic of the physical NIC is redirected to.
+	IFB_INTERFACE_NAME = "koord-ifb0"
+	IFB_TX_QUEUE_LEN   = 1000
+
+	INGRESS_QDISC_MAJOR_ID = 0xffff
+	INGRESS_FILTER_PRIO    = 1
+)
+
+var ipsetToClassMinorID = map[NetQoSClass]int{
+	NETQoSSystem: SYSTEM_CLASS_MINOR_ID,
+	NETQoSLS:     LS_CLASS_MINOR_ID,
+	NETQoSBE:     BE_CLASS_MINOR_ID,
+}
+
+// EnsureIngress shapes the ingress traffic with the same class model as the egress.
+// Since the qdisc can only shape the egress traffic, the ingress traffic of the physical NIC is redirected to an ifb
+// device, where the system/LS/BE classes are built with the ingress bandwidth config. The packets are classified by
+// matching the destination ip with the ipsets of the net qos classes.
+func (p *tcPlugin) EnsureIngress() error {
+	klog.V(5).Infof("start to create ingress rules through ifb device %s.", IFB_INTERFACE_NAME)
+	r := p.getRule()
+	if r == nil {
+		klog.V(5).Infof("hook plugin rule is nil, nothing to do for plugin %v", name)
+		return nil
+	}
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
+
+	ifbLink, err := p.ensureIfbLink()
+	if err != nil {
+		return err
+	}
+
+	if err = p.ensureIngressQdisc(); err != nil {
+		return err
+	}
+	// tc filter add dev eth0 parent ffff: protocol ip prio 1 u32 match u32 0 0 action mirred egress redirect dev koord-ifb0
+	redirectCmd := exec.New().Command("tc", "filter", "add", "dev", link.Attrs().Name,
+		"parent", fmt.Sprintf("%x:", INGRESS_QDISC_MAJOR_ID),
+		"protocol", "ip", "prio", strconv.Itoa(INGRESS_FILTER_PRIO),
+		"u32", "match", "u32", "0", "0",
+		"action", "mirred", "egress", "redirect", "dev", IFB_INTERFACE_NAME,
+	)
+	if err = ensureFilterOnParent(link.Attrs().Name, fmt.Sprintf("%x:", INGRESS_QDISC_MAJOR_ID),
+		KeyByRedirectDev(IFB_INTERFACE_NAME), redirectCmd); err != nil {
+		return err
+	}
+
+	if err = p.ensureHtbQdisc(ifbLink); err != nil {
+		return err
+	}
+
+	errs := []error{
+		p.ensureClassesForLink(ifbLink, r.adaptiveBECeil, r.netCfg.HwRxBpsMax, r.netCfg.L1RxBpsMin,
+			r.netCfg.L1RxBpsMax, r.netCfg.L2RxBpsMin, r.netCfg.L2RxBpsMax),
+	}
+	for _, setName := range ipsets {
+		minor := ipsetToClassMinorID[NetQoSClass(setName)]
+		// tc filter add dev koord-ifb0 parent 1: protocol ip prio 2 basic match 'ipset(ls_class dst)' flowid 1:3
+		filterCmd := exec.New().Command("tc", "filter", "add", "dev", IFB_INTERFACE_NAME,
+			"parent", fmt.Sprintf("%d:", ROOT_CLASS_MINOR_ID),
+			"protocol", "ip", "prio", strconv.Itoa(int(GetPrio(NetQoSClass(setName)))),
+			"basic", "match", fmt.Sprintf("ipset(%s dst)", setName),
+			"flowid", fmt.Sprintf("%d:%d", MAJOR_ID, minor),
+		)
+		errs = append(errs, ensureFilterOnParent(IFB_INTERFACE_NAME, fmt.Sprintf("%d:", ROOT_CLASS_MINOR_ID),
+			KeyByFlowId(strconv.FormatInt(int64(minor), 16)), filterCmd))
+	}
+
+	return apierror.NewAggregate(errs)
+}
+
+// DelIngress deletes the redirect filter on the physical NIC and the ifb device, so the classes and filters on the ifb
+// device are removed too. The ingress qdisc is kept since it may be shared with other components.
+func (p *tcPlugin) DelIngress() error {
+	link, err := netlink.LinkByName(IFB_INTERFACE_NAME)
+	if err != nil {
+		var notFoundErr netlink.LinkNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to get ifb device %s, err=%v", IFB_INTERFACE_NAME, err)
+	}
+	nic, err := p.getLink()
+	if err != nil {
+		return err
+	}
+	klog.V(5).Infof("start to delete ingress rules created by tc plugin.")
+
+	parent := fmt.Sprintf("%x:", INGRESS_QDISC_MAJOR_ID)
+	delFilterCmd := exec.New().Command("tc", "filter", "delete", "dev", nic.Attrs().Name,
+		"parent", parent, "prio", strconv.Itoa(INGRESS_FILTER_PRIO),
+	)
+	errs := []error{deleteFilterOnParent(nic.Attrs().Name, parent, KeyByRedirectDev(IFB_INTERFACE_NAME), delFilterCmd)}
+	if err := netlink.LinkDel(link); err != nil {
+		errs = append(errs, fmt.Errorf("failed to delete ifb device %s, err=%v", IFB_INTERFACE_NAME, err))
+	}
+
+	return apierror.NewAggregate(errs)
+}
+
+func (p *tcPlugin) ensureIfbLink() (netlink.Link, error) {
+	link, err := netlink.LinkByName(IFB_INTERFACE_NAME)
+	if err != nil {
+		var notFoundErr netlink.LinkNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			return nil, fmt.Errorf("failed to get ifb device %s, err=%v", IFB_INTERFACE_NAME, err)
+		}
+		ifb := &netlink.Ifb{
+			LinkAttrs: netlink.LinkAttrs{
+				Name:   IFB_INTERFACE_NAME,
+				TxQLen: IFB_TX_QUEUE_LEN,
+			},
+		}
+		if err = netlink.LinkAdd(ifb); err != nil {
+			return nil, fmt.Errorf("failed to create ifb device %s, err=%v", IFB_INTERFACE_NAME, err)
+		}
+		klog.V(4).Infof("succeed to create ifb device %s", IFB_INTERFACE_NAME)
+		if link, err = netlink.LinkByName(IFB_INTERFACE_NAME); err != nil {
+			return nil, fmt.Errorf("failed to get ifb device %s, err=%v", IFB_INTERFACE_NAME, err)
+		}
+	}
+
+	if link.Attrs().Flags&net.FlagUp == 0 {
+		if err = netlink.LinkSetUp(link); err != nil {
+			return nil, fmt.Errorf("failed to set up ifb device %s, err=%v", IFB_INTERFACE_NAME, err)
+		}
+	}
+
+	return link, nil
+}
+
+func (p *tcPlugin) ensureIngressQdisc() error {
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
+	ingress := &netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(INGRESS_QDISC_MAJOR_ID, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}
+
+	qdiscs, err := p.netLinkHandler.QdiscList(link)
+	if err != nil {
+		return fmt.Errorf("failed to get qdisc. err=%v", err)
+	}
+	for _, qdisc := range qdiscs {
+		if qdisc.Type() == "ingress" {
+			return nil
+		}
+	}
+
+	if err := p.netLinkHandler.QdiscAdd(ingress); err != nil {
+		return fmt.Errorf("failed to create ingress qdisc on %s, err=%v", link.Attrs().Name, err)
+	}
+	return nil
+}
+
+// ensureFilterOnParent creates the filter on the parent of the device if no filter matches the key.
+func ensureFilterOnParent(dev, parent, find string, createCmd exec.Cmd) error {
+	data, err := exec.New().Command("tc", "filter", "show", "dev", dev, "parent", parent).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to get tc filter by key:%s, err:%v", find, err)
+	}
+
+	if strings.Contains(string(data), find) {
+		return nil
+	}
+
+	data, err = createCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create tc filter, output: %s, err: %v", string(data), err)
+	}
+	klog.V(5).Infof("%s created filter on parent %s", dev, parent)
+
+	return nil
+}
+
+// deleteFilterOnParent deletes the filter on the parent of the device if any filter matches the key.
+func deleteFilterOnParent(dev, parent, find string, delCmd exec.Cmd) error {
+	data, err := exec.New().Command("tc", "filter", "show", "dev", dev, "parent", parent).CombinedOutput()
+	if err != nil {
+		// the parent qdisc does not exist
+		return nil
+	}
+
+	if !strings.Contains(string(data), find) {
+		return nil
+	}
+
+	data, err = delCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete tc filter, output: %s, err: %v", string(data), err)
+	}
+	klog.V(5).Infof("%s deleted filter on parent %s", dev, parent)
+
+	return nil
+}
+
+func KeyByRedirectDev(dev string) string {
+	// looks like this one:
+	// action order 1: mirred (Egress Redirect to device koord-ifb0) stolen
+	return "Redirect to device " + dev
+}
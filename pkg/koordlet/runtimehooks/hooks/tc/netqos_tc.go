@@ -122,3 +122,7 @@ func getIngressAndEgress(anno map[string]string) (uint64, uint64, error) {
 func BitsToBytes[T uint64 | float64 | int](bits T) T {
 	return bits / 8
 }
+
+func BytesToBits[T uint64 | float64 | int](bytes T) T {
+	return bytes * 8
+}
@@ -26,6 +26,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
@@ -33,11 +34,13 @@ import (
 )
 
 type tcRule struct {
-	enable      bool
-	netCfg      *NetQosGlobalConfig
-	speed       uint64
-	uidToHandle map[types.UID]uint32
-	handleToUid map[uint32]types.UID
+	enable         bool
+	netCfg         *NetQosGlobalConfig
+	speed          uint64
+	ingress        bool
+	adaptiveBECeil bool
+	uidToHandle    map[types.UID]uint32
+	handleToUid    map[uint32]types.UID
 }
 
 func newRule() *tcRule {
@@ -89,6 +92,8 @@ func (p *tcPlugin) parseRuleForNodeSLO(mergedNodeSLOIf interface{}) (bool, error
 		newRule.enable = true
 		newRule.speed = uint64(mergedNodeSLO.SystemStrategy.TotalNetworkBandwidth.Value())
 		newRule.netCfg = loadConfigFromNodeSlo(mergedNodeSLO)
+		newRule.ingress = ptr.Deref(mergedNodeSLO.SystemStrategy.NetworkIngressShaping, false)
+		newRule.adaptiveBECeil = ptr.Deref(mergedNodeSLO.SystemStrategy.NetworkAdaptiveBECeil, false)
 	} else {
 		newRule.enable = false
 	}
@@ -35,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	apierror "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubelet/util/format"
 	"k8s.io/utils/exec"
@@ -101,6 +102,9 @@ type tcPlugin struct {
 	ruleRWMutex sync.RWMutex
 	rule        *tcRule
 
+	// linkMutex guards the interfLink and lsClassBytes, which are accessed by both the rule callbacks and the
+	// adaptive BE ceil routine.
+	linkMutex sync.Mutex
 	// this is the physical NIC on host, default eth0
 	interfLink netlink.Link
 
@@ -112,6 +116,9 @@ type tcPlugin struct {
 	allPodsSyncOnce sync.Once
 
 	executor resourceexecutor.ResourceUpdateExecutor
+
+	// lsClassBytes records the sent bytes of the LS class on each link for adjusting the BE ceil.
+	lsClassBytes map[string]*classBytes
 }
 
 var singleton *tcPlugin
@@ -129,6 +136,7 @@ func newPlugin() *tcPlugin {
 		netLinkHandler:  netlink.Handle{},
 		allPodsSyncOnce: sync.Once{},
 		rule:            newRule(),
+		lsClassBytes:    map[string]*classBytes{},
 	}
 }
 
@@ -149,6 +157,8 @@ func (p *tcPlugin) Register(op hooks.Options) {
 		p.SetPodNetCls, reconciler.NoneFilter())
 
 	p.executor = op.Executor
+
+	go wait.Until(p.adjustBECeil, ADAPTIVE_BE_CEIL_INTERVAL, op.StopCh)
 }
 
 func (p *tcPlugin) SetPodNetCls(proto protocol.HooksProtocol) error {
@@ -201,10 +211,14 @@ func (p *tcPlugin) SetPodNetCls(proto protocol.HooksProtocol) error {
 }
 
 func (p *tcPlugin) createTcRulesForHostPod(rule *tcRule, pod *v1.Pod, egress uint64) error {
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
 	handle, _ := rule.uidToHandle[pod.UID]
 	netqos := GetNetQoSClassByAttrs(pod.Labels, pod.Annotations)
-	cls := newClass(p.interfLink.Attrs().Index, rootClass, handle, egress, egress, GetPrio(netqos))
-	err := p.ensureClass(p.interfLink, cls)
+	cls := newClass(link.Attrs().Index, rootClass, handle, egress, egress, GetPrio(netqos))
+	err = p.ensureClass(link, cls)
 	if err != nil {
 		return err
 	}
@@ -214,8 +228,8 @@ func (p *tcPlugin) createTcRulesForHostPod(rule *tcRule, pod *v1.Pod, egress uin
 	genFilterCmd := func(op string) exec.Cmd {
 		// tc filter add dev eth0 parent 1: protocol ip prio 2 handle 5: cgroup
 		// means to 1:5 class
-		return exec.New().Command("tc", "filter", op, "dev", p.interfLink.Attrs().Name,
-			"protocol", "ip", "prio", strconv.Itoa(getPrio(p.interfLink.Attrs().Name)),
+		return exec.New().Command("tc", "filter", op, "dev", link.Attrs().Name,
+			"protocol", "ip", "prio", strconv.Itoa(getPrio(link.Attrs().Name)),
 			"parent", fmt.Sprintf("%d:", ROOT_CLASS_MINOR_ID),
 			"handle", fmt.Sprintf("%d:", minorDecimal),
 			"cgroup",
@@ -226,12 +240,16 @@ func (p *tcPlugin) createTcRulesForHostPod(rule *tcRule, pod *v1.Pod, egress uin
 }
 
 func (p *tcPlugin) delTcRules(handle uint32) error {
-	prio := getFilterPrio(p.interfLink.Attrs().Name, handle)
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
+	prio := getFilterPrio(link.Attrs().Name, handle)
 	if prio == "" {
 		return nil
 	}
 
-	delFilterCmd := exec.New().Command("tc", "filter", "delete", "dev", p.interfLink.Attrs().Name,
+	delFilterCmd := exec.New().Command("tc", "filter", "delete", "dev", link.Attrs().Name,
 		"parent", fmt.Sprintf("%d:", ROOT_CLASS_MINOR_ID), "prio", prio,
 	)
 	data, err := delFilterCmd.CombinedOutput()
@@ -239,8 +257,8 @@ func (p *tcPlugin) delTcRules(handle uint32) error {
 		return fmt.Errorf("failed to delete tc filter, output: %s, err: %v", string(data), err)
 	}
 
-	cls := newClass(p.interfLink.Attrs().Index, rootClass, handle, 0, 0, 0)
-	return p.deleteClass(p.interfLink, cls)
+	cls := newClass(link.Attrs().Index, rootClass, handle, 0, 0, 0)
+	return p.deleteClass(link, cls)
 }
 
 func getFilterPrio(nic string, handle uint32) string {
@@ -292,7 +310,9 @@ func (p *tcPlugin) prepare() error {
 		return fmt.Errorf("link info is nil")
 	}
 
+	p.linkMutex.Lock()
 	p.interfLink = linkInfo
+	p.linkMutex.Unlock()
 
 	ipt, err := iptables.New()
 	if err != nil {
@@ -304,6 +324,17 @@ func (p *tcPlugin) prepare() error {
 	return nil
 }
 
+// getLink returns the physical NIC snapshot taken under the linkMutex, since prepare may replace the link while the
+// rules are being reconciled.
+func (p *tcPlugin) getLink() (netlink.Link, error) {
+	p.linkMutex.Lock()
+	defer p.linkMutex.Unlock()
+	if p.interfLink == nil || p.interfLink.Attrs() == nil {
+		return nil, fmt.Errorf("link info is nil")
+	}
+	return p.interfLink, nil
+}
+
 func getMinorId(num uint32) string {
 	minor := num - MAJOR_ID<<16
 	return strconv.FormatUint(uint64(minor), 16)
@@ -410,11 +441,15 @@ func (p *tcPlugin) refreshForAllPods(pods []*statesinformer.PodMeta, rule *tcRul
 }
 
 func (p *tcPlugin) createRulesForPod(rule *tcRule, pod *v1.Pod, netqos NetQoSClass, egress uint64) error {
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
 	klog.V(5).Infof("start to create related rules for pod(uid:%s; ip:%s), anno:%v", pod.UID, pod.Status.PodIP, pod.Annotations)
 
 	handle, _ := rule.uidToHandle[pod.UID]
-	cls := newClass(p.interfLink.Attrs().Index, rootClass, handle, egress, egress, GetPrio(netqos))
-	err := p.ensureClass(p.interfLink, cls)
+	cls := newClass(link.Attrs().Index, rootClass, handle, egress, egress, GetPrio(netqos))
+	err = p.ensureClass(link, cls)
 	if err != nil {
 		klog.Errorf("failed to create class for pod %s, err=%v", string(pod.UID), err)
 		return err
@@ -423,9 +458,9 @@ func (p *tcPlugin) createRulesForPod(rule *tcRule, pod *v1.Pod, netqos NetQoSCla
 	// tc filter add dev eth0 parent 1:0 protocol ip prio 2 u32 match ip dst 0.0.0.0/0 flowid 1:5
 	genFilterCmd := func(op string) exec.Cmd {
 		// tc filter add dev br0 parent 1:0 protocol ip prio 2 u32 match ip src 1.2.0.0 classid 1:5
-		return exec.New().Command("tc", "filter", op, "dev", p.interfLink.Attrs().Name,
+		return exec.New().Command("tc", "filter", op, "dev", link.Attrs().Name,
 			"parent", fmt.Sprintf("%d:", ROOT_CLASS_MINOR_ID),
-			"protocol", "ip", "prio", strconv.Itoa(getPrio(p.interfLink.Attrs().Name)),
+			"protocol", "ip", "prio", strconv.Itoa(getPrio(link.Attrs().Name)),
 			"u32", "match", "ip", "src", pod.Status.PodIP,
 			"classid", fmt.Sprintf("%d:%s", ROOT_CLASS_MINOR_ID, minorHex),
 		)
@@ -494,34 +529,50 @@ func initHandleId(uid types.UID, handleToUid map[uint32]types.UID, uidToHandle m
 }
 
 func (p *tcPlugin) InitRelatedRules() error {
-	return apierror.NewAggregate([]error{
+	errs := []error{
 		p.EnsureQdisc(),
 		p.EnsureClasses(),
 		p.EnsureCgroupFilters(),
 		p.EnsureIpset(),
 		p.EnsureIptables(),
-	})
+	}
+	// the ingress filters match the ipsets, so they are created after the ipsets
+	if r := p.getRule(); r != nil && r.ingress {
+		errs = append(errs, p.EnsureIngress())
+	} else {
+		errs = append(errs, p.DelIngress())
+	}
+	return apierror.NewAggregate(errs)
 }
 
 func (p *tcPlugin) CleanUp() error {
 	return apierror.NewAggregate([]error{
 		p.DelQdisc(),
+		p.DelIngress(),
 		p.DelIptables(),
 		p.DestoryIpset(),
 	})
 }
 
 func (p *tcPlugin) EnsureQdisc() error {
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
 	klog.V(5).Infoln("start to create qdisc for default net interface")
+	return p.ensureHtbQdisc(link)
+}
+
+func (p *tcPlugin) ensureHtbQdisc(link netlink.Link) error {
 	attrs := netlink.QdiscAttrs{
-		LinkIndex: p.interfLink.Attrs().Index,
+		LinkIndex: link.Attrs().Index,
 		Handle:    netlink.MakeHandle(MAJOR_ID, QDISC_MINOR_ID),
 		Parent:    netlink.HANDLE_ROOT,
 	}
 	htb := netlink.NewHtb(attrs)
 	htb.Defcls = SYSTEM_CLASS_MINOR_ID
 
-	qdiscs, err := p.netLinkHandler.QdiscList(p.interfLink)
+	qdiscs, err := p.netLinkHandler.QdiscList(link)
 	if err != nil {
 		return fmt.Errorf("failed to get qdisc. err=%v", err)
 	}
@@ -534,7 +585,7 @@ func (p *tcPlugin) EnsureQdisc() error {
 			return nil
 		}
 		if err := netlink.QdiscDel(htb); err != nil {
-			return fmt.Errorf("failed to delete old qidsc on %s, err=%v", p.interfLink.Attrs().Name, err)
+			return fmt.Errorf("failed to delete old qidsc on %s, err=%v", link.Attrs().Name, err)
 		}
 	}
 
@@ -542,15 +593,19 @@ func (p *tcPlugin) EnsureQdisc() error {
 }
 
 func (p *tcPlugin) DelQdisc() error {
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
 	klog.V(5).Infof("start to delete qdisc created by tc plugin.")
 	attrs := netlink.QdiscAttrs{
-		LinkIndex: p.interfLink.Attrs().Index,
+		LinkIndex: link.Attrs().Index,
 		Handle:    netlink.MakeHandle(MAJOR_ID, QDISC_MINOR_ID),
 		Parent:    netlink.HANDLE_ROOT,
 	}
 	htb := netlink.NewHtb(attrs)
 
-	qdiscs, err := p.netLinkHandler.QdiscList(p.interfLink)
+	qdiscs, err := p.netLinkHandler.QdiscList(link)
 	if err != nil {
 		return err
 	}
@@ -558,7 +613,7 @@ func (p *tcPlugin) DelQdisc() error {
 	for _, qdisc := range qdiscs {
 		if qdisc.Type() == "htb" && qdisc.Attrs().Handle == htb.Handle {
 			if err := netlink.QdiscDel(htb); err != nil {
-				return fmt.Errorf("failed to delete old qidsc on %s, err=%v", p.interfLink.Attrs().Name, err)
+				return fmt.Errorf("failed to delete old qidsc on %s, err=%v", link.Attrs().Name, err)
 			}
 		}
 	}
@@ -573,19 +628,46 @@ func (p *tcPlugin) EnsureClasses() error {
 		klog.V(5).Infof("hook plugin rule is nil, nothing to do for plugin %v", name)
 		return nil
 	}
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
+
+	return p.ensureClassesForLink(link, r.adaptiveBECeil, r.netCfg.HwTxBpsMax, r.netCfg.L1TxBpsMin,
+		r.netCfg.L1TxBpsMax, r.netCfg.L2TxBpsMin, r.netCfg.L2TxBpsMax)
+}
 
+// ensureClassesForLink creates the root, system, LS and BE classes on the link.
+// If the BE ceil is adaptive, the current BE ceil adjusted by adjustBECeil is kept as long as it is in the range of the
+// BE request and limit, so it is not reset to the limit on every rule update.
+// The caller must not hold the linkMutex.
+func (p *tcPlugin) ensureClassesForLink(link netlink.Link, adaptiveBECeil bool, hwMax, lsMin, lsMax, beMin, beMax uint64) error {
+	// hold the linkMutex so the BE ceil read here is not adjusted by adjustBECeil concurrently
+	p.linkMutex.Lock()
+	defer p.linkMutex.Unlock()
+	index := link.Attrs().Index
+	beCeil := beMax
+	if adaptiveBECeil {
+		if ceil, ok := getBECeil(link); ok && ceil >= beMin && ceil <= beMax {
+			beCeil = ceil
+		}
+	}
 	return apierror.NewAggregate([]error{
-		p.ensureClass(p.interfLink, newClass(p.interfLink.Attrs().Index, netlink.HANDLE_ROOT, rootClass, r.netCfg.HwTxBpsMax, r.netCfg.HwTxBpsMax, 0)),
-		p.ensureClass(p.interfLink, newClass(p.interfLink.Attrs().Index, rootClass, systemClass, r.netCfg.HwTxBpsMax-r.netCfg.L1TxBpsMin-r.netCfg.L2TxBpsMin, r.netCfg.HwTxBpsMax, SYSTEM_CLASS_PRIO)),
-		p.ensureClass(p.interfLink, newClass(p.interfLink.Attrs().Index, rootClass, lsClass, r.netCfg.L1TxBpsMin, r.netCfg.L1TxBpsMax, LS_CLASS_PRIO)),
-		p.ensureClass(p.interfLink, newClass(p.interfLink.Attrs().Index, rootClass, beClass, r.netCfg.L2TxBpsMin, r.netCfg.L2TxBpsMax, BE_CLASS_PRIO)),
+		p.ensureClass(link, newClass(index, netlink.HANDLE_ROOT, rootClass, hwMax, hwMax, 0)),
+		p.ensureClass(link, newClass(index, rootClass, systemClass, hwMax-lsMin-beMin, hwMax, SYSTEM_CLASS_PRIO)),
+		p.ensureClass(link, newClass(index, rootClass, lsClass, lsMin, lsMax, LS_CLASS_PRIO)),
+		p.ensureClass(link, newClass(index, rootClass, beClass, beMin, beCeil, BE_CLASS_PRIO)),
 	})
 }
 
 func (p *tcPlugin) EnsureCgroupFilters() error {
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
 	klog.V(5).Infof("start to create tc cgroup filter rules.")
 	genFilterCmd := func(op string, prio int, minor uint16) exec.Cmd {
-		return exec.New().Command("tc", "filter", op, "dev", p.interfLink.Attrs().Name,
+		return exec.New().Command("tc", "filter", op, "dev", link.Attrs().Name,
 			"protocol", "ip", "prio", fmt.Sprintf("%d", prio),
 			"parent", fmt.Sprintf("%d:", ROOT_CLASS_MINOR_ID),
 			"handle", fmt.Sprintf("%d:", minor),
@@ -710,16 +792,16 @@ func (p *tcPlugin) ensureClass(nic netlink.Link, expect *netlink.HtbClass) error
 			return nil
 		}
 		if err := netlink.ClassChange(expect); err != nil {
-			return fmt.Errorf("failed to change class from %v to %v on interface %s. err=: %v", existing, expect, p.interfLink.Attrs().Name, err)
+			return fmt.Errorf("failed to change class from %v to %v on interface %s. err=: %v", existing, expect, nic.Attrs().Name, err)
 		}
-		klog.Infof("succeed to changed htb class from %v to %v on interface %s.", existing, expect, p.interfLink.Attrs().Name)
+		klog.Infof("succeed to changed htb class from %v to %v on interface %s.", existing, expect, nic.Attrs().Name)
 		return nil
 	}
 	if err := netlink.ClassAdd(expect); err != nil {
-		return fmt.Errorf("failed to create htb class %v: %v on interface %s. err=%v", expect, err, p.interfLink.Attrs().Name, err)
+		return fmt.Errorf("failed to create htb class %v: %v on interface %s. err=%v", expect, err, nic.Attrs().Name, err)
 	}
 
-	klog.V(2).Infof("succed to creat htb class: %v on interface %s\n", expect, p.interfLink.Attrs().Name)
+	klog.V(2).Infof("succed to creat htb class: %v on interface %s\n", expect, nic.Attrs().Name)
 	return nil
 }
 
@@ -745,7 +827,11 @@ func (p *tcPlugin) deleteClass(nic netlink.Link, expect *netlink.HtbClass) error
 }
 
 func (p *tcPlugin) deleteFilter(key string, delFunc exec.Cmd) error {
-	matchCmd := exec.New().Command("tc", "filter", "show", "dev", p.interfLink.Attrs().Name)
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
+	matchCmd := exec.New().Command("tc", "filter", "show", "dev", link.Attrs().Name)
 	data, err := matchCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to get tc filter by key:%s, err:%v", key, err)
@@ -759,13 +845,17 @@ func (p *tcPlugin) deleteFilter(key string, delFunc exec.Cmd) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete tc filter, output: %s, err: %v", string(data), err)
 	}
-	klog.V(5).Infof("succeed to delete filter for %s ", p.interfLink.Attrs().Name)
+	klog.V(5).Infof("succeed to delete filter for %s ", link.Attrs().Name)
 
 	return nil
 }
 
 func (p *tcPlugin) ensureFilter(find string, createCmd, updateCmd exec.Cmd) error {
-	matchCmd := exec.New().Command("tc", "filter", "show", "dev", p.interfLink.Attrs().Name)
+	link, err := p.getLink()
+	if err != nil {
+		return err
+	}
+	matchCmd := exec.New().Command("tc", "filter", "show", "dev", link.Attrs().Name)
 	data, err := matchCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to get tc filter by key:%s, err:%v", find, err)
@@ -782,7 +872,7 @@ func (p *tcPlugin) ensureFilter(find string, createCmd, updateCmd exec.Cmd) erro
 	if err != nil {
 		return fmt.Errorf("failed to create tc filter, output: %s, err: %v", string(data), err)
 	}
-	klog.V(5).Infof("%s created filter", p.interfLink.Attrs().Name)
+	klog.V(5).Infof("%s created filter", link.Attrs().Name)
 
 	return nil
 }
@@ -808,14 +898,18 @@ func (p *tcPlugin) checkAllRulesExisted() (bool, error) {
 }
 
 func (p *tcPlugin) QdiscExisted() (bool, error) {
+	link, err := p.getLink()
+	if err != nil {
+		return false, err
+	}
 	attrs := netlink.QdiscAttrs{
-		LinkIndex: p.interfLink.Attrs().Index,
+		LinkIndex: link.Attrs().Index,
 		Handle:    netlink.MakeHandle(MAJOR_ID, QDISC_MINOR_ID),
 		Parent:    netlink.HANDLE_ROOT,
 	}
 	htb := netlink.NewHtb(attrs)
 
-	qdiscs, err := p.netLinkHandler.QdiscList(p.interfLink)
+	qdiscs, err := p.netLinkHandler.QdiscList(link)
 	if err != nil || qdiscs == nil {
 		return false, err
 	}
@@ -829,7 +923,11 @@ func (p *tcPlugin) QdiscExisted() (bool, error) {
 }
 
 func (p *tcPlugin) classesExisted() (bool, error) {
-	link, err := netlink.LinkByIndex(p.interfLink.Attrs().Index)
+	nic, err := p.getLink()
+	if err != nil {
+		return false, err
+	}
+	link, err := netlink.LinkByIndex(nic.Attrs().Index)
 	if err != nil {
 		return false, err
 	}
@@ -847,10 +945,10 @@ func (p *tcPlugin) classesExisted() (bool, error) {
 	lowClassRate := r.speed * BE_CLASS_RATE_PERCENTAGE / 100
 
 	errs := apierror.NewAggregate([]error{
-		p.classExisted(link, newClass(p.interfLink.Attrs().Index, netlink.HANDLE_ROOT, rootClass, maxCeil, maxCeil, 0)),
-		p.classExisted(link, newClass(p.interfLink.Attrs().Index, rootClass, systemClass, highClassRate, maxCeil, SYSTEM_CLASS_PRIO)),
-		p.classExisted(link, newClass(p.interfLink.Attrs().Index, rootClass, lsClass, midClassRate, maxCeil, LS_CLASS_PRIO)),
-		p.classExisted(link, newClass(p.interfLink.Attrs().Index, rootClass, beClass, lowClassRate, maxCeil, BE_CLASS_PRIO)),
+		p.classExisted(link, newClass(nic.Attrs().Index, netlink.HANDLE_ROOT, rootClass, maxCeil, maxCeil, 0)),
+		p.classExisted(link, newClass(nic.Attrs().Index, rootClass, systemClass, highClassRate, maxCeil, SYSTEM_CLASS_PRIO)),
+		p.classExisted(link, newClass(nic.Attrs().Index, rootClass, lsClass, midClassRate, maxCeil, LS_CLASS_PRIO)),
+		p.classExisted(link, newClass(nic.Attrs().Index, rootClass, beClass, lowClassRate, maxCeil, BE_CLASS_PRIO)),
 	})
 
 	if errs != nil {
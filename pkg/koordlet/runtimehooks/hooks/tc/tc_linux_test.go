@@ -23,16 +23,20 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/coreos/go-iptables/iptables"
 	"github.com/stretchr/testify/assert"
 	"github.com/vishvananda/netlink"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
@@ -320,3 +324,108 @@ func Test_getMinorId(t *testing.T) {
 		})
 	}
 }
+
+func Test_tcPlugin_getLink(t *testing.T) {
+	p := newPlugin()
+	_, err := p.getLink()
+	assert.Error(t, err)
+	// the accessors return the error instead of reading a nil link
+	assert.Error(t, p.EnsureQdisc())
+	assert.Error(t, p.EnsureCgroupFilters())
+	_, err = p.QdiscExisted()
+	assert.Error(t, err)
+
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Index: 2}}
+	p.linkMutex.Lock()
+	p.interfLink = link
+	p.linkMutex.Unlock()
+	got, err := p.getLink()
+	assert.NoError(t, err)
+	assert.Equal(t, link, got)
+}
+
+func Test_calculateBECeil(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        uint64
+		lsThroughput uint64
+		beMin        uint64
+		beMax        uint64
+		want         uint64
+	}{
+		{
+			name:         "leave the idle bandwidth to be",
+			total:        1000,
+			lsThroughput: 200,
+			want:         780,
+		},
+		{
+			name:         "no more than the be limit",
+			total:        1000,
+			lsThroughput: 100,
+			beMax:        500,
+			want:         500,
+		},
+		{
+			name:         "no less than the be request",
+			total:        1000,
+			lsThroughput: 800,
+			beMin:        300,
+			beMax:        1000,
+			want:         300,
+		},
+		{
+			name:         "no less than the floor if ls takes all bandwidth",
+			total:        1000,
+			lsThroughput: 1000,
+			want:         10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, calculateBECeil(tt.total, tt.lsThroughput, tt.beMin, tt.beMax))
+		})
+	}
+}
+
+func Test_calculateThroughput(t *testing.T) {
+	now := time.Now()
+	_, ok := calculateThroughput(nil, &classBytes{bytes: 100, timestamp: now})
+	assert.False(t, ok)
+	_, ok = calculateThroughput(&classBytes{bytes: 200, timestamp: now.Add(-time.Second)}, &classBytes{bytes: 100, timestamp: now})
+	assert.False(t, ok)
+	_, ok = calculateThroughput(&classBytes{bytes: 100, timestamp: now}, &classBytes{bytes: 200, timestamp: now})
+	assert.False(t, ok)
+	got, ok := calculateThroughput(&classBytes{bytes: 100, timestamp: now.Add(-2 * time.Second)}, &classBytes{bytes: 300, timestamp: now})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(800), got)
+}
+
+func Test_parseRuleForNodeSLO(t *testing.T) {
+	p := newPlugin()
+	updated, err := p.parseRuleForNodeSLO(&slov1alpha1.NodeSLOSpec{
+		SystemStrategy: &slov1alpha1.SystemStrategy{
+			TotalNetworkBandwidth: resource.MustParse("1000M"),
+			NetworkIngressShaping: ptr.To(true),
+			NetworkAdaptiveBECeil: ptr.To(true),
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	r := p.getRule()
+	assert.True(t, r.enable)
+	assert.True(t, r.ingress)
+	assert.True(t, r.adaptiveBECeil)
+	assert.Equal(t, uint64(1000000000), r.netCfg.HwRxBpsMax)
+
+	updated, err = p.parseRuleForNodeSLO(&slov1alpha1.NodeSLOSpec{
+		SystemStrategy: &slov1alpha1.SystemStrategy{
+			TotalNetworkBandwidth: resource.MustParse("1000M"),
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	r = p.getRule()
+	assert.False(t, r.ingress)
+	assert.False(t, r.adaptiveBECeil)
+}
@@ -54,10 +54,13 @@ type runtimeHook struct {
 	hostAppReconciler reconciler.Reconciler
 	reader            resourceexecutor.CgroupReader
 	executor          resourceexecutor.ResourceUpdateExecutor
+	// pluginStopCh is closed when the runtime hook stops, to stop the background routines of the plugins.
+	pluginStopCh chan struct{}
 }
 
 func (r *runtimeHook) Run(stopCh <-chan struct{}) error {
 	klog.V(5).Infof("runtime hook server start running")
+	defer close(r.pluginStopCh)
 	go r.executor.Run(stopCh)
 	if err := r.server.Start(); err != nil {
 		return err
@@ -149,12 +152,14 @@ func NewRuntimeHook(si statesinformer.StatesInformer, cfg *Config, schema *apiru
 		EventRecorder:     recorder,
 	}
 
+	pluginStopCh := make(chan struct{})
 	newPluginOptions := hooks.Options{
 		Reader:                           cr,
 		Executor:                         e,
 		StatesInformer:                   si,
 		EventRecorder:                    recorder,
 		DisableUnsetCPUQuotaForCPUSetPod: cfg.RuntimeHookDisableUnsetCPUQuota,
+		StopCh:                           pluginStopCh,
 	}
 
 	if err != nil {
@@ -168,6 +173,7 @@ func NewRuntimeHook(si statesinformer.StatesInformer, cfg *Config, schema *apiru
 		hostAppReconciler: reconciler.NewHostAppReconciler(newReconcilerCtx),
 		reader:            cr,
 		executor:          e,
+		pluginStopCh:      pluginStopCh,
 	}
 	registerPlugins(newPluginOptions)
 	si.RegisterCallbacks(statesinformer.RegisterTypeNodeSLOSpec, "runtime-hooks-rule-node-slo",
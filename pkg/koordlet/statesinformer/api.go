@@ -129,4 +129,8 @@ type StatesInformer interface {
 	GetVolumeName(pvcNamespace, pvcName string) string
 
 	RegisterCallbacks(objType RegisterType, name, description string, callbackFn UpdateCbFn)
+
+	// SetNodeSLOStrategyResult records the latest apply result of the NodeSLO strategy, which is reported
+	// in the NodeSLO status. A non-nil err indicates the strategy failed to apply.
+	SetNodeSLOStrategyResult(strategyName string, err error)
 }
@@ -32,6 +32,8 @@ type Config struct {
 	NodeTopologySyncInterval         time.Duration
 	DisableQueryKubeletConfig        bool
 	EnableNodeMetricReport           bool
+	EnableNodeSLOStatusReport        bool
+	NodeSLOStatusReportInterval      time.Duration
 	MetricReportInterval             time.Duration // Deprecated
 	EnablePodTaskIds                 bool
 	XPUEnforceCollectFromDeviceInfos bool
@@ -47,6 +49,8 @@ func NewDefaultConfig() *Config {
 		NodeTopologySyncInterval:         3 * time.Second,
 		DisableQueryKubeletConfig:        false,
 		EnableNodeMetricReport:           true,
+		EnableNodeSLOStatusReport:        true,
+		NodeSLOStatusReportInterval:      60 * time.Second,
 		EnablePodTaskIds:                 false,
 		XPUEnforceCollectFromDeviceInfos: false,
 	}
@@ -62,6 +66,8 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.DisableQueryKubeletConfig, "disable-query-kubelet-config", c.DisableQueryKubeletConfig, "Disables querying the kubelet configuration from kubelet. Flag must be set to true if kubelet-insecure-tls=true is configured")
 	fs.DurationVar(&c.MetricReportInterval, "report-interval", c.MetricReportInterval, "Deprecated since v1.1, use ColocationStrategy.MetricReportIntervalSeconds in config map of slo-controller")
 	fs.BoolVar(&c.EnableNodeMetricReport, "enable-node-metric-report", c.EnableNodeMetricReport, "Enable status update of node metric crd.")
+	fs.BoolVar(&c.EnableNodeSLOStatusReport, "enable-node-slo-status-report", c.EnableNodeSLOStatusReport, "Enable status update of node slo crd, which reports the apply results of the strategies and the kernel capabilities.")
+	fs.DurationVar(&c.NodeSLOStatusReportInterval, "node-slo-status-report-interval", c.NodeSLOStatusReportInterval, "The interval which Koordlet will report the node slo status. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 	fs.BoolVar(&c.EnablePodTaskIds, "enable-pod-taskids", c.EnablePodTaskIds, "Enable pod taskids in statesinformer.")
 	fs.BoolVar(&c.XPUEnforceCollectFromDeviceInfos, "xpu-enforce-collect-from-device-infos", c.XPUEnforceCollectFromDeviceInfos, "Enforce the collection of xpu devices from device infos directory, such as nvidia gpu, ascend npu, etc. Default: false")
 }
@@ -70,6 +70,10 @@ const (
 	// metric is valid only if its (lastSample.Time - firstSample.Time) > 0.5 * targetTimeRange
 	// used during checking node aggregate usage for cold start
 	validateTimeRangeRatio = 0.5
+
+	// defaultMaxReportedContainers is the default limit of the containers reported in the NodeMetric
+	// when the container aggregate policy is enabled
+	defaultMaxReportedContainers = 256
 )
 
 var (
@@ -375,12 +379,14 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 	}
 	node := r.nodeInformer.GetNode()
 	prodPredictor := r.predictorFactory.New(prediction.ProdReclaimablePredictor, prediction.PredictorContext{Node: node})
+	podMetricsByKey := make(map[string]*slov1alpha1.PodMetricInfo, len(podsMeta))
 	for _, podMeta := range podsMeta {
 		podMetric, err := r.collectPodMetric(podMeta, queryParam)
 		if err != nil {
 			klog.Warningf("query pod metric failed, pod %s, err: %v", podMeta.Key(), err)
 			continue
 		}
+		podMetricsByKey[podMeta.Key()] = podMetric
 		// predict pods which have valid metrics; ignore prediction failures
 		err = prodPredictor.AddPod(podMeta.Pod)
 		if err != nil {
@@ -393,6 +399,7 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		}
 		podsMetricInfo = append(podsMetricInfo, podMetric)
 	}
+	r.collectContainersMetric(podsMeta, podMetricsByKey, startTime, endTime, spec.CollectPolicy)
 	sort.Slice(podsMetricInfo, func(i, j int) bool {
 		if podsMetricInfo[i].Namespace != podsMetricInfo[j].Namespace {
 			return podsMetricInfo[i].Namespace < podsMetricInfo[j].Namespace
@@ -881,6 +888,167 @@ func (r *nodeMetricInformer) collectPodMetric(podMeta *statesinformer.PodMeta, q
 	return podMetric, nil
 }
 
+// collectContainersMetric fills the container usages into the pod metrics if the container aggregate policy is
+// enabled. To bound the size of the NodeMetric, the containers of pods with a higher priority are reported first
+// until the number of reported containers reaches the limit. The containers of a pod are reported all or none,
+// so the consumers can sum them as the usage of the pod.
+func (r *nodeMetricInformer) collectContainersMetric(podsMeta []*statesinformer.PodMeta, podMetrics map[string]*slov1alpha1.PodMetricInfo,
+	start, end time.Time, collectPolicy *slov1alpha1.NodeMetricCollectPolicy) {
+	if collectPolicy == nil {
+		return
+	}
+	policy := collectPolicy.ContainerAggregatePolicy
+	if policy == nil || policy.Enable == nil || !*policy.Enable {
+		return
+	}
+	maxContainers := int64(defaultMaxReportedContainers)
+	if policy.MaxContainers != nil {
+		maxContainers = *policy.MaxContainers
+	}
+	durations := policy.Durations
+	if len(durations) <= 0 && collectPolicy.NodeAggregatePolicy != nil {
+		durations = collectPolicy.NodeAggregatePolicy.Durations
+	}
+
+	sortedPods := make([]*statesinformer.PodMeta, 0, len(podsMeta))
+	for _, podMeta := range podsMeta {
+		if _, ok := podMetrics[podMeta.Key()]; ok {
+			sortedPods = append(sortedPods, podMeta)
+		}
+	}
+	sort.SliceStable(sortedPods, func(i, j int) bool {
+		pi := *apiext.GetPodPriorityValueWithDefault(sortedPods[i].Pod)
+		pj := *apiext.GetPodPriorityValueWithDefault(sortedPods[j].Pod)
+		if pi != pj {
+			return pi > pj
+		}
+		return sortedPods[i].Key() < sortedPods[j].Key()
+	})
+
+	var reported int64
+	for _, podMeta := range sortedPods {
+		var containerStats []*corev1.ContainerStatus
+		for i := range podMeta.Pod.Status.ContainerStatuses {
+			if containerStat := &podMeta.Pod.Status.ContainerStatuses[i]; containerStat.ContainerID != "" {
+				containerStats = append(containerStats, containerStat)
+			}
+		}
+		if len(containerStats) == 0 {
+			continue
+		}
+		// truncate at the pod boundary, so that a pod is never reported with only a part of its containers
+		if reported+int64(len(containerStats)) > maxContainers {
+			klog.V(5).Infof("reported containers reach the limit %v, skip the rest pods from %s", maxContainers, podMeta.Key())
+			return
+		}
+		containersMetric := make([]*slov1alpha1.ContainerMetricInfo, 0, len(containerStats))
+		for _, containerStat := range containerStats {
+			containerMetric, err := r.collectContainerMetric(containerStat, start, end, durations)
+			if err != nil {
+				klog.V(5).Infof("query container metric failed, skip the containers of pod %s, container %s, err: %v",
+					podMeta.Key(), containerStat.Name, err)
+				containersMetric = nil
+				break
+			}
+			containersMetric = append(containersMetric, containerMetric)
+		}
+		if len(containersMetric) == 0 {
+			continue
+		}
+		podMetric := podMetrics[podMeta.Key()]
+		podMetric.ContainersMetric = containersMetric
+		reported += int64(len(containersMetric))
+	}
+}
+
+func (r *nodeMetricInformer) collectContainerMetric(containerStat *corev1.ContainerStatus, start, end time.Time,
+	durations []metav1.Duration) (*slov1alpha1.ContainerMetricInfo, error) {
+	usages, err := r.queryContainerMetric(containerStat.ContainerID, start, end, []apiext.AggregationType{apiext.AVG}, false)
+	if err != nil {
+		return nil, err
+	}
+	containerMetric := &slov1alpha1.ContainerMetricInfo{
+		Name:           containerStat.Name,
+		ContainerUsage: usages[apiext.AVG],
+	}
+	for _, d := range durations {
+		aggregateUsages, err := r.queryContainerMetric(containerStat.ContainerID, end.Add(-d.Duration), end,
+			[]apiext.AggregationType{apiext.P50, apiext.P90, apiext.P99, apiext.MAX}, true)
+		if err != nil || len(aggregateUsages) <= 0 {
+			continue
+		}
+		containerMetric.AggregatedContainerUsages = append(containerMetric.AggregatedContainerUsages, slov1alpha1.AggregatedUsage{
+			Usage:    aggregateUsages,
+			Duration: d,
+		})
+	}
+	return containerMetric, nil
+}
+
+var containerAggregationTypes = map[apiext.AggregationType]metriccache.AggregationType{
+	apiext.AVG: metriccache.AggregationTypeAVG,
+	apiext.P50: metriccache.AggregationTypeP50,
+	apiext.P90: metriccache.AggregationTypeP90,
+	apiext.P99: metriccache.AggregationTypeP99,
+	apiext.MAX: metriccache.AggregationTypeMax,
+}
+
+// queryContainerMetric returns the cpu and memory usages of the container for each aggregation type.
+// It returns an empty result if the coldStartFilter is true and the samples are not enough.
+func (r *nodeMetricInformer) queryContainerMetric(containerID string, start, end time.Time, aggregateTypes []apiext.AggregationType,
+	coldStartFilter bool) (map[apiext.AggregationType]slov1alpha1.ResourceMap, error) {
+	querier, err := r.metricCache.Querier(start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer querier.Close()
+
+	properties := metriccache.MetricPropertiesFunc.Container(containerID)
+	cpuAggregateResult, err := doQuery(querier, metriccache.ContainerCPUUsageMetric, properties)
+	if err != nil {
+		return nil, err
+	}
+	memAggregateResult, err := doQuery(querier, r.getContainerMemoryMetric(), properties)
+	if err != nil {
+		return nil, err
+	}
+	if cpuAggregateResult.Count() <= 0 || memAggregateResult.Count() <= 0 {
+		return nil, fmt.Errorf("no samples found for container %s", containerID)
+	}
+
+	usages := map[apiext.AggregationType]slov1alpha1.ResourceMap{}
+	if coldStartFilter && metricsInColdStart(start, end, cpuAggregateResult.TimeRangeDuration()) {
+		return usages, nil
+	}
+	for _, aggregateType := range aggregateTypes {
+		cpuUsed, err := cpuAggregateResult.Value(containerAggregationTypes[aggregateType])
+		if err != nil {
+			return nil, err
+		}
+		memUsed, err := memAggregateResult.Value(containerAggregationTypes[aggregateType])
+		if err != nil {
+			return nil, err
+		}
+		usages[aggregateType] = slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(int64(cpuUsed*1000), resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(int64(memUsed), resource.BinarySI),
+			},
+		}
+	}
+	return usages, nil
+}
+
+func (r *nodeMetricInformer) getContainerMemoryMetric() metriccache.MetricResource {
+	nodeMemoryCollectPolicy := *r.getNodeMetricSpec().CollectPolicy.NodeMemoryCollectPolicy
+	if nodeMemoryCollectPolicy == slov1alpha1.UsageWithHotPageCache && system.GetIsStartColdMemory() {
+		return metriccache.ContainerMemoryWithHotPageUsageMetric
+	} else if nodeMemoryCollectPolicy == slov1alpha1.UsageWithPageCache {
+		return metriccache.ContainerMemoryUsageWithPageCacheMetric
+	}
+	return metriccache.ContainerMemUsageMetric
+}
+
 func (r *nodeMetricInformer) collectHostAppMetric(hostApp *slov1alpha1.HostApplicationSpec, queryParam metriccache.QueryParam) (*slov1alpha1.HostApplicationMetricInfo, error) {
 	if hostApp == nil {
 		return nil, fmt.Errorf("invalid nil host application")
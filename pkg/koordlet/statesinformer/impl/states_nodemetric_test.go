@@ -1699,3 +1699,161 @@ func Test_recordNodeMetricPromMetrics(t *testing.T) {
 		assert.NotNil(t, gauge)
 	})
 }
+
+// realAggregateResultFactory keeps the original factory since other tests replace it with the mock
+var realAggregateResultFactory = metriccache.DefaultAggregateResultFactory
+
+func Test_nodeMetricInformer_collectContainersMetric(t *testing.T) {
+	oldFactory := metriccache.DefaultAggregateResultFactory
+	metriccache.DefaultAggregateResultFactory = realAggregateResultFactory
+	defer func() {
+		metriccache.DefaultAggregateResultFactory = oldFactory
+	}()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		metricCache.Close()
+	}()
+
+	end := time.Now()
+	start := end.Add(-10 * time.Minute)
+	var samples []metriccache.MetricSample
+	for i := 0; i < 10; i++ {
+		sampleTime := start.Add(time.Duration(i+1) * time.Minute)
+		for _, containerID := range []string{"containerd://prod", "containerd://batch"} {
+			cpuSample, err := metriccache.ContainerCPUUsageMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.Container(containerID), sampleTime, float64(i+1))
+			assert.NoError(t, err)
+			memSample, err := metriccache.ContainerMemUsageMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.Container(containerID), sampleTime, float64((i+1)<<20))
+			assert.NoError(t, err)
+			samples = append(samples, cpuSample, memSample)
+		}
+	}
+	appender := metricCache.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+
+	prodPod := &statesinformer.PodMeta{
+		Pod: &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod-pod", Namespace: "default", UID: "prod-uid"},
+			Spec:       v1.PodSpec{Priority: ptr.To[int32](apiext.PriorityProdValueMax)},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{Name: "main", ContainerID: "containerd://prod"}},
+			},
+		},
+	}
+	batchPod := &statesinformer.PodMeta{
+		Pod: &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch-pod", Namespace: "default", UID: "batch-uid"},
+			Spec:       v1.PodSpec{Priority: ptr.To[int32](apiext.PriorityBatchValueMax)},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{Name: "main", ContainerID: "containerd://batch"}},
+			},
+		},
+	}
+
+	multiContainerPod := &statesinformer.PodMeta{
+		Pod: &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "multi-container-pod", Namespace: "default", UID: "multi-container-uid"},
+			Spec:       v1.PodSpec{Priority: ptr.To[int32](apiext.PriorityProdValueMax)},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "main", ContainerID: "containerd://prod"},
+					{Name: "main", ContainerID: "containerd://batch"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		pods          []*statesinformer.PodMeta
+		policy        *slov1alpha1.ContainerAggregatePolicy
+		wantContainer map[string]int
+	}{
+		{
+			name:          "policy not set",
+			policy:        nil,
+			wantContainer: map[string]int{"prod-pod": 0, "batch-pod": 0},
+		},
+		{
+			name: "policy disabled",
+			policy: &slov1alpha1.ContainerAggregatePolicy{
+				Enable: ptr.To(false),
+			},
+			wantContainer: map[string]int{"prod-pod": 0, "batch-pod": 0},
+		},
+		{
+			name: "report all containers",
+			policy: &slov1alpha1.ContainerAggregatePolicy{
+				Enable:    ptr.To(true),
+				Durations: []metav1.Duration{{Duration: 10 * time.Minute}},
+			},
+			wantContainer: map[string]int{"prod-pod": 1, "batch-pod": 1},
+		},
+		{
+			name: "report the high priority containers within the limit",
+			policy: &slov1alpha1.ContainerAggregatePolicy{
+				Enable:        ptr.To(true),
+				Durations:     []metav1.Duration{{Duration: 10 * time.Minute}},
+				MaxContainers: ptr.To[int64](1),
+			},
+			wantContainer: map[string]int{"prod-pod": 1, "batch-pod": 0},
+		},
+		{
+			name: "truncate at the pod boundary",
+			pods: []*statesinformer.PodMeta{batchPod, multiContainerPod},
+			policy: &slov1alpha1.ContainerAggregatePolicy{
+				Enable:        ptr.To(true),
+				Durations:     []metav1.Duration{{Duration: 10 * time.Minute}},
+				MaxContainers: ptr.To[int64](1),
+			},
+			wantContainer: map[string]int{"multi-container-pod": 0, "batch-pod": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memoryCollectPolicy := slov1alpha1.UsageWithoutPageCache
+			collectPolicy := &slov1alpha1.NodeMetricCollectPolicy{
+				NodeMemoryCollectPolicy:  &memoryCollectPolicy,
+				ContainerAggregatePolicy: tt.policy,
+			}
+			r := &nodeMetricInformer{
+				metricCache: metricCache,
+				nodeMetric: &slov1alpha1.NodeMetric{
+					Spec: slov1alpha1.NodeMetricSpec{
+						CollectPolicy: collectPolicy,
+					},
+				},
+			}
+			pods := tt.pods
+			if pods == nil {
+				pods = []*statesinformer.PodMeta{batchPod, prodPod}
+			}
+			podMetrics := map[string]*slov1alpha1.PodMetricInfo{}
+			for _, podMeta := range pods {
+				podMetrics[podMeta.Key()] = &slov1alpha1.PodMetricInfo{Name: podMeta.Pod.Name, Namespace: podMeta.Pod.Namespace}
+			}
+			r.collectContainersMetric(pods, podMetrics, start, end, collectPolicy)
+
+			for _, podMetric := range podMetrics {
+				assert.Len(t, podMetric.ContainersMetric, tt.wantContainer[podMetric.Name], podMetric.Name)
+				for _, containerMetric := range podMetric.ContainersMetric {
+					assert.Equal(t, "main", containerMetric.Name)
+					cpu := containerMetric.ContainerUsage.ResourceList[v1.ResourceCPU]
+					assert.Equal(t, int64(5500), cpu.MilliValue())
+					assert.Len(t, containerMetric.AggregatedContainerUsages, 1)
+					aggregated := containerMetric.AggregatedContainerUsages[0]
+					maxCPU := aggregated.Usage[apiext.MAX].ResourceList[v1.ResourceCPU]
+					assert.Equal(t, int64(10000), maxCPU.MilliValue())
+					p50Memory := aggregated.Usage[apiext.P50].ResourceList[v1.ResourceMemory]
+					assert.Equal(t, int64(5<<20), p50Memory.Value())
+				}
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	clientsetv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
@@ -44,12 +45,25 @@ type nodeSLOInformer struct {
 	nodeSLOInformer cache.SharedIndexInformer
 	nodeSLORWMutex  sync.RWMutex
 	nodeSLO         *slov1alpha1.NodeSLO
+	// observedGeneration is the generation of the latest nodeSLO spec
+	observedGeneration int64
 
 	callbackRunner *callbackRunner
+
+	statusReportEnabled  bool
+	statusReportInterval time.Duration
+	nodeName             string
+	nodeSLOClient        clientsetv1alpha1.NodeSLOInterface
+	strategyResults      *strategyResults
+	// kernelCapabilities is probed once since the kernel does not change at runtime
+	kernelCapabilities     []slov1alpha1.KernelCapability
+	kernelCapabilitiesOnce sync.Once
 }
 
 func NewNodeSLOInformer() *nodeSLOInformer {
-	return &nodeSLOInformer{}
+	return &nodeSLOInformer{
+		strategyResults: newStrategyResults(),
+	}
 }
 
 func (s *nodeSLOInformer) GetNodeSLO() *slov1alpha1.NodeSLO {
@@ -86,11 +100,19 @@ func (s *nodeSLOInformer) Setup(ctx *PluginOption, state *PluginState) {
 		},
 	})
 	s.callbackRunner = state.callbackRunner
+
+	s.statusReportEnabled = ctx.config.EnableNodeSLOStatusReport && ctx.config.NodeSLOStatusReportInterval > 0
+	s.statusReportInterval = ctx.config.NodeSLOStatusReportInterval
+	s.nodeName = ctx.NodeName
+	s.nodeSLOClient = ctx.KoordClient.SloV1alpha1().NodeSLOs()
 }
 
 func (s *nodeSLOInformer) Start(stopCh <-chan struct{}) {
 	klog.V(2).Infof("starting node slo informer")
 	go s.nodeSLOInformer.Run(stopCh)
+	if s.statusReportEnabled {
+		go s.syncNodeSLOStatusWorker(stopCh)
+	}
 	klog.V(2).Infof("node slo informer started")
 }
 
@@ -119,6 +141,7 @@ func (s *nodeSLOInformer) setNodeSLOSpec(nodeSLO *slov1alpha1.NodeSLO) {
 	} else {
 		s.nodeSLO.Spec = nodeSLO.Spec
 	}
+	s.observedGeneration = nodeSLO.Generation
 
 	// merge nodeSLO spec with the default config
 	s.mergeNodeSLOSpec(nodeSLO)
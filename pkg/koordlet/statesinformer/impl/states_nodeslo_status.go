@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"sync"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	strategyReasonDisabled           = "Disabled"
+	strategyReasonKernelNotSupported = "KernelNotSupported"
+	strategyReasonApplyFailed        = "ApplyFailed"
+	strategyReasonApplied            = "Applied"
+	strategyReasonConfigured         = "Configured"
+)
+
+var probeKernelCapabilities = system.ProbeKernelCapabilities
+
+// strategyResults records the latest apply results of the strategies reported by the qos plugins. A nil error means
+// the strategy is applied successfully, while a strategy which has no record is not reported by any plugin yet.
+type strategyResults struct {
+	lock    sync.RWMutex
+	results map[string]error
+}
+
+func newStrategyResults() *strategyResults {
+	return &strategyResults{
+		results: map[string]error{},
+	}
+}
+
+func (r *strategyResults) set(strategyName string, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.results[strategyName] = err
+}
+
+func (r *strategyResults) get(strategyName string) (error, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	err, reported := r.results[strategyName]
+	return err, reported
+}
+
+func (s *nodeSLOInformer) SetNodeSLOStrategyResult(strategyName string, err error) {
+	s.strategyResults.set(strategyName, err)
+}
+
+func (s *nodeSLOInformer) getKernelCapabilities() []slov1alpha1.KernelCapability {
+	s.kernelCapabilitiesOnce.Do(func() {
+		for _, c := range probeKernelCapabilities() {
+			s.kernelCapabilities = append(s.kernelCapabilities, slov1alpha1.KernelCapability{
+				Name:      c.Name,
+				Supported: c.Supported,
+				Message:   c.Message,
+			})
+		}
+		klog.V(4).Infof("kernel capabilities probed: %+v", s.kernelCapabilities)
+	})
+	return s.kernelCapabilities
+}
+
+func (s *nodeSLOInformer) syncNodeSLOStatusWorker(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, s.nodeSLOInformer.HasSynced) {
+		klog.Errorf("timed out waiting for node slo caches to sync")
+		return
+	}
+	wait.Until(s.syncNodeSLOStatus, s.statusReportInterval, stopCh)
+}
+
+func (s *nodeSLOInformer) syncNodeSLOStatus() {
+	obj, exists, err := s.nodeSLOInformer.GetStore().GetByKey(s.nodeName)
+	if err != nil || !exists {
+		klog.V(5).Infof("nodeSLO %s is not found, skip reporting status, err: %v", s.nodeName, err)
+		return
+	}
+	current, ok := obj.(*slov1alpha1.NodeSLO)
+	if !ok {
+		klog.Errorf("unable to convert object to *slov1alpha1.NodeSLO, got %T", obj)
+		return
+	}
+
+	s.nodeSLORWMutex.RLock()
+	if s.nodeSLO == nil {
+		s.nodeSLORWMutex.RUnlock()
+		return
+	}
+	spec := s.nodeSLO.Spec.DeepCopy()
+	observedGeneration := s.observedGeneration
+	s.nodeSLORWMutex.RUnlock()
+
+	capabilities := s.getKernelCapabilities()
+	newStatus := slov1alpha1.NodeSLOStatus{
+		ObservedGeneration: observedGeneration,
+		Strategies:         s.generateStrategyStatuses(spec, capabilities),
+		KernelCapabilities: capabilities,
+	}
+	if apiequality.Semantic.DeepEqual(current.Status, newStatus) {
+		klog.V(6).Infof("nodeSLO %s status has not changed, skip reporting", s.nodeName)
+		return
+	}
+
+	newNodeSLO := current.DeepCopy()
+	newNodeSLO.Status = newStatus
+	if _, err = s.nodeSLOClient.UpdateStatus(context.TODO(), newNodeSLO, metav1.UpdateOptions{}); err != nil {
+		klog.Warningf("failed to update nodeSLO %s status, err: %v", s.nodeName, err)
+		return
+	}
+	klog.V(4).Infof("update nodeSLO %s status successfully, observed generation %d", s.nodeName, observedGeneration)
+}
+
+// generateStrategyStatuses generates the apply results of the strategies according to the merged spec, the kernel
+// capabilities and the results reported by the qos plugins. The strategy is reported Applied only if the plugin
+// applying it has reported a successful reconciliation, otherwise it is reported Configured.
+func (s *nodeSLOInformer) generateStrategyStatuses(spec *slov1alpha1.NodeSLOSpec,
+	capabilities []slov1alpha1.KernelCapability) []slov1alpha1.StrategyStatus {
+	capabilityMap := make(map[string]slov1alpha1.KernelCapability, len(capabilities))
+	for _, c := range capabilities {
+		capabilityMap[c.Name] = c
+	}
+
+	qosStrategy := spec.ResourceQOSStrategy
+	cpuQOSCapability := system.KernelCapabilityGroupIdentity
+	if qosStrategy != nil && qosStrategy.Policies != nil && qosStrategy.Policies.CPUPolicy != nil &&
+		*qosStrategy.Policies.CPUPolicy == slov1alpha1.CPUQOSPolicyCoreSched {
+		cpuQOSCapability = system.KernelCapabilityCoreSched
+	}
+	cpuBurstEnabled, cpuBurstCapability := false, ""
+	if spec.CPUBurstStrategy != nil {
+		switch spec.CPUBurstStrategy.Policy {
+		case slov1alpha1.CPUBurstOnly, slov1alpha1.CPUBurstAuto:
+			cpuBurstEnabled, cpuBurstCapability = true, system.KernelCapabilityCPUBurst
+		case slov1alpha1.CFSQuotaBurstOnly:
+			cpuBurstEnabled = true
+		}
+	}
+
+	strategies := []struct {
+		name       string
+		enabled    bool
+		capability string
+	}{
+		{
+			name: slov1alpha1.StrategyNameCPUQOS,
+			enabled: isResourceQOSEnabled(qosStrategy, func(qos *slov1alpha1.ResourceQOS) *bool {
+				if qos.CPUQOS == nil {
+					return nil
+				}
+				return qos.CPUQOS.Enable
+			}),
+			capability: cpuQOSCapability,
+		},
+		{
+			name: slov1alpha1.StrategyNameMemoryQOS,
+			enabled: isResourceQOSEnabled(qosStrategy, func(qos *slov1alpha1.ResourceQOS) *bool {
+				if qos.MemoryQOS == nil {
+					return nil
+				}
+				return qos.MemoryQOS.Enable
+			}),
+			capability: system.KernelCapabilityMemoryQOS,
+		},
+		{
+			name: slov1alpha1.StrategyNameResctrlQOS,
+			enabled: isResourceQOSEnabled(qosStrategy, func(qos *slov1alpha1.ResourceQOS) *bool {
+				if qos.ResctrlQOS == nil {
+					return nil
+				}
+				return qos.ResctrlQOS.Enable
+			}),
+			capability: system.KernelCapabilityResctrl,
+		},
+		{
+			name: slov1alpha1.StrategyNameBlkIOQOS,
+			enabled: isResourceQOSEnabled(qosStrategy, func(qos *slov1alpha1.ResourceQOS) *bool {
+				if qos.BlkIOQOS == nil {
+					return nil
+				}
+				return qos.BlkIOQOS.Enable
+			}),
+			capability: system.KernelCapabilityBlkIOQOS,
+		},
+		{
+			name:       slov1alpha1.StrategyNameCPUBurst,
+			enabled:    cpuBurstEnabled,
+			capability: cpuBurstCapability,
+		},
+		{
+			name:    slov1alpha1.StrategyNameSystem,
+			enabled: spec.SystemStrategy != nil,
+		},
+	}
+
+	statuses := make([]slov1alpha1.StrategyStatus, 0, len(strategies))
+	for _, strategy := range strategies {
+		status := slov1alpha1.StrategyStatus{Name: strategy.name}
+		capability, hasCapability := capabilityMap[strategy.capability]
+		if !strategy.enabled {
+			status.Phase, status.Reason = slov1alpha1.StrategyPhaseDisabled, strategyReasonDisabled
+		} else if hasCapability && !capability.Supported {
+			status.Phase, status.Reason = slov1alpha1.StrategyPhaseSkipped, strategyReasonKernelNotSupported
+			status.Message = capability.Message
+		} else if err, reported := s.strategyResults.get(strategy.name); !reported {
+			// the plugin applying the strategy may be disabled or not running yet, so the strategy is only known
+			// to be configured in the spec
+			status.Phase, status.Reason = slov1alpha1.StrategyPhaseEnabled, strategyReasonConfigured
+		} else if err != nil {
+			status.Phase, status.Reason = slov1alpha1.StrategyPhaseFailed, strategyReasonApplyFailed
+			status.Message = err.Error()
+		} else {
+			status.Phase, status.Reason = slov1alpha1.StrategyPhaseEnabled, strategyReasonApplied
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// isResourceQOSEnabled returns whether the resource qos is enabled for any of the QoS classes.
+func isResourceQOSEnabled(strategy *slov1alpha1.ResourceQOSStrategy, getEnable func(qos *slov1alpha1.ResourceQOS) *bool) bool {
+	if strategy == nil {
+		return false
+	}
+	for _, qos := range []*slov1alpha1.ResourceQOS{strategy.LSRClass, strategy.LSClass, strategy.BEClass, strategy.SystemClass} {
+		if qos == nil {
+			continue
+		}
+		if enable := getEnable(qos); enable != nil && *enable {
+			return true
+		}
+	}
+	return false
+}
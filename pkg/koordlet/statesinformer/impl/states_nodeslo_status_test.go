@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	fakekoordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_generateStrategyStatuses(t *testing.T) {
+	allSupported := []slov1alpha1.KernelCapability{
+		{Name: system.KernelCapabilityCPUBurst, Supported: true},
+		{Name: system.KernelCapabilityGroupIdentity, Supported: true},
+		{Name: system.KernelCapabilityCoreSched, Supported: true},
+		{Name: system.KernelCapabilityMemoryQOS, Supported: true},
+		{Name: system.KernelCapabilityResctrl, Supported: true},
+		{Name: system.KernelCapabilityBlkIOQOS, Supported: true},
+	}
+	tests := []struct {
+		name         string
+		spec         *slov1alpha1.NodeSLOSpec
+		capabilities []slov1alpha1.KernelCapability
+		results      map[string]error
+		wantStatuses map[string]slov1alpha1.StrategyStatus
+	}{
+		{
+			name:         "all strategies disabled",
+			spec:         &slov1alpha1.NodeSLOSpec{},
+			capabilities: allSupported,
+			wantStatuses: map[string]slov1alpha1.StrategyStatus{
+				slov1alpha1.StrategyNameCPUQOS:     {Name: slov1alpha1.StrategyNameCPUQOS, Phase: slov1alpha1.StrategyPhaseDisabled, Reason: strategyReasonDisabled},
+				slov1alpha1.StrategyNameMemoryQOS:  {Name: slov1alpha1.StrategyNameMemoryQOS, Phase: slov1alpha1.StrategyPhaseDisabled, Reason: strategyReasonDisabled},
+				slov1alpha1.StrategyNameResctrlQOS: {Name: slov1alpha1.StrategyNameResctrlQOS, Phase: slov1alpha1.StrategyPhaseDisabled, Reason: strategyReasonDisabled},
+				slov1alpha1.StrategyNameBlkIOQOS:   {Name: slov1alpha1.StrategyNameBlkIOQOS, Phase: slov1alpha1.StrategyPhaseDisabled, Reason: strategyReasonDisabled},
+				slov1alpha1.StrategyNameCPUBurst:   {Name: slov1alpha1.StrategyNameCPUBurst, Phase: slov1alpha1.StrategyPhaseDisabled, Reason: strategyReasonDisabled},
+				slov1alpha1.StrategyNameSystem:     {Name: slov1alpha1.StrategyNameSystem, Phase: slov1alpha1.StrategyPhaseDisabled, Reason: strategyReasonDisabled},
+			},
+		},
+		{
+			name: "strategies applied, configured, skipped and failed",
+			spec: &slov1alpha1.NodeSLOSpec{
+				ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+					Policies: &slov1alpha1.ResourceQOSPolicies{
+						CPUPolicy: ptr.To(slov1alpha1.CPUQOSPolicyCoreSched),
+					},
+					LSClass: &slov1alpha1.ResourceQOS{
+						CPUQOS:    &slov1alpha1.CPUQOSCfg{Enable: ptr.To(true)},
+						MemoryQOS: &slov1alpha1.MemoryQOSCfg{Enable: ptr.To(false)},
+					},
+					BEClass: &slov1alpha1.ResourceQOS{
+						ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{Enable: ptr.To(true)},
+						BlkIOQOS:   &slov1alpha1.BlkIOQOSCfg{Enable: ptr.To(true)},
+					},
+				},
+				CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+					CPUBurstConfig: slov1alpha1.CPUBurstConfig{Policy: slov1alpha1.CPUBurstAuto},
+				},
+				SystemStrategy: &slov1alpha1.SystemStrategy{},
+			},
+			capabilities: []slov1alpha1.KernelCapability{
+				{Name: system.KernelCapabilityCPUBurst, Supported: false, Message: "cpu burst not supported"},
+				{Name: system.KernelCapabilityGroupIdentity, Supported: false, Message: "group identity not supported"},
+				{Name: system.KernelCapabilityCoreSched, Supported: true},
+				{Name: system.KernelCapabilityMemoryQOS, Supported: true},
+				{Name: system.KernelCapabilityResctrl, Supported: true},
+				{Name: system.KernelCapabilityBlkIOQOS, Supported: true},
+			},
+			results: map[string]error{
+				slov1alpha1.StrategyNameResctrlQOS: fmt.Errorf("resctrl mount failed"),
+				slov1alpha1.StrategyNameBlkIOQOS:   nil,
+				slov1alpha1.StrategyNameSystem:     nil,
+			},
+			wantStatuses: map[string]slov1alpha1.StrategyStatus{
+				slov1alpha1.StrategyNameCPUQOS:     {Name: slov1alpha1.StrategyNameCPUQOS, Phase: slov1alpha1.StrategyPhaseEnabled, Reason: strategyReasonConfigured},
+				slov1alpha1.StrategyNameMemoryQOS:  {Name: slov1alpha1.StrategyNameMemoryQOS, Phase: slov1alpha1.StrategyPhaseDisabled, Reason: strategyReasonDisabled},
+				slov1alpha1.StrategyNameResctrlQOS: {Name: slov1alpha1.StrategyNameResctrlQOS, Phase: slov1alpha1.StrategyPhaseFailed, Reason: strategyReasonApplyFailed, Message: "resctrl mount failed"},
+				slov1alpha1.StrategyNameBlkIOQOS:   {Name: slov1alpha1.StrategyNameBlkIOQOS, Phase: slov1alpha1.StrategyPhaseEnabled, Reason: strategyReasonApplied},
+				slov1alpha1.StrategyNameCPUBurst:   {Name: slov1alpha1.StrategyNameCPUBurst, Phase: slov1alpha1.StrategyPhaseSkipped, Reason: strategyReasonKernelNotSupported, Message: "cpu burst not supported"},
+				slov1alpha1.StrategyNameSystem:     {Name: slov1alpha1.StrategyNameSystem, Phase: slov1alpha1.StrategyPhaseEnabled, Reason: strategyReasonApplied},
+			},
+		},
+		{
+			name: "cfs quota burst does not rely on the kernel",
+			spec: &slov1alpha1.NodeSLOSpec{
+				CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+					CPUBurstConfig: slov1alpha1.CPUBurstConfig{Policy: slov1alpha1.CFSQuotaBurstOnly},
+				},
+			},
+			capabilities: []slov1alpha1.KernelCapability{
+				{Name: system.KernelCapabilityCPUBurst, Supported: false, Message: "cpu burst not supported"},
+			},
+			wantStatuses: map[string]slov1alpha1.StrategyStatus{
+				slov1alpha1.StrategyNameCPUBurst: {Name: slov1alpha1.StrategyNameCPUBurst, Phase: slov1alpha1.StrategyPhaseEnabled, Reason: strategyReasonConfigured},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewNodeSLOInformer()
+			for name, err := range tt.results {
+				s.SetNodeSLOStrategyResult(name, err)
+			}
+			got := s.generateStrategyStatuses(tt.spec, tt.capabilities)
+			assert.Len(t, got, 6)
+			for _, status := range got {
+				want, ok := tt.wantStatuses[status.Name]
+				if !ok {
+					continue
+				}
+				assert.Equal(t, want, status)
+			}
+		})
+	}
+}
+
+func Test_syncNodeSLOStatus(t *testing.T) {
+	oldProbe := probeKernelCapabilities
+	defer func() {
+		probeKernelCapabilities = oldProbe
+	}()
+	probeKernelCapabilities = func() []system.KernelCapability {
+		return []system.KernelCapability{
+			{Name: system.KernelCapabilityCPUBurst, Supported: false, Message: "cpu burst not supported"},
+		}
+	}
+
+	nodeName := "test-node"
+	nodeSLO := &slov1alpha1.NodeSLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       nodeName,
+			Generation: 2,
+		},
+		Spec: slov1alpha1.NodeSLOSpec{
+			SystemStrategy: &slov1alpha1.SystemStrategy{},
+		},
+	}
+	client := fakekoordclientset.NewSimpleClientset(nodeSLO)
+	s := NewNodeSLOInformer()
+	s.nodeName = nodeName
+	s.nodeSLOClient = client.SloV1alpha1().NodeSLOs()
+	s.nodeSLOInformer = newNodeSLOInformer(client, nodeName)
+
+	// skip if the nodeSLO is not found
+	s.syncNodeSLOStatus()
+
+	assert.NoError(t, s.nodeSLOInformer.GetStore().Add(nodeSLO))
+	s.setNodeSLOSpec(nodeSLO)
+	s.SetNodeSLOStrategyResult(slov1alpha1.StrategyNameSystem, fmt.Errorf("write failed"))
+	s.syncNodeSLOStatus()
+
+	got, err := client.SloV1alpha1().NodeSLOs().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), got.Status.ObservedGeneration)
+	assert.Equal(t, []slov1alpha1.KernelCapability{
+		{Name: system.KernelCapabilityCPUBurst, Supported: false, Message: "cpu burst not supported"},
+	}, got.Status.KernelCapabilities)
+	assert.Len(t, got.Status.Strategies, 6)
+	for _, status := range got.Status.Strategies {
+		if status.Name != slov1alpha1.StrategyNameSystem {
+			continue
+		}
+		assert.Equal(t, slov1alpha1.StrategyStatus{
+			Name:    slov1alpha1.StrategyNameSystem,
+			Phase:   slov1alpha1.StrategyPhaseFailed,
+			Reason:  strategyReasonApplyFailed,
+			Message: "write failed",
+		}, status)
+	}
+}
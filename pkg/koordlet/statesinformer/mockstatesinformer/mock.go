@@ -183,3 +183,15 @@ func (mr *MockStatesInformerMockRecorder) Run(stopCh any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockStatesInformer)(nil).Run), stopCh)
 }
+
+// SetNodeSLOStrategyResult mocks base method.
+func (m *MockStatesInformer) SetNodeSLOStrategyResult(strategyName string, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetNodeSLOStrategyResult", strategyName, err)
+}
+
+// SetNodeSLOStrategyResult indicates an expected call of SetNodeSLOStrategyResult.
+func (mr *MockStatesInformerMockRecorder) SetNodeSLOStrategyResult(strategyName, err any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNodeSLOStrategyResult", reflect.TypeOf((*MockStatesInformer)(nil).SetNodeSLOStrategyResult), strategyName, err)
+}
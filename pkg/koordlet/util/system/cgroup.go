@@ -57,6 +57,8 @@ type MemoryStatRaw struct {
 	InactiveAnon int64
 	ActiveAnon   int64
 	Unevictable  int64
+	// WorkingsetRefault is the accumulated count of the refaulted pages, which is zero if the kernel does not support.
+	WorkingsetRefault int64
 	// add more fields
 }
 
@@ -164,10 +166,29 @@ func ParseMemoryStatRaw(content string) (*MemoryStatRaw, error) {
 		}
 		*t.value = v
 	}
+	memoryStatRaw.WorkingsetRefault = parseWorkingsetRefault(m, "total_")
 
 	return memoryStatRaw, nil
 }
 
+// parseWorkingsetRefault returns the refaulted pages in the memory.stat. The refault is split into the anon and file
+// since kernel 5.9, and the missing fields are ignored since it is not supported on the old kernels.
+func parseWorkingsetRefault(m map[string]string, prefix string) int64 {
+	var refault int64
+	for _, key := range []string{"workingset_refault", "workingset_refault_anon", "workingset_refault_file"} {
+		valueStr, ok := m[prefix+key]
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseInt(valueStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		refault += v
+	}
+	return refault
+}
+
 func ParseMemoryNumaStat(content string) ([]NumaMemoryPages, error) {
 	stat := []NumaMemoryPages{}
 	parseErr := errors.New("parse cgroup memory numa stat err")
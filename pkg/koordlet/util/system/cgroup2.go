@@ -229,6 +229,7 @@ func ParseMemoryStatRawV2(content string) (*MemoryStatRaw, error) {
 		}
 		*t.value = v
 	}
+	memoryStatRaw.WorkingsetRefault = parseWorkingsetRefault(m, "")
 
 	return memoryStatRaw, nil
 }
@@ -231,6 +231,20 @@ func TestParseMemoryStatRawV2(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			input: "file 100\nanon 200\ninactive_file 50\nactive_file 60\ninactive_anon 70\nactive_anon 80\nunevictable 10\nworkingset_refault_anon 5\nworkingset_refault_file 15",
+			want: &MemoryStatRaw{
+				Cache:             100,
+				RSS:               200,
+				InactiveFile:      50,
+				ActiveFile:        60,
+				InactiveAnon:      70,
+				ActiveAnon:        80,
+				Unevictable:       10,
+				WorkingsetRefault: 20,
+			},
+			wantErr: false,
+		},
 		{
 			input:   "file not_a_number\nanon 200\ninactive_file 50\nactive_file 60\ninactive_anon 70\nactive_anon 80\nunevictable 10",
 			want:    nil,
@@ -268,7 +282,8 @@ func compareMemoryStatRaw(a, b *MemoryStatRaw) bool {
 		a.ActiveFile == b.ActiveFile &&
 		a.InactiveAnon == b.InactiveAnon &&
 		a.ActiveAnon == b.ActiveAnon &&
-		a.Unevictable == b.Unevictable
+		a.Unevictable == b.Unevictable &&
+		a.WorkingsetRefault == b.WorkingsetRefault
 }
 
 func TestParseMemoryNumaStatV2(t *testing.T) {
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+)
+
+// The kernel capabilities which the NodeSLO strategies rely on.
+const (
+	KernelCapabilityCPUBurst      = "CPUBurst"
+	KernelCapabilityGroupIdentity = "GroupIdentity"
+	KernelCapabilityCoreSched     = "CoreSched"
+	KernelCapabilityMemoryQOS     = "MemoryQOS"
+	KernelCapabilityResctrl       = "Resctrl"
+	KernelCapabilityBlkIOQOS      = "BlkIOQOS"
+)
+
+// KernelCapability is the probe result of a kernel capability.
+type KernelCapability struct {
+	Name      string
+	Supported bool
+	Message   string
+}
+
+type kernelCapabilityProbe struct {
+	name  string
+	probe func() (bool, string)
+}
+
+var kernelCapabilityProbes = []kernelCapabilityProbe{
+	{name: KernelCapabilityCPUBurst, probe: probeCgroupResourceInKubepods(CPUBurstName)},
+	{name: KernelCapabilityGroupIdentity, probe: probeCgroupResourceInKubepods(CPUBVTWarpNsName)},
+	{name: KernelCapabilityCoreSched, probe: IsCoreSchedSupported},
+	{name: KernelCapabilityMemoryQOS, probe: CheckIfAllSupported(
+		probeCgroupResourceInKubepods(MemoryMinName),
+		probeCgroupResourceInKubepods(MemoryLowName),
+		probeCgroupResourceInKubepods(MemoryHighName))},
+	{name: KernelCapabilityResctrl, probe: probeResctrl},
+	{name: KernelCapabilityBlkIOQOS, probe: probeCgroupResourceInKubepods(BlkioTRIopsName)},
+}
+
+// ProbeKernelCapabilities probes whether the kernel capabilities are supported on the node.
+func ProbeKernelCapabilities() []KernelCapability {
+	capabilities := make([]KernelCapability, 0, len(kernelCapabilityProbes))
+	for _, p := range kernelCapabilityProbes {
+		supported, msg := p.probe()
+		capabilities = append(capabilities, KernelCapability{
+			Name:      p.name,
+			Supported: supported,
+			Message:   msg,
+		})
+	}
+	return capabilities
+}
+
+func probeCgroupResourceInKubepods(resourceType ResourceType) func() (bool, string) {
+	return func() (bool, string) {
+		r, err := GetCgroupResource(resourceType)
+		if err != nil {
+			return false, err.Error()
+		}
+		return r.IsSupported(CgroupPathFormatter.ParentDir)
+	}
+}
+
+func probeResctrl() (bool, string) {
+	supported, err := IsSupportResctrl()
+	if err != nil {
+		return false, fmt.Sprintf("failed to check resctrl, err: %v", err)
+	}
+	if !supported {
+		return false, "resctrl is not supported by cpu"
+	}
+	return true, ""
+}
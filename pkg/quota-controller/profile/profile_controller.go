@@ -28,6 +28,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -51,10 +52,16 @@ import (
 const Name = "quotaprofile"
 
 const (
-	ReasonCreateQuotaFailed = "CreateQuotaFailed"
-	ReasonUpdateQuotaFailed = "UpdateQuotaFailed"
+	ReasonCreateQuotaFailed      = "CreateQuotaFailed"
+	ReasonUpdateQuotaFailed      = "UpdateQuotaFailed"
+	ReasonDeriveChildMinFailed   = "DeriveChildMinFailed"
+	ReasonUpdateChildQuotaFailed = "UpdateChildQuotaFailed"
+	ReasonSynced                 = "Synced"
 )
 
+// statusSyncPeriod is the max period to refresh the LastSyncTime of the profile if nothing else changes.
+const statusSyncPeriod = time.Minute
+
 var resourceDecorators = []func(profile *v1alpha1.ElasticQuotaProfile, total corev1.ResourceList){
 	DecorateResourceByResourceRatio,
 }
@@ -140,10 +147,12 @@ func (r *QuotaProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// TODO: consider node status.
 	totalResource := corev1.ResourceList{}
 	unschedulableResource := corev1.ResourceList{}
+	var unschedulableNodeCount int32
 	for _, node := range nodeList.Items {
 		totalResource = quotav1.Add(totalResource, GetNodeAllocatable(node))
 		if node.Spec.Unschedulable || !nodeutil.IsNodeReady(&node) {
 			unschedulableResource = quotav1.Add(unschedulableResource, GetNodeAllocatable(node))
+			unschedulableNodeCount++
 		}
 	}
 
@@ -211,25 +220,262 @@ func (r *QuotaProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 	quota.Annotations[extension.AnnotationUnschedulableResource] = string(data)
 
+	status := profile.Status.DeepCopy()
+	status.TotalResource = totalResource
+	status.UnschedulableResource = unschedulableResource
+	status.NodeCount = int32(len(nodeList.Items))
+	status.UnschedulableNodeCount = unschedulableNodeCount
+
+	// the shrinking resources of the child quotas are updated before the quota and the growing ones after the quota,
+	// so that the sum of the min of the child quotas never exceeds the min of the quota.
+	childUpdates, childMin, err := r.deriveChildQuotas(ctx, profile, min)
+	if profile.Spec.ChildMinPolicy == nil {
+		status.ChildMin = nil
+		meta.RemoveStatusCondition(&status.Conditions, v1alpha1.ElasticQuotaProfileChildMinSynced)
+	} else if err != nil {
+		r.Recorder.Eventf(profile, corev1.EventTypeWarning, ReasonDeriveChildMinFailed, "failed to derive child min, err: %s", err)
+		klog.Errorf("failed derive child min for profile %v, error: %v", req.NamespacedName, err)
+		setCondition(profile, status, v1alpha1.ElasticQuotaProfileChildMinSynced, ReasonDeriveChildMinFailed, err)
+	} else {
+		status.ChildMin = childMin
+	}
+
+	childErr := r.updateChildQuotas(profile, childUpdates, false)
+	quotaReason, quotaErr := r.syncQuota(profile, quota, oldQuota, quotaExist)
+	setCondition(profile, status, v1alpha1.ElasticQuotaProfileQuotaSynced, quotaReason, quotaErr)
+	if quotaErr == nil && childErr == nil {
+		childErr = r.updateChildQuotas(profile, childUpdates, true)
+	}
+	if profile.Spec.ChildMinPolicy != nil && err == nil {
+		reason := ReasonSynced
+		if childErr != nil {
+			reason = ReasonUpdateChildQuotaFailed
+		}
+		setCondition(profile, status, v1alpha1.ElasticQuotaProfileChildMinSynced, reason, childErr)
+	}
+
+	if err := r.updateStatus(profile, status); err != nil {
+		klog.Errorf("failed update status of profile %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+	if quotaErr != nil || childErr != nil {
+		return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
+// syncQuota creates or updates the quota of the profile, it returns the reason of the QuotaSynced condition.
+func (r *QuotaProfileReconciler) syncQuota(profile *v1alpha1.ElasticQuotaProfile, quota, oldQuota *schedv1alpha1.ElasticQuota, quotaExist bool) (string, error) {
 	if !quotaExist {
-		err = r.Client.Create(context.TODO(), quota)
+		err := r.Client.Create(context.TODO(), quota)
 		if err != nil {
 			r.Recorder.Eventf(profile, corev1.EventTypeWarning, ReasonCreateQuotaFailed, "failed to create quota, err: %s", err)
-			klog.Errorf("failed create quota for profile %v, error: %v", req.NamespacedName, err)
-			return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+			klog.Errorf("failed create quota for profile %v/%v, error: %v", profile.Namespace, profile.Name, err)
+			return ReasonCreateQuotaFailed, err
 		}
 	} else {
 		if !reflect.DeepEqual(quota.Labels, oldQuota.Labels) || !reflect.DeepEqual(quota.Annotations, oldQuota.Annotations) || !reflect.DeepEqual(quota.Spec, oldQuota.Spec) {
-			err = r.Client.Update(context.TODO(), quota)
+			err := r.Client.Update(context.TODO(), quota)
 			if err != nil {
 				r.Recorder.Eventf(profile, corev1.EventTypeWarning, ReasonUpdateQuotaFailed, "failed to update quota, err: %s", err)
-				klog.Errorf("failed update quota for profile %v, error: %v", req.NamespacedName, err)
-				return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+				klog.Errorf("failed update quota for profile %v/%v, error: %v", profile.Namespace, profile.Name, err)
+				return ReasonUpdateQuotaFailed, err
 			}
 		}
 	}
+	return ReasonSynced, nil
+}
 
-	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+// childQuotaUpdate is the update of the min of a child quota. The quota has the shrinking resources applied, which
+// are updated before the quota of the profile, and the growing resources are updated after it.
+type childQuotaUpdate struct {
+	quota *schedv1alpha1.ElasticQuota
+	// shrunk indicates some resources of the min shrink
+	shrunk bool
+	// grownMin is the derived min if some resources of the min grow, otherwise nil
+	grownMin corev1.ResourceList
+}
+
+// deriveChildQuotas derives the min of the child quotas of the profile's quota by the ChildMinPolicy. It returns
+// the updates of the child quotas whose min changes, and the derived min keyed by the quota name.
+func (r *QuotaProfileReconciler) deriveChildQuotas(ctx context.Context, profile *v1alpha1.ElasticQuotaProfile, min corev1.ResourceList) (updates []*childQuotaUpdate, childMin map[string]corev1.ResourceList, err error) {
+	policy := profile.Spec.ChildMinPolicy
+	if policy == nil {
+		return nil, nil, nil
+	}
+	if policy.Type != v1alpha1.ChildMinPolicyProportional {
+		return nil, nil, fmt.Errorf("unsupported child min policy type %q", policy.Type)
+	}
+	weightLabelKey := policy.WeightLabelKey
+	if weightLabelKey == "" {
+		weightLabelKey = extension.LabelQuotaMinWeight
+	}
+
+	quotaList := &schedv1alpha1.ElasticQuotaList{}
+	if err := r.Client.List(ctx, quotaList, client.MatchingLabels{extension.LabelQuotaParent: profile.Spec.QuotaName}); err != nil {
+		return nil, nil, err
+	}
+	treeID := profile.Labels[extension.LabelQuotaTreeID]
+	children := make([]schedv1alpha1.ElasticQuota, 0, len(quotaList.Items))
+	for _, child := range quotaList.Items {
+		if childTreeID := child.Labels[extension.LabelQuotaTreeID]; childTreeID != "" && childTreeID != treeID {
+			continue
+		}
+		children = append(children, child)
+	}
+
+	childMin, err = proportionalChildMin(min, children, weightLabelKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range children {
+		child := &children[i]
+		derived, ok := childMin[child.Name]
+		if !ok {
+			continue
+		}
+		// the resources are split by the direction, and the shrunk min keeps the old quantities of the growing ones
+		shrunkMin := child.Spec.Min.DeepCopy()
+		if shrunkMin == nil {
+			shrunkMin = corev1.ResourceList{}
+		}
+		newMin := shrunkMin.DeepCopy()
+		var shrunk, grown bool
+		for resourceName, quantity := range derived {
+			oldQuantity, exist := shrunkMin[resourceName]
+			switch cmp := quantity.Cmp(oldQuantity); {
+			case cmp < 0 || (cmp == 0 && !exist):
+				shrunkMin[resourceName] = quantity
+				shrunk = true
+			case cmp > 0:
+				grown = true
+			}
+			newMin[resourceName] = quantity
+		}
+		if !shrunk && !grown {
+			continue
+		}
+		update := &childQuotaUpdate{quota: child.DeepCopy(), shrunk: shrunk}
+		update.quota.Spec.Min = shrunkMin
+		if grown {
+			update.grownMin = newMin
+		}
+		updates = append(updates, update)
+	}
+	return updates, childMin, nil
+}
+
+// updateChildQuotas updates the shrinking resources of the child quotas, or the growing ones if grow is true.
+func (r *QuotaProfileReconciler) updateChildQuotas(profile *v1alpha1.ElasticQuotaProfile, updates []*childQuotaUpdate, grow bool) error {
+	for _, update := range updates {
+		quota := update.quota
+		if grow {
+			if update.grownMin == nil {
+				continue
+			}
+			// the quota is refreshed by the update of the shrinking resources
+			quota.Spec.Min = update.grownMin
+		} else if !update.shrunk {
+			continue
+		}
+		if err := r.Client.Update(context.TODO(), quota); err != nil {
+			r.Recorder.Eventf(profile, corev1.EventTypeWarning, ReasonUpdateChildQuotaFailed, "failed to update child quota %s, err: %s", quota.Name, err)
+			klog.Errorf("failed update child quota %v/%v for profile %v/%v, error: %v", quota.Namespace, quota.Name, profile.Namespace, profile.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStatus writes the status if it changes or the LastSyncTime is older than the statusSyncPeriod.
+func (r *QuotaProfileReconciler) updateStatus(profile *v1alpha1.ElasticQuotaProfile, status *v1alpha1.ElasticQuotaProfileStatus) error {
+	now := metav1.Now()
+	if !isStatusChanged(&profile.Status, status) && profile.Status.LastSyncTime != nil &&
+		now.Sub(profile.Status.LastSyncTime.Time) < statusSyncPeriod {
+		return nil
+	}
+	status.LastSyncTime = &now
+	profile.Status = *status
+	return r.Client.Status().Update(context.TODO(), profile)
+}
+
+func isStatusChanged(oldStatus, newStatus *v1alpha1.ElasticQuotaProfileStatus) bool {
+	if !quotav1.Equals(oldStatus.TotalResource, newStatus.TotalResource) ||
+		!quotav1.Equals(oldStatus.UnschedulableResource, newStatus.UnschedulableResource) ||
+		oldStatus.NodeCount != newStatus.NodeCount ||
+		oldStatus.UnschedulableNodeCount != newStatus.UnschedulableNodeCount ||
+		len(oldStatus.ChildMin) != len(newStatus.ChildMin) ||
+		!reflect.DeepEqual(oldStatus.Conditions, newStatus.Conditions) {
+		return true
+	}
+	for name, min := range newStatus.ChildMin {
+		oldMin, ok := oldStatus.ChildMin[name]
+		if !ok || !quotav1.Equals(oldMin, min) {
+			return true
+		}
+	}
+	return false
+}
+
+func setCondition(profile *v1alpha1.ElasticQuotaProfile, status *v1alpha1.ElasticQuotaProfileStatus, conditionType, reason string, err error) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: profile.Generation,
+		Reason:             reason,
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// proportionalChildMin divides the min among the child quotas with weights in proportion to their weights, after
+// reserving the min of the child quotas without weights. The derived min of a child quota is capped by its max.
+func proportionalChildMin(min corev1.ResourceList, children []schedv1alpha1.ElasticQuota, weightLabelKey string) (map[string]corev1.ResourceList, error) {
+	weights := map[string]float64{}
+	var totalWeight float64
+	reserved := corev1.ResourceList{}
+	for _, child := range children {
+		raw, ok := child.Labels[weightLabelKey]
+		if !ok {
+			reserved = quotav1.Add(reserved, child.Spec.Min)
+			continue
+		}
+		weight, err := strconv.ParseFloat(raw, 64)
+		if err != nil || weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("invalid weight %q of quota %s/%s", raw, child.Namespace, child.Name)
+		}
+		weights[child.Name] = weight
+		totalWeight += weight
+	}
+
+	childMin := make(map[string]corev1.ResourceList, len(weights))
+	for _, child := range children {
+		weight, ok := weights[child.Name]
+		if !ok {
+			continue
+		}
+		derived := corev1.ResourceList{}
+		for resourceName, quantity := range min {
+			available := quantity.DeepCopy()
+			if reservedQuantity, ok := reserved[resourceName]; ok {
+				available.Sub(reservedQuantity)
+			}
+			ratio := 0.0
+			if totalWeight > 0 && available.Sign() > 0 {
+				ratio = weight / totalWeight
+			}
+			q := MultiplyQuantity(available, resourceName, ratio)
+			if max, ok := child.Spec.Max[resourceName]; ok && q.Cmp(max) > 0 {
+				q = max.DeepCopy()
+			}
+			derived[resourceName] = q
+		}
+		childMin[child.Name] = derived
+	}
+	return childMin, nil
 }
 
 func Add(mgr ctrl.Manager) error {
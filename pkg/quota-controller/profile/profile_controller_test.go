@@ -21,17 +21,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	quotav1alpha1 "github.com/koordinator-sh/koordinator/apis/quota/v1alpha1"
@@ -314,7 +319,7 @@ func TestQuotaProfileReconciler_Reconciler_CreateQuota(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			r := &QuotaProfileReconciler{
-				Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&quotav1alpha1.ElasticQuotaProfile{}).Build(),
 				Scheme: scheme,
 			}
 			// create node
@@ -353,6 +358,240 @@ func TestQuotaProfileReconciler_Reconciler_CreateQuota(t *testing.T) {
 	}
 }
 
+func TestQuotaProfileReconciler_Reconcile_StatusAndChildMin(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = quotav1alpha1.AddToScheme(scheme)
+	_ = schedv1alpha1.AddToScheme(scheme)
+	r := &QuotaProfileReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&quotav1alpha1.ElasticQuotaProfile{}).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+	poolLabels := map[string]string{"pool": "pool1"}
+	assert.NoError(t, r.Client.Create(context.TODO(), defaultCreateNode("node1", poolLabels, createResourceList(10, 100))))
+	assert.NoError(t, r.Client.Create(context.TODO(), defaultCreateUnreadyNode("node2", poolLabels, createResourceList(10, 100))))
+	assert.NoError(t, r.Client.Create(context.TODO(), defaultCreateNode("node3", nil, createResourceList(10, 100))))
+
+	profile := &quotav1alpha1.ElasticQuotaProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system",
+			Name:      "profile1",
+			Labels:    map[string]string{extension.LabelQuotaTreeID: "tree1"},
+		},
+		Spec: quotav1alpha1.ElasticQuotaProfileSpec{
+			QuotaName:      "root-quota",
+			NodeSelector:   &metav1.LabelSelector{MatchLabels: poolLabels},
+			ChildMinPolicy: &quotav1alpha1.ChildMinPolicy{Type: quotav1alpha1.ChildMinPolicyProportional},
+		},
+	}
+	assert.NoError(t, r.Client.Create(context.TODO(), profile))
+	createChild := func(name string, weight string, min, max corev1.ResourceList) {
+		labels := map[string]string{extension.LabelQuotaParent: "root-quota", extension.LabelQuotaTreeID: "tree1"}
+		if weight != "" {
+			labels[extension.LabelQuotaMinWeight] = weight
+		}
+		assert.NoError(t, r.Client.Create(context.TODO(), &schedv1alpha1.ElasticQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: name, Labels: labels},
+			Spec:       schedv1alpha1.ElasticQuotaSpec{Min: min, Max: max},
+		}))
+	}
+	createChild("child-a", "1", createResourceList(0, 0), createResourceList(100, 1000))
+	createChild("child-b", "3", createResourceList(0, 0), createResourceList(10, 1000))
+	createChild("child-c", "", createResourceList(4, 40), createResourceList(100, 1000))
+	// not in the tree
+	createChild("child-d", "1", createResourceList(0, 0), createResourceList(100, 1000))
+	childD := &schedv1alpha1.ElasticQuota{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Namespace: "team", Name: "child-d"}, childD))
+	childD.Labels[extension.LabelQuotaTreeID] = "tree2"
+	assert.NoError(t, r.Client.Update(context.TODO(), childD))
+
+	getChildMin := func(name string) corev1.ResourceList {
+		quota := &schedv1alpha1.ElasticQuota{}
+		assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Namespace: "team", Name: name}, quota))
+		return quota.Spec.Min
+	}
+	getProfile := func() *quotav1alpha1.ElasticQuotaProfile {
+		got := &quotav1alpha1.ElasticQuotaProfile{}
+		assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Namespace: "kube-system", Name: "profile1"}, got))
+		return got
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "kube-system", Name: "profile1"}}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	// the min of the root quota is 20 cores and 200, 16 cores and 160 are left after reserving the min of child-c
+	assert.True(t, quotav1.Equals(createResourceList(4, 40), getChildMin("child-a")))
+	// capped by the max
+	assert.True(t, quotav1.Equals(createResourceList(10, 120), getChildMin("child-b")))
+	assert.True(t, quotav1.Equals(createResourceList(4, 40), getChildMin("child-c")))
+	assert.True(t, quotav1.Equals(createResourceList(0, 0), getChildMin("child-d")))
+
+	got := getProfile()
+	assert.True(t, quotav1.Equals(createResourceList(20, 200), got.Status.TotalResource))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100), got.Status.UnschedulableResource))
+	assert.Equal(t, int32(2), got.Status.NodeCount)
+	assert.Equal(t, int32(1), got.Status.UnschedulableNodeCount)
+	assert.Len(t, got.Status.ChildMin, 2)
+	assert.NotNil(t, got.Status.LastSyncTime)
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, quotav1alpha1.ElasticQuotaProfileQuotaSynced))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, quotav1alpha1.ElasticQuotaProfileChildMinSynced))
+
+	// the capacity of the node pool shrinks
+	node1 := &corev1.Node{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "node1"}, node1))
+	node1.Labels = nil
+	assert.NoError(t, r.Client.Update(context.TODO(), node1))
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	// the child quotas shrink before the root quota
+	assert.True(t, quotav1.Equals(corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(1500, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(15, resource.BinarySI),
+	}, getChildMin("child-a")))
+	assert.True(t, quotav1.Equals(corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(4500, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(45, resource.BinarySI),
+	}, getChildMin("child-b")))
+	got = getProfile()
+	assert.Equal(t, int32(1), got.Status.NodeCount)
+
+	// invalid weight
+	childA := &schedv1alpha1.ElasticQuota{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Namespace: "team", Name: "child-a"}, childA))
+	childA.Labels[extension.LabelQuotaMinWeight] = "abc"
+	assert.NoError(t, r.Client.Update(context.TODO(), childA))
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	got = getProfile()
+	condition := meta.FindStatusCondition(got.Status.Conditions, quotav1alpha1.ElasticQuotaProfileChildMinSynced)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonDeriveChildMinFailed, condition.Reason)
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, quotav1alpha1.ElasticQuotaProfileQuotaSynced))
+}
+
+func TestQuotaProfileReconciler_Reconcile_MixedChildMin(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = quotav1alpha1.AddToScheme(scheme)
+	_ = schedv1alpha1.AddToScheme(scheme)
+	// check the sum of the min of the child quotas never exceeds the min of the parent like the webhook
+	checkChildMinSum := func(ctx context.Context, c client.WithWatch, obj client.Object) error {
+		quota, ok := obj.(*schedv1alpha1.ElasticQuota)
+		if !ok {
+			return nil
+		}
+		parentName, parentMin := quota.Labels[extension.LabelQuotaParent], corev1.ResourceList(nil)
+		if parentName == "" {
+			parentName, parentMin = quota.Name, quota.Spec.Min
+		}
+		quotaList := &schedv1alpha1.ElasticQuotaList{}
+		if err := c.List(ctx, quotaList); err != nil {
+			return err
+		}
+		childMinSum := corev1.ResourceList{}
+		for _, q := range quotaList.Items {
+			if q.Name == parentName && parentMin == nil {
+				parentMin = q.Spec.Min
+			}
+			if q.Labels[extension.LabelQuotaParent] != parentName {
+				continue
+			}
+			if q.Name == quota.Name {
+				q = *quota
+			}
+			childMinSum = quotav1.Add(childMinSum, q.Spec.Min)
+		}
+		if parentMin != nil {
+			if ok, exceeded := quotav1.LessThanOrEqual(childMinSum, parentMin); !ok {
+				return fmt.Errorf("sum of the child min exceeds the min of quota %s on %v", parentName, exceeded)
+			}
+		}
+		return nil
+	}
+	r := &QuotaProfileReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&quotav1alpha1.ElasticQuotaProfile{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					if err := checkChildMinSum(ctx, c, obj); err != nil {
+						return err
+					}
+					return c.Update(ctx, obj, opts...)
+				},
+			}).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+	poolLabels := map[string]string{"pool": "pool1"}
+	assert.NoError(t, r.Client.Create(context.TODO(), defaultCreateNode("node1", poolLabels, createResourceList(10, 100))))
+	assert.NoError(t, r.Client.Create(context.TODO(), defaultCreateNode("node2", poolLabels, createResourceList(10, 100))))
+	assert.NoError(t, r.Client.Create(context.TODO(), &quotav1alpha1.ElasticQuotaProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system",
+			Name:      "profile1",
+			Labels:    map[string]string{extension.LabelQuotaTreeID: "tree1"},
+		},
+		Spec: quotav1alpha1.ElasticQuotaProfileSpec{
+			QuotaName:      "root-quota",
+			NodeSelector:   &metav1.LabelSelector{MatchLabels: poolLabels},
+			ChildMinPolicy: &quotav1alpha1.ChildMinPolicy{Type: quotav1alpha1.ChildMinPolicyProportional},
+		},
+	}))
+	for _, name := range []string{"child-a", "child-b"} {
+		assert.NoError(t, r.Client.Create(context.TODO(), &schedv1alpha1.ElasticQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "team",
+				Name:      name,
+				Labels: map[string]string{
+					extension.LabelQuotaParent:    "root-quota",
+					extension.LabelQuotaTreeID:    "tree1",
+					extension.LabelQuotaMinWeight: "1",
+				},
+			},
+			Spec: schedv1alpha1.ElasticQuotaSpec{Min: createResourceList(0, 0), Max: createResourceList(100, 1000)},
+		}))
+	}
+	getChildMin := func(name string) corev1.ResourceList {
+		quota := &schedv1alpha1.ElasticQuota{}
+		assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Namespace: "team", Name: name}, quota))
+		return quota.Spec.Min
+	}
+	setChildMin := func(name string, min corev1.ResourceList) {
+		quota := &schedv1alpha1.ElasticQuota{}
+		assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Namespace: "team", Name: name}, quota))
+		quota.Spec.Min = min
+		assert.NoError(t, r.Client.Update(context.TODO(), quota))
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "kube-system", Name: "profile1"}}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.True(t, quotav1.Equals(createResourceList(10, 100), getChildMin("child-a")))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100), getChildMin("child-b")))
+
+	// child-a takes more cpu and less memory, and its cpu shrinks while its memory grows after the pool shrinks
+	setChildMin("child-b", createResourceList(0, 0))
+	setChildMin("child-a", createResourceList(15, 0))
+	node1 := &corev1.Node{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "node1"}, node1))
+	node1.Labels = nil
+	assert.NoError(t, r.Client.Update(context.TODO(), node1))
+
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, result.RequeueAfter)
+	assert.True(t, quotav1.Equals(createResourceList(5, 50), getChildMin("child-a")))
+	assert.True(t, quotav1.Equals(createResourceList(5, 50), getChildMin("child-b")))
+	rootQuota := &schedv1alpha1.ElasticQuota{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Namespace: "kube-system", Name: "root-quota"}, rootQuota))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100), rootQuota.Spec.Min))
+	got := &quotav1alpha1.ElasticQuotaProfile{}
+	assert.NoError(t, r.Client.Get(context.TODO(), req.NamespacedName, got))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, quotav1alpha1.ElasticQuotaProfileQuotaSynced))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, quotav1alpha1.ElasticQuotaProfileChildMinSynced))
+}
+
 func TestMultiplyQuantity(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -72,6 +72,9 @@ type LoadAwareSchedulingArgs struct {
 	AllowCustomizeEstimation bool
 	// Aggregated supports resource utilization filtering and scoring based on percentile statistics
 	Aggregated *LoadAwareSchedulingAggregatedArgs
+	// HistoricalEstimation configures the historicalUsageEstimator, which estimates the usage of a pod
+	// from the observed usages of the sibling pods of the same workload, e.g. the same Deployment.
+	HistoricalEstimation *LoadAwareSchedulingHistoricalEstimationArgs
 	// SupportedResourceNames is the list of extra resource names that can be used in load-aware scheduling.
 	// cpu, memory and all other resources that show up in args are supported by default.
 	//
@@ -100,6 +103,21 @@ type LoadAwareSchedulingAggregatedArgs struct {
 	ScoreAggregatedDuration metav1.Duration
 }
 
+type LoadAwareSchedulingHistoricalEstimationArgs struct {
+	// AggregationType indicates the percentile type of the container usages reported in NodeMetrics.
+	// The current usage of the sibling pod is used if the percentile is not reported.
+	// If not set, p90 is used by default.
+	AggregationType extension.AggregationType
+	// AggregatedDuration indicates the statistical period of the percentile of the container usages.
+	// If no specific period is set, the maximum period recorded by NodeMetrics will be used by default.
+	AggregatedDuration metav1.Duration
+	// MinSiblingPods indicates the minimum number of sibling pods with usages to estimate from history,
+	// otherwise the default estimator is used. If not set, 3 is used by default.
+	MinSiblingPods int64
+	// SafetyMarginPercent indicates the percentage added to the observed usages. If not set, 10 is used by default.
+	SafetyMarginPercent *int64
+}
+
 // ScoringStrategyType is a "string" type.
 type ScoringStrategyType string
 
@@ -321,6 +339,15 @@ type ElasticQuotaArgs struct {
 	// Defaults to 120 seconds if unspecified.
 	QuotaSnapshotUpdateInterval metav1.Duration
 
+	// EnableFairShareQueueSort if true, the pending pods are ordered by the weighted dominant resource share of
+	// their quotas when ElasticQuota is enabled as the QueueSort plugin in place of PrioritySort, otherwise by
+	// priority only. It cannot be combined with another QueueSort plugin.
+	EnableFairShareQueueSort bool
+
+	// FairShareAgingPeriod is the waiting duration which offsets the fair share of a pending pod by one, so that
+	// the pods of the quotas using more resources are not starved. Zero means no aging.
+	FairShareAgingPeriod metav1.Duration
+
 	// HookPlugins is expected to be configured with enabled hook plugins
 	HookPlugins []HookPluginConf
 }
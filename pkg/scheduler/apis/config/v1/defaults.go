@@ -80,10 +80,12 @@ var (
 	defaultEnableMinQuotaScale           = ptr.To[bool](true)
 	defaultDisableDefaultQuotaPreemption = ptr.To[bool](true)
 	defaultEnableQueueHint               = ptr.To[bool](false)
+	defaultEnableFairShareQueueSort      = ptr.To[bool](false)
 
 	defaultTimeout                     = 600 * time.Second
 	defaultControllerWorkers           = 1
 	defaultQuotaSnapshotUpdateInterval = 120 * time.Second
+	defaultFairShareAgingPeriod        = 10 * time.Minute
 
 	defaultGPUSharedResourceTemplatesConfig = &GPUSharedResourceTemplatesConfig{
 		ConfigMapNamespace: "koordinator-system",
@@ -229,6 +231,14 @@ func SetDefaults_ElasticQuotaArgs(obj *ElasticQuotaArgs) {
 			Duration: defaultQuotaSnapshotUpdateInterval,
 		}
 	}
+	if obj.EnableFairShareQueueSort == nil {
+		obj.EnableFairShareQueueSort = defaultEnableFairShareQueueSort
+	}
+	if obj.FairShareAgingPeriod == nil {
+		obj.FairShareAgingPeriod = &metav1.Duration{
+			Duration: defaultFairShareAgingPeriod,
+		}
+	}
 }
 
 func SetDefaults_CoschedulingArgs(obj *CoschedulingArgs) {
@@ -71,6 +71,9 @@ type LoadAwareSchedulingArgs struct {
 	AllowCustomizeEstimation bool `json:"allowCustomizeEstimation,omitempty"`
 	// Aggregated supports resource utilization filtering and scoring based on percentile statistics
 	Aggregated *LoadAwareSchedulingAggregatedArgs `json:"aggregated,omitempty"`
+	// HistoricalEstimation configures the historicalUsageEstimator, which estimates the usage of a pod
+	// from the observed usages of the sibling pods of the same workload, e.g. the same Deployment.
+	HistoricalEstimation *LoadAwareSchedulingHistoricalEstimationArgs `json:"historicalEstimation,omitempty"`
 	// SupportedResourceNames is the list of extra resource names that can be used in load-aware scheduling.
 	// cpu, memory and all other resources that show up in args are supported by default.
 	//
@@ -95,6 +98,17 @@ type LoadAwareSchedulingAggregatedArgs struct {
 	ScoreAggregatedDuration *metav1.Duration `json:"scoreAggregatedDuration,omitempty"`
 }
 
+type LoadAwareSchedulingHistoricalEstimationArgs struct {
+	// AggregationType indicates the percentile type of the container usages reported in NodeMetrics
+	AggregationType extension.AggregationType `json:"aggregationType,omitempty"`
+	// AggregatedDuration indicates the statistical period of the percentile of the container usages
+	AggregatedDuration *metav1.Duration `json:"aggregatedDuration,omitempty"`
+	// MinSiblingPods indicates the minimum number of sibling pods with usages to estimate from history
+	MinSiblingPods *int64 `json:"minSiblingPods,omitempty"`
+	// SafetyMarginPercent indicates the percentage added to the observed usages
+	SafetyMarginPercent *int64 `json:"safetyMarginPercent,omitempty"`
+}
+
 // ScoringStrategyType is a "string" type.
 type ScoringStrategyType string
 
@@ -314,6 +328,16 @@ type ElasticQuotaArgs struct {
 	// Defaults to 120 seconds if unspecified.
 	QuotaSnapshotUpdateInterval *metav1.Duration `json:"quotaSnapshotUpdateInterval,omitempty"`
 
+	// EnableFairShareQueueSort if true, the pending pods are ordered by the weighted dominant resource share of
+	// their quotas when ElasticQuota is enabled as the QueueSort plugin in place of PrioritySort, otherwise by
+	// priority only. It cannot be combined with another QueueSort plugin.
+	EnableFairShareQueueSort *bool `json:"enableFairShareQueueSort,omitempty"`
+
+	// FairShareAgingPeriod is the waiting duration which offsets the fair share of a pending pod by one, so that
+	// the pods of the quotas using more resources are not starved. Zero means no aging.
+	// Defaults to 10 minutes if unspecified.
+	FairShareAgingPeriod *metav1.Duration `json:"fairShareAgingPeriod,omitempty"`
+
 	// HookPlugins is expected to be configured with enabled hook plugins
 	HookPlugins []HookPluginConf `json:"hookPlugins,omitempty"`
 }
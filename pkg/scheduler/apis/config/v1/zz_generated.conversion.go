@@ -121,6 +121,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LoadAwareSchedulingHistoricalEstimationArgs)(nil), (*config.LoadAwareSchedulingHistoricalEstimationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_LoadAwareSchedulingHistoricalEstimationArgs_To_config_LoadAwareSchedulingHistoricalEstimationArgs(a.(*LoadAwareSchedulingHistoricalEstimationArgs), b.(*config.LoadAwareSchedulingHistoricalEstimationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LoadAwareSchedulingHistoricalEstimationArgs)(nil), (*LoadAwareSchedulingHistoricalEstimationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LoadAwareSchedulingHistoricalEstimationArgs_To_v1_LoadAwareSchedulingHistoricalEstimationArgs(a.(*config.LoadAwareSchedulingHistoricalEstimationArgs), b.(*LoadAwareSchedulingHistoricalEstimationArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeResourcesFitPlusArgs)(nil), (*config.NodeResourcesFitPlusArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_NodeResourcesFitPlusArgs_To_config_NodeResourcesFitPlusArgs(a.(*NodeResourcesFitPlusArgs), b.(*config.NodeResourcesFitPlusArgs), scope)
 	}); err != nil {
@@ -319,6 +329,12 @@ func autoConvert_v1_ElasticQuotaArgs_To_config_ElasticQuotaArgs(in *ElasticQuota
 	if err := metav1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.QuotaSnapshotUpdateInterval, &out.QuotaSnapshotUpdateInterval, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_Pointer_bool_To_bool(&in.EnableFairShareQueueSort, &out.EnableFairShareQueueSort, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.FairShareAgingPeriod, &out.FairShareAgingPeriod, s); err != nil {
+		return err
+	}
 	out.HookPlugins = *(*[]config.HookPluginConf)(unsafe.Pointer(&in.HookPlugins))
 	return nil
 }
@@ -359,6 +375,12 @@ func autoConvert_config_ElasticQuotaArgs_To_v1_ElasticQuotaArgs(in *config.Elast
 	if err := metav1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.QuotaSnapshotUpdateInterval, &out.QuotaSnapshotUpdateInterval, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_bool_To_Pointer_bool(&in.EnableFairShareQueueSort, &out.EnableFairShareQueueSort, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.FairShareAgingPeriod, &out.FairShareAgingPeriod, s); err != nil {
+		return err
+	}
 	out.HookPlugins = *(*[]HookPluginConf)(unsafe.Pointer(&in.HookPlugins))
 	return nil
 }
@@ -500,6 +522,15 @@ func autoConvert_v1_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(in
 	} else {
 		out.Aggregated = nil
 	}
+	if in.HistoricalEstimation != nil {
+		in, out := &in.HistoricalEstimation, &out.HistoricalEstimation
+		*out = new(config.LoadAwareSchedulingHistoricalEstimationArgs)
+		if err := Convert_v1_LoadAwareSchedulingHistoricalEstimationArgs_To_config_LoadAwareSchedulingHistoricalEstimationArgs(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HistoricalEstimation = nil
+	}
 	out.SupportedResources = *(*[]corev1.ResourceName)(unsafe.Pointer(&in.SupportedResources))
 	return nil
 }
@@ -535,6 +566,15 @@ func autoConvert_config_LoadAwareSchedulingArgs_To_v1_LoadAwareSchedulingArgs(in
 	} else {
 		out.Aggregated = nil
 	}
+	if in.HistoricalEstimation != nil {
+		in, out := &in.HistoricalEstimation, &out.HistoricalEstimation
+		*out = new(LoadAwareSchedulingHistoricalEstimationArgs)
+		if err := Convert_config_LoadAwareSchedulingHistoricalEstimationArgs_To_v1_LoadAwareSchedulingHistoricalEstimationArgs(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HistoricalEstimation = nil
+	}
 	out.SupportedResources = *(*[]corev1.ResourceName)(unsafe.Pointer(&in.SupportedResources))
 	return nil
 }
@@ -544,6 +584,40 @@ func Convert_config_LoadAwareSchedulingArgs_To_v1_LoadAwareSchedulingArgs(in *co
 	return autoConvert_config_LoadAwareSchedulingArgs_To_v1_LoadAwareSchedulingArgs(in, out, s)
 }
 
+func autoConvert_v1_LoadAwareSchedulingHistoricalEstimationArgs_To_config_LoadAwareSchedulingHistoricalEstimationArgs(in *LoadAwareSchedulingHistoricalEstimationArgs, out *config.LoadAwareSchedulingHistoricalEstimationArgs, s conversion.Scope) error {
+	out.AggregationType = extension.AggregationType(in.AggregationType)
+	if err := metav1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.AggregatedDuration, &out.AggregatedDuration, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MinSiblingPods, &out.MinSiblingPods, s); err != nil {
+		return err
+	}
+	out.SafetyMarginPercent = (*int64)(unsafe.Pointer(in.SafetyMarginPercent))
+	return nil
+}
+
+// Convert_v1_LoadAwareSchedulingHistoricalEstimationArgs_To_config_LoadAwareSchedulingHistoricalEstimationArgs is an autogenerated conversion function.
+func Convert_v1_LoadAwareSchedulingHistoricalEstimationArgs_To_config_LoadAwareSchedulingHistoricalEstimationArgs(in *LoadAwareSchedulingHistoricalEstimationArgs, out *config.LoadAwareSchedulingHistoricalEstimationArgs, s conversion.Scope) error {
+	return autoConvert_v1_LoadAwareSchedulingHistoricalEstimationArgs_To_config_LoadAwareSchedulingHistoricalEstimationArgs(in, out, s)
+}
+
+func autoConvert_config_LoadAwareSchedulingHistoricalEstimationArgs_To_v1_LoadAwareSchedulingHistoricalEstimationArgs(in *config.LoadAwareSchedulingHistoricalEstimationArgs, out *LoadAwareSchedulingHistoricalEstimationArgs, s conversion.Scope) error {
+	out.AggregationType = extension.AggregationType(in.AggregationType)
+	if err := metav1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.AggregatedDuration, &out.AggregatedDuration, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MinSiblingPods, &out.MinSiblingPods, s); err != nil {
+		return err
+	}
+	out.SafetyMarginPercent = (*int64)(unsafe.Pointer(in.SafetyMarginPercent))
+	return nil
+}
+
+// Convert_config_LoadAwareSchedulingHistoricalEstimationArgs_To_v1_LoadAwareSchedulingHistoricalEstimationArgs is an autogenerated conversion function.
+func Convert_config_LoadAwareSchedulingHistoricalEstimationArgs_To_v1_LoadAwareSchedulingHistoricalEstimationArgs(in *config.LoadAwareSchedulingHistoricalEstimationArgs, out *LoadAwareSchedulingHistoricalEstimationArgs, s conversion.Scope) error {
+	return autoConvert_config_LoadAwareSchedulingHistoricalEstimationArgs_To_v1_LoadAwareSchedulingHistoricalEstimationArgs(in, out, s)
+}
+
 func autoConvert_v1_NodeNUMAResourceArgs_To_config_NodeNUMAResourceArgs(in *NodeNUMAResourceArgs, out *config.NodeNUMAResourceArgs, s conversion.Scope) error {
 	// WARNING: in.DefaultCPUBindPolicy requires manual conversion: inconvertible types (*github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/v1.CPUBindPolicy vs string)
 	out.ScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
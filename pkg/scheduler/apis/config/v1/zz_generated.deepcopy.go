@@ -186,6 +186,16 @@ func (in *ElasticQuotaArgs) DeepCopyInto(out *ElasticQuotaArgs) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.EnableFairShareQueueSort != nil {
+		in, out := &in.EnableFairShareQueueSort, &out.EnableFairShareQueueSort
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FairShareAgingPeriod != nil {
+		in, out := &in.FairShareAgingPeriod, &out.FairShareAgingPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.HookPlugins != nil {
 		in, out := &in.HookPlugins, &out.HookPlugins
 		*out = make([]HookPluginConf, len(*in))
@@ -365,6 +375,11 @@ func (in *LoadAwareSchedulingArgs) DeepCopyInto(out *LoadAwareSchedulingArgs) {
 		*out = new(LoadAwareSchedulingAggregatedArgs)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HistoricalEstimation != nil {
+		in, out := &in.HistoricalEstimation, &out.HistoricalEstimation
+		*out = new(LoadAwareSchedulingHistoricalEstimationArgs)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SupportedResources != nil {
 		in, out := &in.SupportedResources, &out.SupportedResources
 		*out = make([]corev1.ResourceName, len(*in))
@@ -391,6 +406,37 @@ func (in *LoadAwareSchedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadAwareSchedulingHistoricalEstimationArgs) DeepCopyInto(out *LoadAwareSchedulingHistoricalEstimationArgs) {
+	*out = *in
+	if in.AggregatedDuration != nil {
+		in, out := &in.AggregatedDuration, &out.AggregatedDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MinSiblingPods != nil {
+		in, out := &in.MinSiblingPods, &out.MinSiblingPods
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SafetyMarginPercent != nil {
+		in, out := &in.SafetyMarginPercent, &out.SafetyMarginPercent
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadAwareSchedulingHistoricalEstimationArgs.
+func (in *LoadAwareSchedulingHistoricalEstimationArgs) DeepCopy() *LoadAwareSchedulingHistoricalEstimationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadAwareSchedulingHistoricalEstimationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeNUMAResourceArgs) DeepCopyInto(out *NodeNUMAResourceArgs) {
 	*out = *in
@@ -65,6 +65,10 @@ func ValidateLoadAwareSchedulingArgs(args *config.LoadAwareSchedulingArgs) error
 		allErrs = append(allErrs, err...)
 	}
 
+	if err := validateHistoricalEstimationArgs(args.HistoricalEstimation, field.NewPath("historicalEstimation")); err != nil {
+		allErrs = append(allErrs, err...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -110,6 +114,41 @@ func validateAggregatedArgs(
 	return allErrs
 }
 
+func validateHistoricalEstimationArgs(
+	historical *config.LoadAwareSchedulingHistoricalEstimationArgs,
+	fldPath *field.Path,
+) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if historical == nil {
+		return nil
+	}
+
+	// the max usage is only reported for containers, so it is acceptable here
+	if historical.AggregationType != "" && historical.AggregationType != extension.MAX {
+		if err := validateAggregationType(historical.AggregationType, fldPath.Child("aggregationType")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	if historical.AggregatedDuration.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("aggregatedDuration"),
+			historical.AggregatedDuration, "duration must be >= 0"))
+	}
+
+	if historical.MinSiblingPods < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minSiblingPods"),
+			historical.MinSiblingPods, "minSiblingPods should not be a negative value"))
+	}
+
+	if historical.SafetyMarginPercent != nil && *historical.SafetyMarginPercent < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("safetyMarginPercent"),
+			*historical.SafetyMarginPercent, "safetyMarginPercent should not be a negative value"))
+	}
+
+	return allErrs
+}
+
 func validateAggregationType(aggType extension.AggregationType, fldPath *field.Path) *field.Error {
 	validTypes := []string{
 		string(extension.AVG),
@@ -184,6 +223,10 @@ func ValidateElasticQuotaArgs(elasticArgs *config.ElasticQuotaArgs) error {
 		return fmt.Errorf("elasticQuotaArgs error, RevokePodCycle should be a positive value")
 	}
 
+	if elasticArgs.FairShareAgingPeriod.Duration < 0 {
+		return fmt.Errorf("elasticQuotaArgs error, FairShareAgingPeriod should be a positive value")
+	}
+
 	return nil
 }
 
@@ -199,6 +199,37 @@ func TestValidateLoadAwareSchedulingArgs(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid historical estimation args",
+			args: &config.LoadAwareSchedulingArgs{
+				HistoricalEstimation: &config.LoadAwareSchedulingHistoricalEstimationArgs{
+					AggregationType:     extension.MAX,
+					AggregatedDuration:  metav1.Duration{Duration: time.Hour},
+					MinSiblingPods:      1,
+					SafetyMarginPercent: ptr.To[int64](0),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid historical estimation aggregation type",
+			args: &config.LoadAwareSchedulingArgs{
+				HistoricalEstimation: &config.LoadAwareSchedulingHistoricalEstimationArgs{
+					AggregationType: extension.AggregationType("invalid"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative historical estimation safety margin",
+			args: &config.LoadAwareSchedulingArgs{
+				HistoricalEstimation: &config.LoadAwareSchedulingHistoricalEstimationArgs{
+					MinSiblingPods:      -1,
+					SafetyMarginPercent: ptr.To[int64](-10),
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -124,6 +124,7 @@ func (in *ElasticQuotaArgs) DeepCopyInto(out *ElasticQuotaArgs) {
 		}
 	}
 	out.QuotaSnapshotUpdateInterval = in.QuotaSnapshotUpdateInterval
+	out.FairShareAgingPeriod = in.FairShareAgingPeriod
 	if in.HookPlugins != nil {
 		in, out := &in.HookPlugins, &out.HookPlugins
 		*out = make([]HookPluginConf, len(*in))
@@ -290,6 +291,11 @@ func (in *LoadAwareSchedulingArgs) DeepCopyInto(out *LoadAwareSchedulingArgs) {
 		*out = new(LoadAwareSchedulingAggregatedArgs)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HistoricalEstimation != nil {
+		in, out := &in.HistoricalEstimation, &out.HistoricalEstimation
+		*out = new(LoadAwareSchedulingHistoricalEstimationArgs)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SupportedResources != nil {
 		in, out := &in.SupportedResources, &out.SupportedResources
 		*out = make([]v1.ResourceName, len(*in))
@@ -316,6 +322,28 @@ func (in *LoadAwareSchedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadAwareSchedulingHistoricalEstimationArgs) DeepCopyInto(out *LoadAwareSchedulingHistoricalEstimationArgs) {
+	*out = *in
+	out.AggregatedDuration = in.AggregatedDuration
+	if in.SafetyMarginPercent != nil {
+		in, out := &in.SafetyMarginPercent, &out.SafetyMarginPercent
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadAwareSchedulingHistoricalEstimationArgs.
+func (in *LoadAwareSchedulingHistoricalEstimationArgs) DeepCopy() *LoadAwareSchedulingHistoricalEstimationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadAwareSchedulingHistoricalEstimationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeNUMAResourceArgs) DeepCopyInto(out *NodeNUMAResourceArgs) {
 	*out = *in
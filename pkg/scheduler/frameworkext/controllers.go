@@ -94,6 +94,22 @@ func (cm *ControllersMap) RegisterControllers(plugin fwktype.Plugin, profileName
 	klog.V(4).InfoS("Plugin successfully build controllers", "plugin", plugin.Name(), "profile", profileName, "controllers", len(pluginControllers))
 }
 
+// RegisterController registers a controller which is not provided by a plugin. The owner works as the plugin name
+// to enable or disable the controller with ControllerPlugins.
+func (cm *ControllersMap) RegisterController(owner string, controller Controller) {
+	ownerControllers := cm.controllers[owner]
+	if ownerControllers == nil {
+		ownerControllers = make(map[string]Controller)
+		cm.controllers[owner] = ownerControllers
+	}
+	if _, exist := ownerControllers[controller.Name()]; exist {
+		klog.Warningf("controller: %v already registered", controller.Name())
+		return
+	}
+	ownerControllers[controller.Name()] = controller
+	klog.V(4).Infof("register %v controller:%v", owner, controller.Name())
+}
+
 func (cm *ControllersMap) Start() {
 	for pluginName, pluginControllers := range cm.controllers {
 		if !isControllerPluginEnabled(pluginName) {
@@ -155,3 +155,11 @@ func TestControllersMap_RegisterControllers_NonControllerProvider(t *testing.T)
 	assert.Equal(t, 0, len(cm.controllers),
 		"non-ControllerProvider plugin should not be registered")
 }
+
+func TestControllersMap_RegisterController(t *testing.T) {
+	cm := NewControllersMap()
+	cm.RegisterController("owner", &fakeController{name: "controller1"})
+	cm.RegisterController("owner", &fakeController{name: "controller1"})
+	cm.RegisterController("owner", &fakeController{name: "controller2"})
+	assert.Len(t, cm.controllers["owner"], 2)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	fwktype "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const dryRunCycleStateKey = extension.SchedulingDomainPrefix + "/dry-run-cycle"
+
+// ErrSchedulerProfileNotFound is returned by DryRunSchedule if the Pod is not scheduled by this scheduler.
+var ErrSchedulerProfileNotFound = errors.New("scheduler profile not found")
+
+var _ fwktype.StateData = dryRunCycleMarker{}
+
+type dryRunCycleMarker struct{}
+
+func (dryRunCycleMarker) Clone() fwktype.StateData { return dryRunCycleMarker{} }
+
+func isDryRunCycle(cycleState fwktype.CycleState) bool {
+	_, err := cycleState.Read(dryRunCycleStateKey)
+	return err == nil
+}
+
+// DryRunResult is the result of a dry-run scheduling cycle.
+type DryRunResult struct {
+	// FeasibleNodes are the nodes passed the Filter plugins.
+	FeasibleNodes []string
+	// Diagnosis records why the Pod cannot be scheduled on the other nodes.
+	Diagnosis *Diagnosis
+}
+
+// DryRunSchedule runs the PreFilter and Filter plugins of the Pod's scheduler profile against the snapshot of the
+// latest scheduling cycle. It never runs the PreFilter transformers, FindOneNode, Reserve, Permit or Bind plugins,
+// so nothing is assumed or bound, the shared states of the plugins are untouched and the Pod does not need to exist.
+// The scheduling lock is only held while running PreFilter and copying the snapshot, and the Filter plugins run
+// on the copied NodeInfos so that the scheduling cycle is not blocked while filtering every node.
+func (f *FrameworkExtenderFactory) DryRunSchedule(ctx context.Context, pod *corev1.Pod) (*DryRunResult, error) {
+	fwk := f.GetExtender(pod.Spec.SchedulerName)
+	if fwk == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSchedulerProfileNotFound, pod.Spec.SchedulerName)
+	}
+
+	cycleState := framework.NewCycleState()
+	cycleState.Write(dryRunCycleStateKey, dryRunCycleMarker{})
+	InitDiagnosis(cycleState, pod)
+	diagnosis := GetDiagnosis(cycleState)
+	diagnosis.ScheduleDiagnosis = &ScheduleDiagnosis{
+		SchedulingMode:  PodSchedulingMode,
+		NodeToStatusMap: map[string]*fwktype.Status{},
+	}
+	result := &DryRunResult{Diagnosis: diagnosis}
+
+	nodeInfos, preFilterResult, status, err := f.dryRunPreFilter(ctx, fwk, cycleState, pod)
+	if err != nil {
+		return nil, err
+	}
+	if !status.IsSuccess() {
+		if !status.IsRejected() {
+			return nil, status.AsError()
+		}
+		diagnosis.PreFilterMessage = status.Message()
+		for _, nodeInfo := range nodeInfos {
+			diagnosis.ScheduleDiagnosis.NodeToStatusMap[nodeInfo.Node().Name] = status
+		}
+		return result, nil
+	}
+	for _, nodeInfo := range nodeInfos {
+		nodeName := nodeInfo.Node().Name
+		if !preFilterResult.AllNodes() && !preFilterResult.NodeNames.Has(nodeName) {
+			diagnosis.ScheduleDiagnosis.NodeToStatusMap[nodeName] = fwktype.NewStatus(fwktype.UnschedulableAndUnresolvable, "node is filtered out by the prefilter result")
+			continue
+		}
+		status = fwk.RunFilterPluginsWithNominatedPods(ctx, cycleState, pod, nodeInfo)
+		if status.IsSuccess() {
+			result.FeasibleNodes = append(result.FeasibleNodes, nodeName)
+		} else if status.IsRejected() {
+			diagnosis.ScheduleDiagnosis.NodeToStatusMap[nodeName] = status
+		} else {
+			return nil, status.AsError()
+		}
+	}
+	return result, nil
+}
+
+// dryRunPreFilter runs the PreFilter plugins and copies the NodeInfos of the snapshot with the scheduling lock held,
+// since both of them read the snapshot which is updated at the beginning of the scheduling cycle.
+func (f *FrameworkExtenderFactory) dryRunPreFilter(ctx context.Context, fwk FrameworkExtender, cycleState fwktype.CycleState, pod *corev1.Pod) ([]fwktype.NodeInfo, *fwktype.PreFilterResult, *fwktype.Status, error) {
+	f.schedulingLock.Lock()
+	defer f.schedulingLock.Unlock()
+
+	nodeInfos, err := fwk.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	copied := make([]fwktype.NodeInfo, 0, len(nodeInfos))
+	for _, nodeInfo := range nodeInfos {
+		copied = append(copied, nodeInfo.Snapshot())
+	}
+	preFilterResult, status, _ := fwk.RunPreFilterPlugins(ctx, cycleState, pod)
+	return copied, preFilterResult, status, nil
+}
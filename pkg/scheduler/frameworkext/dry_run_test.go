@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	fwktype "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	schedulertesting "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+)
+
+func TestDryRunSchedule(t *testing.T) {
+	var nodeInfos nodeInfoLister
+	for name, cpu := range map[string]string{"node-1": "4", "node-2": "500m"} {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:  resource.MustParse(cpu),
+					corev1.ResourcePods: resource.MustParse("110"),
+				},
+			},
+		})
+		nodeInfos = append(nodeInfos, nodeInfo)
+	}
+
+	koordClientSet := koordfake.NewSimpleClientset()
+	extenderFactory, err := NewFrameworkExtenderFactory(
+		WithKoordinatorClientSet(koordClientSet),
+		WithKoordinatorSharedInformerFactory(koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)),
+	)
+	assert.NoError(t, err)
+	fakeClient := kubefake.NewSimpleClientset()
+	fh, err := schedulertesting.NewFramework(
+		context.TODO(),
+		[]schedulertesting.RegisterPluginFunc{
+			schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			schedulertesting.RegisterFilterPlugin("FakeFitFilterPlugin", func(_ context.Context, _ runtime.Object, _ fwktype.Handle) (fwktype.Plugin, error) {
+				return &fakeFitFilterPlugin{}, nil
+			}),
+		},
+		"koord-scheduler",
+		frameworkruntime.WithSnapshotSharedLister(fakeNodeInfoLister{nodeInfoLister: nodeInfos}),
+		frameworkruntime.WithClientSet(fakeClient),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(fakeClient, 0)),
+		frameworkruntime.WithPodNominator(NewFakePodNominator()),
+	)
+	assert.NoError(t, err)
+	extender := extenderFactory.NewFrameworkExtender(fh)
+	extender.SetConfiguredPlugins(fh.ListPlugins())
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod", UID: "test-uid"},
+		Spec: corev1.PodSpec{
+			SchedulerName: "koord-scheduler",
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+			},
+		},
+	}
+	result, err := extenderFactory.DryRunSchedule(context.TODO(), pod)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node-1"}, result.FeasibleNodes)
+	assert.Equal(t, "default/test-pod", result.Diagnosis.QuestionedKey)
+	assert.Len(t, result.Diagnosis.ScheduleDiagnosis.NodeToStatusMap, 1)
+	status := result.Diagnosis.ScheduleDiagnosis.NodeToStatusMap["node-2"]
+	if assert.NotNil(t, status) {
+		assert.Equal(t, fwktype.Unschedulable, status.Code())
+		assert.Equal(t, []string{"Insufficient cpu"}, status.Reasons())
+	}
+
+	otherPod := pod.DeepCopy()
+	otherPod.Spec.SchedulerName = "other-scheduler"
+	_, err = extenderFactory.DryRunSchedule(context.TODO(), otherPod)
+	assert.True(t, errors.Is(err, ErrSchedulerProfileNotFound))
+}
@@ -304,7 +304,13 @@ func (ext *frameworkExtenderImpl) GetWorkloadAuditor() workloadauditor.WorkloadA
 func (ext *frameworkExtenderImpl) RunPreFilterPlugins(ctx context.Context, cycleState fwktype.CycleState, pod *corev1.Pod) (*fwktype.PreFilterResult, *fwktype.Status, sets.Set[string]) {
 	trace := utiltrace.New("RunPreFilterPluginTransformers", utiltrace.Field{Key: "namespace", Value: pod.Namespace}, utiltrace.Field{Key: "name", Value: pod.Name})
 	defer trace.LogIfLong(5 * time.Millisecond)
-	for _, transformer := range ext.preFilterTransformersEnabled {
+	// The dry-run cycle skips the transformers since they may update the shared states of the plugins
+	// (e.g. the gang scheduling context of Coscheduling) or restore the reserved resources into the snapshot.
+	transformers := ext.preFilterTransformersEnabled
+	if isDryRunCycle(cycleState) {
+		transformers = nil
+	}
+	for _, transformer := range transformers {
 		startTime := time.Now()
 		trace.Step(fmt.Sprintf("BeforePrefilter %s begin", transformer.Name()))
 		newPod, transformed, status := transformer.BeforePreFilter(ctx, cycleState, pod)
@@ -326,7 +332,7 @@ func (ext *frameworkExtenderImpl) RunPreFilterPlugins(ctx context.Context, cycle
 		return result, status, rejectors
 	}
 
-	for _, transformer := range ext.preFilterTransformersEnabled {
+	for _, transformer := range transformers {
 		startTime := time.Now()
 		trace.Step(fmt.Sprintf("AfterPrefilter %s begin", transformer.Name()))
 		status = transformer.AfterPreFilter(ctx, cycleState, pod, result)
@@ -349,7 +355,8 @@ func (ext *frameworkExtenderImpl) RunPreFilterPlugins(ctx context.Context, cycle
 	// re-running the FindOneNode planner per pod is pure overhead. The framework PreFilter result already
 	// carries the target node (the engine injects it as a PreFilterNodes scheduling hint); an all-nodes
 	// result is equally fine, since the engine filters against the specific node itself.
-	if hinter.IsBatchSchedulingCycle(cycleState) {
+	// The dry-run cycle skips FindOneNode as well since the plugin may assume and bind the job.
+	if hinter.IsBatchSchedulingCycle(cycleState) || isDryRunCycle(cycleState) {
 		return result, nil, rejectors
 	}
 
@@ -562,6 +569,7 @@ func (ext *frameworkExtenderImpl) RunPostFilterPlugins(ctx context.Context, stat
 			transformer.AfterPostFilter(ctx, state, pod, filteredNodeStatusMap, status)
 			ext.metricsRecorder.ObservePluginDurationAsync("AfterPostFilter", transformer.Name(), "", metrics.SinceInSeconds(startTime))
 		}
+		RecordDiagnosis(diagnosis)
 		DumpDiagnosis(diagnosis)
 		if diagnosis != nil && diagnosis.IsRootCausePod && diagnosis.AuditType != "" && ext.workloadAuditor != nil {
 			ext.workloadAuditor.RecordDiagnosis(pod, diagnosis.QuestionedKey, diagnosis.AuditType, diagnosis.AuditMessage)
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	nrtinformers "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/informers/externalversions"
@@ -155,6 +156,9 @@ type FrameworkExtenderFactory struct {
 	monitor                             *SchedulerMonitor
 	scheduler                           Scheduler
 	schedulePod                         func(ctx context.Context, fwk framework.Framework, state fwktype.CycleState, pod *corev1.Pod) (scheduler.ScheduleResult, error)
+	// schedulingLock serializes the scheduling cycles and the PreFilter of the dry-run scheduling cycles since both of them
+	// read the snapshot which is updated at the beginning of the scheduling cycle.
+	schedulingLock sync.Mutex
 	*errorHandlerDispatcher
 
 	networkTopologyTreeManager networktopology.TreeManager
@@ -384,7 +388,9 @@ func (f *FrameworkExtenderFactory) scheduleOne(ctx context.Context, fwk framewor
 	if f.workloadAuditor != nil {
 		f.workloadAuditor.RecordAttemptPod(pod)
 	}
+	f.schedulingLock.Lock()
 	scheduleResult, err := f.schedulePod(ctx, fwk, cycleState, pod)
+	f.schedulingLock.Unlock()
 	if err != nil {
 		if st := getBatchScheduleState(cycleState); st != nil && st.handled && st.success {
 			// The whole job (including this pod) has already been assumed and bound by the inline
@@ -470,6 +476,12 @@ func (f *FrameworkExtenderFactory) InterceptSchedulerError(sched *scheduler.Sche
 	}
 }
 
+// RegisterController registers a controller which is not provided by a plugin, it is started with the plugin
+// controllers in Run.
+func (f *FrameworkExtenderFactory) RegisterController(owner string, controller Controller) {
+	f.controllerMaps.RegisterController(owner, controller)
+}
+
 func (f *FrameworkExtenderFactory) Run(ctx context.Context) {
 	f.controllerMaps.Start()
 	if EnableNetworkTopologyManager {
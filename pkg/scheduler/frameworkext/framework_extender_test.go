@@ -402,14 +402,31 @@ func (c fakeNodeInfoLister) IsPVCUsedByPods(key string) bool {
 
 func Test_frameworkExtenderImpl_RunPreFilterPlugins(t *testing.T) {
 	tests := []struct {
-		name string
-		pod  *corev1.Pod
-		want *fwktype.Status
+		name            string
+		pod             *corev1.Pod
+		dryRun          bool
+		want            *fwktype.Status
+		wantAnnotations map[string]string
 	}{
 		{
 			name: "normal RunPreFilterPlugins",
 			pod:  &corev1.Pod{},
 			want: nil,
+			wantAnnotations: map[string]string{
+				"BeforePreFilter-1": "1",
+				"AfterPreFilter-1":  "1",
+				"BeforePreFilter-2": "2",
+				"AfterPreFilter-2":  "2",
+				"FindOneNode":       "FindOneNode",
+				"PreferNodes":       "PreferNodes",
+			},
+		},
+		{
+			name:            "dry-run cycle skips transformers and FindOneNode",
+			pod:             &corev1.Pod{},
+			dryRun:          true,
+			want:            nil,
+			wantAnnotations: nil,
 		},
 	}
 	for _, tt := range tests {
@@ -448,17 +465,13 @@ func Test_frameworkExtenderImpl_RunPreFilterPlugins(t *testing.T) {
 			assert.NoError(t, err)
 			frameworkExtender := extenderFactory.NewFrameworkExtender(fh)
 			frameworkExtender.SetConfiguredPlugins(fh.ListPlugins())
-			_, status, _ := frameworkExtender.RunPreFilterPlugins(context.TODO(), framework.NewCycleState(), tt.pod)
-			assert.Equal(t, tt.want, status)
-			expectedAnnotations := map[string]string{
-				"BeforePreFilter-1": "1",
-				"AfterPreFilter-1":  "1",
-				"BeforePreFilter-2": "2",
-				"AfterPreFilter-2":  "2",
-				"FindOneNode":       "FindOneNode",
-				"PreferNodes":       "PreferNodes",
+			cycleState := framework.NewCycleState()
+			if tt.dryRun {
+				cycleState.Write(dryRunCycleStateKey, dryRunCycleMarker{})
 			}
-			assert.Equal(t, expectedAnnotations, tt.pod.Annotations)
+			_, status, _ := frameworkExtender.RunPreFilterPlugins(context.TODO(), cycleState, tt.pod)
+			assert.Equal(t, tt.want, status)
+			assert.Equal(t, tt.wantAnnotations, tt.pod.Annotations)
 		})
 	}
 }
@@ -50,6 +50,28 @@ func RegisterCustomDiagnosisProcessor(name string, processor func(diagnosis *Dia
 	customDiagnosisProcessor[name] = processor
 }
 
+// DiagnosisRecorder receives the Diagnosis of every failed scheduling cycle regardless of dumpDiagnosis.
+// It is called synchronously at the end of PostFilter, so it should return quickly and must not retain
+// the Diagnosis since it may be processed asynchronously by DumpDiagnosis.
+type DiagnosisRecorder interface {
+	RecordDiagnosis(diagnosis *Diagnosis)
+}
+
+var diagnosisRecorders []DiagnosisRecorder
+
+func RegisterDiagnosisRecorder(recorder DiagnosisRecorder) {
+	diagnosisRecorders = append(diagnosisRecorders, recorder)
+}
+
+func RecordDiagnosis(diagnosis *Diagnosis) {
+	if diagnosis == nil {
+		return
+	}
+	for _, recorder := range diagnosisRecorders {
+		recorder.RecordDiagnosis(diagnosis)
+	}
+}
+
 // DumpDiagnosisSetter set dumpDiagnosis
 func DumpDiagnosisSetter(val string) (string, error) {
 	toDumpDiagnosis, err := strconv.ParseBool(val)
@@ -218,6 +240,12 @@ type PreemptionDiagnosis struct {
 	FailedMessage         string             `json:"failedMessage,omitempty"`
 }
 
+// PossibleVictimsProvider is implemented by the PreemptionDiagnosis.OtherDiagnosis of the preemption plugins
+// which can tell the victims selected to make room for the preemptor.
+type PossibleVictimsProvider interface {
+	GetPossibleVictims() []v1alpha1.NodePossibleVictim
+}
+
 // DiagnosisQueue is a queue for handling diagnosis logs asynchronously
 type DiagnosisQueue struct {
 	queue chan *Diagnosis
@@ -249,7 +277,7 @@ func (dq *DiagnosisQueue) processDiagnosis(diagnosis *Diagnosis) string {
 	// Process NodeFailedDetails if empty
 	if diagnosis.ScheduleDiagnosis != nil {
 		if len(diagnosis.ScheduleDiagnosis.NodeFailedDetails) == 0 {
-			diagnosis.ScheduleDiagnosis.NodeFailedDetails = convertStatusMapToFailedDetail(diagnosis.ScheduleDiagnosis.NodeToStatusMap)
+			diagnosis.ScheduleDiagnosis.NodeFailedDetails = ConvertStatusMapToFailedDetail(diagnosis.ScheduleDiagnosis.NodeToStatusMap)
 		}
 
 		if diagnosis.ScheduleDiagnosis.SchedulingMode == PodSchedulingMode {
@@ -264,7 +292,7 @@ func (dq *DiagnosisQueue) processDiagnosis(diagnosis *Diagnosis) string {
 
 	if diagnosis.PreemptionDiagnosis != nil && diagnosis.PreemptionDiagnosis.DryRunFilterDiagnosis != nil {
 		if len(diagnosis.PreemptionDiagnosis.DryRunFilterDiagnosis.NodeFailedDetails) == 0 {
-			diagnosis.PreemptionDiagnosis.DryRunFilterDiagnosis.NodeFailedDetails = convertStatusMapToFailedDetail(diagnosis.PreemptionDiagnosis.DryRunFilterDiagnosis.NodeToStatusMap)
+			diagnosis.PreemptionDiagnosis.DryRunFilterDiagnosis.NodeFailedDetails = ConvertStatusMapToFailedDetail(diagnosis.PreemptionDiagnosis.DryRunFilterDiagnosis.NodeToStatusMap)
 		}
 		if diagnosis.PreemptionDiagnosis.DryRunFilterDiagnosis.SchedulingMode == PodSchedulingMode {
 			if len(diagnosis.PreemptionDiagnosis.DryRunFilterDiagnosis.AlreadyWaitForBoundPods) > 0 {
@@ -286,7 +314,8 @@ func (dq *DiagnosisQueue) processDiagnosis(diagnosis *Diagnosis) string {
 	return dumpMessage
 }
 
-func convertStatusMapToFailedDetail(statusMap map[string]*fwktype.Status) v1alpha1.NodeFailedDetails {
+// ConvertStatusMapToFailedDetail groups the nodes by the failed plugin and reason of their statuses.
+func ConvertStatusMapToFailedDetail(statusMap map[string]*fwktype.Status) v1alpha1.NodeFailedDetails {
 	if len(statusMap) == 0 {
 		return nil
 	}
@@ -454,3 +454,24 @@ func BenchmarkDumpDiagnosisWorkerCount(b *testing.B) {
 		diagnosisWorkerCount = originalWorkerCount
 	})
 }
+
+type fakeDiagnosisRecorder struct {
+	diagnoses []*Diagnosis
+}
+
+func (r *fakeDiagnosisRecorder) RecordDiagnosis(diagnosis *Diagnosis) {
+	r.diagnoses = append(r.diagnoses, diagnosis)
+}
+
+func TestRecordDiagnosis(t *testing.T) {
+	defer func() { diagnosisRecorders = nil }()
+	recorder := &fakeDiagnosisRecorder{}
+	RegisterDiagnosisRecorder(recorder)
+
+	// the recorders receive the diagnoses even if dumpDiagnosis is disabled
+	dumpDiagnosis = false
+	diagnosis := &Diagnosis{QuestionedKey: "default/test-pod"}
+	RecordDiagnosis(diagnosis)
+	RecordDiagnosis(nil)
+	assert.Equal(t, []*Diagnosis{diagnosis}, recorder.diagnoses)
+}
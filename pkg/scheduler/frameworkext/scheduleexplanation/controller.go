@@ -0,0 +1,508 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduleexplanation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	schedulinglister "github.com/koordinator-sh/koordinator/pkg/client/listers/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+const (
+	Name = "ScheduleExplanationController"
+
+	defaultTTL = 24 * time.Hour
+	// minDryRunInterval and maxDryRunInterval bound the interval to re-run the dry run of a questioned object
+	// template. The interval doubles whenever the cluster has not changed since the last dry run.
+	minDryRunInterval = time.Minute
+	maxDryRunInterval = 30 * time.Minute
+)
+
+// DryRunScheduler runs side-effect-free scheduling cycles for the questioned Pod templates.
+type DryRunScheduler interface {
+	DryRunSchedule(ctx context.Context, pod *corev1.Pod) (*frameworkext.DryRunResult, error)
+}
+
+var (
+	_ frameworkext.Controller        = &Controller{}
+	_ frameworkext.DiagnosisRecorder = &Controller{}
+)
+
+// Controller fills the status of the ScheduleExplanations. The questioned object keys are explained with the
+// Diagnosis of their latest scheduling cycle, and the questioned object templates are explained with dry-run
+// scheduling cycles. The ScheduleExplanations are deleted after their TTL.
+type Controller struct {
+	sharedInformerFactory      informers.SharedInformerFactory
+	koordSharedInformerFactory koordinatorinformers.SharedInformerFactory
+	nodeLister                 corelister.NodeLister
+	podLister                  corelister.PodLister
+	explanationLister          schedulinglister.ScheduleExplanationLister
+	koordClientSet             koordclientset.Interface
+	scheduler                  DryRunScheduler
+	queue                      workqueue.RateLimitingInterface
+	numWorker                  int
+	clock                      clock.Clock
+
+	lock sync.RWMutex
+	// questionedKeys indexes the keys of the ScheduleExplanations by their questioned object keys
+	questionedKeys map[string]sets.Set[string]
+	// records keeps the latest record of the questioned object keys being asked
+	records map[string]*record
+	// dryRuns keeps the latest dry run of the ScheduleExplanations questioning object templates
+	dryRuns map[string]*dryRun
+
+	// clusterGeneration is bumped whenever the nodes or the pods placed on them change, to tell if a dry run is stale.
+	clusterGeneration atomic.Int64
+}
+
+// dryRun is the latest dry run of a questioned object template.
+type dryRun struct {
+	spec              *schedulingv1alpha1.ScheduleExplanationSpec
+	clusterGeneration int64
+	lastRunTime       time.Time
+	interval          time.Duration
+	record            *record
+}
+
+func New(
+	sharedInformerFactory informers.SharedInformerFactory,
+	koordSharedInformerFactory koordinatorinformers.SharedInformerFactory,
+	koordClientSet koordclientset.Interface,
+	scheduler DryRunScheduler,
+	numWorker int,
+) *Controller {
+	if numWorker <= 0 {
+		numWorker = 1
+	}
+	return &Controller{
+		sharedInformerFactory:      sharedInformerFactory,
+		koordSharedInformerFactory: koordSharedInformerFactory,
+		nodeLister:                 sharedInformerFactory.Core().V1().Nodes().Lister(),
+		podLister:                  sharedInformerFactory.Core().V1().Pods().Lister(),
+		explanationLister:          koordSharedInformerFactory.Scheduling().V1alpha1().ScheduleExplanations().Lister(),
+		koordClientSet:             koordClientSet,
+		scheduler:                  scheduler,
+		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), Name),
+		numWorker:                  numWorker,
+		clock:                      clock.RealClock{},
+		questionedKeys:             map[string]sets.Set[string]{},
+		records:                    map[string]*record{},
+		dryRuns:                    map[string]*dryRun{},
+	}
+}
+
+func (c *Controller) Name() string {
+	return Name
+}
+
+func (c *Controller) Start() {
+	explanationInformer := c.koordSharedInformerFactory.Scheduling().V1alpha1().ScheduleExplanations().Informer()
+	explanationInformer.AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.onExplanationAdd,
+		UpdateFunc: c.onExplanationUpdate,
+		DeleteFunc: c.onExplanationDelete,
+	})
+	podInformer := c.sharedInformerFactory.Core().V1().Pods().Informer()
+	podInformer.AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.onPodAdd,
+		UpdateFunc: c.onPodUpdate,
+		DeleteFunc: c.onPodDelete,
+	})
+	nodeInformer := c.sharedInformerFactory.Core().V1().Nodes().Informer()
+	nodeInformer.AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.clusterGeneration.Add(1) },
+		UpdateFunc: c.onNodeUpdate,
+		DeleteFunc: func(obj interface{}) { c.clusterGeneration.Add(1) },
+	})
+
+	done := context.Background().Done()
+	c.sharedInformerFactory.Start(done)
+	c.koordSharedInformerFactory.Start(done)
+	c.sharedInformerFactory.WaitForCacheSync(done)
+	c.koordSharedInformerFactory.WaitForCacheSync(done)
+
+	for i := 0; i < c.numWorker; i++ {
+		go wait.Until(c.worker, time.Second, done)
+	}
+	klog.Infof("%s started", Name)
+}
+
+// RecordDiagnosis implements frameworkext.DiagnosisRecorder. Only the Diagnoses of the questioned object keys
+// being asked are kept.
+func (c *Controller) RecordDiagnosis(diagnosis *frameworkext.Diagnosis) {
+	if !diagnosis.IsRootCausePod {
+		return
+	}
+	keys := []string{diagnosis.QuestionedKey}
+	if diagnosis.TargetPod != nil {
+		if key := extension.GetExplanationKey(diagnosis.TargetPod.Labels); key != "" && key != diagnosis.QuestionedKey {
+			keys = append(keys, key)
+		}
+	}
+
+	var toSync []string
+	c.lock.Lock()
+	var r *record
+	for _, key := range keys {
+		explanationKeys := c.questionedKeys[key]
+		if explanationKeys.Len() == 0 {
+			continue
+		}
+		if r == nil {
+			r = newRecordFromDiagnosis(diagnosis)
+		}
+		c.records[key] = r
+		toSync = append(toSync, explanationKeys.UnsortedList()...)
+	}
+	c.lock.Unlock()
+	for _, key := range toSync {
+		c.queue.Add(key)
+	}
+}
+
+func (c *Controller) onExplanationAdd(obj interface{}) {
+	explanation, ok := obj.(*schedulingv1alpha1.ScheduleExplanation)
+	if !ok {
+		return
+	}
+	c.track(explanation)
+}
+
+func (c *Controller) onExplanationUpdate(oldObj, newObj interface{}) {
+	oldExplanation, oldOK := oldObj.(*schedulingv1alpha1.ScheduleExplanation)
+	newExplanation, newOK := newObj.(*schedulingv1alpha1.ScheduleExplanation)
+	if !oldOK || !newOK {
+		return
+	}
+	if oldExplanation.Spec.QuestionObjectKey != newExplanation.Spec.QuestionObjectKey {
+		c.untrack(oldExplanation)
+	}
+	if equality.Semantic.DeepEqual(oldExplanation.Spec, newExplanation.Spec) {
+		// skip the status updates made by ourselves
+		return
+	}
+	c.track(newExplanation)
+}
+
+func (c *Controller) onExplanationDelete(obj interface{}) {
+	var explanation *schedulingv1alpha1.ScheduleExplanation
+	switch t := obj.(type) {
+	case *schedulingv1alpha1.ScheduleExplanation:
+		explanation = t
+	case cache.DeletedFinalStateUnknown:
+		explanation, _ = t.Obj.(*schedulingv1alpha1.ScheduleExplanation)
+	}
+	if explanation == nil {
+		return
+	}
+	c.untrack(explanation)
+}
+
+func (c *Controller) onNodeUpdate(oldObj, newObj interface{}) {
+	oldNode, oldOK := oldObj.(*corev1.Node)
+	newNode, newOK := newObj.(*corev1.Node)
+	if !oldOK || !newOK {
+		return
+	}
+	// skip the heartbeats of the node status
+	if !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+		!equality.Semantic.DeepEqual(oldNode.Spec, newNode.Spec) ||
+		!equality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable) {
+		c.clusterGeneration.Add(1)
+	}
+}
+
+func (c *Controller) onPodAdd(obj interface{}) {
+	if pod, ok := obj.(*corev1.Pod); ok && pod.Spec.NodeName != "" {
+		c.clusterGeneration.Add(1)
+	}
+}
+
+func (c *Controller) onPodDelete(obj interface{}) {
+	var pod *corev1.Pod
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		pod, _ = t.Obj.(*corev1.Pod)
+	}
+	if pod != nil && pod.Spec.NodeName != "" {
+		c.clusterGeneration.Add(1)
+	}
+}
+
+func (c *Controller) onPodUpdate(oldObj, newObj interface{}) {
+	oldPod, oldOK := oldObj.(*corev1.Pod)
+	newPod, newOK := newObj.(*corev1.Pod)
+	if !oldOK || !newOK {
+		return
+	}
+	if newPod.Spec.NodeName != "" && !isPodTerminated(oldPod) && isPodTerminated(newPod) {
+		// the resources of the terminated pod are released
+		c.clusterGeneration.Add(1)
+	}
+	if oldPod.Spec.NodeName != "" || newPod.Spec.NodeName == "" {
+		return
+	}
+	c.clusterGeneration.Add(1)
+	var toSync []string
+	c.lock.RLock()
+	for _, key := range []string{newPod.Namespace + "/" + newPod.Name, extension.GetExplanationKey(newPod.Labels)} {
+		toSync = append(toSync, c.questionedKeys[key].UnsortedList()...)
+	}
+	c.lock.RUnlock()
+	for _, key := range toSync {
+		c.queue.Add(key)
+	}
+}
+
+func (c *Controller) track(explanation *schedulingv1alpha1.ScheduleExplanation) {
+	key, err := cache.MetaNamespaceKeyFunc(explanation)
+	if err != nil {
+		return
+	}
+	if explanation.Spec.QuestionObjectTemplate == nil && explanation.Spec.QuestionObjectKey != "" {
+		c.lock.Lock()
+		explanationKeys := c.questionedKeys[explanation.Spec.QuestionObjectKey]
+		if explanationKeys == nil {
+			explanationKeys = sets.New[string]()
+			c.questionedKeys[explanation.Spec.QuestionObjectKey] = explanationKeys
+		}
+		explanationKeys.Insert(key)
+		c.lock.Unlock()
+	}
+	c.queue.Add(key)
+}
+
+func (c *Controller) untrack(explanation *schedulingv1alpha1.ScheduleExplanation) {
+	key, err := cache.MetaNamespaceKeyFunc(explanation)
+	if err != nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	questionedKey := explanation.Spec.QuestionObjectKey
+	explanationKeys := c.questionedKeys[questionedKey]
+	explanationKeys.Delete(key)
+	if explanationKeys.Len() == 0 {
+		delete(c.questionedKeys, questionedKey)
+		delete(c.records, questionedKey)
+	}
+	delete(c.dryRuns, key)
+}
+
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func (c *Controller) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.sync(key.(string))
+	if err != nil {
+		c.queue.AddRateLimited(key)
+		klog.ErrorS(err, "failed to sync ScheduleExplanation", "explanation", key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *Controller) sync(key string) (time.Duration, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return 0, nil
+	}
+	explanation, err := c.explanationLister.ScheduleExplanations(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		c.lock.Lock()
+		delete(c.dryRuns, key)
+		c.lock.Unlock()
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	ttl := defaultTTL
+	if explanation.Spec.TTL != nil {
+		ttl = explanation.Spec.TTL.Duration
+	}
+	now := c.clock.Now()
+	// the questioned object keys are synced on their scheduling events, and only the dry runs of the questioned
+	// object templates are polled
+	var requeueAfter time.Duration
+	if ttl > 0 {
+		expiration := explanation.CreationTimestamp.Add(ttl)
+		if !now.Before(expiration) {
+			err = c.koordClientSet.SchedulingV1alpha1().ScheduleExplanations(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return 0, err
+			}
+			klog.V(4).InfoS("ScheduleExplanation expired and deleted", "explanation", key, "ttl", ttl)
+			return 0, nil
+		}
+		requeueAfter = expiration.Sub(now)
+	}
+
+	var r *record
+	if explanation.Spec.QuestionObjectTemplate != nil {
+		var nextRunAfter time.Duration
+		r, nextRunAfter, err = c.dryRunTemplate(key, explanation, now)
+		if err != nil {
+			return 0, err
+		}
+		if requeueAfter <= 0 || nextRunAfter < requeueAfter {
+			requeueAfter = nextRunAfter
+		}
+	} else {
+		r = c.explainKey(explanation.Spec.QuestionObjectKey, now)
+	}
+	if r == nil {
+		// the questioned object has not been scheduled by this scheduler yet
+		return requeueAfter, nil
+	}
+
+	status := buildStatus(r, c.nodeLister)
+	oldStatus := explanation.Status.DeepCopy()
+	oldStatus.LastUpdateTime = metav1.Time{}
+	if equality.Semantic.DeepEqual(oldStatus, &status) {
+		return requeueAfter, nil
+	}
+	status.LastUpdateTime = metav1.NewTime(now)
+	newExplanation := explanation.DeepCopy()
+	newExplanation.Status = status
+	_, err = c.koordClientSet.SchedulingV1alpha1().ScheduleExplanations(namespace).UpdateStatus(context.TODO(), newExplanation, metav1.UpdateOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return requeueAfter, nil
+}
+
+// dryRunTemplate returns the record to explain the questioned object template with, or nil if there is nothing
+// to explain, and when to check the dry run again. The dry run holds the scheduling lock, so it is re-run only
+// if the spec has changed, or if the cluster has changed and the interval since the last dry run has elapsed.
+// The interval doubles while the cluster does not change.
+func (c *Controller) dryRunTemplate(key string, explanation *schedulingv1alpha1.ScheduleExplanation, now time.Time) (*record, time.Duration, error) {
+	clusterGeneration := c.clusterGeneration.Load()
+	interval := minDryRunInterval
+	c.lock.Lock()
+	if last := c.dryRuns[key]; last != nil && equality.Semantic.DeepEqual(last.spec, &explanation.Spec) {
+		if elapsed := now.Sub(last.lastRunTime); elapsed < last.interval {
+			c.lock.Unlock()
+			return last.record, last.interval - elapsed, nil
+		}
+		if last.clusterGeneration == clusterGeneration {
+			last.lastRunTime = now
+			last.interval *= 2
+			if last.interval > maxDryRunInterval {
+				last.interval = maxDryRunInterval
+			}
+			c.lock.Unlock()
+			return last.record, last.interval, nil
+		}
+		interval = last.interval
+	}
+	c.lock.Unlock()
+
+	var r *record
+	result, err := c.scheduler.DryRunSchedule(context.TODO(), podFromTemplate(explanation))
+	if err == nil {
+		r = newRecordFromDryRun(result)
+	} else if !errors.Is(err, frameworkext.ErrSchedulerProfileNotFound) {
+		return nil, 0, err
+	}
+
+	c.lock.Lock()
+	c.dryRuns[key] = &dryRun{
+		spec:              explanation.Spec.DeepCopy(),
+		clusterGeneration: clusterGeneration,
+		lastRunTime:       now,
+		interval:          interval,
+		record:            r,
+	}
+	c.lock.Unlock()
+	return r, interval, nil
+}
+
+// explainKey returns the record to explain the questioned object key with, or nil if there is nothing to explain.
+func (c *Controller) explainKey(questionedKey string, now time.Time) *record {
+	if namespace, name, err := cache.SplitMetaNamespaceKey(questionedKey); err == nil && namespace != "" {
+		pod, err := c.podLister.Pods(namespace).Get(name)
+		if err == nil && pod.Spec.NodeName != "" {
+			return newRecordFromScheduledPod(pod, metav1.NewTime(now))
+		}
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.records[questionedKey]
+}
+
+// podFromTemplate makes the Pod to dry-run from the questioned object template. The Pod is in the namespace of
+// the ScheduleExplanation unless specified, and it takes the UID of the ScheduleExplanation to not collide with
+// the existing Pods.
+func podFromTemplate(explanation *schedulingv1alpha1.ScheduleExplanation) *corev1.Pod {
+	template := explanation.Spec.QuestionObjectTemplate
+	pod := &corev1.Pod{
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	if pod.Namespace == "" {
+		pod.Namespace = explanation.Namespace
+	}
+	if pod.Name == "" {
+		pod.Name = explanation.Name
+	}
+	pod.UID = explanation.UID
+	if pod.Spec.SchedulerName == "" {
+		pod.Spec.SchedulerName = corev1.DefaultSchedulerName
+	}
+	return pod
+}
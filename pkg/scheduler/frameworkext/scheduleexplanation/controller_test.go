@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduleexplanation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	fwktype "k8s.io/kube-scheduler/framework"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+type fakeDryRunScheduler struct {
+	pods   []*corev1.Pod
+	result *frameworkext.DryRunResult
+}
+
+func (s *fakeDryRunScheduler) DryRunSchedule(ctx context.Context, pod *corev1.Pod) (*frameworkext.DryRunResult, error) {
+	s.pods = append(s.pods, pod)
+	return s.result, nil
+}
+
+func newTestController(t *testing.T, scheduler DryRunScheduler, explanations ...*schedulingv1alpha1.ScheduleExplanation) (*Controller, *koordfake.Clientset) {
+	sharedInformerFactory := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0)
+	koordClientSet := koordfake.NewSimpleClientset()
+	koordSharedInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)
+	explanationInformer := koordSharedInformerFactory.Scheduling().V1alpha1().ScheduleExplanations().Informer()
+	for _, explanation := range explanations {
+		_, err := koordClientSet.SchedulingV1alpha1().ScheduleExplanations(explanation.Namespace).Create(context.TODO(), explanation, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, explanationInformer.GetStore().Add(explanation))
+	}
+	c := New(sharedInformerFactory, koordSharedInformerFactory, koordClientSet, scheduler, 1)
+	return c, koordClientSet
+}
+
+func TestSyncQuestionObjectKey(t *testing.T) {
+	now := time.Now()
+	explanation := &schedulingv1alpha1.ScheduleExplanation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "why", CreationTimestamp: metav1.NewTime(now)},
+		Spec:       schedulingv1alpha1.ScheduleExplanationSpec{QuestionObjectKey: "default/test-pod"},
+	}
+	c, koordClientSet := newTestController(t, &fakeDryRunScheduler{}, explanation)
+	fakeClock := clocktesting.NewFakeClock(now)
+	c.clock = fakeClock
+
+	// nothing is recorded yet
+	c.onExplanationAdd(explanation)
+	requeueAfter, err := c.sync("default/why")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultTTL, requeueAfter)
+	got, err := koordClientSet.SchedulingV1alpha1().ScheduleExplanations("default").Get(context.TODO(), "why", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, schedulingv1alpha1.ScheduleExplanationStatus{}, got.Status)
+
+	// the diagnoses of the other pods are ignored
+	c.RecordDiagnosis(&frameworkext.Diagnosis{QuestionedKey: "default/other-pod", IsRootCausePod: true})
+	assert.Len(t, c.records, 0)
+
+	c.RecordDiagnosis(&frameworkext.Diagnosis{
+		QuestionedKey:  "default/test-pod",
+		TargetPod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"}},
+		IsRootCausePod: true,
+		ScheduleDiagnosis: &frameworkext.ScheduleDiagnosis{
+			NodeToStatusMap: map[string]*fwktype.Status{"node-1": fwktype.NewStatus(fwktype.Unschedulable, "Insufficient cpu")},
+		},
+	})
+	assert.Len(t, c.records, 1)
+	_, err = c.sync("default/why")
+	assert.NoError(t, err)
+	got, err = koordClientSet.SchedulingV1alpha1().ScheduleExplanations("default").Get(context.TODO(), "why", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, got.Status.Schedulable)
+	assert.Equal(t, "0/1 nodes are available: 1 Insufficient cpu.", got.Status.FailedMessage)
+	assert.Equal(t, now.Unix(), got.Status.LastUpdateTime.Unix())
+
+	// the status is not written again if nothing changes
+	fakeClock.Step(10 * time.Minute)
+	assert.NoError(t, c.koordSharedInformerFactory.Scheduling().V1alpha1().ScheduleExplanations().Informer().GetStore().Update(got))
+	_, err = c.sync("default/why")
+	assert.NoError(t, err)
+	got, err = koordClientSet.SchedulingV1alpha1().ScheduleExplanations("default").Get(context.TODO(), "why", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, now.Unix(), got.Status.LastUpdateTime.Unix())
+
+	c.onExplanationDelete(explanation)
+	assert.Len(t, c.records, 0)
+	assert.Len(t, c.questionedKeys, 0)
+}
+
+func TestSyncQuestionObjectTemplate(t *testing.T) {
+	explanation := &schedulingv1alpha1.ScheduleExplanation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "what-if", UID: "what-if-uid", CreationTimestamp: metav1.Now()},
+		Spec: schedulingv1alpha1.ScheduleExplanationSpec{
+			QuestionObjectTemplate: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{SchedulerName: "koord-scheduler"},
+			},
+		},
+	}
+	scheduler := &fakeDryRunScheduler{
+		result: &frameworkext.DryRunResult{
+			FeasibleNodes: []string{"node-1"},
+			Diagnosis: &frameworkext.Diagnosis{
+				TargetPod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "what-if", UID: "what-if-uid"}},
+			},
+		},
+	}
+	c, koordClientSet := newTestController(t, scheduler, explanation)
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	c.clock = fakeClock
+	requeueAfter, err := c.sync("default/what-if")
+	assert.NoError(t, err)
+	assert.Equal(t, minDryRunInterval, requeueAfter)
+	if assert.Len(t, scheduler.pods, 1) {
+		assert.Equal(t, "default", scheduler.pods[0].Namespace)
+		assert.Equal(t, "what-if", scheduler.pods[0].Name)
+		assert.Equal(t, "koord-scheduler", scheduler.pods[0].Spec.SchedulerName)
+	}
+	got, err := koordClientSet.SchedulingV1alpha1().ScheduleExplanations("default").Get(context.TODO(), "what-if", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, got.Status.Schedulable)
+	if assert.Len(t, got.Status.DetailedExplanation, 1) {
+		assert.Equal(t, []schedulingv1alpha1.SchedulingResult{
+			{NamespacedName: schedulingv1alpha1.NamespacedName{Namespace: "default", Name: "what-if"}, NodeName: "node-1"},
+		}, got.Status.DetailedExplanation[0].ScheduleExplanation.FeasibleSchedulingResult)
+	}
+
+	// the dry run is not re-run before the interval elapses
+	fakeClock.Step(30 * time.Second)
+	c.clusterGeneration.Add(1)
+	requeueAfter, err = c.sync("default/what-if")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, requeueAfter)
+	assert.Len(t, scheduler.pods, 1)
+
+	// the dry run is re-run since the cluster has changed
+	fakeClock.Step(30 * time.Second)
+	requeueAfter, err = c.sync("default/what-if")
+	assert.NoError(t, err)
+	assert.Equal(t, minDryRunInterval, requeueAfter)
+	assert.Len(t, scheduler.pods, 2)
+
+	// the interval backs off while the cluster does not change
+	fakeClock.Step(minDryRunInterval)
+	requeueAfter, err = c.sync("default/what-if")
+	assert.NoError(t, err)
+	assert.Equal(t, 2*minDryRunInterval, requeueAfter)
+	assert.Len(t, scheduler.pods, 2)
+	for i := 0; i < 10; i++ {
+		fakeClock.Step(requeueAfter)
+		requeueAfter, err = c.sync("default/what-if")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, maxDryRunInterval, requeueAfter)
+	assert.Len(t, scheduler.pods, 2)
+
+	// the dry run is re-run immediately since the spec has changed
+	newExplanation := explanation.DeepCopy()
+	newExplanation.Spec.QuestionObjectTemplate.Spec.NodeName = "node-1"
+	assert.NoError(t, c.koordSharedInformerFactory.Scheduling().V1alpha1().ScheduleExplanations().Informer().GetStore().Update(newExplanation))
+	requeueAfter, err = c.sync("default/what-if")
+	assert.NoError(t, err)
+	assert.Equal(t, minDryRunInterval, requeueAfter)
+	assert.Len(t, scheduler.pods, 3)
+
+	c.onExplanationDelete(newExplanation)
+	assert.Len(t, c.dryRuns, 0)
+}
+
+func TestSyncTTL(t *testing.T) {
+	now := time.Now()
+	explanation := &schedulingv1alpha1.ScheduleExplanation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "why", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		Spec: schedulingv1alpha1.ScheduleExplanationSpec{
+			QuestionObjectKey: "default/test-pod",
+			TTL:               &metav1.Duration{Duration: time.Hour + 30*time.Second},
+		},
+	}
+	c, koordClientSet := newTestController(t, &fakeDryRunScheduler{}, explanation)
+	fakeClock := clocktesting.NewFakeClock(now)
+	c.clock = fakeClock
+
+	requeueAfter, err := c.sync("default/why")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, requeueAfter)
+
+	fakeClock.Step(30 * time.Second)
+	_, err = c.sync("default/why")
+	assert.NoError(t, err)
+	_, err = koordClientSet.SchedulingV1alpha1().ScheduleExplanations("default").Get(context.TODO(), "why", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduleexplanation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	fwktype "k8s.io/kube-scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+const (
+	// maxFeasibleSchedulingResults limits the feasible nodes listed in each topology domain to keep the CR small.
+	maxFeasibleSchedulingResults = 10
+	// maxFailedNodesPerStatus limits the failed nodes kept for each distinct failed status, so that a record does
+	// not grow with the cluster size.
+	maxFailedNodesPerStatus = 100
+)
+
+// record is what a questioned object is explained with, which is taken from the Diagnosis of the latest
+// scheduling cycle, the dry-run scheduling cycle or the scheduled Pod.
+type record struct {
+	timestamp   metav1.Time
+	pod         v1alpha1.NamespacedName
+	topologyKey string
+
+	preFilterMessage string
+	feasibleNodes    []string
+	failedNodes      nodeStatuses
+
+	preemptFailedNodes      nodeStatuses
+	possibleVictims         []v1alpha1.NodePossibleVictim
+	preemptionFailedMessage string
+}
+
+// nodeStatuses is the failed statuses of the nodes trimmed from a Diagnosis, since the Diagnosis must not be
+// retained. The statuses are copied and deduplicated, and at most maxFailedNodesPerStatus nodes are kept for each
+// distinct status, while the reasons are still counted on all the failed nodes.
+type nodeStatuses struct {
+	nodeToStatus map[string]*fwktype.Status
+	// reasonToNumNodes counts the failed nodes of each reason, including the nodes not kept in nodeToStatus.
+	reasonToNumNodes map[string]int
+	numNodes         int
+}
+
+func trimNodeToStatus(nodeToStatus map[string]*fwktype.Status) nodeStatuses {
+	trimmed := nodeStatuses{
+		nodeToStatus:     map[string]*fwktype.Status{},
+		reasonToNumNodes: map[string]int{},
+	}
+	type statusKey struct {
+		code    fwktype.Code
+		plugin  string
+		message string
+	}
+	statuses := map[statusKey]*fwktype.Status{}
+	numKept := map[statusKey]int{}
+	nodeNames := make([]string, 0, len(nodeToStatus))
+	for nodeName, status := range nodeToStatus {
+		if status != nil {
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		status := nodeToStatus[nodeName]
+		trimmed.numNodes++
+		for _, reason := range status.Reasons() {
+			trimmed.reasonToNumNodes[reason]++
+		}
+		key := statusKey{code: status.Code(), plugin: status.Plugin(), message: status.Message()}
+		if numKept[key] >= maxFailedNodesPerStatus {
+			continue
+		}
+		numKept[key]++
+		s := statuses[key]
+		if s == nil {
+			s = fwktype.NewStatus(status.Code(), status.Reasons()...).WithPlugin(status.Plugin())
+			statuses[key] = s
+		}
+		trimmed.nodeToStatus[nodeName] = s
+	}
+	return trimmed
+}
+
+func newRecordFromDiagnosis(diagnosis *frameworkext.Diagnosis) *record {
+	r := &record{
+		timestamp:        diagnosis.Timestamp,
+		preFilterMessage: diagnosis.PreFilterMessage,
+	}
+	if pod := diagnosis.TargetPod; pod != nil {
+		r.pod = v1alpha1.NamespacedName{Namespace: pod.Namespace, Name: pod.Name, UID: string(pod.UID)}
+		r.topologyKey = extension.GetTopologyKeyToExplain(pod)
+	}
+	if diagnosis.ScheduleDiagnosis != nil {
+		r.failedNodes = trimNodeToStatus(diagnosis.ScheduleDiagnosis.NodeToStatusMap)
+	}
+	if preemption := diagnosis.PreemptionDiagnosis; preemption != nil {
+		if preemption.DryRunFilterDiagnosis != nil {
+			r.preemptFailedNodes = trimNodeToStatus(preemption.DryRunFilterDiagnosis.NodeToStatusMap)
+		}
+		if provider, ok := preemption.OtherDiagnosis.(frameworkext.PossibleVictimsProvider); ok {
+			r.possibleVictims = provider.GetPossibleVictims()
+		}
+		r.preemptionFailedMessage = preemption.FailedMessage
+	}
+	return r
+}
+
+func newRecordFromDryRun(result *frameworkext.DryRunResult) *record {
+	r := newRecordFromDiagnosis(result.Diagnosis)
+	// the UID of the dry-run Pod is made up
+	r.pod.UID = ""
+	r.feasibleNodes = result.FeasibleNodes
+	return r
+}
+
+func newRecordFromScheduledPod(pod *corev1.Pod, now metav1.Time) *record {
+	return &record{
+		timestamp:     now,
+		pod:           v1alpha1.NamespacedName{Namespace: pod.Namespace, Name: pod.Name, UID: string(pod.UID)},
+		topologyKey:   extension.GetTopologyKeyToExplain(pod),
+		feasibleNodes: []string{pod.Spec.NodeName},
+	}
+}
+
+// buildStatus explains the record in each topology domain. The nodes are grouped into the domains by the value of
+// the topology key label, and all nodes are in the same domain if the topology key is not specified.
+func buildStatus(r *record, nodeLister corelisters.NodeLister) v1alpha1.ScheduleExplanationStatus {
+	domainOf := func(nodeName string) string {
+		if r.topologyKey == "" {
+			return ""
+		}
+		node, err := nodeLister.Get(nodeName)
+		if err != nil {
+			return ""
+		}
+		return node.Labels[r.topologyKey]
+	}
+
+	domains := map[string]*domainRecord{}
+	getDomain := func(nodeName string) *domainRecord {
+		name := domainOf(nodeName)
+		d := domains[name]
+		if d == nil {
+			d = &domainRecord{
+				nodeToStatus:        map[string]*fwktype.Status{},
+				preemptNodeToStatus: map[string]*fwktype.Status{},
+			}
+			domains[name] = d
+		}
+		return d
+	}
+	for _, nodeName := range r.feasibleNodes {
+		d := getDomain(nodeName)
+		d.feasibleNodes = append(d.feasibleNodes, nodeName)
+	}
+	for nodeName, status := range r.failedNodes.nodeToStatus {
+		getDomain(nodeName).nodeToStatus[nodeName] = status
+	}
+	for nodeName, status := range r.preemptFailedNodes.nodeToStatus {
+		getDomain(nodeName).preemptNodeToStatus[nodeName] = status
+	}
+	for _, victims := range r.possibleVictims {
+		d := getDomain(victims.NodeName)
+		d.possibleVictims = append(d.possibleVictims, victims)
+	}
+
+	status := v1alpha1.ScheduleExplanationStatus{}
+	for name, d := range domains {
+		explanation := d.explain(r.pod, r.preFilterMessage)
+		explanation.TopologyDomain = name
+		status.Schedulable = status.Schedulable || explanation.Schedulable
+		status.SchedulableAfterPreemption = status.SchedulableAfterPreemption || explanation.SchedulableAfterPreemption
+		status.DetailedExplanation = append(status.DetailedExplanation, explanation)
+	}
+	sort.Slice(status.DetailedExplanation, func(i, j int) bool {
+		return status.DetailedExplanation[i].TopologyDomain < status.DetailedExplanation[j].TopologyDomain
+	})
+	if status.Schedulable {
+		status.SchedulableAfterPreemption = false
+		return status
+	}
+	if r.preFilterMessage != "" {
+		status.FailedMessage = r.preFilterMessage
+	} else {
+		status.FailedMessage = fitErrorMessage(len(r.feasibleNodes)+r.failedNodes.numNodes, r.failedNodes.reasonToNumNodes)
+	}
+	if r.preemptionFailedMessage != "" {
+		status.FailedMessage = fmt.Sprintf("%s; preemption: %s", status.FailedMessage, r.preemptionFailedMessage)
+	}
+	return status
+}
+
+type domainRecord struct {
+	feasibleNodes       []string
+	nodeToStatus        map[string]*fwktype.Status
+	preemptNodeToStatus map[string]*fwktype.Status
+	possibleVictims     []v1alpha1.NodePossibleVictim
+}
+
+func (d *domainRecord) explain(pod v1alpha1.NamespacedName, preFilterMessage string) *v1alpha1.TopologyDomainLevelExplanation {
+	explanation := &v1alpha1.TopologyDomainLevelExplanation{
+		Schedulable:                len(d.feasibleNodes) > 0,
+		SchedulableAfterPreemption: len(d.feasibleNodes) == 0 && len(d.possibleVictims) > 0,
+		NodePossibleVictims:        d.possibleVictims,
+		ScheduleExplanation: v1alpha1.NodeLevelExplanations{
+			Schedulable:       len(d.feasibleNodes) > 0,
+			NodeFailedDetails: frameworkext.ConvertStatusMapToFailedDetail(d.nodeToStatus),
+		},
+	}
+	sort.Strings(d.feasibleNodes)
+	for i, nodeName := range d.feasibleNodes {
+		if i >= maxFeasibleSchedulingResults {
+			break
+		}
+		explanation.ScheduleExplanation.FeasibleSchedulingResult = append(explanation.ScheduleExplanation.FeasibleSchedulingResult,
+			v1alpha1.SchedulingResult{NamespacedName: pod, NodeName: nodeName})
+	}
+	if !explanation.Schedulable {
+		if preFilterMessage != "" {
+			explanation.ScheduleExplanation.FailedMessage = preFilterMessage
+		} else {
+			explanation.ScheduleExplanation.FailedMessage = fitErrorMessage(len(d.nodeToStatus), countReasons(d.nodeToStatus))
+		}
+		explanation.FailedMessage = explanation.ScheduleExplanation.FailedMessage
+	}
+	if len(d.preemptNodeToStatus) > 0 || len(d.possibleVictims) > 0 {
+		explanation.PreemptExplanation = v1alpha1.NodeLevelExplanations{
+			Schedulable:       explanation.SchedulableAfterPreemption,
+			NodeFailedDetails: frameworkext.ConvertStatusMapToFailedDetail(d.preemptNodeToStatus),
+		}
+		if !explanation.SchedulableAfterPreemption {
+			explanation.PreemptExplanation.FailedMessage = fitErrorMessage(len(d.preemptNodeToStatus), countReasons(d.preemptNodeToStatus))
+		}
+	}
+	return explanation
+}
+
+func countReasons(nodeToStatus map[string]*fwktype.Status) map[string]int {
+	reasons := map[string]int{}
+	for _, status := range nodeToStatus {
+		for _, reason := range status.Reasons() {
+			reasons[reason]++
+		}
+	}
+	return reasons
+}
+
+// fitErrorMessage summarizes the failed reasons in the same format as the FitError of kube-scheduler.
+func fitErrorMessage(numAllNodes int, reasons map[string]int) string {
+	var reasonStrings []string
+	for reason, count := range reasons {
+		reasonStrings = append(reasonStrings, fmt.Sprintf("%v %v", count, reason))
+	}
+	sort.Strings(reasonStrings)
+	return fmt.Sprintf("0/%v nodes are available: %v.", numAllNodes, strings.Join(reasonStrings, ", "))
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduleexplanation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	fwktype "k8s.io/kube-scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+type fakeVictimsProvider []v1alpha1.NodePossibleVictim
+
+func (p fakeVictimsProvider) GetPossibleVictims() []v1alpha1.NodePossibleVictim {
+	return p
+}
+
+func TestBuildStatus(t *testing.T) {
+	sharedInformerFactory := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0)
+	nodeInformer := sharedInformerFactory.Core().V1().Nodes().Informer()
+	for name, zone := range map[string]string{"node-1": "zone-a", "node-2": "zone-a", "node-3": "zone-b"} {
+		_ = nodeInformer.GetStore().Add(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone}},
+		})
+	}
+	nodeLister := sharedInformerFactory.Core().V1().Nodes().Lister()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+			UID:       "test-uid",
+			Labels:    map[string]string{extension.LabelTopologyKeyToExplain: corev1.LabelTopologyZone},
+		},
+	}
+	insufficientCPU := fwktype.NewStatus(fwktype.Unschedulable, "Insufficient cpu").WithPlugin("NodeResourcesFit")
+	taint := fwktype.NewStatus(fwktype.UnschedulableAndUnresolvable, "node(s) had untolerated taint").WithPlugin("TaintToleration")
+	victims := []v1alpha1.NodePossibleVictim{
+		{
+			NodeName:        "node-1",
+			PossibleVictims: []v1alpha1.PossibleVictim{{NamespacedName: v1alpha1.NamespacedName{Namespace: "default", Name: "victim"}}},
+		},
+	}
+	r := newRecordFromDiagnosis(&frameworkext.Diagnosis{
+		QuestionedKey:  "default/test-pod",
+		TargetPod:      pod,
+		IsRootCausePod: true,
+		ScheduleDiagnosis: &frameworkext.ScheduleDiagnosis{
+			NodeToStatusMap: map[string]*fwktype.Status{"node-1": insufficientCPU, "node-2": insufficientCPU, "node-3": taint},
+		},
+		PreemptionDiagnosis: &frameworkext.PreemptionDiagnosis{
+			DryRunFilterDiagnosis: &frameworkext.ScheduleDiagnosis{
+				NodeToStatusMap: map[string]*fwktype.Status{"node-2": insufficientCPU},
+			},
+			OtherDiagnosis: fakeVictimsProvider(victims),
+		},
+	})
+	status := buildStatus(r, nodeLister)
+	assert.Equal(t, v1alpha1.ScheduleExplanationStatus{
+		SchedulableAfterPreemption: true,
+		FailedMessage:              "0/3 nodes are available: 1 node(s) had untolerated taint, 2 Insufficient cpu.",
+		DetailedExplanation: []*v1alpha1.TopologyDomainLevelExplanation{
+			{
+				TopologyDomain:             "zone-a",
+				SchedulableAfterPreemption: true,
+				FailedMessage:              "0/2 nodes are available: 2 Insufficient cpu.",
+				ScheduleExplanation: v1alpha1.NodeLevelExplanations{
+					FailedMessage: "0/2 nodes are available: 2 Insufficient cpu.",
+					NodeFailedDetails: v1alpha1.NodeFailedDetails{
+						{
+							NodeFailedStatus: v1alpha1.NodeFailedStatus{FailedPlugin: "NodeResourcesFit", Reason: "Insufficient cpu", PreemptMightHelp: true},
+							FailedNodes:      []string{"node-1", "node-2"},
+						},
+					},
+				},
+				NodePossibleVictims: victims,
+				PreemptExplanation: v1alpha1.NodeLevelExplanations{
+					Schedulable: true,
+					NodeFailedDetails: v1alpha1.NodeFailedDetails{
+						{
+							NodeFailedStatus: v1alpha1.NodeFailedStatus{FailedPlugin: "NodeResourcesFit", Reason: "Insufficient cpu", PreemptMightHelp: true},
+							FailedNodes:      []string{"node-2"},
+						},
+					},
+				},
+			},
+			{
+				TopologyDomain: "zone-b",
+				FailedMessage:  "0/1 nodes are available: 1 node(s) had untolerated taint.",
+				ScheduleExplanation: v1alpha1.NodeLevelExplanations{
+					FailedMessage: "0/1 nodes are available: 1 node(s) had untolerated taint.",
+					NodeFailedDetails: v1alpha1.NodeFailedDetails{
+						{
+							NodeFailedStatus: v1alpha1.NodeFailedStatus{FailedPlugin: "TaintToleration", Reason: "node(s) had untolerated taint"},
+							FailedNodes:      []string{"node-3"},
+						},
+					},
+				},
+			},
+		},
+	}, status)
+
+	status = buildStatus(newRecordFromScheduledPod(&corev1.Pod{
+		ObjectMeta: pod.ObjectMeta,
+		Spec:       corev1.PodSpec{NodeName: "node-3"},
+	}, metav1.Now()), nodeLister)
+	assert.Equal(t, v1alpha1.ScheduleExplanationStatus{
+		Schedulable: true,
+		DetailedExplanation: []*v1alpha1.TopologyDomainLevelExplanation{
+			{
+				TopologyDomain: "zone-b",
+				Schedulable:    true,
+				ScheduleExplanation: v1alpha1.NodeLevelExplanations{
+					Schedulable: true,
+					FeasibleSchedulingResult: []v1alpha1.SchedulingResult{
+						{NamespacedName: v1alpha1.NamespacedName{Namespace: "default", Name: "test-pod", UID: "test-uid"}, NodeName: "node-3"},
+					},
+				},
+			},
+		},
+	}, status)
+}
+
+func TestTrimNodeToStatus(t *testing.T) {
+	nodeToStatus := map[string]*fwktype.Status{"node-nil": nil}
+	for i := 0; i < maxFailedNodesPerStatus+10; i++ {
+		nodeToStatus[fmt.Sprintf("node-cpu-%03d", i)] = fwktype.NewStatus(fwktype.Unschedulable, "Insufficient cpu").WithPlugin("NodeResourcesFit")
+	}
+	nodeToStatus["node-taint"] = fwktype.NewStatus(fwktype.UnschedulableAndUnresolvable, "node(s) had untolerated taint").WithPlugin("TaintToleration")
+
+	trimmed := trimNodeToStatus(nodeToStatus)
+	assert.Equal(t, maxFailedNodesPerStatus+11, trimmed.numNodes)
+	assert.Equal(t, map[string]int{"Insufficient cpu": maxFailedNodesPerStatus + 10, "node(s) had untolerated taint": 1}, trimmed.reasonToNumNodes)
+	assert.Len(t, trimmed.nodeToStatus, maxFailedNodesPerStatus+1)
+	assert.NotContains(t, trimmed.nodeToStatus, "node-nil")
+	assert.NotContains(t, trimmed.nodeToStatus, fmt.Sprintf("node-cpu-%03d", maxFailedNodesPerStatus))
+	// the statuses are copied rather than retained from the diagnosis
+	assert.NotSame(t, nodeToStatus["node-taint"], trimmed.nodeToStatus["node-taint"])
+	assert.Same(t, trimmed.nodeToStatus["node-cpu-000"], trimmed.nodeToStatus["node-cpu-001"])
+	assert.Equal(t, "TaintToleration", trimmed.nodeToStatus["node-taint"].Plugin())
+	assert.Equal(t, "0/111 nodes are available: 1 node(s) had untolerated taint, 110 Insufficient cpu.",
+		fitErrorMessage(trimmed.numNodes, trimmed.reasonToNumNodes))
+}
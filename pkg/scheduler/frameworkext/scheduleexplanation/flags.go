@@ -0,0 +1,33 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduleexplanation
+
+import (
+	"github.com/spf13/pflag"
+)
+
+var (
+	// ScheduleExplanationEnabled enables the ScheduleExplanation controller, the ScheduleExplanation CRD must be installed.
+	ScheduleExplanationEnabled = false
+	ScheduleExplanationWorkers = 1
+)
+
+// AddFlags registers the ScheduleExplanation controller command-line flags.
+func AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&ScheduleExplanationEnabled, "enable-schedule-explanation", ScheduleExplanationEnabled, "enable the controller to explain the scheduling of the questioned objects in ScheduleExplanations")
+	fs.IntVar(&ScheduleExplanationWorkers, "schedule-explanation-workers", ScheduleExplanationWorkers, "the number of workers to reconcile ScheduleExplanations")
+}
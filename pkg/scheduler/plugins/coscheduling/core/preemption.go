@@ -134,6 +134,13 @@ type JobPreemptionState struct {
 	Victims                 []v1alpha1.NodePossibleVictim `json:"victims,omitempty"`
 }
 
+var _ frameworkext.PossibleVictimsProvider = &JobPreemptionState{}
+
+// GetPossibleVictims returns the victims selected on each node to make room for the preemptor.
+func (s *JobPreemptionState) GetPossibleVictims() []v1alpha1.NodePossibleVictim {
+	return s.Victims
+}
+
 func (s *JobPreemptionState) addMoreDetailForStateToMarshal() {
 	s.UnschedulablePodsNumber = len(s.unschedulablePods)
 	if s.selectVictimError != nil {
@@ -204,12 +204,13 @@ func (gqm *GroupQuotaManager) recursiveUpdateGroupTreeWithDeltaRequest(deltaReq,
 
 		curQuotaInfo.addChildRequestNonNegativeNoLock(deltaReq)
 		realRequest := curQuotaInfo.CalculateInfo.ChildRequest.DeepCopy()
-		// If the quota not allow to lent resource. we should request for min
-		if !curQuotaInfo.AllowLentResource {
+		// If the quota not allow to lent resource. we should request for min, or the part of min not lent
+		// in the active lending schedules.
+		if reservedMin := curQuotaInfo.getReservedMinNoLock(); reservedMin != nil {
 			if realRequest == nil {
 				realRequest = v1.ResourceList{}
 			}
-			for r, q := range curQuotaInfo.CalculateInfo.Min {
+			for r, q := range reservedMin {
 				p, ok := realRequest[r]
 				if !ok {
 					realRequest[r] = q
@@ -667,6 +668,8 @@ func (gqm *GroupQuotaManager) GetAllQuotaNames() map[string]struct{} {
 type QuotaSnapshot struct {
 	lock         sync.RWMutex
 	quotaInfoMap map[string]*QuotaInfo
+	// dominantShares stores the dominant resource share of each quota when the snapshot is taken
+	dominantShares map[string]float64
 }
 
 // GetQuotaSnapshot returns a snapshot of quotaInfoMap
@@ -677,16 +680,26 @@ func (gqm *GroupQuotaManager) GetQuotaSnapshot() *QuotaSnapshot {
 	defer gqm.hierarchyUpdateLock.RUnlock()
 
 	snapshot := &QuotaSnapshot{
-		quotaInfoMap: make(map[string]*QuotaInfo, len(gqm.quotaInfoMap)),
+		quotaInfoMap:   make(map[string]*QuotaInfo, len(gqm.quotaInfoMap)),
+		dominantShares: make(map[string]float64, len(gqm.quotaInfoMap)),
 	}
 
 	for name, quotaInfo := range gqm.quotaInfoMap {
 		// Deep copy QuotaInfo for snapshot
-		snapshot.quotaInfoMap[name] = quotaInfo.DeepCopy()
+		quotaInfoCopy := quotaInfo.DeepCopy()
+		snapshot.quotaInfoMap[name] = quotaInfoCopy
+		snapshot.dominantShares[name] = quotaInfoCopy.GetDominantShare()
 	}
 	return snapshot
 }
 
+// GetDominantShare returns the dominant resource share of the quota when the snapshot is taken
+func (s *QuotaSnapshot) GetDominantShare(quotaName string) float64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.dominantShares[quotaName]
+}
+
 // GetQuotaInfoByName returns a QuotaInfo from the snapshot by name
 func (s *QuotaSnapshot) GetQuotaInfoByName(quotaName string) *QuotaInfo {
 	s.lock.RLock()
@@ -1183,6 +1196,14 @@ func (gqm *GroupQuotaManager) updateQuotaInternalNoLock(newQuotaInfo, oldQuotaIn
 			gqm.runtimeQuotaCalculatorMap[newQuotaInfo.ParentName] = NewRuntimeQuotaCalculator(newQuotaInfo.ParentName)
 		}
 		gqm.quotaInfoMap[newQuotaInfo.Name] = NewQuotaInfo(newQuotaInfo.IsParent, newQuotaInfo.AllowLentResource, newQuotaInfo.Name, newQuotaInfo.ParentName)
+		gqm.quotaInfoMap[newQuotaInfo.Name].setLendingPolicyNoLock(newQuotaInfo)
+	} else if oldQuotaInfo.isLendingPolicyChangeNoLock(newQuotaInfo) {
+		// the lend ratio is updated at last, as it affects the request
+		lendRatio := oldQuotaInfo.ScheduledLendRatio
+		oldQuotaInfo.lock.Lock()
+		oldQuotaInfo.setLendingPolicyNoLock(newQuotaInfo)
+		oldQuotaInfo.ScheduledLendRatio = lendRatio
+		oldQuotaInfo.lock.Unlock()
 	}
 
 	oldMax := v1.ResourceList{}
@@ -1219,6 +1240,73 @@ func (gqm *GroupQuotaManager) updateQuotaInternalNoLock(newQuotaInfo, oldQuotaIn
 			newQuotaInfo.Name, util.DumpJSON(oldSharedWeight), util.DumpJSON(newQuotaInfo.CalculateInfo.SharedWeight))
 		gqm.doUpdateOneGroupSharedWeightNoLock(newQuotaInfo.Name, newQuotaInfo.CalculateInfo.SharedWeight)
 	}
+
+	// lend ratio changed
+	if oldQuotaInfo != nil && oldQuotaInfo.ScheduledLendRatio != newQuotaInfo.ScheduledLendRatio {
+		klog.V(4).Infof("[updateQuotaInternalNoLock] quota %v lend ratio change, oldLendRatio: %v, newLendRatio: %v",
+			newQuotaInfo.Name, oldQuotaInfo.ScheduledLendRatio, newQuotaInfo.ScheduledLendRatio)
+		gqm.doUpdateOneGroupLendRatioNoLock(newQuotaInfo.Name, newQuotaInfo.ScheduledLendRatio)
+	}
+}
+
+// RefreshLendingSchedules re-evaluates the lending schedules of all quota groups at now, and updates the lend ratio
+// of the quota groups whose active lending schedules change.
+func (gqm *GroupQuotaManager) RefreshLendingSchedules(now time.Time) {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		quotaInfo.lock.RLock()
+		schedules, oldLendRatio := quotaInfo.LendingSchedules, quotaInfo.ScheduledLendRatio
+		quotaInfo.lock.RUnlock()
+		if len(schedules) == 0 && oldLendRatio == 0 {
+			continue
+		}
+		lendRatio := extension.GetActiveLendRatio(schedules, now)
+		if lendRatio != oldLendRatio {
+			klog.V(4).Infof("quota %v lend ratio change by lending schedules, oldLendRatio: %v, newLendRatio: %v",
+				quotaName, oldLendRatio, lendRatio)
+			gqm.doUpdateOneGroupLendRatioNoLock(quotaName, lendRatio)
+		}
+	}
+}
+
+// doUpdateOneGroupLendRatioNoLock updates the lend ratio of the active lending schedules. The part of min not lent
+// affects the request, and whether the runtime can be less than min.
+func (gqm *GroupQuotaManager) doUpdateOneGroupLendRatioNoLock(quotaName string, lendRatio float64) {
+	curToAllParInfos := gqm.getCurToAllParentGroupQuotaInfoNoLock(quotaName)
+	quotaInfoLen := len(curToAllParInfos)
+	if quotaInfoLen <= 0 {
+		return
+	}
+
+	defer gqm.scopedLockForQuotaInfo(curToAllParInfos)()
+
+	curQuotaInfo := curToAllParInfos[0]
+	curQuotaInfo.ScheduledLendRatio = lendRatio
+
+	oldSubLimitReq := curQuotaInfo.getLimitRequestNoLock()
+	realRequest := curQuotaInfo.CalculateInfo.ChildRequest.DeepCopy()
+	if reservedMin := curQuotaInfo.getReservedMinNoLock(); reservedMin != nil {
+		realRequest = quotav1.Max(realRequest, reservedMin)
+	}
+	curQuotaInfo.CalculateInfo.Request = realRequest
+
+	if quotaInfoLen > 1 {
+		parentRuntimeCalculator := gqm.getRuntimeQuotaCalculatorByNameNoLock(curQuotaInfo.ParentName)
+		if parentRuntimeCalculator == nil {
+			klog.Errorf("runtimeQuotaCalculator not exist! quotaName: %v, parentName: %v", curQuotaInfo.Name, curQuotaInfo.ParentName)
+			return
+		}
+		parentRuntimeCalculator.updateOneGroupAllowLentResource(curQuotaInfo)
+		if parentRuntimeCalculator.needUpdateOneGroupRequest(curQuotaInfo) {
+			parentRuntimeCalculator.updateOneGroupRequest(curQuotaInfo)
+		}
+
+		newSubLimitReq := curQuotaInfo.getLimitRequestNoLock()
+		deltaRequest := quotav1.Subtract(newSubLimitReq, oldSubLimitReq)
+		gqm.recursiveUpdateGroupTreeWithDeltaRequest(deltaRequest, nil, curToAllParInfos[1:], -1)
+	}
 }
 
 func (gqm *GroupQuotaManager) updateQuotaNoLockWhenParentChange(newQuota *v1alpha1.ElasticQuota) {
@@ -1391,8 +1479,8 @@ func (gqm *GroupQuotaManager) doUpdateOneGroupMinQuotaNoLock(quotaName string, n
 	// Update request. If the quota not allow to lent resource, the new min will effect the request.
 	oldSubLimitReq := curQuotaInfo.getLimitRequestNoLock()
 	realRequest := curQuotaInfo.CalculateInfo.ChildRequest.DeepCopy()
-	if !curQuotaInfo.AllowLentResource {
-		realRequest = quotav1.Max(realRequest, curQuotaInfo.CalculateInfo.Min)
+	if reservedMin := curQuotaInfo.getReservedMinNoLock(); reservedMin != nil {
+		realRequest = quotav1.Max(realRequest, reservedMin)
 	}
 	curQuotaInfo.CalculateInfo.Request = realRequest
 
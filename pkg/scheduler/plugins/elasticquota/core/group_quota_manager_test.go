@@ -439,6 +439,71 @@ func TestGroupQuotaManager_NotAllowLentResource(t *testing.T) {
 	assert.Equal(t, int64(40), runtime.Cpu().Value())
 }
 
+func TestGroupQuotaManager_LendingSchedules(t *testing.T) {
+	gqm := NewGroupQuotaManagerForTest()
+	cpuOf := func(res v1.ResourceList) int64 {
+		return res.Cpu().Value()
+	}
+
+	deltaRes := createResourceList(100, 0)
+	gqm.UpdateClusterTotalResource(deltaRes)
+
+	AddQuotaToManager(t, gqm, "test1", extension.RootQuotaName, 96, 0, 60, 0, true, false)
+	quota := CreateQuota("test2", extension.RootQuotaName, 96, 0, 40, 0, false, false)
+	quota.Annotations[extension.AnnotationLendingSchedules] = `[{"days":["Sat","Sun"],"start":"00:00","end":"00:00","lendRatio":0.5}]`
+	quota.Annotations[extension.AnnotationMaxBorrowDuration] = "1h"
+	quota.Annotations[extension.AnnotationRevokeGracePeriod] = "10m"
+	assert.NoError(t, gqm.UpdateQuota(quota))
+	quotaInfo := gqm.GetQuotaInfoByName("test2")
+	assert.Len(t, quotaInfo.LendingSchedules, 1)
+	assert.Equal(t, time.Hour, quotaInfo.GetMaxBorrowDuration())
+	gracePeriod, ok := quotaInfo.GetRevokeGracePeriod()
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Minute, gracePeriod)
+
+	request := createResourceList(120, 0)
+	gqm.updateGroupDeltaRequestNoLock("test1", request, request, 0)
+
+	// 2024-01-06 is a Saturday, test2 lends half of its min
+	gqm.RefreshLendingSchedules(time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, 0.5, quotaInfo.ScheduledLendRatio)
+	assert.Equal(t, int64(20), cpuOf(quotaInfo.GetRequest()))
+	assert.Equal(t, int64(80), cpuOf(gqm.RefreshRuntime("test1")))
+	assert.Equal(t, int64(20), cpuOf(gqm.RefreshRuntime("test2")))
+
+	// test2 requests less than its min but more than the part not lent
+	request = createResourceList(30, 0)
+	gqm.updateGroupDeltaRequestNoLock("test2", request, request, 0)
+	assert.Equal(t, int64(70), cpuOf(gqm.RefreshRuntime("test1")))
+	assert.Equal(t, int64(30), cpuOf(gqm.RefreshRuntime("test2")))
+	request = createResourceList(-30, 0)
+	gqm.updateGroupDeltaRequestNoLock("test2", request, request, 0)
+
+	// 2024-01-08 is a Monday, test2 lends nothing
+	gqm.RefreshLendingSchedules(time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, 0.0, quotaInfo.ScheduledLendRatio)
+	assert.Equal(t, int64(40), cpuOf(quotaInfo.GetRequest()))
+	assert.Equal(t, int64(60), cpuOf(gqm.RefreshRuntime("test1")))
+	assert.Equal(t, int64(40), cpuOf(gqm.RefreshRuntime("test2")))
+
+	// lend the whole min all the time
+	quota = quota.DeepCopy()
+	quota.Annotations[extension.AnnotationLendingSchedules] = `[{"start":"00:00","end":"00:00","lendRatio":1}]`
+	assert.NoError(t, gqm.UpdateQuota(quota))
+	assert.Equal(t, 1.0, quotaInfo.ScheduledLendRatio)
+	assert.Equal(t, int64(96), cpuOf(gqm.RefreshRuntime("test1")))
+	assert.Equal(t, int64(0), cpuOf(gqm.RefreshRuntime("test2")))
+
+	// remove the lending schedules
+	quota = quota.DeepCopy()
+	delete(quota.Annotations, extension.AnnotationLendingSchedules)
+	assert.NoError(t, gqm.UpdateQuota(quota))
+	assert.Nil(t, quotaInfo.LendingSchedules)
+	assert.Equal(t, 0.0, quotaInfo.ScheduledLendRatio)
+	assert.Equal(t, int64(60), cpuOf(gqm.RefreshRuntime("test1")))
+	assert.Equal(t, int64(40), cpuOf(gqm.RefreshRuntime("test2")))
+}
+
 func TestGroupQuotaManager_NotAllowLentResource_2(t *testing.T) {
 	gqm := NewGroupQuotaManagerForTest()
 
@@ -2768,6 +2833,7 @@ func TestGroupQuotaManager_GetQuotaSnapshot(t *testing.T) {
 	// Verify non-existent quota returns nil
 	quotaInfo = snapshot.GetQuotaInfoByName("non-existent")
 	assert.Nil(t, quotaInfo)
+	assert.Equal(t, float64(0), snapshot.GetDominantShare("non-existent"))
 }
 
 func TestQuotaSnapshot_GetQuotaPathToRoot(t *testing.T) {
@@ -2822,3 +2888,28 @@ func TestQuotaSnapshot_GetQuotaPathToRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestQuotaSnapshot_GetDominantShare(t *testing.T) {
+	gqm := NewGroupQuotaManagerForTest()
+	gqm.UpdateClusterTotalResource(createResourceList(50, 50))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 40, 40, 10, 10, true, false)
+
+	pod := schetesting.MakePod().Name("1").Obj()
+	pod.Spec.Containers = []v1.Container{
+		{
+			Resources: v1.ResourceRequirements{
+				Requests: createResourceList(5, 2),
+			},
+		},
+	}
+	gqm.OnPodAdd("1", pod)
+	gqm.ReservePod("1", pod)
+
+	snapshot := gqm.GetQuotaSnapshot()
+	assert.InDelta(t, 0.5, snapshot.GetDominantShare("1"), 1e-9)
+
+	// the snapshot is not changed by the later updates
+	gqm.UnreservePod("1", pod)
+	assert.InDelta(t, 0.5, snapshot.GetDominantShare("1"), 1e-9)
+	assert.Equal(t, float64(0), gqm.GetQuotaSnapshot().GetDominantShare("1"))
+}
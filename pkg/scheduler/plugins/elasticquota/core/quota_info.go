@@ -18,7 +18,9 @@ package core
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
@@ -78,6 +80,16 @@ type QuotaInfo struct {
 	RuntimeVersion int64
 	// Allow lent resource to other quota group
 	AllowLentResource bool
+	// LendingSchedules are the time windows when the quota group lends a ratio of its min to other quota groups
+	LendingSchedules []extension.LendingSchedule
+	// ScheduledLendRatio is the lend ratio of the active lending schedules, zero if no schedule is active
+	ScheduledLendRatio float64
+	// MaxBorrowDuration is how long the quota group can use the resources borrowed from other quota groups,
+	// zero means unlimited
+	MaxBorrowDuration time.Duration
+	// RevokeGracePeriod is how long the quota group can use more than its runtime before its pods are revoked,
+	// nil means using the default of the scheduler
+	RevokeGracePeriod *time.Duration
 	CalculateInfo     QuotaCalculateInfo
 	PodCache          map[string]*PodInfo
 	lock              sync.RWMutex
@@ -145,6 +157,7 @@ func (qi *QuotaInfo) DeepCopy() *QuotaInfo {
 			SelfNonPreemptibleUsed:    qi.CalculateInfo.SelfNonPreemptibleUsed.DeepCopy(),
 		},
 	}
+	quotaInfo.setLendingPolicyNoLock(qi)
 	for name, pod := range qi.PodCache {
 		quotaInfo.PodCache[name] = pod
 	}
@@ -161,6 +174,7 @@ func (qi *QuotaInfo) GetQuotaSummary(treeID string, includePods bool) *QuotaInfo
 	quotaInfoSummary.IsParent = qi.IsParent
 	quotaInfoSummary.RuntimeVersion = qi.RuntimeVersion
 	quotaInfoSummary.AllowLentResource = qi.AllowLentResource
+	quotaInfoSummary.ScheduledLendRatio = qi.ScheduledLendRatio
 	quotaInfoSummary.Tree = treeID
 	quotaInfoSummary.Max = qi.CalculateInfo.Max.DeepCopy()
 	quotaInfoSummary.Min = qi.CalculateInfo.Min.DeepCopy()
@@ -191,8 +205,8 @@ func (qi *QuotaInfo) GetQuotaSummary(treeID string, includePods bool) *QuotaInfo
 	return quotaInfoSummary
 }
 
-// updateQuotaInfoFromRemote the CRD(max/oriMin/sharedWeight/allowLentResource/isParent/ParentName) of the quota maybe changed,
-// so need update localQuotaInfo's information from inputQuotaInfo.
+// updateQuotaInfoFromRemote the CRD(max/oriMin/sharedWeight/allowLentResource/lendingPolicy/isParent/ParentName) of the quota
+// maybe changed, so need update localQuotaInfo's information from inputQuotaInfo.
 func (qi *QuotaInfo) updateQuotaInfoFromRemote(quotaInfo *QuotaInfo) {
 	qi.lock.Lock()
 	defer qi.lock.Unlock()
@@ -205,10 +219,49 @@ func (qi *QuotaInfo) updateQuotaInfoFromRemote(quotaInfo *QuotaInfo) {
 	}
 	qi.CalculateInfo.SharedWeight = sharedWeight
 	qi.AllowLentResource = quotaInfo.AllowLentResource
+	qi.setLendingPolicyNoLock(quotaInfo)
 	qi.IsParent = quotaInfo.IsParent
 	qi.ParentName = quotaInfo.ParentName
 }
 
+// setLendingPolicyNoLock copies the lending schedules, the max borrow duration and the revoke grace period.
+func (qi *QuotaInfo) setLendingPolicyNoLock(quotaInfo *QuotaInfo) {
+	qi.LendingSchedules = quotaInfo.LendingSchedules
+	qi.ScheduledLendRatio = quotaInfo.ScheduledLendRatio
+	qi.MaxBorrowDuration = quotaInfo.MaxBorrowDuration
+	qi.RevokeGracePeriod = quotaInfo.RevokeGracePeriod
+}
+
+func (qi *QuotaInfo) isLendingPolicyChangeNoLock(quotaInfo *QuotaInfo) bool {
+	return !reflect.DeepEqual(qi.LendingSchedules, quotaInfo.LendingSchedules) ||
+		qi.ScheduledLendRatio != quotaInfo.ScheduledLendRatio ||
+		qi.MaxBorrowDuration != quotaInfo.MaxBorrowDuration ||
+		!reflect.DeepEqual(qi.RevokeGracePeriod, quotaInfo.RevokeGracePeriod)
+}
+
+// isAllowLentResourceNoLock returns whether the quota group lends the unused min to other quota groups now.
+func (qi *QuotaInfo) isAllowLentResourceNoLock() bool {
+	return qi.AllowLentResource || qi.ScheduledLendRatio > 0
+}
+
+// getReservedMinNoLock returns the part of min which is not lent to other quota groups, the quota group requests
+// for it even if its pods don't. It returns nil if the whole min can be lent, e.g. the quota group allows lending
+// its resources, where the lending schedules cannot reserve any more of the min.
+func (qi *QuotaInfo) getReservedMinNoLock() v1.ResourceList {
+	if qi.AllowLentResource || qi.ScheduledLendRatio >= 1 {
+		return nil
+	}
+	if qi.ScheduledLendRatio <= 0 {
+		return qi.CalculateInfo.Min
+	}
+	reservedMin := v1.ResourceList{}
+	for resName, quantity := range qi.CalculateInfo.Min {
+		reserved := float64(getQuantityValue(quantity, resName)) * (1 - qi.ScheduledLendRatio)
+		reservedMin[resName] = createQuantity(int64(reserved), resName)
+	}
+	return reservedMin
+}
+
 // getLimitRequestNoLock returns the min value of request and max, as max is the quotaGroup's upper limit of resources.
 // As the multi-hierarchy quota Model described in the PR, when passing a request upwards, passing a request exceeding its
 // max will result in a wrong/invalid runtime distribution. For example, parentQuotaGroup's Max is 20, childGroup's Max
@@ -393,6 +446,51 @@ func (qi *QuotaInfo) GetMin() v1.ResourceList {
 	return qi.CalculateInfo.Min.DeepCopy()
 }
 
+func (qi *QuotaInfo) GetAutoScaleMin() v1.ResourceList {
+	qi.lock.RLock()
+	defer qi.lock.RUnlock()
+	return qi.CalculateInfo.AutoScaleMin.DeepCopy()
+}
+
+func (qi *QuotaInfo) GetMaxBorrowDuration() time.Duration {
+	qi.lock.RLock()
+	defer qi.lock.RUnlock()
+	return qi.MaxBorrowDuration
+}
+
+// GetRevokeGracePeriod returns the revoke grace period of the quota group, and false if it's not specified.
+func (qi *QuotaInfo) GetRevokeGracePeriod() (time.Duration, bool) {
+	qi.lock.RLock()
+	defer qi.lock.RUnlock()
+	if qi.RevokeGracePeriod == nil {
+		return 0, false
+	}
+	return *qi.RevokeGracePeriod, true
+}
+
+// GetDominantShare returns the max ratio of used to min among the resources of the quota, which is the dominant
+// resource share in the Dominant Resource Fairness. The max is used instead for the resources without min, and
+// the resources without both min and max are ignored.
+func (qi *QuotaInfo) GetDominantShare() float64 {
+	qi.lock.RLock()
+	defer qi.lock.RUnlock()
+
+	var share float64
+	for resName, used := range qi.CalculateInfo.Used {
+		total, ok := qi.CalculateInfo.AutoScaleMin[resName]
+		if !ok || total.IsZero() {
+			total, ok = qi.CalculateInfo.Max[resName]
+		}
+		if !ok || total.IsZero() {
+			continue
+		}
+		if ratio := used.AsApproximateFloat64() / total.AsApproximateFloat64(); ratio > share {
+			share = ratio
+		}
+	}
+	return share
+}
+
 func NewQuotaInfoFromQuota(quota *v1alpha1.ElasticQuota) *QuotaInfo {
 	isParent := extension.IsParentQuota(quota)
 	parentName := extension.GetParentQuotaName(quota)
@@ -408,6 +506,26 @@ func NewQuotaInfoFromQuota(quota *v1alpha1.ElasticQuota) *QuotaInfo {
 	newSharedWeight := extension.GetSharedWeight(quota)
 	quotaInfo.setSharedWeightNoLock(newSharedWeight)
 
+	// the guaranteed usage is not lent to other quota groups, so the lending schedules don't take effect.
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ElasticQuotaGuaranteeUsage) {
+		schedules, err := extension.GetLendingSchedules(quota)
+		if err != nil {
+			klog.Errorf("failed to get lending schedules of quota %v, err: %v", quota.Name, err)
+		}
+		quotaInfo.LendingSchedules = schedules
+		quotaInfo.ScheduledLendRatio = extension.GetActiveLendRatio(schedules, time.Now())
+	}
+	maxBorrowDuration, err := extension.GetMaxBorrowDuration(quota)
+	if err != nil {
+		klog.Errorf("failed to get max borrow duration of quota %v, err: %v", quota.Name, err)
+	}
+	quotaInfo.MaxBorrowDuration = maxBorrowDuration
+	if revokeGracePeriod, ok, err := extension.GetRevokeGracePeriod(quota); err != nil {
+		klog.Errorf("failed to get revoke grace period of quota %v, err: %v", quota.Name, err)
+	} else if ok {
+		quotaInfo.RevokeGracePeriod = &revokeGracePeriod
+	}
+
 	return quotaInfo
 }
 
@@ -464,6 +582,10 @@ func (qi *QuotaInfo) IsQuotaChange(quotaInfo *QuotaInfo) bool {
 	if !quotav1.Equals(qi.CalculateInfo.SharedWeight, quotaInfo.CalculateInfo.SharedWeight) {
 		return true
 	}
+
+	if qi.isLendingPolicyChangeNoLock(quotaInfo) {
+		return true
+	}
 	return false
 }
 
@@ -20,7 +20,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	schetesting "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
 )
 
 func TestQuotaInfo_AddPodIfNotPresent_RemovePodIfPresent_GetPodCache(t *testing.T) {
@@ -76,6 +79,99 @@ func TestQuotaInfo_DeepCopy(t *testing.T) {
 	assert.Equal(t, createResourceList(3, 4), copyObj.GetRuntime())
 }
 
+func TestQuotaInfo_GetReservedMinNoLock(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowLentResource bool
+		lendRatio         float64
+		want              corev1.ResourceList
+	}{
+		{
+			name: "not allow lent resource",
+			want: createResourceList(100, 1000),
+		},
+		{
+			name:              "allow lent resource",
+			allowLentResource: true,
+		},
+		{
+			name:              "allow lent resource with lending schedule",
+			allowLentResource: true,
+			lendRatio:         0.5,
+		},
+		{
+			name:      "lend part of min by schedule",
+			lendRatio: 0.5,
+			want:      createResourceList(50, 500),
+		},
+		{
+			name:      "lend the whole min by schedule",
+			lendRatio: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qi := NewQuotaInfo(false, tt.allowLentResource, "test", extension.RootQuotaName)
+			qi.CalculateInfo.Min = createResourceList(100, 1000)
+			qi.ScheduledLendRatio = tt.lendRatio
+			assert.Equal(t, tt.want, qi.getReservedMinNoLock())
+		})
+	}
+}
+
+func TestQuotaInfo_GetDominantShare(t *testing.T) {
+	tests := []struct {
+		name         string
+		max          corev1.ResourceList
+		autoScaleMin corev1.ResourceList
+		used         corev1.ResourceList
+		want         float64
+	}{
+		{
+			name:         "nothing used",
+			max:          createResourceList(100, 1000),
+			autoScaleMin: createResourceList(10, 100),
+			want:         0,
+		},
+		{
+			name:         "memory is dominant",
+			max:          createResourceList(100, 1000),
+			autoScaleMin: createResourceList(10, 100),
+			used:         createResourceList(5, 80),
+			want:         0.8,
+		},
+		{
+			name:         "used more than min",
+			max:          createResourceList(100, 1000),
+			autoScaleMin: createResourceList(10, 100),
+			used:         createResourceList(30, 80),
+			want:         3,
+		},
+		{
+			name:         "use max for resources without min",
+			max:          createResourceList(100, 1000),
+			autoScaleMin: createResourceList(10, 0),
+			used:         createResourceList(1, 500),
+			want:         0.5,
+		},
+		{
+			name: "ignore resources without min and max",
+			max:  createResourceList(100, 0),
+			used: createResourceList(10, 500),
+			want: 0.1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qi := NewQuotaInfo(false, true, "test", extension.RootQuotaName)
+			qi.CalculateInfo.Max = tt.max
+			qi.CalculateInfo.AutoScaleMin = tt.autoScaleMin
+			qi.CalculateInfo.Used = tt.used
+			assert.InDelta(t, tt.want, qi.GetDominantShare(), 1e-9)
+		})
+	}
+}
+
 func TestUpdateQuotaInfoFromRemote(t *testing.T) {
 	remoteQuotaInfo := &QuotaInfo{
 		Name:              "test",
@@ -31,7 +31,9 @@ type QuotaInfoSummary struct {
 	IsParent          bool   `json:"isParent"`
 	RuntimeVersion    int64  `json:"runtimeVersion"`
 	AllowLentResource bool   `json:"allowLentResource"`
-	Tree              string `json:"tree"`
+	// ScheduledLendRatio is the lend ratio of the active lending schedules
+	ScheduledLendRatio float64 `json:"scheduledLendRatio,omitempty"`
+	Tree               string  `json:"tree"`
 
 	Max                       v1.ResourceList `json:"max"`
 	Min                       v1.ResourceList `json:"min"`
@@ -92,6 +92,14 @@ func (qt *quotaTree) updateRequest(groupName string, request int64) {
 	}
 }
 
+func (qt *quotaTree) updateAllowLentResource(groupName string, allowLentResource bool) {
+	if nodeValue, exist := qt.quotaNodes[groupName]; exist {
+		if nodeValue.allowLentResource != allowLentResource {
+			qt.quotaNodes[groupName].allowLentResource = allowLentResource
+		}
+	}
+}
+
 func (qt *quotaTree) updateGuaranteed(groupName string, guarantee int64) {
 	if nodeValue, exist := qt.quotaNodes[groupName]; exist {
 		if nodeValue.guarantee != guarantee {
@@ -327,7 +335,7 @@ func (qtw *RuntimeQuotaCalculator) updateOneGroupMaxQuota(quotaInfo *QuotaInfo)
 			autoScaleMinQuotaPerKey := *quotaInfo.CalculateInfo.AutoScaleMin.Name(resKey, resource.DecimalSI)
 			guaranteePerKey := *quotaInfo.CalculateInfo.Guaranteed.Name(resKey, resource.DecimalSI)
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, getQuantityValue(sharedWeightPerKey, resKey), getQuantityValue(reqLimitPerKey, resKey),
-				getQuantityValue(autoScaleMinQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.AllowLentResource)
+				getQuantityValue(autoScaleMinQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.isAllowLentResourceNoLock())
 		}
 
 		// update reqLimitPerKey
@@ -358,7 +366,7 @@ func (qtw *RuntimeQuotaCalculator) updateOneGroupMinQuota(quotaInfo *QuotaInfo)
 			reqLimitPerKey := *reqLimit.Name(resKey, resource.DecimalSI)
 			guaranteePerKey := *quotaInfo.CalculateInfo.Guaranteed.Name(resKey, resource.DecimalSI)
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, getQuantityValue(sharedWeightPerKey, resKey), getQuantityValue(reqLimitPerKey, resKey),
-				getQuantityValue(newMinQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.AllowLentResource)
+				getQuantityValue(newMinQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.isAllowLentResourceNoLock())
 		}
 	}
 
@@ -386,7 +394,7 @@ func (qtw *RuntimeQuotaCalculator) updateOneGroupSharedWeight(quotaInfo *QuotaIn
 			minQuotaPerKey := *quotaInfo.CalculateInfo.AutoScaleMin.Name(resKey, resource.DecimalSI)
 			guaranteePerKey := *quotaInfo.CalculateInfo.Guaranteed.Name(resKey, resource.DecimalSI)
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, getQuantityValue(newSharedWeightPerKey, resKey), getQuantityValue(reqLimitPerKey, resKey),
-				getQuantityValue(minQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.AllowLentResource)
+				getQuantityValue(minQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.isAllowLentResourceNoLock())
 		}
 	}
 
@@ -397,6 +405,24 @@ func (qtw *RuntimeQuotaCalculator) updateOneGroupSharedWeight(quotaInfo *QuotaIn
 	}
 }
 
+// updateOneGroupAllowLentResource whether the unused min can be lent to other groups changes, then increase
+// globalRuntimeVersion
+func (qtw *RuntimeQuotaCalculator) updateOneGroupAllowLentResource(quotaInfo *QuotaInfo) {
+	qtw.lock.Lock()
+	defer qtw.lock.Unlock()
+
+	allowLentResource := quotaInfo.isAllowLentResourceNoLock()
+	for resKey := range qtw.resourceKeys {
+		qtw.quotaTree[resKey].updateAllowLentResource(quotaInfo.Name, allowLentResource)
+	}
+
+	qtw.globalRuntimeVersion++
+
+	if klog.V(5).Enabled() {
+		qtw.logQuotaInfoNoLock("UpdateOneGroupAllowLentResource finish", quotaInfo)
+	}
+}
+
 // needUpdateOneGroupRequest if oldReqLimit is the same as newReqLimit, no need to adjustQuota.
 // the request of one group may change frequently, but the cost of adjustQuota is high, so here
 // need to judge whether you need to update QuotaNode's request or not.
@@ -434,7 +460,7 @@ func (qtw *RuntimeQuotaCalculator) updateOneGroupRequest(quotaInfo *QuotaInfo) {
 			minQuotaPerKey := *quotaInfo.CalculateInfo.AutoScaleMin.Name(resKey, resource.DecimalSI)
 			guaranteePerKey := *quotaInfo.CalculateInfo.Guaranteed.Name(resKey, resource.DecimalSI)
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, getQuantityValue(sharedWeightPerKey, resKey), getQuantityValue(reqLimitPerKey, resKey),
-				getQuantityValue(minQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.AllowLentResource)
+				getQuantityValue(minQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.isAllowLentResourceNoLock())
 		}
 
 		// update reqLimitPerKey
@@ -483,7 +509,7 @@ func (qtw *RuntimeQuotaCalculator) updateOneGroupGuaranteed(quotaInfo *QuotaInfo
 			sharedWeightPerKey := *quotaInfo.CalculateInfo.SharedWeight.Name(resKey, resource.DecimalSI)
 			minQuotaPerKey := *quotaInfo.CalculateInfo.AutoScaleMin.Name(resKey, resource.DecimalSI)
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, getQuantityValue(sharedWeightPerKey, resKey), getQuantityValue(reqLimitPerKey, resKey),
-				getQuantityValue(minQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.AllowLentResource)
+				getQuantityValue(minQuotaPerKey, resKey), getQuantityValue(guaranteePerKey, resKey), quotaInfo.isAllowLentResourceNoLock())
 		}
 
 		// update guaranteePerKey
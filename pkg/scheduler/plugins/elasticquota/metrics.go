@@ -43,6 +43,15 @@ var (
 		[]string{"name", "resource", "tree", "is_parent", "parent", "field"},
 	)
 
+	ElasticQuotaResourceHoursMetric = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem: schedulermetrics.SchedulerSubsystem,
+			Name:      "elastic_quota_resource_hours_total",
+			Help:      "ElasticQuota resources integrated over time in resource-hours, cpu in core-hours",
+		},
+		[]string{"name", "resource", "tree", "is_parent", "parent", "field"},
+	)
+
 	UpdateElasticQuotaStatusLatency = metrics.NewHistogram(
 		&metrics.HistogramOpts{
 			Subsystem: schedulermetrics.SchedulerSubsystem,
@@ -57,6 +66,7 @@ func init() {
 	koordschedulermetrics.RegisterMetrics(
 		ElasticQuotaSpecMetric,
 		ElasticQuotaStatusMetric,
+		ElasticQuotaResourceHoursMetric,
 		UpdateElasticQuotaStatusLatency,
 	)
 }
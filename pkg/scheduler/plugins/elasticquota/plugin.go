@@ -24,6 +24,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	k8sfeature "k8s.io/apiserver/pkg/util/feature"
@@ -106,6 +107,21 @@ type Plugin struct {
 	// This snapshot is updated periodically in background and doesn't need to stay in sync with quotaToTreeMap
 	quotaToTreeMapSnapshotLock sync.RWMutex
 	quotaToTreeMapSnapshot     map[string]string
+
+	// fairShareWeights stores the fair-share weights of the quotas, the key is the quota name
+	// This snapshot is updated together with quotaSnapshot
+	fairShareWeightsLock sync.RWMutex
+	fairShareWeights     map[string]float64
+	// queueSortKeys stores the sort keys of the pending pods computed when they are enqueued, the key is the pod UID
+	queueSortKeysLock sync.Mutex
+	queueSortKeys     map[types.UID]queueSortKey
+
+	// expiredBorrowedPods stores the pods which have used the borrowed resources longer than the max borrow duration
+	// of their quotas, the key is the pod UID. It is updated by QuotaLendingController periodically.
+	expiredBorrowedPodsLock sync.RWMutex
+	expiredBorrowedPods     map[types.UID]struct{}
+
+	quotaUsageAccountingController *QuotaUsageAccountingController
 }
 
 var (
@@ -166,7 +182,11 @@ func New(ctx context.Context, args runtime.Object, handle fwktype.Handle) (fwkty
 		quotaToTreeMap:                 make(map[string]string),
 		quotaSnapshot:                  make(map[string]*core.QuotaSnapshot),
 		quotaToTreeMapSnapshot:         make(map[string]string),
+		fairShareWeights:               make(map[string]float64),
+		queueSortKeys:                  make(map[types.UID]queueSortKey),
+		expiredBorrowedPods:            make(map[types.UID]struct{}),
 	}
+	elasticQuota.quotaUsageAccountingController = NewQuotaUsageAccountingController(elasticQuota)
 	elasticQuota.groupQuotaManager = core.NewGroupQuotaManager("", pluginArgs.EnableMinQuotaScale, pluginArgs.SystemQuotaGroupMax,
 		pluginArgs.DefaultQuotaGroupMax)
 	err := elasticQuota.groupQuotaManager.InitHookPlugins(pluginArgs)
@@ -216,7 +236,7 @@ func (g *Plugin) Start() {
 	klog.Infof("start migrate pod from defaultQuotaGroup")
 
 	// Start background goroutine to periodically update quota parent snapshot
-	if g.pluginArgs.EnableQueueHint {
+	if g.pluginArgs.EnableQueueHint || g.pluginArgs.EnableFairShareQueueSort {
 		updateInterval := g.pluginArgs.QuotaSnapshotUpdateInterval.Duration
 		go wait.Until(g.updateQuotaSnapshot, updateInterval, nil)
 		klog.Infof("start background quota snapshot updater with interval %v", updateInterval)
@@ -226,7 +246,9 @@ func (g *Plugin) Start() {
 func (g *Plugin) NewControllers() ([]frameworkext.Controller, error) {
 	quotaOverUsedRevokeController := NewQuotaOverUsedRevokeController(g)
 	elasticQuotaController := NewElasticQuotaController(g)
-	return []frameworkext.Controller{g, quotaOverUsedRevokeController, elasticQuotaController}, nil
+	quotaLendingController := NewQuotaLendingController(g)
+	return []frameworkext.Controller{g, quotaOverUsedRevokeController, elasticQuotaController, quotaLendingController,
+		g.quotaUsageAccountingController}, nil
 }
 
 func (g *Plugin) Name() string {
@@ -611,4 +633,8 @@ func (g *Plugin) updateQuotaSnapshot() {
 	g.quotaSnapshotLock.Lock()
 	g.quotaSnapshot = newSnapshots
 	g.quotaSnapshotLock.Unlock()
+
+	if g.pluginArgs.EnableFairShareQueueSort {
+		g.updateFairShareWeights()
+	}
 }
@@ -43,4 +43,17 @@ func (g *Plugin) RegisterEndpoints(group *gin.RouterGroup) {
 		quotaSummaries := g.GetQuotaSummaries(tree, includePods)
 		c.JSON(http.StatusOK, quotaSummaries)
 	})
+	group.GET("/quotaUsage", func(c *gin.Context) {
+		accounts := g.quotaUsageAccountingController.GetQuotaUsageAccounts(c.Query("tree"))
+		if c.Query("format") == "csv" {
+			data, err := quotaUsageAccountsToCSV(accounts)
+			if err != nil {
+				services.ResponseErrorMessage(c, http.StatusInternalServerError, "failed to render quota usage, err: %v", err)
+				return
+			}
+			c.Data(http.StatusOK, "text/csv", data)
+			return
+		}
+		c.JSON(http.StatusOK, accounts)
+	})
 }
@@ -173,6 +173,7 @@ func (g *Plugin) SelectVictimsOnNode(
 
 	postFilterState, _ := getPostFilterState(state)
 	podReq := core.PodRequests(pod)
+	podQuotaName := g.getPodAssociateQuotaName(pod)
 
 	reprievePod := func(pi fwktype.PodInfo) (bool, error) {
 		if err := addPod(pi); err != nil {
@@ -189,6 +190,10 @@ func (g *Plugin) SelectVictimsOnNode(
 			klog.V(5).InfoS("Pod is a potential preemption victim on node", "pod", klog.KObj(rpi), "node", klog.KObj(nodeInfo.Node()))
 		}
 
+		// the victims of other quotas don't release the used of the quota of the preemptor.
+		if g.getPodAssociateQuotaName(pi.GetPod()) != podQuotaName {
+			return fits, nil
+		}
 		newUsed := quotav1.Mask(quotav1.Add(postFilterState.used, podReq), quotav1.ResourceNames(podReq))
 		if isLessEqual, _ := quotav1.LessThanOrEqual(newUsed, postFilterState.usedLimit); !isLessEqual {
 			if err := removePod(pi); err != nil {
@@ -306,5 +311,16 @@ func (g *Plugin) canPreempt(pod, victim *corev1.Pod) bool {
 		return false
 	}
 
-	return podPri > vicPri && podQuotaName == vicQuotaName
+	if podQuotaName == vicQuotaName {
+		return podPri > vicPri
+	}
+
+	// The pods which have used the borrowed resources longer than the max borrow duration of their quota can be
+	// preempted by the pods of other quotas in the same quota tree with no lower priority.
+	if podPri < vicPri || !g.isExpiredBorrowedPod(victim) {
+		return false
+	}
+	_, podTreeID := g.getPodAssociateQuotaNameAndTreeID(pod)
+	_, vicTreeID := g.getPodAssociateQuotaNameAndTreeID(victim)
+	return podTreeID == vicTreeID
 }
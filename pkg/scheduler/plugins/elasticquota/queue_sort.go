@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	fwktype "k8s.io/kube-scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+var _ fwktype.QueueSortPlugin = &Plugin{}
+
+// Less sorts the pending pods when ElasticQuota is enabled as the QueueSort plugin. The pods are sorted by priority
+// first, which is the same as PrioritySort. If EnableFairShareQueueSort, the pods with the same priority are sorted
+// by the fair share of their quotas, so that the pods of a quota using less resources than its siblings are scheduled
+// first, and a tenant submitting lots of pods cannot block the others. The fair share of a quota is its dominant
+// resource share divided by its fair-share weight, and a pod takes the max fair share of the quotas on its path to
+// the root, since a quota cannot be fairer than its ancestors. To avoid starvation, every FairShareAgingPeriod a pod
+// waits offsets its fair share by one. The pods with the same fair share are sorted by the time they are enqueued.
+//
+// A scheduling profile has exactly one QueueSort plugin, so the fair-share order takes effect only if ElasticQuota
+// replaces PrioritySort, and it cannot be combined with another QueueSort plugin like the Coscheduling of the
+// scheduler-plugins. The Coscheduling of koord-scheduler gates the gangs in PreEnqueue and does not need QueueSort.
+//
+// The queue keeps the pods in a heap, so the order must be a strict weak order which does not change while the
+// pods are queued. Thus the sort key of a pod is computed once from the quota snapshot when the pod is enqueued,
+// and kept until the pod is enqueued again, rather than changing with every snapshot refresh.
+func (g *Plugin) Less(podInfo1, podInfo2 fwktype.QueuedPodInfo) bool {
+	pod1, pod2 := podInfo1.GetPodInfo().GetPod(), podInfo2.GetPodInfo().GetPod()
+	priority1, priority2 := corev1helpers.PodPriority(pod1), corev1helpers.PodPriority(pod2)
+	if priority1 != priority2 {
+		return priority1 > priority2
+	}
+	if g.pluginArgs.EnableFairShareQueueSort {
+		key1, key2 := g.getQueueSortKey(podInfo1), g.getQueueSortKey(podInfo2)
+		if key1 != key2 {
+			return key1 < key2
+		}
+	}
+	return podInfo1.GetTimestamp().Before(podInfo2.GetTimestamp())
+}
+
+type queueSortKey struct {
+	// timestamp is the time the pod is enqueued when the key is computed
+	timestamp time.Time
+	key       float64
+}
+
+// getQueueSortKey returns the fair share of the pod offset by its age. Rather than the duration waited until now, the
+// time of the initial attempt divided by the aging period is added, so that the key is irrelevant to the current time.
+func (g *Plugin) getQueueSortKey(podInfo fwktype.QueuedPodInfo) float64 {
+	pod := podInfo.GetPodInfo().GetPod()
+	timestamp := podInfo.GetTimestamp()
+	g.queueSortKeysLock.Lock()
+	defer g.queueSortKeysLock.Unlock()
+	if cached, ok := g.queueSortKeys[pod.UID]; ok && cached.timestamp.Equal(timestamp) {
+		return cached.key
+	}
+	key := g.getFairShareOfPod(pod)
+	if agingPeriod := g.pluginArgs.FairShareAgingPeriod.Duration; agingPeriod > 0 {
+		key += float64(getInitialAttemptTimestamp(podInfo).UnixNano()) / float64(agingPeriod)
+	}
+	g.queueSortKeys[pod.UID] = queueSortKey{timestamp: timestamp, key: key}
+	return key
+}
+
+// forgetQueueSortKey removes the sort key of the pod which is not pending any more.
+func (g *Plugin) forgetQueueSortKey(pod *corev1.Pod) {
+	g.queueSortKeysLock.Lock()
+	delete(g.queueSortKeys, pod.UID)
+	g.queueSortKeysLock.Unlock()
+}
+
+// getFairShareOfPod returns the max fair share of the quotas on the path of the pod's quota to the root, which are
+// all taken from the same quota snapshot. It is zero if the pod doesn't belong to any quota.
+func (g *Plugin) getFairShareOfPod(pod *corev1.Pod) float64 {
+	quotaName, treeID := g.getPodAssociateQuotaNameAndTreeIDFromSnapshot(pod)
+	if quotaName == "" {
+		return 0
+	}
+	snapshot, exists := g.getQuotaSnapshot(treeID)
+	if !exists || snapshot == nil {
+		return 0
+	}
+	g.fairShareWeightsLock.RLock()
+	weights := g.fairShareWeights
+	g.fairShareWeightsLock.RUnlock()
+
+	var share float64
+	for _, name := range snapshot.GetQuotaPathToRoot(quotaName) {
+		if name == extension.RootQuotaName {
+			continue
+		}
+		weight, ok := weights[name]
+		if !ok {
+			weight = extension.DefaultFairShareWeight
+		}
+		if s := snapshot.GetDominantShare(name) / weight; s > share {
+			share = s
+		}
+	}
+	return share
+}
+
+// updateFairShareWeights updates the fair-share weights of the quotas, it runs together with updateQuotaSnapshot.
+// The weights map is replaced rather than modified, so that a sort key is computed with the same weights.
+func (g *Plugin) updateFairShareWeights() {
+	quotas, err := g.quotaLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list ElasticQuotas for fair-share weights")
+		return
+	}
+	weights := make(map[string]float64, len(quotas))
+	for _, quota := range quotas {
+		weight, err := extension.GetFairShareWeight(quota)
+		if err != nil {
+			klog.V(4).InfoS("Invalid fair-share weight of ElasticQuota, use the default", "quota", quota.Name, "err", err)
+		}
+		weights[quota.Name] = weight
+	}
+	g.fairShareWeightsLock.Lock()
+	g.fairShareWeights = weights
+	g.fairShareWeightsLock.Unlock()
+}
+
+func getInitialAttemptTimestamp(podInfo fwktype.QueuedPodInfo) time.Time {
+	if timestamp := podInfo.GetInitialAttemptTimestamp(); timestamp != nil {
+		return *timestamp
+	}
+	return podInfo.GetTimestamp()
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+)
+
+func TestPlugin_Less(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name            string
+		disable         bool
+		weights         map[string]string
+		pod1            *corev1.Pod
+		pod1WaitingTime time.Duration
+		pod2            *corev1.Pod
+		pod2WaitingTime time.Duration
+		want            bool
+	}{
+		{
+			name: "higher priority first",
+			pod1: makeQueueSortPod("pod1", 100, "team-a-1"),
+			pod2: makeQueueSortPod("pod2", 0, "team-b"),
+			want: true,
+		},
+		{
+			name: "compare the fair shares of the top level quotas",
+			pod1: makeQueueSortPod("pod1", 0, "team-a-2"),
+			pod2: makeQueueSortPod("pod2", 0, "team-b"),
+			want: false,
+		},
+		{
+			name: "compare the fair shares of the sibling quotas",
+			pod1: makeQueueSortPod("pod1", 0, "team-a-2"),
+			pod2: makeQueueSortPod("pod2", 0, "team-a-1"),
+			want: true,
+		},
+		{
+			name:            "the same quota is sorted by the enqueued time",
+			pod1:            makeQueueSortPod("pod1", 0, "team-a-1"),
+			pod1WaitingTime: time.Second,
+			pod2:            makeQueueSortPod("pod2", 0, "team-a-1"),
+			want:            true,
+		},
+		{
+			name:    "the quota with a larger weight is entitled to a larger share",
+			weights: map[string]string{"team-a": "10"},
+			pod1:    makeQueueSortPod("pod1", 0, "team-a-2"),
+			pod2:    makeQueueSortPod("pod2", 0, "team-b"),
+			want:    true,
+		},
+		{
+			name:            "the pod waiting longer is not starved",
+			pod1:            makeQueueSortPod("pod1", 0, "team-a-2"),
+			pod1WaitingTime: 20 * time.Minute,
+			pod2:            makeQueueSortPod("pod2", 0, "team-b"),
+			pod2WaitingTime: 10 * time.Minute,
+			want:            true,
+		},
+		{
+			name:            "sorted by priority and the enqueued time if disabled",
+			disable:         true,
+			pod1:            makeQueueSortPod("pod1", 0, "team-a-1"),
+			pod1WaitingTime: time.Second,
+			pod2:            makeQueueSortPod("pod2", 0, "team-b"),
+			want:            true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suit := newPluginTestSuit(t, nil, func(args *config.ElasticQuotaArgs) {
+				args.EnableMinQuotaScale = false
+				args.EnableFairShareQueueSort = !tt.disable
+				args.FairShareAgingPeriod.Duration = 10 * time.Minute
+			})
+			p := suit.createPlugin(t).(*Plugin)
+			// team-a Min[100, 100] used[60, 0]
+			//   |-- team-a-1 Min[50, 50] used[60, 0]
+			//   `-- team-a-2 Min[50, 50] used[0, 0]
+			// team-b Min[100, 100] used[10, 0]
+			quotas := []*v1alpha1.ElasticQuota{
+				p.addQuota("team-a", extension.RootQuotaName, 200, 200, 100, 100, 200, 200, true, "", ""),
+				p.addQuota("team-a-1", "team-a", 200, 200, 50, 50, 200, 200, false, "", ""),
+				p.addQuota("team-a-2", "team-a", 200, 200, 50, 50, 200, 200, false, "", ""),
+				p.addQuota("team-b", extension.RootQuotaName, 200, 200, 100, 100, 200, 200, false, "", ""),
+			}
+			for _, quota := range quotas {
+				if weight, ok := tt.weights[quota.Name]; ok {
+					quota.Annotations[extension.AnnotationFairShareWeight] = weight
+				}
+				assert.NoError(t, p.quotaInformer.GetIndexer().Add(quota))
+			}
+			runningPod := defaultCreatePod("running-a-1", 0, 60, 0)
+			runningPod.Labels[extension.LabelQuotaName] = "team-a-1"
+			p.OnPodAdd(runningPod)
+			runningPod = defaultCreatePod("running-b", 0, 10, 0)
+			runningPod.Labels[extension.LabelQuotaName] = "team-b"
+			p.OnPodAdd(runningPod)
+			p.quotaToTreeMapLock.Lock()
+			for _, name := range []string{"team-a", "team-a-1", "team-a-2", "team-b"} {
+				p.quotaToTreeMap[name] = ""
+			}
+			p.quotaToTreeMapLock.Unlock()
+			p.updateQuotaSnapshot()
+
+			podInfo1 := makeQueuedPodInfo(tt.pod1, now.Add(-tt.pod1WaitingTime))
+			podInfo2 := makeQueuedPodInfo(tt.pod2, now.Add(-tt.pod2WaitingTime))
+			assert.Equal(t, tt.want, p.Less(podInfo1, podInfo2))
+			assert.Equal(t, !tt.want, p.Less(podInfo2, podInfo1))
+		})
+	}
+}
+
+func TestPlugin_LessWithSnapshotRefresh(t *testing.T) {
+	suit := newPluginTestSuit(t, nil, func(args *config.ElasticQuotaArgs) {
+		args.EnableMinQuotaScale = false
+		args.EnableFairShareQueueSort = true
+		args.FairShareAgingPeriod.Duration = 10 * time.Minute
+	})
+	p := suit.createPlugin(t).(*Plugin)
+	quotas := []*v1alpha1.ElasticQuota{
+		p.addQuota("team-a", extension.RootQuotaName, 200, 200, 100, 100, 200, 200, false, "", ""),
+		p.addQuota("team-b", extension.RootQuotaName, 200, 200, 100, 100, 200, 200, false, "", ""),
+	}
+	for _, quota := range quotas {
+		assert.NoError(t, p.quotaInformer.GetIndexer().Add(quota))
+	}
+	p.quotaToTreeMapLock.Lock()
+	p.quotaToTreeMap["team-a"] = ""
+	p.quotaToTreeMap["team-b"] = ""
+	p.quotaToTreeMapLock.Unlock()
+	runningPod := defaultCreatePod("running-a", 0, 60, 0)
+	runningPod.Labels[extension.LabelQuotaName] = "team-a"
+	p.OnPodAdd(runningPod)
+	p.updateQuotaSnapshot()
+
+	now := time.Now()
+	podInfoA := makeQueuedPodInfo(makeQueueSortPod("pod-a", 0, "team-a"), now)
+	podInfoB := makeQueuedPodInfo(makeQueueSortPod("pod-b", 0, "team-b"), now)
+	assert.True(t, p.Less(podInfoB, podInfoA))
+
+	// the keys of the queued pods are kept after the snapshot is refreshed
+	runningPod = defaultCreatePod("running-b", 0, 90, 0)
+	runningPod.Labels[extension.LabelQuotaName] = "team-b"
+	p.OnPodAdd(runningPod)
+	p.updateQuotaSnapshot()
+	assert.True(t, p.Less(podInfoB, podInfoA))
+	assert.False(t, p.Less(podInfoA, podInfoB))
+
+	// the key is computed again when the pod is enqueued again
+	podInfoB = makeQueuedPodInfo(podInfoB.Pod, now.Add(time.Second))
+	podInfoB.InitialAttemptTimestamp = &now
+	assert.True(t, p.Less(podInfoA, podInfoB))
+
+	p.OnPodDelete(podInfoB.Pod)
+	p.queueSortKeysLock.Lock()
+	assert.NotContains(t, p.queueSortKeys, podInfoB.Pod.UID)
+	p.queueSortKeysLock.Unlock()
+}
+
+func makeQueueSortPod(name string, priority int32, quotaName string) *corev1.Pod {
+	pod := defaultCreatePod(name, priority, 1, 1)
+	pod.UID = types.UID(name)
+	pod.Spec.NodeName = ""
+	pod.Status.Phase = corev1.PodPending
+	pod.Labels[extension.LabelQuotaName] = quotaName
+	return pod
+}
+
+func makeQueuedPodInfo(pod *corev1.Pod, timestamp time.Time) *framework.QueuedPodInfo {
+	return &framework.QueuedPodInfo{
+		PodInfo:                 &framework.PodInfo{Pod: pod},
+		Timestamp:               timestamp,
+		InitialAttemptTimestamp: &timestamp,
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+	k8sutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+const (
+	QuotaLendingControllerName = "QuotaLendingController"
+	QuotaLendingSyncCycle      = 30 * time.Second
+)
+
+// QuotaLendingController applies the lending schedules of the quota groups, and finds the pods which have used the
+// resources borrowed from other quota groups longer than the max borrow duration of their quota groups. These pods
+// can be preempted by the pods of other quota groups in the same quota tree.
+// The borrowing time of the pods is only kept in memory, so it restarts from zero when the scheduler restarts or the
+// leader changes, which delays the preemption of the borrowed pods by up to the max borrow duration.
+type QuotaLendingController struct {
+	plugin *Plugin
+	lock   sync.Mutex
+	// borrowingSince is when the pod started to use the borrowed resources continuously, the key is the pod uid
+	borrowingSince map[types.UID]time.Time
+}
+
+func NewQuotaLendingController(plugin *Plugin) *QuotaLendingController {
+	return &QuotaLendingController{
+		plugin:         plugin,
+		borrowingSince: make(map[types.UID]time.Time),
+	}
+}
+
+func (controller *QuotaLendingController) Name() string {
+	return QuotaLendingControllerName
+}
+
+func (controller *QuotaLendingController) Start() {
+	go wait.Until(controller.sync, QuotaLendingSyncCycle, nil)
+	klog.Infof("start elasticQuota QuotaLendingController")
+}
+
+func (controller *QuotaLendingController) sync() {
+	controller.syncAt(time.Now())
+}
+
+func (controller *QuotaLendingController) syncAt(now time.Time) {
+	controller.lock.Lock()
+	defer controller.lock.Unlock()
+
+	managers := []*core.GroupQuotaManager{controller.plugin.groupQuotaManager}
+	managers = append(managers, controller.plugin.ListGroupQuotaManagersForQuotaTree()...)
+
+	borrowedPods := make(map[types.UID]struct{})
+	expiredBorrowedPods := make(map[types.UID]struct{})
+	for _, mgr := range managers {
+		mgr.RefreshLendingSchedules(now)
+		for quotaName := range mgr.GetAllQuotaNames() {
+			if quotaName == extension.SystemQuotaName || quotaName == extension.RootQuotaName {
+				continue
+			}
+			quotaInfo := mgr.GetQuotaInfoByName(quotaName)
+			if quotaInfo == nil || quotaInfo.IsParent {
+				continue
+			}
+			maxBorrowDuration := quotaInfo.GetMaxBorrowDuration()
+			if maxBorrowDuration <= 0 {
+				continue
+			}
+			min := quotaInfo.GetAutoScaleMin()
+			if isLessEqual, _ := quotav1.LessThanOrEqual(quotaInfo.GetUsed(), min); isLessEqual {
+				continue
+			}
+
+			for _, pod := range getBorrowedPods(quotaInfo, min) {
+				borrowedPods[pod.UID] = struct{}{}
+				since, ok := controller.borrowingSince[pod.UID]
+				if !ok {
+					controller.borrowingSince[pod.UID] = now
+					continue
+				}
+				if now.Sub(since) <= maxBorrowDuration {
+					continue
+				}
+				klog.V(5).Infof("pod %v of quota %v has used the borrowed resources longer than %v, borrowing since %v",
+					klog.KObj(pod), quotaName, maxBorrowDuration, since)
+				expiredBorrowedPods[pod.UID] = struct{}{}
+			}
+		}
+	}
+
+	for uid := range controller.borrowingSince {
+		if _, ok := borrowedPods[uid]; !ok {
+			delete(controller.borrowingSince, uid)
+		}
+	}
+	controller.plugin.setExpiredBorrowedPods(expiredBorrowedPods)
+}
+
+// getBorrowedPods returns the preemptible pods using the resources beyond the min of the quota group. The min is
+// assigned to the non-preemptible pods and then the more important pods first.
+func getBorrowedPods(quotaInfo *core.QuotaInfo, min v1.ResourceList) []*v1.Pod {
+	pods := quotaInfo.GetPodThatIsAssigned()
+	sort.Slice(pods, func(i, j int) bool {
+		iNonPreemptible, jNonPreemptible := extension.IsPodNonPreemptible(pods[i]), extension.IsPodNonPreemptible(pods[j])
+		if iNonPreemptible != jNonPreemptible {
+			return iNonPreemptible
+		}
+		return k8sutil.MoreImportantPod(pods[i], pods[j])
+	})
+
+	var borrowedPods []*v1.Pod
+	used := v1.ResourceList{}
+	for _, pod := range pods {
+		used = quotav1.Add(used, core.PodRequests(pod))
+		if isLessEqual, _ := quotav1.LessThanOrEqual(used, min); isLessEqual {
+			continue
+		}
+		if !extension.IsPodNonPreemptible(pod) {
+			borrowedPods = append(borrowedPods, pod)
+		}
+	}
+	return borrowedPods
+}
+
+func (g *Plugin) setExpiredBorrowedPods(pods map[types.UID]struct{}) {
+	g.expiredBorrowedPodsLock.Lock()
+	defer g.expiredBorrowedPodsLock.Unlock()
+	g.expiredBorrowedPods = pods
+}
+
+// isExpiredBorrowedPod returns whether the pod has used the borrowed resources longer than the max borrow duration
+// of its quota group.
+func (g *Plugin) isExpiredBorrowedPod(pod *v1.Pod) bool {
+	g.expiredBorrowedPodsLock.RLock()
+	defer g.expiredBorrowedPodsLock.RUnlock()
+	_, ok := g.expiredBorrowedPods[pod.UID]
+	return ok
+}
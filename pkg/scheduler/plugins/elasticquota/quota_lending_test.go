@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestQuotaLendingController(t *testing.T) {
+	suit := newPluginTestSuit(t, nil)
+	plugin := suit.createPlugin(t).(*Plugin)
+	gqm := plugin.groupQuotaManager
+	gqm.UpdateClusterTotalResource(createResourceList(1000, 1000))
+
+	quota := CreateQuota2("test1", extension.RootQuotaName, 100, 100, 30, 30, 100, 100, false, "")
+	quota.Annotations[extension.AnnotationMaxBorrowDuration] = "1h"
+	plugin.OnQuotaAdd(quota)
+	plugin.OnQuotaAdd(CreateQuota2("test2", extension.RootQuotaName, 100, 100, 30, 30, 100, 100, false, ""))
+
+	pod1 := defaultCreatePodWithQuotaAndNonPreemptible("1", "test1", 10, 20, 0, false)
+	pod2 := defaultCreatePodWithQuotaAndNonPreemptible("2", "test1", 9, 10, 0, false)
+	pod3 := defaultCreatePodWithQuotaAndNonPreemptible("3", "test1", 8, 20, 0, false)
+	pod4 := defaultCreatePodWithQuotaAndNonPreemptible("4", "test1", 7, 10, 0, true)
+	for _, pod := range []*corev1.Pod{pod1, pod2, pod3, pod4} {
+		gqm.OnPodAdd("test1", pod)
+	}
+
+	controller := NewQuotaLendingController(plugin)
+	now := time.Now()
+	controller.syncAt(now)
+	// the non-preemptible pod4 and the more important pod1 are within the min
+	assert.Equal(t, map[types.UID]time.Time{pod2.UID: now, pod3.UID: now}, controller.borrowingSince)
+	assert.False(t, plugin.isExpiredBorrowedPod(pod3))
+
+	controller.syncAt(now.Add(30 * time.Minute))
+	assert.False(t, plugin.isExpiredBorrowedPod(pod3))
+
+	controller.syncAt(now.Add(2 * time.Hour))
+	assert.Equal(t, now, controller.borrowingSince[pod3.UID])
+	assert.False(t, plugin.isExpiredBorrowedPod(pod1))
+	assert.True(t, plugin.isExpiredBorrowedPod(pod2))
+	assert.True(t, plugin.isExpiredBorrowedPod(pod3))
+	assert.False(t, plugin.isExpiredBorrowedPod(pod4))
+
+	preemptor := defaultCreatePodWithQuotaAndNonPreemptible("preemptor", "test2", 8, 10, 0, false)
+	assert.True(t, plugin.canPreempt(preemptor, pod3))
+	assert.False(t, plugin.canPreempt(preemptor, pod2))
+	assert.False(t, plugin.canPreempt(preemptor, pod1))
+	lowPriorityPreemptor := defaultCreatePodWithQuotaAndNonPreemptible("low-priority-preemptor", "test2", 7, 10, 0, false)
+	assert.False(t, plugin.canPreempt(lowPriorityPreemptor, pod3))
+
+	// the borrowing time is tracked per pod, so the pod starting to borrow later is not expired with the quota
+	pod5 := defaultCreatePodWithQuotaAndNonPreemptible("5", "test1", 6, 10, 0, false)
+	gqm.OnPodAdd("test1", pod5)
+	controller.syncAt(now.Add(150 * time.Minute))
+	assert.Equal(t, now.Add(150*time.Minute), controller.borrowingSince[pod5.UID])
+	assert.True(t, plugin.isExpiredBorrowedPod(pod3))
+	assert.False(t, plugin.isExpiredBorrowedPod(pod5))
+	gqm.OnPodDelete("test1", pod5)
+
+	// test1 uses no more than its min
+	gqm.OnPodDelete("test1", pod2)
+	gqm.OnPodDelete("test1", pod3)
+	controller.syncAt(now.Add(3 * time.Hour))
+	assert.Empty(t, controller.borrowingSince)
+	assert.False(t, plugin.isExpiredBorrowedPod(pod1))
+}
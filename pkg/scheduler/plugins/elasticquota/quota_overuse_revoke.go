@@ -80,9 +80,15 @@ func (monitor *QuotaOverUsedGroupMonitor) monitor() bool {
 		monitor.lastUnderUsedTime = time.Now()
 	}
 
-	if overUseContinueDuration > monitor.overUsedTriggerEvictDuration {
+	// the revoke grace period of the quota overrides the default
+	overUsedTriggerEvictDuration := monitor.overUsedTriggerEvictDuration
+	if revokeGracePeriod, ok := quotaInfo.GetRevokeGracePeriod(); ok {
+		overUsedTriggerEvictDuration = revokeGracePeriod
+	}
+
+	if overUseContinueDuration > overUsedTriggerEvictDuration {
 		klog.V(5).Infof("Quota used continue large than runtime, prepare trigger evict, quotaName: %v, overUseContinueDuration: %v, config: %v",
-			monitor.quotaName, overUseContinueDuration, monitor.overUsedTriggerEvictDuration)
+			monitor.quotaName, overUseContinueDuration, overUsedTriggerEvictDuration)
 		monitor.lastUnderUsedTime = time.Now()
 		return true
 	}
@@ -92,6 +92,28 @@ func TestQuotaOverUsedGroupMonitor_Monitor(t *testing.T) {
 	}
 }
 
+func TestQuotaOverUsedGroupMonitor_RevokeGracePeriod(t *testing.T) {
+	suit := newPluginTestSuit(t, nil)
+	plugin := suit.createPlugin(t).(*Plugin)
+	gqm := plugin.groupQuotaManager
+	gqm.UpdateClusterTotalResource(createResourceList(1000, 1000))
+	quota := CreateQuota2("test1", extension.RootQuotaName, 100, 100, 10, 10, 100, 100, false, "")
+	quota.Annotations[extension.AnnotationRevokeGracePeriod] = "1h"
+	plugin.OnQuotaAdd(quota)
+	gqm.OnPodAdd("test1", makePod2("pod", createResourceList(50, 0)))
+	qi := gqm.GetQuotaInfoByName("test1")
+	qi.Lock()
+	qi.CalculateInfo.Runtime = createResourceList(10, 0)
+	qi.UnLock()
+
+	// the default is overridden by the revoke grace period of the quota
+	monitor := NewQuotaOverUsedGroupMonitor("test1", gqm, 0)
+	monitor.lastUnderUsedTime = time.Now().Add(-30 * time.Minute)
+	assert.False(t, monitor.monitor())
+	monitor.lastUnderUsedTime = time.Now().Add(-2 * time.Hour)
+	assert.True(t, monitor.monitor())
+}
+
 func TestQuotaOverUsedRevokeController_GetToRevokePodList(t *testing.T) {
 	suit := newPluginTestSuit(t, nil)
 	plugin := suit.createPlugin(t).(*Plugin)
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+const (
+	QuotaUsageAccountingControllerName = "QuotaUsageAccountingController"
+	QuotaUsageAccountingSyncCycle      = time.Minute
+)
+
+const (
+	QuotaUsageFieldUsed     = "used"
+	QuotaUsageFieldRequest  = "request"
+	QuotaUsageFieldRuntime  = "runtime"
+	QuotaUsageFieldBorrowed = "borrowed"
+)
+
+// QuotaUsageAccount is the resources of a quota group integrated over time in resource-hours. The cpu is accounted
+// in core-hours and the other resources in their base units multiplied by hours.
+type QuotaUsageAccount struct {
+	Name       string `json:"name"`
+	ParentName string `json:"parentName"`
+	IsParent   bool   `json:"isParent"`
+	Tree       string `json:"tree"`
+	// Since is when the quota group was sampled for the first time.
+	Since time.Time `json:"since"`
+	// LastSampleTime is when the quota group was sampled for the last time.
+	LastSampleTime time.Time `json:"lastSampleTime"`
+	// ResourceHours is the resource-hours keyed by the field, i.e. used, request, runtime and borrowed.
+	// The borrowed is the used beyond the min of the quota group.
+	ResourceHours map[string]map[v1.ResourceName]float64 `json:"resourceHours"`
+
+	lastSample map[string]v1.ResourceList
+}
+
+// QuotaUsageAccountingController samples the used, request, runtime and borrowed resources of the quota groups
+// periodically, and integrates them into resource-hours for chargeback. Each sample is accounted until the next
+// sample. The accounts are kept in memory and exported as the counters, so they start over after restarting.
+type QuotaUsageAccountingController struct {
+	plugin *Plugin
+	lock   sync.RWMutex
+	// accounts stores the accounts of the quota groups, the key is the tree id and the quota name
+	accounts map[string]*QuotaUsageAccount
+}
+
+func NewQuotaUsageAccountingController(plugin *Plugin) *QuotaUsageAccountingController {
+	return &QuotaUsageAccountingController{
+		plugin:   plugin,
+		accounts: make(map[string]*QuotaUsageAccount),
+	}
+}
+
+func (controller *QuotaUsageAccountingController) Name() string {
+	return QuotaUsageAccountingControllerName
+}
+
+func (controller *QuotaUsageAccountingController) Start() {
+	go wait.Until(controller.sync, QuotaUsageAccountingSyncCycle, nil)
+	klog.Infof("start elasticQuota QuotaUsageAccountingController")
+}
+
+func (controller *QuotaUsageAccountingController) sync() {
+	controller.syncAt(time.Now())
+}
+
+func (controller *QuotaUsageAccountingController) syncAt(now time.Time) {
+	managers := []*core.GroupQuotaManager{controller.plugin.groupQuotaManager}
+	managers = append(managers, controller.plugin.ListGroupQuotaManagersForQuotaTree()...)
+
+	controller.lock.Lock()
+	defer controller.lock.Unlock()
+	for _, mgr := range managers {
+		for _, summary := range mgr.GetQuotaSummaries(false) {
+			controller.accountNoLock(summary, now)
+		}
+	}
+}
+
+func (controller *QuotaUsageAccountingController) accountNoLock(summary *core.QuotaInfoSummary, now time.Time) {
+	key := summary.Tree + "/" + summary.Name
+	account := controller.accounts[key]
+	if account == nil {
+		account = &QuotaUsageAccount{
+			Tree:          summary.Tree,
+			Since:         now,
+			ResourceHours: make(map[string]map[v1.ResourceName]float64),
+		}
+		controller.accounts[key] = account
+	}
+	account.Name = summary.Name
+	account.ParentName = summary.ParentName
+	account.IsParent = summary.IsParent
+
+	if hours := now.Sub(account.LastSampleTime).Hours(); account.lastSample != nil && hours > 0 {
+		labels := map[string]string{
+			"name":      account.Name,
+			"tree":      account.Tree,
+			"is_parent": strconv.FormatBool(account.IsParent),
+			"parent":    account.ParentName,
+		}
+		for field, resources := range account.lastSample {
+			resourceHours := account.ResourceHours[field]
+			if resourceHours == nil {
+				resourceHours = make(map[v1.ResourceName]float64)
+				account.ResourceHours[field] = resourceHours
+			}
+			for resourceName, quantity := range resources {
+				value := quantityValue(resourceName, quantity) * hours
+				resourceHours[resourceName] += value
+				labels["resource"] = string(resourceName)
+				labels["field"] = field
+				ElasticQuotaResourceHoursMetric.With(labels).Add(value)
+			}
+		}
+	}
+
+	used := summary.Used.DeepCopy()
+	account.lastSample = map[string]v1.ResourceList{
+		QuotaUsageFieldUsed:     used,
+		QuotaUsageFieldRequest:  summary.Request.DeepCopy(),
+		QuotaUsageFieldRuntime:  summary.Runtime.DeepCopy(),
+		QuotaUsageFieldBorrowed: quotav1.Mask(quotav1.SubtractWithNonNegativeResult(used, summary.AutoScaleMin), quotav1.ResourceNames(used)),
+	}
+	account.LastSampleTime = now
+}
+
+// GetQuotaUsageAccounts returns the accounts of the quota groups sorted by the tree and the name. All the trees are
+// returned if the tree is empty.
+func (controller *QuotaUsageAccountingController) GetQuotaUsageAccounts(tree string) []*QuotaUsageAccount {
+	controller.lock.RLock()
+	defer controller.lock.RUnlock()
+
+	accounts := make([]*QuotaUsageAccount, 0, len(controller.accounts))
+	for _, account := range controller.accounts {
+		if tree != "" && account.Tree != tree {
+			continue
+		}
+		accountCopy := *account
+		accountCopy.lastSample = nil
+		accountCopy.ResourceHours = make(map[string]map[v1.ResourceName]float64, len(account.ResourceHours))
+		for field, resourceHours := range account.ResourceHours {
+			resourceHoursCopy := make(map[v1.ResourceName]float64, len(resourceHours))
+			for resourceName, value := range resourceHours {
+				resourceHoursCopy[resourceName] = value
+			}
+			accountCopy.ResourceHours[field] = resourceHoursCopy
+		}
+		accounts = append(accounts, &accountCopy)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Tree != accounts[j].Tree {
+			return accounts[i].Tree < accounts[j].Tree
+		}
+		return accounts[i].Name < accounts[j].Name
+	})
+	return accounts
+}
+
+// quotaUsageAccountsToCSV renders the accounts as CSV, one row for each field and resource of the quota groups.
+func quotaUsageAccountsToCSV(accounts []*QuotaUsageAccount) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write([]string{"tree", "name", "parent", "isParent", "since", "lastSampleTime", "field", "resource", "resourceHours"}); err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		fields := make([]string, 0, len(account.ResourceHours))
+		for field := range account.ResourceHours {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			resourceNames := make([]string, 0, len(account.ResourceHours[field]))
+			for resourceName := range account.ResourceHours[field] {
+				resourceNames = append(resourceNames, string(resourceName))
+			}
+			sort.Strings(resourceNames)
+			for _, resourceName := range resourceNames {
+				record := []string{
+					account.Tree,
+					account.Name,
+					account.ParentName,
+					strconv.FormatBool(account.IsParent),
+					account.Since.UTC().Format(time.RFC3339),
+					account.LastSampleTime.UTC().Format(time.RFC3339),
+					field,
+					resourceName,
+					strconv.FormatFloat(account.ResourceHours[field][v1.ResourceName(resourceName)], 'f', -1, 64),
+				}
+				if err := w.Write(record); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func quantityValue(resourceName v1.ResourceName, quantity resource.Quantity) float64 {
+	if resourceName == v1.ResourceCPU {
+		return float64(quantity.MilliValue()) / 1000
+	}
+	return float64(quantity.Value())
+}
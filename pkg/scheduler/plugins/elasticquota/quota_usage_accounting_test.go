@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestQuotaUsageAccountingController(t *testing.T) {
+	suit := newPluginTestSuit(t, nil)
+	plugin := suit.createPlugin(t).(*Plugin)
+	gqm := plugin.groupQuotaManager
+	gqm.UpdateClusterTotalResource(createResourceList(1000, 1000))
+
+	plugin.OnQuotaAdd(CreateQuota2("test1", extension.RootQuotaName, 100, 100, 30, 30, 100, 100, false, ""))
+	plugin.OnQuotaAdd(CreateQuota2("test2", extension.RootQuotaName, 100, 100, 30, 30, 100, 100, false, ""))
+	gqm.OnPodAdd("test1", defaultCreatePodWithQuotaAndNonPreemptible("1", "test1", 10, 40, 40, false))
+	pod2 := defaultCreatePodWithQuotaAndNonPreemptible("2", "test2", 10, 20, 20, false)
+	gqm.OnPodAdd("test2", pod2)
+
+	controller := plugin.quotaUsageAccountingController
+	now := time.Now()
+	controller.syncAt(now)
+	controller.syncAt(now.Add(2 * time.Hour))
+	// the second sample is accounted until the next sample
+	gqm.OnPodDelete("test2", pod2)
+	controller.syncAt(now.Add(3 * time.Hour))
+
+	accounts := map[string]*QuotaUsageAccount{}
+	for _, account := range controller.GetQuotaUsageAccounts("") {
+		accounts[account.Name] = account
+	}
+	assert.NotContains(t, accounts, extension.RootQuotaName)
+	test1 := accounts["test1"]
+	assert.NotNil(t, test1)
+	assert.Equal(t, extension.RootQuotaName, test1.ParentName)
+	assert.Equal(t, now, test1.Since)
+	assert.Equal(t, now.Add(3*time.Hour), test1.LastSampleTime)
+	assert.InDelta(t, 120, test1.ResourceHours[QuotaUsageFieldUsed][corev1.ResourceCPU], 1e-6)
+	assert.InDelta(t, 120, test1.ResourceHours[QuotaUsageFieldUsed][corev1.ResourceMemory], 1e-6)
+	assert.InDelta(t, 120, test1.ResourceHours[QuotaUsageFieldRequest][corev1.ResourceCPU], 1e-6)
+	assert.InDelta(t, 30, test1.ResourceHours[QuotaUsageFieldBorrowed][corev1.ResourceCPU], 1e-6)
+	test2 := accounts["test2"]
+	assert.NotNil(t, test2)
+	assert.InDelta(t, 60, test2.ResourceHours[QuotaUsageFieldUsed][corev1.ResourceCPU], 1e-6)
+	assert.InDelta(t, 0, test2.ResourceHours[QuotaUsageFieldBorrowed][corev1.ResourceCPU], 1e-6)
+
+	engine := gin.Default()
+	plugin.RegisterEndpoints(engine.Group("/"))
+	{
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/quotaUsage", nil)
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		var got []*QuotaUsageAccount
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, len(accounts), len(got))
+	}
+	{
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/quotaUsage?format=csv", nil)
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, "text/csv", w.Result().Header.Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Equal(t, "tree,name,parent,isParent,since,lastSampleTime,field,resource,resourceHours", lines[0])
+		since := now.UTC().Format(time.RFC3339)
+		lastSampleTime := now.Add(3 * time.Hour).UTC().Format(time.RFC3339)
+		assert.Contains(t, lines, ",test1,"+extension.RootQuotaName+",false,"+since+","+lastSampleTime+",borrowed,cpu,30")
+		assert.Contains(t, lines, ",test1,"+extension.RootQuotaName+",false,"+since+","+lastSampleTime+",used,memory,120")
+	}
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	resourceapi "k8s.io/component-helpers/resource"
+	"k8s.io/klog/v2"
+	fwktype "k8s.io/kube-scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	slolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+const (
+	historicalUsageEstimatorName = "historicalUsageEstimator"
+
+	// podWorkloadIndex indexes the pods by their top-level workload to find the sibling pods.
+	podWorkloadIndex = "loadaware.koordinator.sh/workload"
+
+	defaultHistoricalAggregationType       = extension.P90
+	defaultHistoricalMinSiblingPods  int64 = 3
+	defaultHistoricalSafetyMargin    int64 = 10
+)
+
+// historicalResources are the resources estimated from the usages of the sibling pods.
+var historicalResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// HistoricalUsageEstimator estimates the usage of a pod from the observed usages of the sibling pods of the same
+// workload, which are reported in NodeMetrics. It falls back to the DefaultEstimator if there are
+// not enough sibling pods having usages, e.g. the first pods of a new workload.
+type HistoricalUsageEstimator struct {
+	defaultEstimator     Estimator
+	podIndexer           cache.Indexer
+	nodeMetricLister     slolisters.NodeMetricLister
+	nodeMetricExpiration time.Duration
+	aggregationType      extension.AggregationType
+	aggregatedDuration   time.Duration
+	minSiblingPods       int
+	safetyMarginPercent  int64
+}
+
+func NewHistoricalUsageEstimator(args *config.LoadAwareSchedulingArgs, handle fwktype.Handle) (Estimator, error) {
+	extendedHandle, ok := handle.(frameworkext.ExtendedHandle)
+	if !ok {
+		return nil, fmt.Errorf("want handle to be of type frameworkext.ExtendedHandle, got %T", handle)
+	}
+	defaultEstimator, err := NewDefaultEstimator(args, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	// the indexer may be added already by another scheduling profile
+	if _, ok := podInformer.GetIndexer().GetIndexers()[podWorkloadIndex]; !ok {
+		if err := podInformer.AddIndexers(cache.Indexers{podWorkloadIndex: podWorkloadIndexFunc}); err != nil {
+			return nil, err
+		}
+	}
+
+	nodeMetricLister := extendedHandle.KoordinatorSharedInformerFactory().Slo().V1alpha1().NodeMetrics().Lister()
+	return newHistoricalUsageEstimator(args, defaultEstimator, podInformer.GetIndexer(), nodeMetricLister), nil
+}
+
+func newHistoricalUsageEstimator(args *config.LoadAwareSchedulingArgs, defaultEstimator Estimator,
+	podIndexer cache.Indexer, nodeMetricLister slolisters.NodeMetricLister) *HistoricalUsageEstimator {
+	e := &HistoricalUsageEstimator{
+		defaultEstimator:    defaultEstimator,
+		podIndexer:          podIndexer,
+		nodeMetricLister:    nodeMetricLister,
+		aggregationType:     defaultHistoricalAggregationType,
+		minSiblingPods:      int(defaultHistoricalMinSiblingPods),
+		safetyMarginPercent: defaultHistoricalSafetyMargin,
+	}
+	if args.NodeMetricExpirationSeconds != nil {
+		e.nodeMetricExpiration = time.Duration(*args.NodeMetricExpirationSeconds) * time.Second
+	}
+	if historical := args.HistoricalEstimation; historical != nil {
+		if historical.AggregationType != "" {
+			e.aggregationType = historical.AggregationType
+		}
+		e.aggregatedDuration = historical.AggregatedDuration.Duration
+		if historical.MinSiblingPods > 0 {
+			e.minSiblingPods = int(historical.MinSiblingPods)
+		}
+		if historical.SafetyMarginPercent != nil {
+			e.safetyMarginPercent = *historical.SafetyMarginPercent
+		}
+	}
+	return e
+}
+
+func podWorkloadIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+	key := getPodWorkloadKey(pod)
+	if key == "" {
+		return nil, nil
+	}
+	return []string{key}, nil
+}
+
+// getPodWorkloadKey returns the key of the top-level workload of the pod, so that the pods of the different
+// revisions are siblings and a rollout does not lose the observed usages. The pods of a ReplicaSet created by a
+// Deployment, which is named by the Deployment and the pod-template-hash, are keyed by the Deployment. The others
+// are keyed by the UID of their controller. It returns empty if the pod has no controller.
+func getPodWorkloadKey(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return ""
+	}
+	if owner.Kind == "ReplicaSet" && strings.HasPrefix(owner.APIVersion, appsv1.GroupName+"/") {
+		hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		if deploymentName := strings.TrimSuffix(owner.Name, "-"+hash); hash != "" && deploymentName != owner.Name {
+			return "Deployment/" + pod.Namespace + "/" + deploymentName
+		}
+	}
+	return string(owner.UID)
+}
+
+func (e *HistoricalUsageEstimator) Name() string {
+	return historicalUsageEstimatorName
+}
+
+func (e *HistoricalUsageEstimator) EstimatePod(pod *corev1.Pod) (map[corev1.ResourceName]int64, error) {
+	estimated, err := e.defaultEstimator.EstimatePod(pod)
+	if err != nil {
+		return nil, err
+	}
+	observed := e.observeSiblingPodsUsage(pod)
+	if len(observed) == 0 {
+		return estimated, nil
+	}
+
+	limits := resourceapi.PodLimits(pod, resourceapi.PodResourcesOptions{})
+	priorityClass := extension.GetPodPriorityClassWithDefault(pod)
+	for resourceName := range estimated {
+		used, ok := observed[resourceName]
+		if !ok {
+			continue
+		}
+		used = int64(math.Round(float64(used) * float64(100+e.safetyMarginPercent) / 100))
+		// the usage never exceeds the limit of the pod
+		realResourceName := extension.TranslateResourceNameByPriorityClass(priorityClass, resourceName)
+		if limitQuantity, ok := limits[realResourceName]; ok {
+			limit := limitQuantity.Value()
+			if resourceName == corev1.ResourceCPU {
+				limit = limitQuantity.MilliValue()
+			}
+			if limit > 0 && used > limit {
+				used = limit
+			}
+		}
+		estimated[resourceName] = used
+	}
+	return estimated, nil
+}
+
+// observeSiblingPodsUsage returns the maximum usages of the running sibling pods of the same workload, where
+// cpu is in milli-cores and memory is in bytes. It returns nil if there are not enough sibling pods having usages.
+func (e *HistoricalUsageEstimator) observeSiblingPodsUsage(pod *corev1.Pod) map[corev1.ResourceName]int64 {
+	workloadKey := getPodWorkloadKey(pod)
+	if workloadKey == "" {
+		return nil
+	}
+	objs, err := e.podIndexer.ByIndex(podWorkloadIndex, workloadKey)
+	if err != nil {
+		klog.V(4).InfoS("Failed to get sibling pods", "pod", klog.KObj(pod), "err", err)
+		return nil
+	}
+
+	podMetricsByNode := map[string]map[string]*slov1alpha1.PodMetricInfo{}
+	observed := map[corev1.ResourceName]int64{}
+	count := 0
+	for _, obj := range objs {
+		sibling, ok := obj.(*corev1.Pod)
+		if !ok || sibling.UID == pod.UID || sibling.Namespace != pod.Namespace ||
+			sibling.Spec.NodeName == "" || sibling.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		podMetrics, ok := podMetricsByNode[sibling.Spec.NodeName]
+		if !ok {
+			podMetrics = e.getNodePodMetrics(sibling.Spec.NodeName)
+			podMetricsByNode[sibling.Spec.NodeName] = podMetrics
+		}
+		podMetric := podMetrics[sibling.Namespace+"/"+sibling.Name]
+		if podMetric == nil {
+			continue
+		}
+		usage := e.getPodUsage(sibling, podMetric)
+		if len(usage) == 0 {
+			continue
+		}
+		count++
+		for _, resourceName := range historicalResources {
+			quantity, ok := usage[resourceName]
+			if !ok {
+				continue
+			}
+			value := quantity.Value()
+			if resourceName == corev1.ResourceCPU {
+				value = quantity.MilliValue()
+			}
+			if value > observed[resourceName] {
+				observed[resourceName] = value
+			}
+		}
+	}
+	if count < e.minSiblingPods {
+		return nil
+	}
+	return observed
+}
+
+// getNodePodMetrics returns the pod metrics of the node keyed by the namespaced name of the pods.
+// It returns nil if the NodeMetric is not found or expired.
+func (e *HistoricalUsageEstimator) getNodePodMetrics(nodeName string) map[string]*slov1alpha1.PodMetricInfo {
+	nodeMetric, err := e.nodeMetricLister.Get(nodeName)
+	if err != nil || nodeMetric.Status.UpdateTime == nil {
+		return nil
+	}
+	if e.nodeMetricExpiration > 0 && time.Since(nodeMetric.Status.UpdateTime.Time) >= e.nodeMetricExpiration {
+		return nil
+	}
+	podMetrics := make(map[string]*slov1alpha1.PodMetricInfo, len(nodeMetric.Status.PodsMetric))
+	for _, podMetric := range nodeMetric.Status.PodsMetric {
+		if podMetric == nil {
+			continue
+		}
+		podMetrics[podMetric.Namespace+"/"+podMetric.Name] = podMetric
+	}
+	return podMetrics
+}
+
+// getPodUsage sums the aggregated usages of the containers if the container usages are reported, otherwise the
+// current usage of the pod is returned. It returns nil if only a part of the containers of the pod are reported,
+// since the sum of them underestimates the usage of the pod.
+func (e *HistoricalUsageEstimator) getPodUsage(pod *corev1.Pod, podMetric *slov1alpha1.PodMetricInfo) corev1.ResourceList {
+	if len(podMetric.ContainersMetric) <= 0 {
+		return podMetric.PodUsage.ResourceList
+	}
+	reported := sets.New[string]()
+	for _, containerMetric := range podMetric.ContainersMetric {
+		if containerMetric != nil {
+			reported.Insert(containerMetric.Name)
+		}
+	}
+	for i := range pod.Spec.Containers {
+		if !reported.Has(pod.Spec.Containers[i].Name) {
+			klog.V(5).InfoS("Skip the pod whose container metrics are incomplete", "pod", klog.KObj(pod), "container", pod.Spec.Containers[i].Name)
+			return nil
+		}
+	}
+	podUsage := corev1.ResourceList{}
+	for _, containerMetric := range podMetric.ContainersMetric {
+		if containerMetric == nil {
+			continue
+		}
+		containerUsage := e.getContainerAggregatedUsage(containerMetric.AggregatedContainerUsages)
+		if containerUsage == nil {
+			return podMetric.PodUsage.ResourceList
+		}
+		for resourceName, quantity := range containerUsage {
+			q := podUsage[resourceName]
+			q.Add(quantity)
+			podUsage[resourceName] = q
+		}
+	}
+	return podUsage
+}
+
+// getContainerAggregatedUsage returns the usage of the configured aggregation type and duration.
+// If no specific duration is set, the usage of the maximum duration is used.
+func (e *HistoricalUsageEstimator) getContainerAggregatedUsage(aggregatedUsages []slov1alpha1.AggregatedUsage) corev1.ResourceList {
+	var usage corev1.ResourceList
+	var maxDuration time.Duration
+	for i := range aggregatedUsages {
+		aggregatedUsage := &aggregatedUsages[i]
+		resourceMap, ok := aggregatedUsage.Usage[e.aggregationType]
+		if !ok || len(resourceMap.ResourceList) <= 0 {
+			continue
+		}
+		d := aggregatedUsage.Duration.Duration
+		if e.aggregatedDuration > 0 {
+			if d == e.aggregatedDuration {
+				return resourceMap.ResourceList
+			}
+			continue
+		}
+		if usage == nil || d > maxDuration {
+			usage, maxDuration = resourceMap.ResourceList, d
+		}
+	}
+	return usage
+}
+
+func (e *HistoricalUsageEstimator) EstimateNode(node *corev1.Node) (corev1.ResourceList, error) {
+	return e.defaultEstimator.EstimateNode(node)
+}
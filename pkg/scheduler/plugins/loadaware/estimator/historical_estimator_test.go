@@ -0,0 +1,322 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	slolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	v1 "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/v1"
+)
+
+func newTestHistoricalPod(name, nodeName string, ownerUID types.UID, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "apps/v1",
+					Kind:       "ReplicaSet",
+					Name:       "test-rs",
+					UID:        ownerUID,
+					Controller: ptr.To(true),
+				},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("4"),
+							corev1.ResourceMemory: resource.MustParse("8Gi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("4"),
+							corev1.ResourceMemory: resource.MustParse("8Gi"),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func newTestHistoricalUsageEstimator(t *testing.T, historicalArgs *config.LoadAwareSchedulingHistoricalEstimationArgs,
+	pods []*corev1.Pod, nodeMetrics []*slov1alpha1.NodeMetric) *HistoricalUsageEstimator {
+	var v1args v1.LoadAwareSchedulingArgs
+	v1.SetDefaults_LoadAwareSchedulingArgs(&v1args)
+	var args config.LoadAwareSchedulingArgs
+	err := v1.Convert_v1_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1args, &args, nil)
+	assert.NoError(t, err)
+	args.HistoricalEstimation = historicalArgs
+	defaultEstimator, err := NewDefaultEstimator(&args, nil)
+	assert.NoError(t, err)
+
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{podWorkloadIndex: podWorkloadIndexFunc})
+	for _, pod := range pods {
+		assert.NoError(t, podIndexer.Add(pod))
+	}
+	nodeMetricIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, nodeMetric := range nodeMetrics {
+		assert.NoError(t, nodeMetricIndexer.Add(nodeMetric))
+	}
+
+	return newHistoricalUsageEstimator(&args, defaultEstimator, podIndexer, slolisters.NewNodeMetricLister(nodeMetricIndexer))
+}
+
+func newTestPodMetric(name string, cpu, memory string) *slov1alpha1.PodMetricInfo {
+	return &slov1alpha1.PodMetricInfo{
+		Namespace: "default",
+		Name:      name,
+		PodUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func TestHistoricalUsageEstimatorEstimatePod(t *testing.T) {
+	ownerUID := types.UID("test-rs-uid")
+	var siblings []*corev1.Pod
+	for i := 0; i < 3; i++ {
+		siblings = append(siblings, newTestHistoricalPod(fmt.Sprintf("sibling-%d", i), "test-node", ownerUID, corev1.PodRunning))
+	}
+	pendingSibling := newTestHistoricalPod("pending-sibling", "", ownerUID, corev1.PodPending)
+	otherPod := newTestHistoricalPod("other", "test-node", "other-rs-uid", corev1.PodRunning)
+	now := metav1.Now()
+	nodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Status: slov1alpha1.NodeMetricStatus{
+			UpdateTime: &now,
+			PodsMetric: []*slov1alpha1.PodMetricInfo{
+				newTestPodMetric("sibling-0", "1", "1Gi"),
+				newTestPodMetric("sibling-1", "2", "2Gi"),
+				newTestPodMetric("sibling-2", "1500m", "4Gi"),
+				newTestPodMetric("other", "4", "8Gi"),
+			},
+		},
+	}
+	expiredNodeMetric := nodeMetric.DeepCopy()
+	expiredNodeMetric.Status.UpdateTime = &metav1.Time{Time: now.Add(-time.Hour)}
+	aggregatedNodeMetric := nodeMetric.DeepCopy()
+	aggregatedNodeMetric.Status.PodsMetric[0].ContainersMetric = []*slov1alpha1.ContainerMetricInfo{
+		{
+			Name: "main",
+			AggregatedContainerUsages: []slov1alpha1.AggregatedUsage{
+				{
+					Duration: metav1.Duration{Duration: 5 * time.Minute},
+					Usage: map[extension.AggregationType]slov1alpha1.ResourceMap{
+						extension.P90: {ResourceList: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("2"),
+							corev1.ResourceMemory: resource.MustParse("5Gi"),
+						}},
+					},
+				},
+				{
+					Duration: metav1.Duration{Duration: 30 * time.Minute},
+					Usage: map[extension.AggregationType]slov1alpha1.ResourceMap{
+						extension.P90: {ResourceList: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("3"),
+							corev1.ResourceMemory: resource.MustParse("6Gi"),
+						}},
+					},
+				},
+			},
+		},
+	}
+	incompleteNodeMetric := aggregatedNodeMetric.DeepCopy()
+	incompleteNodeMetric.Status.PodsMetric[0].ContainersMetric[0].Name = "sidecar"
+
+	pod := newTestHistoricalPod("incoming", "", ownerUID, corev1.PodPending)
+	tests := []struct {
+		name           string
+		historicalArgs *config.LoadAwareSchedulingHistoricalEstimationArgs
+		pod            *corev1.Pod
+		nodeMetric     *slov1alpha1.NodeMetric
+		want           map[corev1.ResourceName]int64
+	}{
+		{
+			name:       "estimate from the maximum usage of siblings",
+			pod:        pod,
+			nodeMetric: nodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    2200,
+				corev1.ResourceMemory: 4724464026, // 4Gi * 110%
+			},
+		},
+		{
+			name: "estimate without safety margin",
+			historicalArgs: &config.LoadAwareSchedulingHistoricalEstimationArgs{
+				SafetyMarginPercent: ptr.To[int64](0),
+			},
+			pod:        pod,
+			nodeMetric: nodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    2000,
+				corev1.ResourceMemory: 4 * 1024 * 1024 * 1024,
+			},
+		},
+		{
+			name: "estimate from the aggregated usage of the maximum duration",
+			historicalArgs: &config.LoadAwareSchedulingHistoricalEstimationArgs{
+				SafetyMarginPercent: ptr.To[int64](0),
+			},
+			pod:        pod,
+			nodeMetric: aggregatedNodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    3000,
+				corev1.ResourceMemory: 6 * 1024 * 1024 * 1024,
+			},
+		},
+		{
+			name: "estimate from the aggregated usage of the specified duration",
+			historicalArgs: &config.LoadAwareSchedulingHistoricalEstimationArgs{
+				AggregatedDuration:  metav1.Duration{Duration: 5 * time.Minute},
+				SafetyMarginPercent: ptr.To[int64](0),
+			},
+			pod:        pod,
+			nodeMetric: aggregatedNodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    2000,
+				corev1.ResourceMemory: 5 * 1024 * 1024 * 1024,
+			},
+		},
+		{
+			name: "estimated usage is limited by the pod limits",
+			historicalArgs: &config.LoadAwareSchedulingHistoricalEstimationArgs{
+				SafetyMarginPercent: ptr.To[int64](300),
+			},
+			pod:        pod,
+			nodeMetric: nodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    4000,
+				corev1.ResourceMemory: 8 * 1024 * 1024 * 1024,
+			},
+		},
+		{
+			name: "fallback to default estimator if not enough siblings",
+			historicalArgs: &config.LoadAwareSchedulingHistoricalEstimationArgs{
+				MinSiblingPods: 4,
+			},
+			pod:        pod,
+			nodeMetric: nodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    3400,
+				corev1.ResourceMemory: 6012954214,
+			},
+		},
+		{
+			name:       "fallback to default estimator if container metrics of a sibling are incomplete",
+			pod:        pod,
+			nodeMetric: incompleteNodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    3400,
+				corev1.ResourceMemory: 6012954214,
+			},
+		},
+		{
+			name:       "fallback to default estimator if nodeMetric expired",
+			pod:        pod,
+			nodeMetric: expiredNodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    3400,
+				corev1.ResourceMemory: 6012954214,
+			},
+		},
+		{
+			name:       "fallback to default estimator if pod has no controller",
+			pod:        &corev1.Pod{Spec: pod.Spec},
+			nodeMetric: nodeMetric,
+			want: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:    3400,
+				corev1.ResourceMemory: 6012954214,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods := append([]*corev1.Pod{pendingSibling, otherPod}, siblings...)
+			e := newTestHistoricalUsageEstimator(t, tt.historicalArgs, pods, []*slov1alpha1.NodeMetric{tt.nodeMetric})
+			assert.Equal(t, historicalUsageEstimatorName, e.Name())
+			got, err := e.EstimatePod(tt.pod)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetPodWorkloadKey(t *testing.T) {
+	newDeploymentPod := func(name, rsName, rsUID, hash string) *corev1.Pod {
+		pod := newTestHistoricalPod(name, "test-node", types.UID(rsUID), corev1.PodRunning)
+		pod.OwnerReferences[0].Name = rsName
+		pod.Labels = map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash}
+		return pod
+	}
+	assert.Equal(t, "", getPodWorkloadKey(&corev1.Pod{}))
+	assert.Equal(t, "test-rs-uid", getPodWorkloadKey(newTestHistoricalPod("pod", "", "test-rs-uid", corev1.PodPending)))
+	// the pods of the different revisions of a Deployment are siblings
+	assert.Equal(t, "Deployment/default/test-deploy", getPodWorkloadKey(newDeploymentPod("pod-1", "test-deploy-5d8f7b", "rs-1", "5d8f7b")))
+	assert.Equal(t, "Deployment/default/test-deploy", getPodWorkloadKey(newDeploymentPod("pod-2", "test-deploy-7c9d6a", "rs-2", "7c9d6a")))
+	// the ReplicaSet not named by the pod-template-hash is not created by a Deployment
+	assert.Equal(t, "rs-3", getPodWorkloadKey(newDeploymentPod("pod-3", "test-rs", "rs-3", "5d8f7b")))
+
+	now := metav1.Now()
+	var pods []*corev1.Pod
+	var podMetrics []*slov1alpha1.PodMetricInfo
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("old-revision-%d", i)
+		pods = append(pods, newDeploymentPod(name, "test-deploy-5d8f7b", "rs-1", "5d8f7b"))
+		podMetrics = append(podMetrics, newTestPodMetric(name, "1", "1Gi"))
+	}
+	e := newTestHistoricalUsageEstimator(t, &config.LoadAwareSchedulingHistoricalEstimationArgs{SafetyMarginPercent: ptr.To[int64](0)},
+		pods, []*slov1alpha1.NodeMetric{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Status:     slov1alpha1.NodeMetricStatus{UpdateTime: &now, PodsMetric: podMetrics},
+			},
+		})
+	got, err := e.EstimatePod(newDeploymentPod("new-revision", "test-deploy-7c9d6a", "rs-2", "7c9d6a"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[corev1.ResourceName]int64{
+		corev1.ResourceCPU:    1000,
+		corev1.ResourceMemory: 1024 * 1024 * 1024,
+	}, got)
+}
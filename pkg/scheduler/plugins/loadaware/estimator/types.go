@@ -26,7 +26,8 @@ import (
 type FactoryFn func(args *config.LoadAwareSchedulingArgs, handle fwktype.Handle) (Estimator, error)
 
 var Estimators = map[string]FactoryFn{
-	defaultEstimatorName: NewDefaultEstimator,
+	defaultEstimatorName:         NewDefaultEstimator,
+	historicalUsageEstimatorName: NewHistoricalUsageEstimator,
 }
 
 type Estimator interface {
@@ -160,6 +160,12 @@ func TestNew(t *testing.T) {
 	p, err := proxyNew(context.TODO(), &loadAwareSchedulingArgs, fh)
 	assert.NotNil(t, p)
 	assert.Nil(t, err)
+
+	loadAwareSchedulingArgs.Estimator = "historicalUsageEstimator"
+	p, err = proxyNew(context.TODO(), &loadAwareSchedulingArgs, fh)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+	assert.Equal(t, "historicalUsageEstimator", p.(*Plugin).estimator.Name())
 }
 
 func TestFilterExpiredNodeMetric(t *testing.T) {
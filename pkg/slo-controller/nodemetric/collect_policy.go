@@ -42,6 +42,7 @@ func getNodeMetricCollectPolicy(strategy *configuration.ColocationStrategy) (*sl
 		ReportIntervalSeconds:    strategy.MetricReportIntervalSeconds,
 		NodeAggregatePolicy:      strategy.MetricAggregatePolicy,
 		NodeMemoryCollectPolicy:  strategy.MetricMemoryCollectPolicy,
+		ContainerAggregatePolicy: strategy.MetricContainerAggregatePolicy,
 	}
 	return collectPolicy, nil
 }
@@ -61,6 +61,40 @@ func Test_getNodeMetricCollectPolicy(t *testing.T) {
 				NodeMemoryCollectPolicy:  &defaultNodeMemoryCollectPolicy,
 			},
 		},
+		{
+			name: "config enabled with container aggregate policy",
+			config: &configuration.ColocationStrategy{
+				Enable:                         ptr.To[bool](true),
+				MetricAggregateDurationSeconds: ptr.To[int64](60),
+				MetricReportIntervalSeconds:    ptr.To[int64](180),
+				MetricMemoryCollectPolicy:      &defaultNodeMemoryCollectPolicy,
+				MetricContainerAggregatePolicy: &slov1alpha1.ContainerAggregatePolicy{
+					Enable:        ptr.To[bool](true),
+					MaxContainers: ptr.To[int64](100),
+				},
+			},
+			want: &slov1alpha1.NodeMetricCollectPolicy{
+				AggregateDurationSeconds: ptr.To[int64](60),
+				ReportIntervalSeconds:    ptr.To[int64](180),
+				NodeMemoryCollectPolicy:  &defaultNodeMemoryCollectPolicy,
+				ContainerAggregatePolicy: &slov1alpha1.ContainerAggregatePolicy{
+					Enable:        ptr.To[bool](true),
+					MaxContainers: ptr.To[int64](100),
+				},
+			},
+		},
+		{
+			name: "invalid container aggregate policy",
+			config: &configuration.ColocationStrategy{
+				Enable: ptr.To[bool](true),
+				MetricContainerAggregatePolicy: &slov1alpha1.ContainerAggregatePolicy{
+					Enable:        ptr.To[bool](true),
+					MaxContainers: ptr.To[int64](-1),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
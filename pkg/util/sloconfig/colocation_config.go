@@ -92,6 +92,8 @@ func IsColocationStrategyValid(strategy *configuration.ColocationStrategy) bool
 		(strategy.UpdateTimeThresholdSeconds == nil || *strategy.UpdateTimeThresholdSeconds > 0) &&
 		(strategy.ResourceDiffThreshold == nil || *strategy.ResourceDiffThreshold > 0) &&
 		(strategy.MetricMemoryCollectPolicy == nil || len(*strategy.MetricMemoryCollectPolicy) > 0) &&
+		(strategy.MetricContainerAggregatePolicy == nil || strategy.MetricContainerAggregatePolicy.MaxContainers == nil ||
+			*strategy.MetricContainerAggregatePolicy.MaxContainers >= 0) &&
 		(strategy.MidCPUThresholdPercent == nil || (*strategy.MidCPUThresholdPercent >= 0 && *strategy.MidCPUThresholdPercent <= 100)) &&
 		(strategy.MidMemoryThresholdPercent == nil || (*strategy.MidMemoryThresholdPercent >= 0 && *strategy.MidMemoryThresholdPercent <= 100)) &&
 		(strategy.MidUnallocatedPercent == nil || (*strategy.MidUnallocatedPercent >= 0 && *strategy.MidUnallocatedPercent <= 100)) &&
@@ -58,6 +58,19 @@ func (qt *quotaTopology) validateQuotaSelfItem(quota *v1alpha1.ElasticQuota) err
 		}
 	}
 
+	if _, err := extension.GetFairShareWeight(quota); err != nil {
+		return fmt.Errorf("%v quota.Annotation[%v]'s value is invalid: %w", quota.Name, extension.AnnotationFairShareWeight, err)
+	}
+	if _, err := extension.GetLendingSchedules(quota); err != nil {
+		return fmt.Errorf("%v quota.Annotation[%v]'s value is invalid: %w", quota.Name, extension.AnnotationLendingSchedules, err)
+	}
+	if _, err := extension.GetMaxBorrowDuration(quota); err != nil {
+		return fmt.Errorf("%v quota.Annotation[%v]'s value is invalid: %w", quota.Name, extension.AnnotationMaxBorrowDuration, err)
+	}
+	if _, _, err := extension.GetRevokeGracePeriod(quota); err != nil {
+		return fmt.Errorf("%v quota.Annotation[%v]'s value is invalid: %w", quota.Name, extension.AnnotationRevokeGracePeriod, err)
+	}
+
 	// 1. check if all key in min are included in max
 	// 2. check if all quantities in min <= that in max
 	for key, val := range quota.Spec.Min {
@@ -92,6 +92,39 @@ func TestQuotaTopology_basicItemCheck(t *testing.T) {
 			quota: MakeQuota("temp").sharedWeight(MakeResourceList().CPU(-1).Mem(1048576).Obj()).Max(MakeResourceList().CPU(0).Mem(1048576).Obj()).Obj(),
 			err:   fmt.Errorf("%v quota.Annotation[%v]'s value < 0, in dimension :%v", "temp", extension.AnnotationSharedWeight, "[cpu]"),
 		},
+		{
+			name: "annotation fairShareWeight <= 0",
+			quota: MakeQuota("temp").Annotations(map[string]string{extension.AnnotationFairShareWeight: "0"}).
+				Max(MakeResourceList().CPU(0).Mem(1048576).Obj()).Obj(),
+			err: fmt.Errorf("%v quota.Annotation[%v]'s value is invalid: %w", "temp", extension.AnnotationFairShareWeight,
+				fmt.Errorf("fair-share weight should be positive, got 0")),
+		},
+		{
+			name: "annotation lendingSchedules with invalid lendRatio",
+			quota: MakeQuota("temp").Annotations(map[string]string{extension.AnnotationLendingSchedules: `[{"start":"20:00","end":"08:00","lendRatio":1.5}]`}).
+				Max(MakeResourceList().CPU(0).Mem(1048576).Obj()).Obj(),
+			err: fmt.Errorf("%v quota.Annotation[%v]'s value is invalid: %w", "temp", extension.AnnotationLendingSchedules,
+				fmt.Errorf("invalid lending schedule %d, err: %w", 0, fmt.Errorf("lendRatio should be in (0, 1], got 1.5"))),
+		},
+		{
+			name: "annotation lendingSchedules valid",
+			quota: MakeQuota("temp").Annotations(map[string]string{extension.AnnotationLendingSchedules: `[{"days":["Sat","Sun"],"start":"00:00","end":"00:00","lendRatio":0.8}]`}).
+				Max(MakeResourceList().CPU(0).Mem(1048576).Obj()).Obj(),
+		},
+		{
+			name: "annotation maxBorrowDuration < 0",
+			quota: MakeQuota("temp").Annotations(map[string]string{extension.AnnotationMaxBorrowDuration: "-1h"}).
+				Max(MakeResourceList().CPU(0).Mem(1048576).Obj()).Obj(),
+			err: fmt.Errorf("%v quota.Annotation[%v]'s value is invalid: %w", "temp", extension.AnnotationMaxBorrowDuration,
+				fmt.Errorf("duration should not be negative, got -1h")),
+		},
+		{
+			name: "annotation revokeGracePeriod < 0",
+			quota: MakeQuota("temp").Annotations(map[string]string{extension.AnnotationRevokeGracePeriod: "-5m"}).
+				Max(MakeResourceList().CPU(0).Mem(1048576).Obj()).Obj(),
+			err: fmt.Errorf("%v quota.Annotation[%v]'s value is invalid: %w", "temp", extension.AnnotationRevokeGracePeriod,
+				fmt.Errorf("duration should not be negative, got -5m")),
+		},
 		{
 			name: "annotation check max >= used",
 			quota: MakeQuota("temp").Annotations(map[string]string{extension.AnnotationMaxStrictCheckResourceKeys: `["cpu","memory"]`}).